@@ -14,9 +14,11 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/zmqge/vireo-gin-admin/app/admin/services"
 	"github.com/zmqge/vireo-gin-admin/config"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/auth"
 	"github.com/zmqge/vireo-gin-admin/pkg/redis"
 	"github.com/zmqge/vireo-gin-admin/pkg/response"
+	"github.com/zmqge/vireo-gin-admin/pkg/sysconfig"
 	"github.com/zmqge/vireo-gin-admin/utils"
 )
 
@@ -42,8 +44,8 @@ func (c *AuthController) Login(ctx *gin.Context) {
 	type loginRequest struct {
 		Username   string `json:"username" form:"username" binding:"required"`
 		Password   string `json:"password" form:"password" binding:"required"`
-		CaptchaID  string `json:"captchaKey" form:"captchaKey" binding:"required"`
-		CaptchaAns string `json:"captchaCode" form:"captchaCode" binding:"required"`
+		CaptchaID  string `json:"captchaKey" form:"captchaKey"`
+		CaptchaAns string `json:"captchaCode" form:"captchaCode"`
 	}
 
 	// 2. 绑定参数
@@ -53,9 +55,9 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		return
 	}
 
-	// 3. 验证验证码
-	if !utils.VerifyCaptcha(req.CaptchaID, req.CaptchaAns) {
-		response.BadRequest(ctx, "验证码错误")
+	// 3. 验证验证码（可通过系统配置 sys.captcha.enabled 关闭）
+	if sysconfig.GetBool(sysconfig.KeyCaptchaEnabled, true) && !utils.VerifyCaptcha(req.CaptchaID, req.CaptchaAns) {
+		response.Error(ctx, apperr.ErrCaptchaInvalid)
 		return
 	}
 
@@ -113,8 +115,9 @@ func (c *AuthController) GetCaptcha(ctx *gin.Context) {
 
 	// 返回验证码 ID 和图片
 	response.Success(ctx, gin.H{
-		"captchaKey":    id,
-		"captchaBase64": b64s,
+		"captchaKey":     id,
+		"captchaBase64":  b64s,
+		"captchaEnabled": sysconfig.GetBool(sysconfig.KeyCaptchaEnabled, true),
 	}, "一切ok")
 }
 
@@ -192,7 +195,7 @@ func (c *AuthController) RefreshToken(ctx *gin.Context) {
 	// 签名并生成 Token 字符串
 	newAccessToken, err := token.SignedString([]byte(config.App.JWT.AccessSecret))
 	if err != nil {
-		response.Error(ctx, errors.New("生成 Access Token 失败"))
+		response.Error(ctx, err)
 		return
 	}
 
@@ -6,11 +6,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/response"
 )
 
 // ConfigController Config控制器
-// @Group(path="/api/v1/", name="Config管理")
+// @Group(path="/api/v1/", name="Config管理", middlewares=["jwt"])
 type ConfigController struct {
 	service services.ConfigService
 }
@@ -21,13 +22,13 @@ func NewConfigController(service services.ConfigService) *ConfigController {
 }
 
 // getConfig 获取单个Config
-// @Route(method=GET, path="/config/:id", middlewares=["jwt","dataperm"])
+// @Route(method=GET, path="/config/:id", middlewares=["dataperm"])
 // @Permission(code="sys:config:view", name="Config详情",modules="Config管理", desc="查看Config详情")
 func (c *ConfigController) GetConfigDetails(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -40,7 +41,7 @@ func (c *ConfigController) GetConfigDetails(ctx *gin.Context) {
 }
 
 // listConfigs 获取Config分页列表
-// @Route(method=GET, path="/config/page", middlewares=["jwt","dataperm"])
+// @Route(method=GET, path="/config/page", middlewares=["dataperm"])
 // @Permission(code="sys:config:query",name="Config列表",modules="Config管理", desc="查看Config列表")
 func (c *ConfigController) ListConfigs(ctx *gin.Context) {
 	keywords := ctx.Query("keywords")
@@ -68,12 +69,12 @@ func (c *ConfigController) ListConfigs(ctx *gin.Context) {
 }
 
 // createConfig 创建Config
-// @Route(method=POST, path="/config", middlewares=["jwt"])
+// @Route(method=POST, path="/config")
 // @Permission(code="sys:config:add",name="新建Config",modules="Config管理", desc="创建Config")
 func (c *ConfigController) CreateConfig(ctx *gin.Context) {
 	var entity models.ConfigModel
 	if err := ctx.ShouldBindJSON(&entity); err != nil {
-		response.BadRequest(ctx, "Invalid request body")
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	err := c.service.CreateConfig(ctx, &entity)
@@ -85,19 +86,19 @@ func (c *ConfigController) CreateConfig(ctx *gin.Context) {
 }
 
 // updateConfig 更新Config
-// @Route(method=PUT, path="/config/:id", middlewares=["jwt","dataperm"])
+// @Route(method=PUT, path="/config/:id", middlewares=["dataperm"])
 // @Permission(code="sys:config:update",name="更新Config",modules="Config管理", desc="更新Config")
 func (c *ConfigController) UpdateConfig(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
 	var entity models.ConfigModel
 	if err := ctx.ShouldBindJSON(&entity); err != nil {
-		response.BadRequest(ctx, "Invalid request body")
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	entity.ID = uint(id)
@@ -110,13 +111,13 @@ func (c *ConfigController) UpdateConfig(ctx *gin.Context) {
 }
 
 // deleteConfig 删除Config
-// @Route(method=DELETE, path="/config/:id", middlewares=["jwt"])
+// @Route(method=DELETE, path="/config/:id")
 // @Permission(code="sys:config:delete",name="删除Config",modules="Config管理", desc="删除Config")
 func (c *ConfigController) DeleteConfig(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -129,13 +130,13 @@ func (c *ConfigController) DeleteConfig(ctx *gin.Context) {
 }
 
 // getDict 获取Config表单
-// @Route(method=GET, path="/config/:id/form", middlewares=["jwt","dataperm"])
+// @Route(method=GET, path="/config/:id/form", middlewares=["dataperm"])
 // @Permission(code="sys:config:details",name="Config详情",modules="Config管理", desc="查看Config详情")
 func (c *ConfigController) GetConfigForm(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 	entity, err := c.service.GetConfigByID(ctx, uint(id))
@@ -146,3 +147,64 @@ func (c *ConfigController) GetConfigForm(ctx *gin.Context) {
 	}
 	response.Success(ctx, entity)
 }
+
+// ListConfigHistory Config版本历史
+// @Route(method=GET, path="/config/:id/history", middlewares=["dataperm"])
+// @Permission(code="sys:config:history",name="Config版本历史",modules="Config管理", desc="查看Config的版本历史")
+func (c *ConfigController) ListConfigHistory(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	list, err := c.service.ListConfigHistory(ctx, uint(id))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, list)
+}
+
+// DiffConfigVersion 比较Config历史版本与当前值
+// @Route(method=GET, path="/config/:id/history/:version/diff", middlewares=["dataperm"])
+// @Permission(code="sys:config:diff",name="Config版本对比",modules="Config管理", desc="比较Config历史版本与当前值")
+func (c *ConfigController) DiffConfigVersion(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		response.BadRequest(ctx, "Invalid version")
+		return
+	}
+	diff, err := c.service.DiffConfigVersion(ctx, uint(id), version)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, diff)
+}
+
+// RollbackConfig 回滚Config到历史版本
+// @Route(method=PUT, path="/config/:id/rollback/:version", middlewares=["dataperm"])
+// @Permission(code="sys:config:rollback",name="Config版本回滚",modules="Config管理", desc="将Config回滚到历史版本")
+func (c *ConfigController) RollbackConfig(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		response.BadRequest(ctx, "Invalid version")
+		return
+	}
+	entity, err := c.service.RollbackConfig(ctx, uint(id), version)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, entity)
+}
@@ -1,18 +1,17 @@
 package controllers
 
 import (
-	"errors"
-
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/response"
 	"github.com/zmqge/vireo-gin-admin/utils"
 	"gorm.io/gorm"
 )
 
-// @Group(path="/api/v1/", name="部门管理")
+// @Group(path="/api/v1/", name="部门管理", middlewares=["jwt"])
 type DeptController struct {
 	deptService *services.DeptService
 }
@@ -24,7 +23,7 @@ func NewDeptController(db *gorm.DB) *DeptController {
 
 // 创建部门
 // @Summary 创建部门
-// @Route(method="POST", path="/dept",  middlewares=["jwt"])
+// @Route(method="POST", path="/dept")
 // @Permission(code="sys:dept:add", name="创建部门", modules="部门管理", desc="创建部门")
 func (c *DeptController) CreateDept(ctx *gin.Context) {
 	var form struct {
@@ -36,7 +35,7 @@ func (c *DeptController) CreateDept(ctx *gin.Context) {
 	}
 	if err := ctx.ShouldBindJSON(&form); err != nil {
 		logrus.Errorf("Invalid request body: %v", err)
-		response.Error(ctx, errors.New("invalid request body: "+err.Error()))
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 
@@ -50,7 +49,7 @@ func (c *DeptController) CreateDept(ctx *gin.Context) {
 	}
 	if err := c.deptService.CreateDept(dept); err != nil {
 		logrus.Errorf("Failed to create dept: %v", err)
-		response.Error(ctx, errors.New("failed to create dept"))
+		response.Error(ctx, err)
 		return
 	}
 
@@ -60,7 +59,7 @@ func (c *DeptController) CreateDept(ctx *gin.Context) {
 }
 
 // 更新部门
-// @Route(method="PUT", path="/dept/:id",  middlewares=["jwt"])
+// @Route(method="PUT", path="/dept/:id")
 // @Permission(code="sys:dept:edit", name="编辑部门", modules="部门管理", desc="更新部门")
 func (c *DeptController) UpdateDept(ctx *gin.Context) {
 	var form struct {
@@ -72,7 +71,7 @@ func (c *DeptController) UpdateDept(ctx *gin.Context) {
 	}
 	if err := ctx.ShouldBindJSON(&form); err != nil {
 		logrus.Errorf("Invalid request body: %v", err)
-		response.Error(ctx, errors.New("invalid request body: "+err.Error()))
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 
@@ -84,7 +83,7 @@ func (c *DeptController) UpdateDept(ctx *gin.Context) {
 	var err error
 	if deptID, err = utils.ParseUintID(id); err != nil {
 		logrus.Errorf("Failed to convert id to uint: %v", err)
-		response.Error(ctx, errors.New("invalid id"))
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -106,7 +105,7 @@ func (c *DeptController) UpdateDept(ctx *gin.Context) {
 }
 
 // 获取部门详情
-// @Route(method="GET", path="/dept/:id/form",  middlewares=["jwt"])
+// @Route(method="GET", path="/dept/:id/form")
 // @Permission(code="sys:dept:view", name="查看部门", modules="部门管理", desc="获取部门详情")
 func (c *DeptController) GetDept(ctx *gin.Context) {
 	// 获取部门ID
@@ -129,14 +128,14 @@ func (c *DeptController) GetDept(ctx *gin.Context) {
 }
 
 // 获取部门下拉列表
-// @Route(method="GET", path="/dept/options",  middlewares=["jwt","dataperm"])
+// @Route(method="GET", path="/dept/options",  middlewares=["dataperm"])
 // @Permission(code="sys:dept:options", name="部门下拉列表", modules="部门管理", desc="获取部门下拉列表")
 func (c *DeptController) GetDeptOptions(ctx *gin.Context) {
 	// 调用服务层获取菜单列表
 	menus, err := c.deptService.GetDeptOptions(ctx)
 	if err != nil {
 		logrus.Errorf("Failed to get depts: %v", err)
-		response.Error(ctx, errors.New("failed to fetch depts"))
+		response.Error(ctx, err)
 		return
 	}
 	response.Success(ctx, menus)
@@ -145,7 +144,7 @@ func (c *DeptController) GetDeptOptions(ctx *gin.Context) {
 // @Summary 获取部门列表
 // @Description 获取部门列表
 // @Tags 部门管理
-// @Route(method="GET", path="/dept",  middlewares=["jwt"])
+// @Route(method="GET", path="/dept")
 // @Permission(code="sys:dept:query", name="部门列表", modules="部门管理", desc="获取部门列表")
 func (c *DeptController) GetDepts(ctx *gin.Context) {
 	// 获取查询参数
@@ -156,7 +155,7 @@ func (c *DeptController) GetDepts(ctx *gin.Context) {
 	menus, err := c.deptService.GetDepts(ctx, keywords, status)
 	if err != nil {
 		logrus.Errorf("Failed to get depts: %v", err)
-		response.Error(ctx, errors.New("failed to fetch depts"))
+		response.Error(ctx, err)
 		return
 	}
 
@@ -164,7 +163,7 @@ func (c *DeptController) GetDepts(ctx *gin.Context) {
 }
 
 // 删除部门
-// @Route(method=DELETE, path="/dept/:id",  middlewares=["jwt"])
+// @Route(method=DELETE, path="/dept/:id")
 // @Permission(code="sys:dept:delete", name="删除部门", modules="部门管理", desc="删除部门")
 func (c *DeptController) DeleteDept(ctx *gin.Context) {
 	// 获取部门ID
@@ -174,7 +173,7 @@ func (c *DeptController) DeleteDept(ctx *gin.Context) {
 	var err error
 	if deptID, err = utils.ParseUintID(id); err != nil {
 		logrus.Errorf("Failed to convert id to uint: %v", err)
-		response.Error(ctx, errors.New("invalid id"))
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -1,16 +1,23 @@
 package controllers
 
 import (
+	"io"
+	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/dictbundle"
+	"github.com/zmqge/vireo-gin-admin/pkg/dictcache"
+	"github.com/zmqge/vireo-gin-admin/pkg/i18n"
 	"github.com/zmqge/vireo-gin-admin/pkg/response"
 )
 
 // DictController Dict控制器
-// @Group(path="/api/v1/", desc="Dict相关接口")
+// @Group(path="/api/v1/", desc="Dict相关接口", middlewares=["jwt"])
 type DictController struct {
 	service services.DictService
 }
@@ -21,13 +28,13 @@ func NewDictController(service services.DictService) *DictController {
 }
 
 // getDict 获取单个Dict
-// @Route(method=GET, path="/dicts/:id/form", middlewares=["jwt"])
+// @Route(method=GET, path="/dicts/:id/form")
 // @Permission(code="sys:dict:details",name="查看字典",modules="字典管理", desc="查看Dict详情")
 func (c *DictController) GetDict(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -40,7 +47,7 @@ func (c *DictController) GetDict(ctx *gin.Context) {
 }
 
 // listDicts 获取Dict分页列表
-// @Route(method=GET, path="/dicts/page", middlewares=["jwt"])
+// @Route(method=GET, path="/dicts/page")
 // @Permission(code="sys:dict:query",name="字典查询",modules="字典管理", desc="查看Dict列表")
 func (c *DictController) ListDicts(ctx *gin.Context) {
 	keywords := ctx.Query("keywords")
@@ -68,12 +75,12 @@ func (c *DictController) ListDicts(ctx *gin.Context) {
 }
 
 // createDict 创建Dict
-// @Route(method=POST, path="/dicts", middlewares=["jwt"])
+// @Route(method=POST, path="/dicts")
 // @Permission(code="sys:dict:add",name="新增字典",modules="字典管理", desc="创建Dict")
 func (c *DictController) CreateDict(ctx *gin.Context) {
 	var entity models.DictModel
 	if err := ctx.ShouldBindJSON(&entity); err != nil {
-		response.BadRequest(ctx, "Invalid request body")
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	err := c.service.CreateDict(&entity)
@@ -85,19 +92,19 @@ func (c *DictController) CreateDict(ctx *gin.Context) {
 }
 
 // updateDict 更新Dict
-// @Route(method=PUT, path="/dicts/:id", middlewares=["jwt"])
+// @Route(method=PUT, path="/dicts/:id")
 // @Permission(code="sys:dict:edit",name="编辑字典",modules="字典管理", desc="创建Dict")
 func (c *DictController) UpdateDict(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
 	var entity models.DictModel
 	if err := ctx.ShouldBindJSON(&entity); err != nil {
-		response.BadRequest(ctx, "Invalid request body")
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	entity.ID = uint(id)
@@ -110,13 +117,13 @@ func (c *DictController) UpdateDict(ctx *gin.Context) {
 }
 
 // deleteDict 删除Dict
-// @Route(method=DELETE, path="/dicts/:id", middlewares=["jwt"])
+// @Route(method=DELETE, path="/dicts/:id")
 // @Permission(code="sys:dict-item:delete",name="删除字典项", desc="删除字典项",modules="字典管理")
 func (c *DictController) DeleteDict(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -129,7 +136,7 @@ func (c *DictController) DeleteDict(ctx *gin.Context) {
 }
 
 // GetDictItem 获取Dict选项
-// @Route(method=GET, path="/dicts-items/:dictCode/items", middlewares=["jwt"])
+// @Route(method=GET, path="/dicts-items/:dictCode/items")
 // @Permission(code="sys:dict-item:details",name="查看字典项",modules="字典项管理", desc="查看Dict选项")
 func (c *DictController) GetDictItem(ctx *gin.Context) {
 	dictCode := ctx.Param("dictCode")
@@ -138,25 +145,123 @@ func (c *DictController) GetDictItem(ctx *gin.Context) {
 		return
 	}
 
-	items, err := c.service.GetDictItemsByCode(dictCode)
+	entry, err := c.service.GetDictOptions(ctx, dictCode)
 	if err != nil {
 		response.Error(ctx, err)
 		return
 	}
+	locale := i18n.FromContext(ctx)
+	if notModified(ctx, dictcache.LocalizeETag(entry.ETag, locale)) {
+		return
+	}
 	// OpenAPI 规范要求返回数组，且字段为 value/label/tagType
-	var result []map[string]interface{}
-	for _, item := range items {
-		result = append(result, map[string]interface{}{
-			"value":   item.Value,
-			"label":   item.Label,
-			"tagType": item.TagType,
-		})
+	response.Success(ctx, dictcache.Localize(entry.Options, locale))
+}
+
+// GetDictItemsBatch 批量获取多个字典的选项，codes 以逗号分隔，最多 50 个，含不存在的字典编码时返回 404
+// @Route(method=GET, path="/dicts-items/batch")
+// @Permission(code="sys:dict-item:batch",name="批量查看字典项",modules="字典项管理", desc="一次获取多个Dict的选项")
+func (c *DictController) GetDictItemsBatch(ctx *gin.Context) {
+	codes := dictcache.UniqueCodes(strings.Split(ctx.Query("codes"), ","))
+	if len(codes) == 0 {
+		response.BadRequest(ctx, "codes is required")
+		return
+	}
+	result, etag, err := c.service.GetDictOptionsBatch(ctx, codes)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	locale := i18n.FromContext(ctx)
+	if notModified(ctx, dictcache.LocalizeETag(etag, locale)) {
+		return
+	}
+	for code, options := range result {
+		result[code] = dictcache.Localize(options, locale)
 	}
 	response.Success(ctx, result)
 }
 
+// ExportDicts 导出字典及字典项
+// @Route(method=GET, path="/dicts/export")
+// @Permission(code="sys:dict:export",name="导出字典",modules="字典管理", desc="导出字典及字典项，codes 逗号分隔（为空导出全部），format=json|yaml")
+func (c *DictController) ExportDicts(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", dictbundle.FormatYAML)
+	var codes []string
+	if raw := ctx.Query("codes"); raw != "" {
+		codes = strings.Split(raw, ",")
+	}
+	bundle, err := c.service.ExportDicts(codes)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	data, err := dictbundle.Encode(bundle, format)
+	if err != nil {
+		response.Error(ctx, apperr.ErrDictBundleInvalid.WithArgs(err.Error()).Wrap(err))
+		return
+	}
+	contentType := "application/json; charset=utf-8"
+	if format == dictbundle.FormatYAML {
+		contentType = "application/yaml; charset=utf-8"
+	}
+	ctx.Header("Content-Disposition", "attachment; filename=dicts."+format)
+	ctx.Data(http.StatusOK, contentType, data)
+}
+
+// ImportDicts 导入字典包
+// @Route(method=POST, path="/dicts/import")
+// @Permission(code="sys:dict:import",name="导入字典",modules="字典管理", desc="按字典编码和字典项值新增或更新字典，dryRun=true 仅预览变更")
+// ImportDicts 表单参数: file 字典包（.json/.yaml/.yml）; dryRun=true 仅预览
+func (c *DictController) ImportDicts(ctx *gin.Context) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		response.Error(ctx, apperr.ErrFileRequired)
+		return
+	}
+	format, err := dictbundle.FormatOf(fileHeader.Filename)
+	if err != nil {
+		response.Error(ctx, apperr.ErrDictBundleInvalid.WithArgs(err.Error()).Wrap(err))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	bundle, err := dictbundle.Decode(data, format)
+	if err != nil {
+		response.Error(ctx, apperr.ErrDictBundleInvalid.WithArgs(err.Error()).Wrap(err))
+		return
+	}
+	dryRun, _ := strconv.ParseBool(ctx.DefaultPostForm("dryRun", "false"))
+	plan, err := c.service.ImportDicts(bundle, dryRun)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, plan)
+}
+
+// notModified 设置 ETag，客户端缓存未变化时返回 304
+func notModified(ctx *gin.Context, etag string) bool {
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "no-cache")
+	if dictcache.MatchETag(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
 // GetDictItemPage 获取字典项分页列表
-// @Route(method=GET, path="/dicts-items/:dictCode/items/page", middlewares=["jwt"])
+// @Route(method=GET, path="/dicts-items/:dictCode/items/page")
 // @Permission(code="sys:dict-item:query",name="字典项查询",modules="字典项管理", desc="查看Dict选项")
 func (c *DictController) GetDictItemPage(ctx *gin.Context) {
 	dictCode := ctx.Param("dictCode")
@@ -189,7 +294,7 @@ func (c *DictController) GetDictItemPage(ctx *gin.Context) {
 }
 
 // CreateDictItem 新增字典项
-// @Route(method=POST, path="/dicts-items/:dictCode/items", middlewares=["jwt"])
+// @Route(method=POST, path="/dicts-items/:dictCode/items")
 // @Permission(code="sys:dict-item:add",name="新增字典项",modules="字典项管理", desc="查看Dict选项")
 func (c *DictController) CreateDictItem(ctx *gin.Context) {
 	dictCode := ctx.Param("dictCode")
@@ -199,7 +304,7 @@ func (c *DictController) CreateDictItem(ctx *gin.Context) {
 	}
 	var form models.DictItemModel
 	if err := ctx.ShouldBindJSON(&form); err != nil {
-		response.BadRequest(ctx, "Invalid request body")
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	form.DictCode = dictCode // 路径参数优先生效
@@ -212,7 +317,7 @@ func (c *DictController) CreateDictItem(ctx *gin.Context) {
 }
 
 // UpdateDictItem 更新字典项
-// @Route(method=PUT, path="/dicts-items/:dictCode/items/:id", middlewares=["jwt"])
+// @Route(method=PUT, path="/dicts-items/:dictCode/items/:id")
 // @Permission(code="sys:dict-item:edit",name="编辑字典项",modules="字典项管理", desc="查看Dict选项")
 func (c *DictController) UpdateDictItem(ctx *gin.Context) {
 	dictCode := ctx.Param("dictCode")
@@ -223,13 +328,13 @@ func (c *DictController) UpdateDictItem(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
 	var form models.DictItemModel
 	if err := ctx.ShouldBindJSON(&form); err != nil {
-		response.BadRequest(ctx, "Invalid request body")
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	form.ID = uint(id)
@@ -244,7 +349,7 @@ func (c *DictController) UpdateDictItem(ctx *gin.Context) {
 }
 
 // DeleteDictItem 删除字典项
-// @Route(method=DELETE, path="/dicts-items/:dictCode/items/:id", middlewares=["jwt"])
+// @Route(method=DELETE, path="/dicts-items/:dictCode/items/:id")
 // @Permission(code="sys:dict:delete",name="删除字典项", desc="删除字典项",modules="字典项管理")
 func (c *DictController) DeleteDictItem(ctx *gin.Context) {
 	dictCode := ctx.Param("dictCode")
@@ -255,7 +360,7 @@ func (c *DictController) DeleteDictItem(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -268,7 +373,7 @@ func (c *DictController) DeleteDictItem(ctx *gin.Context) {
 }
 
 // GetDictItemForm 获取字典项表单数据
-// @Route(method=GET, path="/dicts-items/:dictCode/items/:itemId/form", middlewares=["jwt"])
+// @Route(method=GET, path="/dicts-items/:dictCode/items/:itemId/form")
 // @Permission(code="sys:dict-item:form",name="字典项表单",modules="字典项管理", desc="查看Dict选项")
 func (c *DictController) GetDictItemForm(ctx *gin.Context) {
 	dictCode := ctx.Param("dictCode")
@@ -294,3 +399,45 @@ func (c *DictController) GetDictItemForm(ctx *gin.Context) {
 
 	response.Success(ctx, item)
 }
+
+// GetDictItemI18n 获取字典项标签的翻译
+// @Route(method=GET, path="/dicts-items/:dictCode/i18n")
+// @Permission(code="sys:dict-item:i18n",name="字典项翻译",modules="字典项管理", desc="查看字典项标签的多语言翻译")
+func (c *DictController) GetDictItemI18n(ctx *gin.Context) {
+	dictCode := ctx.Param("dictCode")
+	if dictCode == "" {
+		response.BadRequest(ctx, "Dict code is required")
+		return
+	}
+	list, err := c.service.GetDictItemI18n(dictCode)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, gin.H{
+		"defaultLocale": i18n.DefaultLocale(),
+		"locales":       i18n.Supported(),
+		"list":          list,
+	})
+}
+
+// UpdateDictItemI18n 整体保存字典项标签的翻译，请求体为 [{value, locale, label}]
+// @Route(method=PUT, path="/dicts-items/:dictCode/i18n")
+// @Permission(code="sys:dict-item:i18n-edit",name="编辑字典项翻译",modules="字典项管理", desc="维护字典项标签的多语言翻译")
+func (c *DictController) UpdateDictItemI18n(ctx *gin.Context) {
+	dictCode := ctx.Param("dictCode")
+	if dictCode == "" {
+		response.BadRequest(ctx, "Dict code is required")
+		return
+	}
+	var list []*models.DictItemI18n
+	if err := ctx.ShouldBindJSON(&list); err != nil {
+		response.Error(ctx, apperr.FromBinding(err))
+		return
+	}
+	if err := c.service.SaveDictItemI18n(ctx, dictCode, list); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, nil)
+}
@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"encoding/json"
-	"errors"
 	"strconv"
 	"strings"
 
@@ -10,11 +9,13 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/i18n"
 	"github.com/zmqge/vireo-gin-admin/pkg/response"
 	"gorm.io/gorm"
 )
 
-// @Group(name="菜单管理",path="/api/v1")
+// @Group(name="菜单管理",path="/api/v1", middlewares=["jwt"])
 type MenuController struct {
 	menuService *services.MenuService
 }
@@ -26,17 +27,18 @@ func NewMenuController(db *gorm.DB) *MenuController {
 }
 
 // GetCurrentUserRoutes 获取当前用户的路由列表
-// @Route(method=GET, path="/menus/routes", middlewares=["jwt"])
+// @Route(method=GET, path="/menus/routes")
+// @Permission(code="sys:menu:routes", name="路由列表", modules="菜单管理", desc="查看菜单路由")
 func (c *MenuController) GetCurrentUserRoutes(ctx *gin.Context) {
 	userID := ctx.GetString("userID")
 	if userID == "" {
-		response.Error(ctx, errors.New("userID is required"))
+		response.Error(ctx, apperr.ErrUnauthorized)
 		return
 	}
-	routes, err := c.menuService.GetCurrentUserRoutes(userID)
+	routes, err := c.menuService.GetCurrentUserRoutes(userID, i18n.FromContext(ctx))
 	if err != nil {
 		logrus.Errorf("Failed to get current user routes: %v", err)
-		response.Error(ctx, errors.New("failed to fetch user routes"))
+		response.Error(ctx, err)
 		return
 	}
 
@@ -44,7 +46,7 @@ func (c *MenuController) GetCurrentUserRoutes(ctx *gin.Context) {
 }
 
 // ListMenus 获取菜单列表
-// @Route(method=GET, path="/menus", middlewares=["jwt"])
+// @Route(method=GET, path="/menus")
 // @Permission(code="sys:menu:query", name="菜单查询", modules="菜单管理", desc="查看菜单列表")
 func (c *MenuController) ListMenus(ctx *gin.Context) {
 	// 获取查询参数
@@ -54,7 +56,7 @@ func (c *MenuController) ListMenus(ctx *gin.Context) {
 	menus, err := c.menuService.GetMenus(keywords)
 	if err != nil {
 		logrus.Errorf("Failed to get menus: %v", err)
-		response.Error(ctx, errors.New("failed to fetch menus"))
+		response.Error(ctx, err)
 		return
 	}
 
@@ -62,17 +64,17 @@ func (c *MenuController) ListMenus(ctx *gin.Context) {
 }
 
 // ListMenuOptions 获取菜单下拉列表
-// @Route(method=GET, path="/menus/options", middlewares=["jwt"])
+// @Route(method=GET, path="/menus/options")
 // @Permission(code="sys:menu:options", name="菜单下拉列表", modules="菜单管理", desc="获取菜单下拉选项")
 func (c *MenuController) ListMenuOptions(ctx *gin.Context) {
 	// 获取查询参数
 	onlyParent := ctx.Query("onlyParent") == "true"
 
 	// 调用服务层获取菜单下拉列表
-	options, err := c.menuService.GetMenuOptions(onlyParent)
+	options, err := c.menuService.GetMenuOptions(onlyParent, i18n.FromContext(ctx))
 	if err != nil {
 		logrus.Errorf("Failed to get menu options: %v", err)
-		response.Error(ctx, errors.New("failed to fetch menu options"))
+		response.Error(ctx, err)
 		return
 	}
 
@@ -80,13 +82,13 @@ func (c *MenuController) ListMenuOptions(ctx *gin.Context) {
 }
 
 // GetMenuDetail 获取菜单详情
-// @Route(method=GET, path="/menus/:id/form", middlewares=["jwt"])
+// @Route(method=GET, path="/menus/:id/form")
 // @Permission(code="sys:menu:details", name="菜单详情", modules="菜单管理", desc="查看菜单详情")
 func (c *MenuController) GetMenuDetail(ctx *gin.Context) {
 	// 获取路径参数
 	id := ctx.Param("id")
 	if id == "" {
-		response.Error(ctx, errors.New("menu id is required"))
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -94,7 +96,7 @@ func (c *MenuController) GetMenuDetail(ctx *gin.Context) {
 	menu, err := c.menuService.GetMenuDetail(id)
 	if err != nil {
 		logrus.Errorf("Failed to get menu detail: %v", err)
-		response.Error(ctx, errors.New("failed to fetch menu detail"))
+		response.Error(ctx, err)
 		return
 	}
 	// jsonData, err := json.Marshal(menu.Params)
@@ -134,7 +136,7 @@ func (c *MenuController) GetMenuDetail(ctx *gin.Context) {
 }
 
 // AddMenu 新增菜单
-// @Route(method=POST, path="/menus", middlewares=["jwt"])
+// @Route(method=POST, path="/menus")
 // @Permission(code="sys:menu:add", name="新增菜单", modules="菜单管理", desc="新增菜单")
 func (c *MenuController) AddMenu(ctx *gin.Context) {
 	var form struct {
@@ -166,17 +168,17 @@ func (c *MenuController) AddMenu(ctx *gin.Context) {
 					form.ParentID = uint(parentID)
 				}
 			}
-			response.Error(ctx, errors.New("invalid parentId type"))
+			response.Error(ctx, apperr.ErrValidation.WithField("parentId", "类型不正确"))
 			return
 		}
 		logrus.Errorf("Invalid request body: %v", err)
-		response.Error(ctx, errors.New("invalid request body: "+err.Error()))
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 
 	// Validate required fields
-	if form.Name == "" || form.Type == 0 {
-		response.Error(ctx, errors.New("name and type are required"))
+	if err := validateMenuForm(form.Name, form.Type); err != nil {
+		response.Error(ctx, err)
 		return
 	}
 
@@ -200,7 +202,7 @@ func (c *MenuController) AddMenu(ctx *gin.Context) {
 
 	if err := c.menuService.CreateMenu(&menu); err != nil {
 		logrus.Errorf("Failed to create menu: %v", err)
-		response.Error(ctx, errors.New("failed to create menu"))
+		response.Error(ctx, err)
 		return
 	}
 
@@ -225,8 +227,26 @@ func paramsToString(params []struct {
 	return string(jsonData)
 }
 
+// validateMenuForm 校验菜单名称和类型必填
+func validateMenuForm(name string, menuType int) error {
+	var errs *apperr.Error
+	if name == "" {
+		errs = apperr.ErrValidation.WithField("name", "不能为空")
+	}
+	if menuType == 0 {
+		if errs == nil {
+			errs = apperr.ErrValidation
+		}
+		errs = errs.WithField("type", "不能为空")
+	}
+	if errs == nil {
+		return nil
+	}
+	return errs
+}
+
 // UpdateMenu 修改菜单
-// @Route(method=PUT, path="/menus/:id", middlewares=["jwt"])
+// @Route(method=PUT, path="/menus/:id")
 // @Permission(code="sys:menu:edit", name="编辑菜单", modules="菜单管理", desc="编辑菜单")
 func (c *MenuController) UpdateMenu(ctx *gin.Context) {
 	// 获取路径参数id
@@ -263,17 +283,17 @@ func (c *MenuController) UpdateMenu(ctx *gin.Context) {
 					form.ParentID = uint(parentID)
 				}
 			}
-			response.Error(ctx, errors.New("invalid parentId type"))
+			response.Error(ctx, apperr.ErrValidation.WithField("parentId", "类型不正确"))
 			return
 		}
 		logrus.Errorf("Invalid request body: %v", err)
-		response.Error(ctx, errors.New("invalid request body: "+err.Error()))
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 
 	// Validate required fields
-	if form.Name == "" || form.Type == 0 {
-		response.Error(ctx, errors.New("name and type are required"))
+	if err := validateMenuForm(form.Name, form.Type); err != nil {
+		response.Error(ctx, err)
 		return
 	}
 
@@ -298,7 +318,7 @@ func (c *MenuController) UpdateMenu(ctx *gin.Context) {
 
 	if err := c.menuService.UpdateMenu(&menu); err != nil {
 		logrus.Errorf("Failed to update menu: %v", err)
-		response.Error(ctx, errors.New("failed to update menu"))
+		response.Error(ctx, err)
 		return
 	}
 
@@ -308,7 +328,7 @@ func (c *MenuController) UpdateMenu(ctx *gin.Context) {
 }
 
 // DeleteMenu 删除菜单
-// @Route(method=DELETE, path="/menus/:id", middlewares=["jwt"])
+// @Route(method=DELETE, path="/menus/:id")
 // @Permission(code="sys:menu:delete", name="删除菜单", modules="菜单管理", desc="删除菜单")
 func (c *MenuController) DeleteMenu(ctx *gin.Context) {
 
@@ -321,3 +341,45 @@ func (c *MenuController) DeleteMenu(ctx *gin.Context) {
 
 	response.Success(ctx, nil)
 }
+
+// GetMenuI18n 获取菜单标题的翻译
+// @Route(method=GET, path="/menus/:id/i18n")
+// @Permission(code="sys:menu:i18n", name="菜单翻译", modules="菜单管理", desc="查看菜单标题的多语言翻译")
+func (c *MenuController) GetMenuI18n(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	titles, err := c.menuService.GetMenuI18n(uint(id))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, gin.H{
+		"defaultLocale": i18n.DefaultLocale(),
+		"locales":       i18n.Supported(),
+		"titles":        titles,
+	})
+}
+
+// UpdateMenuI18n 保存菜单标题的翻译，请求体为 语言 -> 标题
+// @Route(method=PUT, path="/menus/:id/i18n")
+// @Permission(code="sys:menu:i18n-edit", name="编辑菜单翻译", modules="菜单管理", desc="维护菜单标题的多语言翻译")
+func (c *MenuController) UpdateMenuI18n(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	var titles map[string]string
+	if err := ctx.ShouldBindJSON(&titles); err != nil {
+		response.Error(ctx, apperr.FromBinding(err))
+		return
+	}
+	if err := c.menuService.SaveMenuI18n(uint(id), titles); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, nil)
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/response"
+	"github.com/zmqge/vireo-gin-admin/utils"
+)
+
+// NoticeAttachmentController 通知附件控制器
+// @Group(path="/api/v1/", name="Notices管理")
+type NoticeAttachmentController struct {
+	service services.NoticeAttachmentService
+}
+
+// NewNoticeAttachmentController 创建通知附件控制器
+func NewNoticeAttachmentController(service services.NoticeAttachmentService) *NoticeAttachmentController {
+	return &NoticeAttachmentController{service: service}
+}
+
+// UploadAttachment 上传通知附件或正文图片
+// @Route(method=POST, path="/notices/attachments", middlewares=["jwt"])
+// @Permission(code="sys:notice:attachment-upload",name="上传通知附件",modules="Notices管理", desc="上传通知附件或正文内嵌图片")
+func (c *NoticeAttachmentController) UploadAttachment(ctx *gin.Context) {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		response.Error(ctx, apperr.ErrFileRequired)
+		return
+	}
+	inline, _ := strconv.ParseBool(ctx.PostForm("inline"))
+	entity, err := c.service.Upload(ctx, file, inline)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, entity)
+}
+
+// DownloadAttachment 下载通知附件，凭签名链接访问（<img> 无法携带令牌）
+// @Route(method=GET, path="/notices/attachments/:id/download", middlewares=[])
+func (c *NoticeAttachmentController) DownloadAttachment(ctx *gin.Context) {
+	id, err := utils.ParseUintID(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	expires, _ := strconv.ParseInt(ctx.Query("expires"), 10, 64)
+	entity, file, err := c.service.Open(ctx, id, expires, ctx.Query("sign"))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	disposition := "attachment"
+	if strings.HasPrefix(entity.MimeType, "image/") {
+		disposition = "inline"
+	}
+	ctx.Header("Content-Type", entity.MimeType)
+	ctx.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": entity.FileName}))
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(ctx.Writer, ctx.Request, entity.FileName, entity.CreatedAt, file)
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/response"
+	"github.com/zmqge/vireo-gin-admin/utils"
+)
+
+// NoticeDeliveryController 通知渠道投递控制器
+// @Group(path="/api/v1/", name="Notices管理", middlewares=["jwt"])
+type NoticeDeliveryController struct {
+	service services.NoticeDeliveryService
+}
+
+// NewNoticeDeliveryController 创建通知渠道投递控制器
+func NewNoticeDeliveryController(service services.NoticeDeliveryService) *NoticeDeliveryController {
+	return &NoticeDeliveryController{service: service}
+}
+
+// ListDeliveries 通知的邮件/短信/Webhook 投递记录
+// @Route(method=GET, path="/notices/:id/deliveries", middlewares=["dataperm"])
+// @Permission(code="sys:notice:deliveries",name="通知投递记录",modules="Notices管理", desc="查看通知的邮件、短信、Webhook投递状态")
+func (c *NoticeDeliveryController) ListDeliveries(ctx *gin.Context) {
+	id, err := utils.ParseUintID(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	list, err := c.service.ListDeliveries(ctx, id)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, list)
+}
+
+// GetMyPreference 获取当前用户的通知渠道偏好
+// @Route(method=GET, path="/notices/notify-preference")
+// @Permission(code="sys:notice:preference-view",name="查看通知渠道偏好",modules="Notices管理", desc="查看本人的通知接收渠道")
+func (c *NoticeDeliveryController) GetMyPreference(ctx *gin.Context) {
+	userID, err := utils.ParseUintID(ctx.GetString("userID"))
+	if err != nil {
+		response.BadRequest(ctx, "Invalid userID")
+		return
+	}
+	pref, err := c.service.GetPreference(ctx, userID)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, pref)
+}
+
+// notifyPreferenceRequest 通知渠道偏好请求体
+type notifyPreferenceRequest struct {
+	Email      bool   `json:"email"`
+	SMS        bool   `json:"sms"`
+	Webhook    bool   `json:"webhook"`
+	WebhookURL string `json:"webhookUrl" binding:"max=255"`
+}
+
+// UpdateMyPreference 更新当前用户的通知渠道偏好
+// @Route(method=PUT, path="/notices/notify-preference")
+// @Permission(code="sys:notice:preference-update",name="设置通知渠道偏好",modules="Notices管理", desc="设置本人的通知接收渠道")
+func (c *NoticeDeliveryController) UpdateMyPreference(ctx *gin.Context) {
+	userID, err := utils.ParseUintID(ctx.GetString("userID"))
+	if err != nil {
+		response.BadRequest(ctx, "Invalid userID")
+		return
+	}
+	var req notifyPreferenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, apperr.FromBinding(err))
+		return
+	}
+	pref := &models.UserNotifyPreference{
+		UserID:     userID,
+		Email:      req.Email,
+		SMS:        req.SMS,
+		Webhook:    req.Webhook,
+		WebhookURL: req.WebhookURL,
+	}
+	if err := c.service.SavePreference(ctx, pref); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, pref)
+}
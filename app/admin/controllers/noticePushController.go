@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/config"
+	"github.com/zmqge/vireo-gin-admin/pkg/auth"
+	"github.com/zmqge/vireo-gin-admin/pkg/push"
+	"github.com/zmqge/vireo-gin-admin/pkg/response"
+	"github.com/zmqge/vireo-gin-admin/utils"
+	"golang.org/x/net/websocket"
+)
+
+// pushHeartbeat 心跳间隔，防止代理或浏览器因空闲断开连接
+const pushHeartbeat = 25 * time.Second
+
+// NoticePushController 通知实时推送控制器
+// EventSource、WebSocket 无法设置请求头，先以访问令牌换取一次性票据，再通过 ?ticket= 建立连接
+// @Group(path="/api/v1/", name="Notices管理", middlewares=["jwt(ticket)"])
+type NoticePushController struct {
+	service services.NoticesService
+}
+
+// NewNoticePushController 创建通知推送控制器
+func NewNoticePushController(service services.NoticesService) *NoticePushController {
+	return &NoticePushController{service: service}
+}
+
+// streamTicketResponse 流连接票据
+type streamTicketResponse struct {
+	Ticket    string `json:"ticket"`
+	ExpiresIn int    `json:"expiresIn"` // 有效期（秒）
+}
+
+// StreamTicket 签发建立 SSE/WebSocket 连接用的一次性票据
+// 票据 30 秒内有效，使用一次后作废
+// @Route(method=POST, path="/notices/stream-ticket")
+func (c *NoticePushController) StreamTicket(ctx *gin.Context) {
+	ticket, err := auth.IssueStreamTicket(ctx.Request.Context(), ctx.GetString("userID"))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, streamTicketResponse{Ticket: ticket, ExpiresIn: int(auth.StreamTicketTTL / time.Second)})
+}
+
+// Stream 通过 SSE 推送新通知、未读数和撤回事件
+// 先调用 POST /notices/stream-ticket 取得票据，再通过 ?ticket= 建立连接
+// @Route(method=GET, path="/notices/stream")
+// @Permission(code="sys:notice:stream",name="通知实时推送",modules="Notices管理", desc="通过SSE接收通知实时推送")
+func (c *NoticePushController) Stream(ctx *gin.Context) {
+	userID, err := utils.ParseUintID(ctx.GetString("userID"))
+	if err != nil {
+		response.BadRequest(ctx, "Invalid userID")
+		return
+	}
+
+	client := push.Default.Subscribe(userID)
+	defer push.Default.Unsubscribe(client)
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no") // 关闭 Nginx 缓冲
+
+	// 连接建立后先下发当前未读数
+	if count, err := c.service.CountUnread(ctx, userID); err == nil {
+		ctx.SSEvent(push.EventUnread, gin.H{"count": count})
+		ctx.Writer.Flush()
+	}
+
+	heartbeat := time.NewTicker(pushHeartbeat)
+	defer heartbeat.Stop()
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case msg := <-client.Send:
+			ctx.SSEvent(msg.Type, msg.Data)
+		case <-heartbeat.C:
+			_, _ = io.WriteString(w, ": ping\n\n")
+		}
+		return true
+	})
+}
+
+// WebSocket 通过 WebSocket 推送，消息格式为 {"type": "...", "data": {...}}
+// 先调用 POST /notices/stream-ticket 取得票据，再通过 ?ticket= 建立连接
+// @Route(method=GET, path="/notices/ws")
+// @Permission(code="sys:notice:ws",name="通知实时推送(WebSocket)",modules="Notices管理", desc="通过WebSocket接收通知实时推送")
+func (c *NoticePushController) WebSocket(ctx *gin.Context) {
+	userID, err := utils.ParseUintID(ctx.GetString("userID"))
+	if err != nil {
+		response.BadRequest(ctx, "Invalid userID")
+		return
+	}
+
+	server := websocket.Server{
+		Handshake: checkWebSocketOrigin,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			client := push.Default.Subscribe(userID)
+			defer push.Default.Unsubscribe(client)
+
+			if count, err := c.service.CountUnread(ctx, userID); err == nil {
+				_ = websocket.JSON.Send(ws, gin.H{"type": push.EventUnread, "data": gin.H{"count": count}})
+			}
+
+			// 读循环只用于感知客户端断开
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var discard []byte
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			heartbeat := time.NewTicker(pushHeartbeat)
+			defer heartbeat.Stop()
+			for {
+				select {
+				case <-closed:
+					return
+				case msg := <-client.Send:
+					if err := websocket.JSON.Send(ws, msg); err != nil {
+						return
+					}
+				case <-heartbeat.C:
+					if err := websocket.JSON.Send(ws, gin.H{"type": "ping"}); err != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+	server.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+// checkWebSocketOrigin 校验来源，与 CORS 允许的来源保持一致
+func checkWebSocketOrigin(cfg *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return nil // 非浏览器客户端
+	}
+	for _, allowed := range config.App.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			u, err := url.Parse(origin)
+			if err != nil {
+				return err
+			}
+			cfg.Origin = u
+			return nil
+		}
+	}
+	return websocket.ErrBadWebSocketOrigin
+}
@@ -6,11 +6,12 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/response"
 )
 
 // NoticeReceiverController NoticeReceiver控制器
-// @Group(path="/api/v1/", name="NoticeReceiver管理")
+// @Group(path="/api/v1/", name="NoticeReceiver管理", middlewares=["jwt"])
 type NoticeReceiverController struct {
 	service services.NoticeReceiverService
 }
@@ -21,13 +22,13 @@ func NewNoticeReceiverController(service services.NoticeReceiverService) *Notice
 }
 
 // getNoticeReceiver 获取单个NoticeReceiver
-// @Route(method=GET, path="/noticereceiver/:id", middlewares=["jwt","dataperm"])
+// @Route(method=GET, path="/noticereceiver/:id", middlewares=["dataperm"])
 // @Permission(code="sys:noticereceiver:view", name="NoticeReceiver详情",modules="NoticeReceiver管理", desc="查看NoticeReceiver详情")
 func (c *NoticeReceiverController) GetNoticeReceiverDetails(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -40,7 +41,7 @@ func (c *NoticeReceiverController) GetNoticeReceiverDetails(ctx *gin.Context) {
 }
 
 // listNoticeReceivers 获取NoticeReceiver分页列表
-// @Route(method=GET, path="/noticereceiver/page", middlewares=["jwt","dataperm"])
+// @Route(method=GET, path="/noticereceiver/page", middlewares=["dataperm"])
 // @Permission(code="sys:noticereceiver:query",name="NoticeReceiver列表",modules="NoticeReceiver管理", desc="查看NoticeReceiver列表")
 func (c *NoticeReceiverController) ListNoticeReceivers(ctx *gin.Context) {
 	keywords := ctx.Query("keywords")
@@ -68,12 +69,12 @@ func (c *NoticeReceiverController) ListNoticeReceivers(ctx *gin.Context) {
 }
 
 // createNoticeReceiver 创建NoticeReceiver
-// @Route(method=POST, path="/noticereceiver", middlewares=["jwt"])
+// @Route(method=POST, path="/noticereceiver")
 // @Permission(code="sys:noticereceiver:add",name="新建NoticeReceiver",modules="NoticeReceiver管理", desc="创建NoticeReceiver")
 func (c *NoticeReceiverController) CreateNoticeReceiver(ctx *gin.Context) {
 	var entity models.NoticeReceiverModel
 	if err := ctx.ShouldBindJSON(&entity); err != nil {
-		response.BadRequest(ctx, "Invalid request body")
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	err := c.service.CreateNoticeReceiver(ctx, &entity)
@@ -85,19 +86,19 @@ func (c *NoticeReceiverController) CreateNoticeReceiver(ctx *gin.Context) {
 }
 
 // updateNoticeReceiver 更新NoticeReceiver
-// @Route(method=PUT, path="/noticereceiver/:id", middlewares=["jwt","dataperm"])
+// @Route(method=PUT, path="/noticereceiver/:id", middlewares=["dataperm"])
 // @Permission(code="sys:noticereceiver:update",name="更新NoticeReceiver",modules="NoticeReceiver管理", desc="更新NoticeReceiver")
 func (c *NoticeReceiverController) UpdateNoticeReceiver(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
 	var entity models.NoticeReceiverModel
 	if err := ctx.ShouldBindJSON(&entity); err != nil {
-		response.BadRequest(ctx, "Invalid request body")
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	entity.ID = uint(id)
@@ -110,13 +111,13 @@ func (c *NoticeReceiverController) UpdateNoticeReceiver(ctx *gin.Context) {
 }
 
 // deleteNoticeReceiver 删除NoticeReceiver
-// @Route(method=DELETE, path="/noticereceiver/:id", middlewares=["jwt"])
+// @Route(method=DELETE, path="/noticereceiver/:id")
 // @Permission(code="sys:noticereceiver:delete",name="删除NoticeReceiver",modules="NoticeReceiver管理", desc="删除NoticeReceiver")
 func (c *NoticeReceiverController) DeleteNoticeReceiver(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -129,13 +130,13 @@ func (c *NoticeReceiverController) DeleteNoticeReceiver(ctx *gin.Context) {
 }
 
 // getDict 获取NoticeReceiver表单
-// @Route(method=GET, path="/noticereceiver/:id/form", middlewares=["jwt","dataperm"])
+// @Route(method=GET, path="/noticereceiver/:id/form", middlewares=["dataperm"])
 // @Permission(code="sys:noticereceiver:details",name="NoticeReceiver详情",modules="NoticeReceiver管理", desc="查看NoticeReceiver详情")
 func (c *NoticeReceiverController) GetNoticeReceiverForm(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 	entity, err := c.service.GetNoticeReceiverByID(ctx, uint(id))
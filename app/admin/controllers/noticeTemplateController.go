@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/response"
+	"github.com/zmqge/vireo-gin-admin/utils"
+)
+
+// NoticeTemplateController 通知模板控制器
+// @Group(path="/api/v1/", name="NoticeTemplate管理", middlewares=["jwt"])
+type NoticeTemplateController struct {
+	service services.NoticeTemplateService
+}
+
+// NewNoticeTemplateController 创建通知模板控制器
+func NewNoticeTemplateController(service services.NoticeTemplateService) *NoticeTemplateController {
+	return &NoticeTemplateController{service: service}
+}
+
+// getNoticeTemplate 获取通知模板详情
+// @Route(method=GET, path="/noticetemplate/:id", middlewares=["dataperm"])
+// @Permission(code="sys:noticetemplate:view",name="通知模板详情",modules="NoticeTemplate管理", desc="查看通知模板详情")
+func (c *NoticeTemplateController) GetNoticeTemplateDetails(ctx *gin.Context) {
+	id, err := utils.ParseUintID(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	entity, err := c.service.GetNoticeTemplateByID(ctx, id)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, entity)
+}
+
+// listNoticeTemplates 获取通知模板分页列表
+// @Route(method=GET, path="/noticetemplate/page", middlewares=["dataperm"])
+// @Permission(code="sys:noticetemplate:query",name="通知模板列表",modules="NoticeTemplate管理", desc="查看通知模板列表")
+func (c *NoticeTemplateController) ListNoticeTemplates(ctx *gin.Context) {
+	keywords := ctx.Query("keywords")
+	pageNum, _ := strconv.Atoi(ctx.DefaultQuery("pageNum", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	list, total, err := c.service.PageNoticeTemplates(ctx, keywords, pageNum, pageSize)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	resp := map[string]interface{}{
+		"list":  list,
+		"total": total,
+	}
+	response.Success(ctx, resp)
+}
+
+// createNoticeTemplate 创建通知模板
+// @Route(method=POST, path="/noticetemplate")
+// @Permission(code="sys:noticetemplate:add",name="新建通知模板",modules="NoticeTemplate管理", desc="创建通知模板")
+func (c *NoticeTemplateController) CreateNoticeTemplate(ctx *gin.Context) {
+	var entity models.NoticeTemplateModel
+	if err := ctx.ShouldBindJSON(&entity); err != nil {
+		response.Error(ctx, apperr.FromBinding(err))
+		return
+	}
+	if err := c.service.CreateNoticeTemplate(ctx, &entity); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, entity)
+}
+
+// updateNoticeTemplate 更新通知模板
+// @Route(method=PUT, path="/noticetemplate/:id", middlewares=["dataperm"])
+// @Permission(code="sys:noticetemplate:update",name="更新通知模板",modules="NoticeTemplate管理", desc="更新通知模板")
+func (c *NoticeTemplateController) UpdateNoticeTemplate(ctx *gin.Context) {
+	id, err := utils.ParseUintID(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	var entity models.NoticeTemplateModel
+	if err := ctx.ShouldBindJSON(&entity); err != nil {
+		response.Error(ctx, apperr.FromBinding(err))
+		return
+	}
+	entity.ID = id
+	if err := c.service.UpdateNoticeTemplate(ctx, &entity); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, entity)
+}
+
+// deleteNoticeTemplate 删除通知模板
+// @Route(method=DELETE, path="/noticetemplate/:id")
+// @Permission(code="sys:noticetemplate:delete",name="删除通知模板",modules="NoticeTemplate管理", desc="删除通知模板")
+func (c *NoticeTemplateController) DeleteNoticeTemplate(ctx *gin.Context) {
+	id, err := utils.ParseUintID(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	if err := c.service.DeleteNoticeTemplate(ctx, id); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, nil)
+}
+
+// previewNoticeTemplate 预览模板渲染结果，默认以当前用户渲染，可通过 ?userId= 指定用户
+// @Route(method=POST, path="/noticetemplate/:id/preview", middlewares=["dataperm"])
+// @Permission(code="sys:noticetemplate:preview",name="预览通知模板",modules="NoticeTemplate管理", desc="预览通知模板渲染结果")
+func (c *NoticeTemplateController) PreviewNoticeTemplate(ctx *gin.Context) {
+	id, err := utils.ParseUintID(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	userIDStr := ctx.Query("userId")
+	if userIDStr == "" {
+		userIDStr = ctx.GetString("userID")
+	}
+	userID, err := utils.ParseUintID(userIDStr)
+	if err != nil {
+		response.BadRequest(ctx, "Invalid userId")
+		return
+	}
+	var req models.NoticeTemplatePreviewRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			response.Error(ctx, apperr.FromBinding(err))
+			return
+		}
+	}
+	result, err := c.service.PreviewNoticeTemplate(ctx, id, userID, req.Vars)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, result)
+}
+
+// createNoticeFromTemplate 从模板创建通知草稿
+// @Route(method=POST, path="/notices/from-template")
+// @Permission(code="sys:notice:from-template",name="从模板创建通知",modules="Notices管理", desc="选择通知模板并填写变量创建通知")
+func (c *NoticeTemplateController) CreateNoticeFromTemplate(ctx *gin.Context) {
+	var req models.NoticeFromTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, apperr.FromBinding(err))
+		return
+	}
+	notice, err := c.service.CreateNoticeFromTemplate(ctx, &req)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, notice)
+}
@@ -6,12 +6,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/response"
 	"github.com/zmqge/vireo-gin-admin/utils"
 )
 
 // NoticesController Notices控制器
-// @Group(path="/api/v1/", name="Notices管理")
+// @Group(path="/api/v1/", name="Notices管理", middlewares=["jwt"])
 type NoticesController struct {
 	service services.NoticesService
 }
@@ -22,13 +23,13 @@ func NewNoticesController(service services.NoticesService) *NoticesController {
 }
 
 // getNotices 获取单个Notices
-// @Route(method=GET, path="/notices/:id/detail", middlewares=["jwt","dataperm"])
+// @Route(method=GET, path="/notices/:id/detail", middlewares=["dataperm"])
 // @Permission(code="sys:notice:detail", name="Notices详情",modules="Notices管理", desc="查看Notices详情")
 func (c *NoticesController) GetNoticesDetails(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -41,13 +42,13 @@ func (c *NoticesController) GetNoticesDetails(ctx *gin.Context) {
 }
 
 // getMyNotices
-// @Route(method=GET, path="/notices/:id/my-detail", middlewares=["jwt"])
+// @Route(method=GET, path="/notices/:id/my-detail")
 // @Permission(code="sys:notice:my-detail", name="我的Notices详情",modules="Notices管理", desc="查看我的Notices详情")
 func (c *NoticesController) GetMyNoticesDetails(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 	userIDstr := ctx.GetString("userID")
@@ -62,7 +63,7 @@ func (c *NoticesController) GetMyNoticesDetails(ctx *gin.Context) {
 }
 
 // listNoticess 获取Notices分页列表
-// @Route(method=GET, path="/notices/page", middlewares=["jwt","dataperm"])
+// @Route(method=GET, path="/notices/page", middlewares=["dataperm"])
 // @Permission(code="sys:notice:query",name="Notices列表",modules="Notices管理", desc="查看Notices列表")
 func (c *NoticesController) ListNoticess(ctx *gin.Context) {
 	keywords := ctx.Query("keywords")
@@ -91,12 +92,12 @@ func (c *NoticesController) ListNoticess(ctx *gin.Context) {
 }
 
 // createNotices 创建Notices
-// @Route(method=POST, path="/notices", middlewares=["jwt"])
+// @Route(method=POST, path="/notices")
 // @Permission(code="sys:notice:add",name="新建Notices",modules="Notices管理", desc="创建Notices")
 func (c *NoticesController) CreateNotices(ctx *gin.Context) {
 	var entity models.NoticesModel
 	if err := ctx.ShouldBindJSON(&entity); err != nil {
-		response.BadRequest(ctx, err.Error())
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	err := c.service.CreateNotices(ctx, &entity)
@@ -108,19 +109,19 @@ func (c *NoticesController) CreateNotices(ctx *gin.Context) {
 }
 
 // updateNotices 更新Notices
-// @Route(method=PUT, path="/notices/:id", middlewares=["jwt","dataperm"])
+// @Route(method=PUT, path="/notices/:id", middlewares=["dataperm"])
 // @Permission(code="sys:notice:update",name="更新Notices",modules="Notices管理", desc="更新Notices")
 func (c *NoticesController) UpdateNotices(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
 	var entity models.NoticesModel
 	if err := ctx.ShouldBindJSON(&entity); err != nil {
-		response.BadRequest(ctx, "Invalid request body")
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	entity.ID = uint(id)
@@ -133,13 +134,13 @@ func (c *NoticesController) UpdateNotices(ctx *gin.Context) {
 }
 
 // deleteNotices 删除Notices
-// @Route(method=DELETE, path="/notices/:id", middlewares=["jwt"])
+// @Route(method=DELETE, path="/notices/:id")
 // @Permission(code="sys:notice:delete",name="删除Notices",modules="Notices管理", desc="删除Notices")
 func (c *NoticesController) DeleteNotices(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -152,13 +153,13 @@ func (c *NoticesController) DeleteNotices(ctx *gin.Context) {
 }
 
 // getDict 获取Notices表单
-// @Route(method=GET, path="/notices/:id/form", middlewares=["jwt","dataperm"])
+// @Route(method=GET, path="/notices/:id/form", middlewares=["dataperm"])
 // @Permission(code="sys:notice:form",name="Notices表单",modules="Notices管理", desc="查看Notices详情")
 func (c *NoticesController) GetNoticesForm(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 	entity, err := c.service.GetNoticesByID(ctx, uint(id))
@@ -171,12 +172,12 @@ func (c *NoticesController) GetNoticesForm(ctx *gin.Context) {
 }
 
 // revokeNotice 撤销公告
-// @Route(method=PUT, path="/notices/:id/revoke", middlewares=["jwt"])
+// @Route(method=PUT, path="/notices/:id/revoke")
 // @Permission(code="sys:notice:revoke",name="撤销公告",modules="Notices管理", desc="撤销公告")
 func (c *NoticesController) RevokeNotice(ctx *gin.Context) {
 	id, err := utils.ParseUintID(ctx.Param("id"))
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -189,12 +190,12 @@ func (c *NoticesController) RevokeNotice(ctx *gin.Context) {
 }
 
 // publishNotice 发布公告
-// @Route(method=PUT, path="/notices/:id/publish", middlewares=["jwt"])
+// @Route(method=PUT, path="/notices/:id/publish")
 // @Permission(code="sys:notice:publish",name="发布公告",modules="Notices管理", desc="发布公告")
 func (c *NoticesController) PublishNotice(ctx *gin.Context) {
 	id, err := utils.ParseUintID(ctx.Param("id"))
 	if err != nil {
-		response.BadRequest(ctx, "Invalid ID")
+		response.Error(ctx, apperr.ErrInvalidID)
 		return
 	}
 
@@ -206,8 +207,155 @@ func (c *NoticesController) PublishNotice(ctx *gin.Context) {
 	response.Success(ctx, nil)
 }
 
+// cancelSchedule 取消定时发布
+// @Route(method=PUT, path="/notices/:id/cancel-schedule")
+// @Permission(code="sys:notice:cancel-schedule",name="取消定时发布",modules="Notices管理", desc="取消定时发布，通知回到草稿")
+func (c *NoticesController) CancelSchedule(ctx *gin.Context) {
+	id, err := utils.ParseUintID(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+
+	err = c.service.CancelScheduledNotice(ctx, id)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, nil)
+}
+
+// noticeReviewRequest 审核请求体
+type noticeReviewRequest struct {
+	Comment string `json:"comment"`
+}
+
+// submitNotice 提交审核
+// @Route(method=PUT, path="/notices/:id/submit")
+// @Permission(code="sys:notice:submit",name="提交审核",modules="Notices管理", desc="将草稿提交审核")
+func (c *NoticesController) SubmitNotice(ctx *gin.Context) {
+	c.review(ctx, c.service.SubmitNotice)
+}
+
+// approveNotice 审核通过
+// @Route(method=PUT, path="/notices/:id/approve")
+// @Permission(code="sys:notice:approve",name="审核通过",modules="Notices管理", desc="审核通过待审核的通知")
+func (c *NoticesController) ApproveNotice(ctx *gin.Context) {
+	c.review(ctx, c.service.ApproveNotice)
+}
+
+// rejectNotice 审核驳回
+// @Route(method=PUT, path="/notices/:id/reject")
+// @Permission(code="sys:notice:reject",name="审核驳回",modules="Notices管理", desc="驳回待审核的通知")
+func (c *NoticesController) RejectNotice(ctx *gin.Context) {
+	c.review(ctx, c.service.RejectNotice)
+}
+
+// review 审核类操作的公共处理
+func (c *NoticesController) review(ctx *gin.Context, action func(ctx *gin.Context, id uint, comment string) error) {
+	id, err := utils.ParseUintID(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	var req noticeReviewRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			response.Error(ctx, apperr.FromBinding(err))
+			return
+		}
+	}
+	if err := action(ctx, id, req.Comment); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, nil)
+}
+
+// getNoticeHistory 通知状态流转记录
+// @Route(method=GET, path="/notices/:id/history", middlewares=["dataperm"])
+// @Permission(code="sys:notice:history",name="通知流转记录",modules="Notices管理", desc="查看通知的审核与发布记录")
+func (c *NoticesController) GetNoticeHistory(ctx *gin.Context) {
+	id, err := utils.ParseUintID(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	list, err := c.service.GetNoticeStatusHistory(ctx, id)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, list)
+}
+
+// getNoticeReadStats 通知阅读回执统计
+// @Route(method=GET, path="/notices/:id/read-stats", middlewares=["dataperm"])
+// @Permission(code="sys:notice:read-stats",name="通知阅读统计",modules="Notices管理", desc="查看通知的已读/未读人数、阅读趋势和部门分布")
+func (c *NoticesController) GetNoticeReadStats(ctx *gin.Context) {
+	id, err := utils.ParseUintID(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	stats, err := c.service.GetNoticeReadStats(ctx, id, ctx.Query("granularity"))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, stats)
+}
+
+// listNoticeUnreadUsers 通知未读用户分页列表
+// @Route(method=GET, path="/notices/:id/unread", middlewares=["dataperm"])
+// @Permission(code="sys:notice:unread",name="通知未读用户",modules="Notices管理", desc="查看通知的未读用户列表")
+func (c *NoticesController) ListNoticeUnreadUsers(ctx *gin.Context) {
+	id, err := utils.ParseUintID(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	pageNum, _ := strconv.Atoi(ctx.DefaultQuery("pageNum", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	deptID, _ := strconv.ParseUint(ctx.Query("deptId"), 10, 64)
+
+	list, total, err := c.service.PageNoticeUnreadUsers(ctx, id, uint(deptID), ctx.Query("keywords"), pageNum, pageSize)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, map[string]interface{}{
+		"list":  list,
+		"total": total,
+	})
+}
+
+// remindUnread 再次提醒未读用户
+// @Route(method=POST, path="/notices/:id/remind", middlewares=["dataperm"])
+// @Permission(code="sys:notice:remind",name="提醒未读用户",modules="Notices管理", desc="向未读用户再次发送通知")
+func (c *NoticesController) RemindUnread(ctx *gin.Context) {
+	id, err := utils.ParseUintID(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, apperr.ErrInvalidID)
+		return
+	}
+	count, err := c.service.RemindUnread(ctx, id)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, gin.H{"count": count})
+}
+
 // 获取我的公告列表
-// @Route(method=GET, path="/notices/my-page", middlewares=["jwt","dataperm"])
+// @Route(method=GET, path="/notices/my-page", middlewares=["dataperm"])
+// @Permission(code="sys:notice:mynotice", name="我的公告列表", modules="Notices管理", desc="查看我的列表")
 func (c *NoticesController) GetMyNoticess(ctx *gin.Context) {
 	keywords := ctx.Query("keywords")
 	pageNumStr := ctx.DefaultQuery("pageNum", "1")
@@ -249,7 +397,7 @@ func (c *NoticesController) GetMyNoticess(ctx *gin.Context) {
 }
 
 // MarkAllAsRead 标记全部为已读
-// @Route(method=PUT, path="/notices/my-page/read-all", middlewares=["jwt"])
+// @Route(method=PUT, path="/notices/my-page/read-all")
 // @Permission(code="sys:notice:read-all",name="标记全部为已读",modules="Notices管理", desc="标记全部为已读")
 func (c *NoticesController) MarkAllAsRead(ctx *gin.Context) {
 	userIDStr := ctx.GetString("userID")
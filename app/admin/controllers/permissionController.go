@@ -3,10 +3,11 @@ package controllers
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/config"
 	"github.com/zmqge/vireo-gin-admin/pkg/response"
 )
 
-// @Group(path="/api/v1", name="权限管理", desc="权限相关接口")
+// @Group(path="/api/v1", name="权限管理", desc="权限相关接口", middlewares=["jwt"])
 type PermissionController struct {
 	permissionService *services.PermissionService
 }
@@ -27,17 +28,23 @@ func (c *PermissionController) Tree(ctx *gin.Context) {
 	response.Success(ctx, tree)
 }
 
-// 同步权限（从代码扫描）
+// 同步权限（从代码扫描），代码中已删除的权限软删除
 func (c *PermissionController) Sync(ctx *gin.Context) {
-	if err := c.permissionService.SyncPermissions(); err != nil {
+	perms, _, err := services.ScanPermissions(config.App.ControllerDirs)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	report, err := c.permissionService.SyncPermissions(perms, services.PermissionSyncOptions{Deprecate: true})
+	if err != nil {
 		response.Error(ctx, err)
 		return
 	}
-	response.Success(ctx, nil)
+	response.Success(ctx, report)
 }
 
 // ListPermOptions 获取权限下拉列表
-// @Route(method=GET, path="/perms/options", middlewares=["jwt"])
+// @Route(method=GET, path="/perms/options")
 // @Permission(code="sys:perm:options", name="权限下拉列表", modules="角色管理", desc="获取权限下拉选项")
 func (c *PermissionController) ListPermOptions(ctx *gin.Context) {
 	options, err := c.permissionService.ListPermOptions()
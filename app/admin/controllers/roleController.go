@@ -1,18 +1,18 @@
 package controllers
 
 import (
-	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/response"
 	"gorm.io/gorm"
 )
 
-// @Group(path="/api/v1/", name="角色管理")
+// @Group(path="/api/v1/", name="角色管理", middlewares=["jwt"])
 type RoleController struct {
 	roleService *services.RoleService
 }
@@ -23,7 +23,7 @@ func NewRoleController(db *gorm.DB) *RoleController {
 }
 
 // 创建角色
-// @Route(method="POST", path="/roles", middlewares=["jwt"])
+// @Route(method="POST", path="/roles")
 // @Permission(code="sys:role:add",name="新增角色",modules="角色管理", desc="创建角色")
 func (c *RoleController) Create(ctx *gin.Context) {
 	var input struct {
@@ -35,7 +35,7 @@ func (c *RoleController) Create(ctx *gin.Context) {
 		Permissions []string `json:"permissions"`
 	}
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		response.BadRequest(ctx, err.Error())
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	role := models.Role{
@@ -54,7 +54,7 @@ func (c *RoleController) Create(ctx *gin.Context) {
 }
 
 // 更新角色
-// @Route(method="PUT", path="/roles/:id", middlewares=["jwt"])
+// @Route(method="PUT", path="/roles/:id")
 // @Permission(code="sys:role:edit",name="编辑角色",modules="角色管理", desc="更新角色")
 func (c *RoleController) UpdateRole(ctx *gin.Context) {
 	var input struct {
@@ -66,7 +66,7 @@ func (c *RoleController) UpdateRole(ctx *gin.Context) {
 		Permissions []string `json:"permissions"`
 	}
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		response.BadRequest(ctx, err.Error())
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 
@@ -81,7 +81,7 @@ func (c *RoleController) UpdateRole(ctx *gin.Context) {
 	// 假设角色的 ID 已经在 role 结构体中设置，直接传递 role 指针
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
-		response.BadRequest(ctx, "无效的角色 ID")
+		response.Error(ctx, apperr.ErrRoleInvalidID)
 		return
 	}
 	role.ID = uint(id)
@@ -94,7 +94,7 @@ func (c *RoleController) UpdateRole(ctx *gin.Context) {
 }
 
 // 删除角色
-// @Route(method="DELETE", path="/roles/:id", middlewares=["jwt"])
+// @Route(method="DELETE", path="/roles/:id")
 // @Permission(code="sys:role:delete",name="删除角色",modules="角色管理", desc="删除角色")
 func (c *RoleController) DeleteRole(ctx *gin.Context) {
 	err := c.roleService.DeleteRole(ctx.Param("id"))
@@ -106,7 +106,7 @@ func (c *RoleController) DeleteRole(ctx *gin.Context) {
 }
 
 // 获取角色详情
-// @Route(method="GET", path="/roles/:id/form", middlewares=["jwt"])
+// @Route(method="GET", path="/roles/:id/form")
 // @Permission(code="sys:role:detail",name="角色详情",modules="角色管理", desc="获取角色详情")
 func (c *RoleController) GetRoleDetail(ctx *gin.Context) {
 	role, err := c.roleService.GetRoleDetails(ctx.Param("id"))
@@ -118,7 +118,7 @@ func (c *RoleController) GetRoleDetail(ctx *gin.Context) {
 }
 
 // 获取角色分页列表
-// @Route(method="GET", path="/roles/page", middlewares=["jwt"])
+// @Route(method="GET", path="/roles/page")
 // @Permission(code="sys:role:query",name="角色列表",modules="角色管理", desc="获取角色分页列表")
 func (c *RoleController) List(ctx *gin.Context) {
 	pageNum, _ := strconv.Atoi(ctx.DefaultQuery("pageNum", "1"))
@@ -138,12 +138,12 @@ func (c *RoleController) List(ctx *gin.Context) {
 }
 
 // GetRoleMenus 获取角色菜单
-// @Route(method="GET", path="/roles/:id/menuIds", middlewares=["jwt"])
+// @Route(method="GET", path="/roles/:id/menuIds")
 // @Permission(code="sys:role:menu",name="角色菜单列表",modules="角色管理", desc="获取角色菜单")
 func (c *RoleController) GetRoleMenus(ctx *gin.Context) {
 	roleID := ctx.Param("id")
 	if roleID == "" {
-		response.BadRequest(ctx, "无效的角色 ID")
+		response.Error(ctx, apperr.ErrRoleInvalidID)
 		return
 	}
 	// 由于 c.roleService.GetRoleMenus 可能需要字符串类型参数，将 uint 类型的 roleID 转换为字符串
@@ -156,12 +156,12 @@ func (c *RoleController) GetRoleMenus(ctx *gin.Context) {
 }
 
 // GetRolePerms 获取角色权限 code 列表
-// @Route(method="GET", path="/roles/:id/permCodes", middlewares=["jwt"])
+// @Route(method="GET", path="/roles/:id/permCodes")
 // @Permission(code="sys:role:perm",name="角色权限列表",modules="角色管理", desc="获取角色权限")
 func (c *RoleController) GetRolePerms(ctx *gin.Context) {
 	roleID := ctx.Param("id")
 	if roleID == "" {
-		response.BadRequest(ctx, "无效的角色 ID")
+		response.Error(ctx, apperr.ErrRoleInvalidID)
 		return
 	}
 	permCodes, err := c.roleService.GetRolePerms(roleID)
@@ -173,19 +173,19 @@ func (c *RoleController) GetRolePerms(ctx *gin.Context) {
 }
 
 // UpdateRoleMenus 更新角色菜单
-// @Route(method="PUT", path="/roles/:id/menus", middlewares=["jwt"])
+// @Route(method="PUT", path="/roles/:id/menus")
 // @Permission(code="sys:role:menu:update",name="更新角色菜单",modules="角色管理", desc="更新角色菜单")
 func (c *RoleController) UpdateRoleMenus(ctx *gin.Context) {
 	var input struct {
 		MenuIDs []uint `json:"menuIds"`
 	}
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		response.BadRequest(ctx, err.Error())
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	roleID, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
-		response.BadRequest(ctx, "无效的角色 ID")
+		response.Error(ctx, apperr.ErrRoleInvalidID)
 		return
 	}
 	// 由于 c.roleService.UpdateRoleMenus 可能需要字符串类型参数，将 uint 类型的 roleID 转换为字符串
@@ -199,19 +199,19 @@ func (c *RoleController) UpdateRoleMenus(ctx *gin.Context) {
 }
 
 // UpdateRolePerms 更新角色权限
-// @Route(method="PUT", path="/roles/:id/perms", middlewares=["jwt"])
+// @Route(method="PUT", path="/roles/:id/perms")
 // @Permission(code="sys:role:perm:update",name="更新角色权限",modules="角色管理", desc="更新角色权限")
 func (c *RoleController) UpdateRolePerms(ctx *gin.Context) {
 	var input struct {
 		PermCodes []string `json:"permCodes"`
 	}
 	if err := ctx.ShouldBindJSON(&input); err != nil {
-		response.BadRequest(ctx, err.Error())
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	roleID, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
-		response.BadRequest(ctx, "无效的角色 ID")
+		response.Error(ctx, apperr.ErrRoleInvalidID)
 		return
 	}
 	// 过滤掉 "0"
@@ -230,7 +230,7 @@ func (c *RoleController) UpdateRolePerms(ctx *gin.Context) {
 }
 
 // ListRoleOptions 获取角色下拉列表
-// @Route(method=GET, path="/roles/options", middlewares=["jwt"])
+// @Route(method=GET, path="/roles/options")
 // @Permission(code="sys:role:options",name="角色下拉列表",modules="角色管理", desc="获取角色下拉列表")
 func (c *RoleController) ListRoleOptions(ctx *gin.Context) {
 
@@ -238,7 +238,7 @@ func (c *RoleController) ListRoleOptions(ctx *gin.Context) {
 	options, err := c.roleService.GetRoleOptions()
 	if err != nil {
 		logrus.Errorf("Failed to get role options: %v", err)
-		response.Error(ctx, errors.New("failed to fetch role options"))
+		response.Error(ctx, err)
 		return
 	}
 
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/response"
+	"github.com/zmqge/vireo-gin-admin/pkg/routemeta"
+)
+
+// SystemController 系统信息控制器
+// @Group(path="/api/v1/", name="系统管理", middlewares=["jwt"])
+type SystemController struct {
+	permissionService *services.PermissionService
+}
+
+// NewSystemController 创建系统信息控制器
+func NewSystemController() *SystemController {
+	return &SystemController{permissionService: services.NewPermissionService()}
+}
+
+// GetRoutes 路由清单：每个接口的方法、路径、权限、分组和处理函数，可按权限码或关键字过滤
+// @Summary 路由清单
+// @Route(method=GET, path="/system/routes")
+// @Permission(code="sys:system:routes", name="路由清单", modules="系统管理", desc="查看接口路由与权限的对应关系")
+func (c *SystemController) GetRoutes(ctx *gin.Context) {
+	permission := ctx.Query("permission")
+	keywords := strings.ToLower(ctx.Query("keywords"))
+
+	list := make([]routemeta.Route, 0)
+	for _, r := range routemeta.All() {
+		if permission != "" && r.Permission != permission {
+			continue
+		}
+		if keywords != "" && !strings.Contains(strings.ToLower(r.Path+" "+r.Handler+" "+r.Group), keywords) {
+			continue
+		}
+		list = append(list, r)
+	}
+
+	// 路由引用但权限表中不存在的权限码，这些接口除超级管理员外无人可访问
+	missing, err := c.permissionService.MissingRoutePermissions()
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, gin.H{
+		"list":        list,
+		"permissions": routemeta.Permissions(),
+		"missing":     missing,
+	})
+}
@@ -1,19 +1,23 @@
 package controllers
 
 import (
+	"bytes"
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/database"
 	"github.com/zmqge/vireo-gin-admin/pkg/response"
+	"github.com/zmqge/vireo-gin-admin/pkg/spreadsheet"
 )
 
 // UserController 用户控制器
-// @Group(name="用户管理",path="/api/v1/")
+// @Group(name="用户管理",path="/api/v1/", middlewares=["jwt"])
 type UserController struct {
 	BaseController
 	userService services.UserService
@@ -40,7 +44,8 @@ func NewUserController(userService services.UserService) *UserController {
 	}
 }
 
-// @Route(method=GET, path="users/me", middlewares=["jwt"])
+// @Route(method=GET, path="users/me")
+// @Permission(code="sys:user:me", name="获取当前用户信息", modules="个人中心", desc="获取当前登录用户的详细信息")
 func (c *UserController) Me(ctx *gin.Context) {
 	// 从上下文中获取用户 ID
 	userID := ctx.GetString("userID") // 或者使用 ctx.Get("userID")
@@ -73,7 +78,7 @@ func (c *UserController) Me(ctx *gin.Context) {
 
 }
 
-// @Route(method=DELETE, path="/users/:id", middlewares=["jwt","rbac"])
+// @Route(method=DELETE, path="/users/:id", middlewares=["rbac"])
 // @Permission(code="sys:user:delete",name="删除用户",modules="用户管理", desc="删除用户")
 func (c *UserController) Delete(ctx *gin.Context) {
 	if err := c.userService.Delete(ctx.Param("id")); err != nil {
@@ -83,7 +88,7 @@ func (c *UserController) Delete(ctx *gin.Context) {
 	response.Success(ctx, nil)
 }
 
-// @Route(method=GET, path="users/page",middlewares=["jwt","dataPerm"])
+// @Route(method=GET, path="users/page",middlewares=["dataPerm"])
 // @Permission(code="sys:user:page",name="用户分页列表", desc="获取用户分页列表",modules="用户管理")
 // GetUserPage 获取用户分页列表
 func (c *UserController) GetUserPage(ctx *gin.Context) {
@@ -104,20 +109,20 @@ func (c *UserController) GetUserPage(ctx *gin.Context) {
 	}
 	// 绑定参数并验证必填项
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		response.Error(ctx, fmt.Errorf("缺少必填参数: "+err.Error()))
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 
 	// 转换必填参数为int类型
 	pageNum, err := strconv.Atoi(req.PageNum)
 	if err != nil || pageNum <= 0 {
-		response.Error(ctx, fmt.Errorf("pageNum必须是正整数"))
+		response.Error(ctx, apperr.ErrValidation.WithField("pageNum", "必须是正整数"))
 		return
 	}
 
 	pageSize, err := strconv.Atoi(req.PageSize)
 	if err != nil || pageSize <= 0 {
-		response.Error(ctx, fmt.Errorf("pageSize必须是正整数"))
+		response.Error(ctx, apperr.ErrValidation.WithField("pageSize", "必须是正整数"))
 		return
 	}
 
@@ -210,7 +215,7 @@ func (c *UserController) GetUserPage(ctx *gin.Context) {
 }
 
 // 获取用户信息
-// @Route(method=GET, path="/users/:id/form", middlewares=["jwt"])
+// @Route(method=GET, path="/users/:id/form")
 // @Permission(code="sys:user:info",name="用户信息表单", modules="用户管理", desc="获取用户信息")
 func (c *UserController) GetUser(ctx *gin.Context) {
 	userID := ctx.Param("id") // 从URL参数中获取用户ID
@@ -247,7 +252,7 @@ func (c *UserController) GetUser(ctx *gin.Context) {
 }
 
 // 修改用户
-// @Route(method=PUT, path="/users/:id", middlewares=["jwt"])
+// @Route(method=PUT, path="/users/:id")
 // @Permission(code="sys:user:edit",name="用户编辑", modules="用户管理", desc="更新用户信息")
 func (c *UserController) UpdateUser(ctx *gin.Context) {
 	userID := ctx.Param("id")
@@ -263,7 +268,7 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 		OpenId   string  `json:"openId"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(ctx, err.Error())
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	// 调用 service 层
@@ -276,7 +281,7 @@ func (c *UserController) UpdateUser(ctx *gin.Context) {
 }
 
 // 新增用户
-// @Route(method=POST, path="/users", middlewares=["jwt"])
+// @Route(method=POST, path="/users")
 // @Permission(code="sys:user:add",name="用户新增", modules="用户管理", desc="创建新用户")
 func (c *UserController) CreateUser(ctx *gin.Context) {
 	var req struct {
@@ -290,16 +295,13 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 		DeptID   uint    `json:"deptId"`
 		RoleIds  []int64 `json:"roleIds" binding:"required"`
 		OpenId   string  `json:"openId"`
-		Password string  `json:"password"` // 可选，若有初始密码
+		Password string  `json:"password"` // 可选，为空时使用系统配置的初始密码
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(ctx, err.Error())
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 
-	if req.Password == "" {
-		req.Password = "123456" // 默认初始密码
-	}
 	// 调用 service 层
 	err := c.userService.CreateUserFull(req.Username, req.Nickname, req.Mobile, req.Gender, req.Avatar, req.Email, req.Status, req.DeptID, req.RoleIds, req.OpenId, req.Password)
 	if err != nil {
@@ -311,7 +313,7 @@ func (c *UserController) CreateUser(ctx *gin.Context) {
 }
 
 // 重置用户密码
-// @Route(method=PUT, path="/users/:id/password/reset", middlewares=["jwt"])
+// @Route(method=PUT, path="/users/:id/password/reset")
 // @Permission(code="sys:user:reset-password",name="重置密码",modules="用户管理", desc="重置用户密码")
 func (c *UserController) ResetPassword(ctx *gin.Context) {
 	userID := ctx.Param("id")
@@ -330,7 +332,7 @@ func (c *UserController) ResetPassword(ctx *gin.Context) {
 }
 
 // 获取个人中心用户信息
-// @Route(method=GET, path="/users/profile", middlewares=["jwt"])
+// @Route(method=GET, path="/users/profile")
 // @Permission(code="sys:user:profile", name="个人信息", modules="个人中心", desc="获取当前登录用户的个人中心信息")
 func (c *UserController) GetUserProfile(ctx *gin.Context) {
 	userID := ctx.GetString("userID")
@@ -367,7 +369,7 @@ func (c *UserController) GetUserProfile(ctx *gin.Context) {
 }
 
 // 修改当前用户密码
-// @Route(method=PUT, path="/users/password", middlewares=["jwt"])
+// @Route(method=PUT, path="/users/password")
 // @Permission(code="sys:user:change-password", name="修改密码", modules="个人中心", desc="修改当前登录用户的密码")
 // ChangePassword 修改当前登录用户的密码
 func (c *UserController) ChangePassword(ctx *gin.Context) {
@@ -381,11 +383,11 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 		NewPassword string `json:"newPassword" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(ctx, "参数错误: "+err.Error())
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	if req.OldPassword == req.NewPassword {
-		response.BadRequest(ctx, "新密码不能与原密码相同")
+		response.Error(ctx, apperr.ErrPasswordUnchanged)
 		return
 	}
 	if err := c.userService.ChangePassword(userID, req.OldPassword, req.NewPassword); err != nil {
@@ -396,7 +398,7 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 }
 
 // 修改当前登录用户的个人中心信息
-// @Route(method=PUT, path="/users/profile", middlewares=["jwt"])
+// @Route(method=PUT, path="/users/profile")
 // @Permission(code="sys:user:update-profile", name="修改个人信息", modules="个人中心", desc="修改当前登录用户的个人中心信息")
 func (c *UserController) UpdateMyProfile(ctx *gin.Context) {
 	userID := ctx.GetString("userID")
@@ -414,7 +416,7 @@ func (c *UserController) UpdateMyProfile(ctx *gin.Context) {
 		Email    string `json:"email"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(ctx, "参数错误: "+err.Error())
+		response.Error(ctx, apperr.FromBinding(err))
 		return
 	}
 	// 只允许本人操作自己的信息
@@ -432,7 +434,7 @@ func (c *UserController) UpdateMyProfile(ctx *gin.Context) {
 
 // @Summary 获取用户下拉选项
 // @Tags 用户管理
-// @Route(method=GET, path="/users/options", middlewares=["jwt","dataPerm"])
+// @Route(method=GET, path="/users/options", middlewares=["dataPerm"])
 // @Permission(code="sys:user:options", name="用户下拉选项", modules="用户管理", desc="获取用户下拉选项")
 func (c *UserController) ListUserOptions(ctx *gin.Context) {
 	options, err := c.userService.ListUserOptions(ctx)
@@ -446,3 +448,87 @@ func (c *UserController) ListUserOptions(ctx *gin.Context) {
 	}
 	response.Success(ctx, options)
 }
+
+// 批量导入用户
+// @Route(method=POST, path="/users/import", middlewares=["dataperm"])
+// @Permission(code="sys:user:import", name="导入用户", modules="用户管理", desc="从xlsx/csv文件批量导入用户")
+// ImportUsers 表单参数: file 文件; dryRun=true 仅校验; mode=all 全部成功才提交 / skip 跳过失败行
+// 部门需在当前用户的数据权限范围内，密码需符合密码策略
+func (c *UserController) ImportUsers(ctx *gin.Context) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		response.Error(ctx, apperr.ErrFileRequired)
+		return
+	}
+	format, err := spreadsheet.FormatOf(fileHeader.Filename)
+	if err != nil {
+		response.Error(ctx, apperr.ErrImportFileInvalid.WithArgs(err.Error()).Wrap(err))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	rows, err := spreadsheet.ReadRows(format, file)
+	if err != nil {
+		response.Error(ctx, apperr.ErrImportFileInvalid.WithArgs(err.Error()).Wrap(err))
+		return
+	}
+	importRows, err := services.ParseUserImportRows(rows)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(ctx.DefaultPostForm("dryRun", "false"))
+	opts := models.UserImportOptions{
+		DryRun:    dryRun,
+		Mode:      ctx.DefaultPostForm("mode", models.UserImportModeAll),
+		RequestID: response.RequestID(ctx),
+	}
+	report, err := c.userService.ImportUsers(ctx, importRows, opts)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, report)
+}
+
+// 下载用户导入模板
+// @Route(method=GET, path="/users/import/template")
+// @Permission(code="sys:user:import-template", name="下载导入模板", modules="用户管理", desc="下载用户导入模板，format=xlsx|csv")
+func (c *UserController) DownloadImportTemplate(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", spreadsheet.FormatXLSX)
+	writeSpreadsheet(ctx, format, "用户导入模板", services.UserImportTemplateRows())
+}
+
+// 下载用户导入报告
+// @Route(method=GET, path="/users/import/report/:reportId")
+// @Permission(code="sys:user:import-report", name="下载导入报告", modules="用户管理", desc="下载逐行导入结果，format=xlsx|csv")
+func (c *UserController) DownloadImportReport(ctx *gin.Context) {
+	report, err := c.userService.GetImportReport(ctx, ctx.Param("reportId"))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	format := ctx.DefaultQuery("format", spreadsheet.FormatCSV)
+	writeSpreadsheet(ctx, format, "用户导入报告", services.UserImportReportRows(report))
+}
+
+// writeSpreadsheet 以附件形式输出表格
+func writeSpreadsheet(ctx *gin.Context, format, name string, rows [][]string) {
+	if format != spreadsheet.FormatXLSX && format != spreadsheet.FormatCSV {
+		response.BadRequest(ctx, spreadsheet.ErrUnsupportedFormat.Error())
+		return
+	}
+	var buf bytes.Buffer
+	if err := spreadsheet.WriteRows(format, &buf, name, rows); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	filename := url.PathEscape(name + "." + format)
+	ctx.Header("Content-Disposition", "attachment; filename*=UTF-8''"+filename)
+	ctx.Data(200, spreadsheet.ContentType(format), buf.Bytes())
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/pkg/textdiff"
 	"gorm.io/gorm"
 )
 
@@ -14,7 +15,11 @@ type ConfigModel struct {
 	ID          uint           `json:"id" gorm:"primaryKey;autoIncrement"`
 	ConfigName  string         `json:"configName" gorm:"size:100;comment:配置名称"`
 	ConfigKey   string         `json:"configKey" gorm:"size:100;not null;uniqueIndex;comment:配置键"`
-	ConfigValue string         `json:"configValue" gorm:"size:500;comment:配置值"`
+	ConfigValue string         `json:"configValue" gorm:"type:text;comment:配置值（密文配置加密存储）"`
+	ValueType   string         `json:"valueType" gorm:"size:20;default:string;comment:值类型(string/int/bool/json/enum)"`
+	DictCode    string         `json:"dictCode" gorm:"size:50;comment:enum类型的取值字典编码"`
+	IsSecret    bool           `json:"isSecret" gorm:"default:false;comment:是否密文配置"`
+	Version     int            `json:"version" gorm:"default:1;comment:版本号"`
 	Remark      string         `json:"remark" gorm:"size:500;comment:描述备注"`
 	CreatorID   uint           `json:"creator_id" gorm:"column:creator_id;index;comment:创建人ID"`
 	DeptID      uint           `json:"dept_id" gorm:"column:dept_id;index;comment:部门ID"`
@@ -28,6 +33,44 @@ func (ConfigModel) TableName() string {
 	return "Config" // 返回您想要的表名
 }
 
+// 配置历史动作
+const (
+	ConfigActionCreate   = "create"
+	ConfigActionUpdate   = "update"
+	ConfigActionDelete   = "delete"
+	ConfigActionRollback = "rollback"
+)
+
+// ConfigHistory 配置值的版本历史
+type ConfigHistory struct {
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ConfigID    uint      `json:"configId" gorm:"column:config_id;uniqueIndex:uk_config_version;comment:配置ID"`
+	Version     int       `json:"version" gorm:"uniqueIndex:uk_config_version;comment:版本号"`
+	ConfigKey   string    `json:"configKey" gorm:"size:100;comment:配置键"`
+	ConfigValue string    `json:"configValue" gorm:"type:text;comment:配置值（与配置表相同的存储形式）"`
+	ValueType   string    `json:"valueType" gorm:"size:20;comment:值类型"`
+	DictCode    string    `json:"dictCode" gorm:"size:50;comment:enum类型的取值字典编码"`
+	IsSecret    bool      `json:"isSecret" gorm:"comment:是否密文配置"`
+	Action      string    `json:"action" gorm:"size:20;comment:动作(create/update/delete/rollback)"`
+	Remark      string    `json:"remark" gorm:"size:255;comment:备注"`
+	OperatorID  uint      `json:"operatorId" gorm:"column:operator_id;comment:操作人ID"`
+	Operator    string    `json:"operator" gorm:"->;column:operator"` // 只读，查询时关联用户表
+	CreatedAt   time.Time `json:"createTime"`
+}
+
+// TableName 指定表名
+func (ConfigHistory) TableName() string {
+	return "config_histories"
+}
+
+// ConfigDiff 两个版本之间的差异
+type ConfigDiff struct {
+	FromVersion int             `json:"fromVersion"`
+	ToVersion   int             `json:"toVersion"`
+	Changed     bool            `json:"changed"`
+	Lines       []textdiff.Line `json:"lines"`
+}
+
 // BeforeCreate 钩子函数，在创建前设置创建人ID和部门IDc
 func (c *ConfigModel) BeforeCreate(db *gorm.DB) error {
 	type User struct {
@@ -36,3 +36,18 @@ type DictItemModel struct {
 func (DictItemModel) TableName() string {
 	return "dict_item" // 返回您想要的表名
 }
+
+// DictItemI18n 字典项标签的多语言翻译，按字典编码 + 值关联，导入导出字典后仍然有效
+// 默认语言的标签即 dict_item.label，不在此表保存
+type DictItemI18n struct {
+	ID       uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	DictCode string `json:"dictCode" gorm:"size:50;not null;uniqueIndex:uk_dict_item_locale;comment:字典编码"`
+	Value    string `json:"value" gorm:"size:50;not null;uniqueIndex:uk_dict_item_locale;comment:字典项值"`
+	Locale   string `json:"locale" gorm:"size:10;not null;uniqueIndex:uk_dict_item_locale;comment:语言"`
+	Label    string `json:"label" gorm:"size:50;not null;comment:标签译文"`
+}
+
+// TableName 指定表名
+func (DictItemI18n) TableName() string {
+	return "dict_item_i18n"
+}
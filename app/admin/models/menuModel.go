@@ -40,6 +40,20 @@ type MenuVO struct {
 	Redirect  string    `json:"redirect"`
 	Children  *[]MenuVO `json:"children,omitempty"` // 使用指针和omitempty
 }
+
+// MenuI18n 菜单标题的多语言翻译，默认语言的标题即 menu.title
+type MenuI18n struct {
+	ID     uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	MenuID uint   `json:"menuId" gorm:"column:menu_id;not null;uniqueIndex:uk_menu_locale;comment:菜单ID"`
+	Locale string `json:"locale" gorm:"size:10;not null;uniqueIndex:uk_menu_locale;comment:语言"`
+	Title  string `json:"title" gorm:"size:50;not null;comment:标题译文"`
+}
+
+// TableName 指定表名
+func (MenuI18n) TableName() string {
+	return "menu_i18n"
+}
+
 type MenuPermission struct {
 	MenuID       uint `gorm:"primaryKey"`
 	PermissionID uint `gorm:"primaryKey"`
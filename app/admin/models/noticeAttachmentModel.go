@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// NoticeAttachment 通知附件（含正文中的内嵌图片）
+// 上传后 NoticeID 为 0，保存通知时绑定到通知
+type NoticeAttachment struct {
+	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	NoticeID   uint      `json:"noticeId" gorm:"column:notice_id;index;default:0;comment:通知ID（0为未绑定）"`
+	FileName   string    `json:"fileName" gorm:"column:file_name;size:255;comment:原始文件名"`
+	StorageKey string    `json:"-" gorm:"column:storage_key;size:255;comment:存储路径"`
+	Size       int64     `json:"size" gorm:"comment:文件大小（字节）"`
+	MimeType   string    `json:"mimeType" gorm:"column:mime_type;size:100;comment:文件类型"`
+	Inline     bool      `json:"inline" gorm:"column:inline;default:false;comment:是否为正文内嵌图片"`
+	UploaderID uint      `json:"uploaderId" gorm:"column:uploader_id;index;comment:上传人ID"`
+	CreatedAt  time.Time `json:"createTime"`
+	URL        string    `json:"url" gorm:"-"` // 带签名的下载地址
+}
+
+// TableName 指定表名
+func (NoticeAttachment) TableName() string {
+	return "notice_attachments"
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// 投递状态
+const (
+	DeliveryStatusPending = "pending" // 待发送（含等待重试）
+	DeliveryStatusSending = "sending" // 发送中
+	DeliveryStatusSuccess = "success" // 发送成功
+	DeliveryStatusFailed  = "failed"  // 最终失败（超过最大重试次数或不可重试）
+)
+
+// NoticeDelivery 通知外部渠道投递记录（邮件/短信/Webhook）
+type NoticeDelivery struct {
+	ID          uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	NoticeID    uint       `json:"noticeId" gorm:"column:notice_id;index;comment:通知ID"`
+	UserID      uint       `json:"userId" gorm:"column:user_id;comment:接收人ID"`
+	Nickname    string     `json:"nickname" gorm:"->;column:nickname"` // 只读，查询时关联用户表
+	Channel     string     `json:"channel" gorm:"size:20;comment:渠道(email/sms/webhook)"`
+	Status      string     `json:"status" gorm:"size:20;default:pending;comment:状态"`
+	Attempts    int        `json:"attempts" gorm:"default:0;comment:已尝试次数"`
+	LastError   string     `json:"lastError" gorm:"column:last_error;size:500;comment:最后一次错误"`
+	NextRetryAt time.Time  `json:"nextRetryAt" gorm:"column:next_retry_at;index;comment:下次尝试时间"`
+	SentAt      *time.Time `json:"sentAt" gorm:"column:sent_at;default:NULL;comment:发送成功时间"`
+	Content     *string    `json:"-" gorm:"->;column:receiver_content"` // 只读，接收人的个性化内容（模板渲染）
+	CreatedAt   time.Time  `json:"createTime"`
+	UpdatedAt   time.Time  `json:"updateTime"`
+}
+
+// TableName 指定表名
+func (NoticeDelivery) TableName() string {
+	return "notice_deliveries"
+}
+
+// UserNotifyPreference 用户通知渠道偏好
+type UserNotifyPreference struct {
+	UserID        uint      `json:"userId" gorm:"primaryKey;column:user_id"`
+	Email         bool      `json:"email" gorm:"default:true;comment:接收邮件"`
+	SMS           bool      `json:"sms" gorm:"column:sms;default:true;comment:接收短信"`
+	Webhook       bool      `json:"webhook" gorm:"default:false;comment:接收Webhook"`
+	WebhookURL    string    `json:"webhookUrl" gorm:"column:webhook_url;size:255;comment:自定义Webhook地址"`
+	WebhookSecret string    `json:"webhookSecret" gorm:"column:webhook_secret;size:64;comment:自定义Webhook签名密钥"` // 仅本人可见，用于校验签名
+	UpdatedAt     time.Time `json:"updateTime"`
+}
+
+// TableName 指定表名
+func (UserNotifyPreference) TableName() string {
+	return "user_notify_preferences"
+}
+
+// DefaultNotifyPreference 用户未设置偏好时的默认值：接收邮件和短信，不接收 Webhook
+func DefaultNotifyPreference(userID uint) *UserNotifyPreference {
+	return &UserNotifyPreference{UserID: userID, Email: true, SMS: true}
+}
+
+// Allows 用户是否接收该渠道
+func (p *UserNotifyPreference) Allows(channel string) bool {
+	switch channel {
+	case "email":
+		return p.Email
+	case "sms":
+		return p.SMS
+	case "webhook":
+		return p.Webhook
+	}
+	return false
+}
+
+// NotifyRecipient 投递时需要的接收人信息
+type NotifyRecipient struct {
+	UserID   uint   `json:"userId" gorm:"column:id"`
+	Nickname string `json:"nickname"`
+	Email    string `json:"email"`
+	Mobile   string `json:"mobile"`
+}
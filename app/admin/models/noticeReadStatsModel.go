@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// 阅读趋势统计粒度
+const (
+	ReadStatsGranularityHour = "hour"
+	ReadStatsGranularityDay  = "day"
+)
+
+// NoticeReadStats 通知阅读回执统计
+type NoticeReadStats struct {
+	NoticeID uint                 `json:"noticeId"`
+	Total    int64                `json:"total"`    // 接收人数
+	Read     int64                `json:"read"`     // 已读人数
+	Unread   int64                `json:"unread"`   // 未读人数
+	ReadRate float64              `json:"readRate"` // 已读率（0-100，保留两位小数）
+	Timeline []NoticeReadPoint    `json:"timeline"` // 阅读趋势（累计）
+	Depts    []NoticeDeptReadStat `json:"depts"`    // 按部门统计
+}
+
+// NoticeReadPoint 阅读趋势中的一个时间点
+type NoticeReadPoint struct {
+	Time     string  `json:"time" gorm:"column:time"` // 时间段（按小时 2006-01-02 15:00，按天 2006-01-02）
+	Count    int64   `json:"count" gorm:"column:count"`
+	Total    int64   `json:"total" gorm:"-"`    // 截至该时间段的累计已读人数
+	ReadRate float64 `json:"readRate" gorm:"-"` // 截至该时间段的累计已读率
+}
+
+// NoticeDeptReadStat 部门阅读情况
+type NoticeDeptReadStat struct {
+	DeptID   uint    `json:"deptId" gorm:"column:dept_id"`
+	DeptName string  `json:"deptName" gorm:"column:dept_name"`
+	Total    int64   `json:"total" gorm:"column:total"`
+	Read     int64   `json:"read" gorm:"column:read_count"`
+	Unread   int64   `json:"unread" gorm:"-"`
+	ReadRate float64 `json:"readRate" gorm:"-"`
+}
+
+// NoticeUnreadUser 未读用户
+type NoticeUnreadUser struct {
+	UserID      uint      `json:"userId" gorm:"column:user_id"`
+	Username    string    `json:"username" gorm:"column:username"`
+	Nickname    string    `json:"nickname" gorm:"column:nickname"`
+	DeptID      uint      `json:"deptId" gorm:"column:dept_id"`
+	DeptName    string    `json:"deptName" gorm:"column:dept_name"`
+	ReceiveTime time.Time `json:"receiveTime" gorm:"column:receive_time"`
+}
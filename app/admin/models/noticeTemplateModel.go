@@ -0,0 +1,104 @@
+package models
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// NoticeTemplateModel 通知模板
+type NoticeTemplateModel struct {
+	ID            uint                `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name          string              `json:"name" gorm:"size:100;not null;comment:模板名称"`
+	Title         string              `json:"title" gorm:"size:50;comment:通知标题（仅支持自定义变量）"`
+	Content       string              `json:"content" gorm:"type:text;comment:通知内容模板"`
+	ContentFormat string              `json:"contentFormat" gorm:"column:content_format;size:10;default:html;comment:内容格式(html/markdown)"`
+	Type          string              `json:"type" gorm:"default:0;comment:类型" dict:"notice_type"`
+	Level         string              `json:"level" gorm:"default:0;comment:级别" dict:"notice_level"`
+	Variables     []NoticeTemplateVar `json:"variables" gorm:"serializer:json;type:json;comment:自定义变量定义"`
+	Remark        string              `json:"remark" gorm:"size:500;comment:备注"`
+	CreatorID     uint                `json:"creator_id" gorm:"column:creator_id;index;comment:创建人ID"`
+	DeptID        uint                `json:"dept_id" gorm:"column:dept_id;index;comment:部门ID"`
+	CreatedAt     time.Time           `json:"createTime"`
+	UpdatedAt     time.Time           `json:"updateTime"`
+	DeletedAt     gorm.DeletedAt      `json:"-" gorm:"index"`
+}
+
+// TableName 指定表名
+func (NoticeTemplateModel) TableName() string {
+	return "notice_templates"
+}
+
+// NoticeTemplateVar 模板自定义变量
+type NoticeTemplateVar struct {
+	Name     string `json:"name"`     // 变量名，模板中以 {{.Vars.name}} 引用
+	Label    string `json:"label"`    // 显示名称
+	Default  string `json:"default"`  // 默认值
+	Required bool   `json:"required"` // 是否必填（无默认值时创建通知必须传入）
+}
+
+// NoticeFromTemplateRequest 从模板创建通知
+type NoticeFromTemplateRequest struct {
+	TemplateID  uint              `json:"templateId" binding:"required"`
+	Vars        map[string]string `json:"vars"`
+	TargetType  uint              `json:"targetType" binding:"required"`
+	TargetIDs   []uint            `json:"targetIds"`
+	ScheduledAt *time.Time        `json:"scheduledAt"`
+	ExpiresAt   *time.Time        `json:"expiresAt"`
+}
+
+// NoticeTemplatePreviewRequest 模板预览
+type NoticeTemplatePreviewRequest struct {
+	Vars map[string]string `json:"vars"`
+}
+
+// BeforeCreate 钩子函数，在创建前设置创建人ID和部门ID
+func (c *NoticeTemplateModel) BeforeCreate(db *gorm.DB) error {
+	type User struct {
+		ID     uint `json:"id" gorm:"primaryKey;autoIncrement"`
+		DeptID int  `json:"dept_id" gorm:"column:dept_id;index;comment:部门ID"`
+	}
+
+	ctx, ok := db.Statement.Context.Value("ginContext").(*gin.Context)
+	if !ok {
+		log.Println("[NoticeTemplateModel] 错误：无法获取gin.Context")
+		return nil
+	}
+	var UserID uint
+	if idStr, ok := ctx.Get("userID"); ok {
+		if idStrStr, ok := idStr.(string); ok {
+			parsedID, err := strconv.ParseUint(idStrStr, 10, 64)
+			if err != nil {
+				log.Printf("[NoticeTemplateModel] 转换 userID 为 uint 类型失败: %v", err)
+			}
+			UserID = uint(parsedID)
+		}
+	}
+	c.CreatorID = UserID
+
+	var deptID uint
+	if err := db.Model(User{}).
+		Where("id = ?", UserID).
+		Select("dept_id").
+		First(&deptID).Error; err != nil {
+		log.Printf("[NoticeTemplateModel] 查询部门ID失败: %v", err)
+		return nil
+	}
+	c.DeptID = deptID
+	return nil
+}
+
+// NoticeTemplateRecipient 渲染模板时需要的接收人信息
+type NoticeTemplateRecipient struct {
+	UserID   uint   `gorm:"column:user_id"`
+	Username string `gorm:"column:username"`
+	Nickname string `gorm:"column:nickname"`
+	Email    string `gorm:"column:email"`
+	Mobile   string `gorm:"column:mobile"`
+	DeptID   uint   `gorm:"column:dept_id"`
+	DeptName string `gorm:"column:dept_name"`
+	DeptCode string `gorm:"column:dept_code"`
+}
@@ -11,24 +11,33 @@ import (
 
 // NoticesModel Notices实体
 type NoticesModel struct {
-	ID            uint           `json:"id" gorm:"primaryKey;autoIncrement"`
-	Title         string         `json:"title" gorm:"size:50;comment:Notices名称"`
-	Content       string         `json:"content" gorm:"size:255;comment:Notices内容"`
-	Type          string         `json:"type" gorm:"default:0;comment:类型"`
-	Level         string         `json:"level" gorm:"default:0;comment:级别"`
-	TargetType    uint           `json:"targetType" gorm:"default:0;comment:'1:全体用户 2:指定部门 3:指定角色 4:指定用户'"`
-	TargetIDs     []uint         `json:"targetIds" gorm:"column:target_ids;serializer:json;comment:目标用户ID"` // 改为uint数组
-	Status        int            `json:"publishStatus" gorm:"default:0;comment:状态"`
-	IsRead        int            `json:"isRead" gorm:"default:0;comment:是否已读"`
-	PublisherName string         `json:"publisherName" gorm:"default:NULL;size:50;comment:发布人"`
-	PublishedAt   time.Time      `json:"publishTime" gorm:"default:NULL;comment:发布时间"`
-	RevokedAt     time.Time      `json:"revokeTime" gorm:"default:NULL;comment:撤回时间"`
-	CreatorID     uint           `json:"creator_id" gorm:"column:creator_id;index;comment:创建人ID"`
-	DeptID        uint           `json:"dept_id" gorm:"column:dept_id;index;comment:部门ID"`
-	CreatedAt     time.Time      `json:"createTime" gorm:"comment:创建时间"`
-	UpdatedAt     time.Time      `json:"-"`
-	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
-	Receivers     []User         `gorm:"many2many:notice_receiver;joinForeignKey:notice_id;joinReferences:user_id"`
+	ID              uint               `json:"id" gorm:"primaryKey;autoIncrement"`
+	Title           string             `json:"title" gorm:"size:50;comment:Notices名称"`
+	Content         string             `json:"content" gorm:"type:longtext;comment:Notices内容（HTML 或 Markdown 原文）"`
+	ContentFormat   string             `json:"contentFormat" gorm:"column:content_format;size:10;default:html;comment:内容格式(html/markdown)"`
+	ContentHTML     string             `json:"contentHtml" gorm:"column:content_html;type:longtext;comment:过滤后的HTML内容"`
+	AttachmentIDs   []uint             `json:"attachmentIds" gorm:"-"` // 保存时提交的附件ID
+	Attachments     []NoticeAttachment `json:"attachments" gorm:"-"`   // 附件（查询详情时填充）
+	Type            string             `json:"type" gorm:"default:0;comment:类型" dict:"notice_type"`
+	Level           string             `json:"level" gorm:"default:0;comment:级别" dict:"notice_level"`
+	TargetType      uint               `json:"targetType" gorm:"default:0;comment:'1:全体用户 2:指定部门 3:指定角色 4:指定用户'"`
+	TargetIDs       []uint             `json:"targetIds" gorm:"column:target_ids;serializer:json;comment:目标用户ID"` // 改为uint数组
+	Status          int                `json:"publishStatus" gorm:"default:0;comment:状态"`
+	IsRead          int                `json:"isRead" gorm:"default:0;comment:是否已读"`
+	PublisherName   string             `json:"publisherName" gorm:"default:NULL;size:50;comment:发布人"`
+	PublishedAt     time.Time          `json:"publishTime" gorm:"default:NULL;comment:发布时间"`
+	RevokedAt       time.Time          `json:"revokeTime" gorm:"default:NULL;comment:撤回时间"`
+	ScheduledAt     *time.Time         `json:"scheduledAt" gorm:"column:scheduled_at;index;default:NULL;comment:定时发布时间"`
+	ExpiresAt       *time.Time         `json:"expiresAt" gorm:"column:expires_at;index;default:NULL;comment:过期时间"`
+	TemplateID      *uint              `json:"templateId" gorm:"column:template_id;default:NULL;comment:来源模板ID"`
+	TemplateVars    map[string]string  `json:"templateVars" gorm:"column:template_vars;serializer:json;comment:模板自定义变量"`
+	ReceiverContent *string            `json:"-" gorm:"->;column:receiver_content"` // 只读，接收人的个性化内容
+	CreatorID       uint               `json:"creator_id" gorm:"column:creator_id;index;comment:创建人ID"`
+	DeptID          uint               `json:"dept_id" gorm:"column:dept_id;index;comment:部门ID"`
+	CreatedAt       time.Time          `json:"createTime" gorm:"comment:创建时间"`
+	UpdatedAt       time.Time          `json:"-"`
+	DeletedAt       gorm.DeletedAt     `json:"-" gorm:"index"`
+	Receivers       []User             `gorm:"many2many:notice_receiver;joinForeignKey:notice_id;joinReferences:user_id"`
 }
 
 // TableName 指定表名
@@ -36,6 +45,24 @@ func (NoticesModel) TableName() string {
 	return "notices" // 返回您想要的表名
 }
 
+// HTML 过滤后的 HTML 内容，兼容未生成 content_html 的历史数据
+func (n *NoticesModel) HTML() string {
+	if n.ContentHTML != "" {
+		return n.ContentHTML
+	}
+	return n.Content
+}
+
+// UseReceiverContent 接收人查看时返回 HTML 内容，有个性化内容（模板渲染）时优先使用
+func (n *NoticesModel) UseReceiverContent() {
+	if n.ReceiverContent != nil {
+		n.Content = *n.ReceiverContent
+	} else {
+		n.Content = n.HTML()
+	}
+	n.ContentFormat = "html"
+}
+
 // // NoticeReceiverDTO 接收者数据传输对象
 // type NoticeReceiverDTO struct {
 // 	UserID uint `json:"user_id" binding:"required"`
@@ -47,6 +74,7 @@ type NoticeReceiver struct {
 	UserID    uint      `gorm:"primaryKey"`
 	IsRead    uint      `gorm:"default:0"`
 	ReadTime  time.Time `gorm:"default:NULL"`
+	Content   *string   `gorm:"type:text;default:NULL"` // 按模板为该接收人渲染的内容，为空时使用通知内容
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 }
 
@@ -58,11 +86,45 @@ func (NoticeReceiver) TableName() string {
 const (
 	NoticeStatusDraft     = 0 // 草稿
 	NoticeStatusPublished = 1 // 已发布
-	NoticeStatusRevoked   = 3 // 已撤回
+	NoticeStatusRevoked   = 2 // 已撤回（与表结构注释及历史数据保持一致）
 	NoticeStatusPending   = 4 // 待审核
+	NoticeStatusScheduled = 5 // 定时待发布
+	NoticeStatusExpired   = 6 // 已过期
+	NoticeStatusApproved  = 7 // 审核通过（待发布）
+	NoticeStatusRejected  = 8 // 审核驳回
+)
 
+// 通知状态流转动作
+const (
+	NoticeActionSubmit         = "submit"          // 提交审核
+	NoticeActionApprove        = "approve"         // 审核通过
+	NoticeActionReject         = "reject"          // 审核驳回
+	NoticeActionPublish        = "publish"         // 发布
+	NoticeActionSchedule       = "schedule"        // 设置定时发布
+	NoticeActionCancelSchedule = "cancel_schedule" // 取消定时发布
+	NoticeActionRevoke         = "revoke"          // 撤回
+	NoticeActionExpire         = "expire"          // 过期下线
+	NoticeActionEdit           = "edit"            // 编辑（审核通过后编辑需重新审核）
 )
 
+// NoticeStatusHistory 通知状态流转记录
+type NoticeStatusHistory struct {
+	ID           uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	NoticeID     uint      `json:"noticeId" gorm:"column:notice_id;index;comment:通知ID"`
+	FromStatus   int       `json:"fromStatus" gorm:"column:from_status;comment:原状态"`
+	ToStatus     int       `json:"toStatus" gorm:"column:to_status;comment:新状态"`
+	Action       string    `json:"action" gorm:"size:20;comment:动作"`
+	Comment      string    `json:"comment" gorm:"size:500;comment:审核意见/备注"`
+	OperatorID   uint      `json:"operatorId" gorm:"column:operator_id;comment:操作人ID（0为系统任务）"`
+	OperatorName string    `json:"operatorName" gorm:"->;column:operator_name"` // 只读，查询时关联用户表
+	CreatedAt    time.Time `json:"createTime" gorm:"comment:操作时间"`
+}
+
+// TableName 指定表名
+func (NoticeStatusHistory) TableName() string {
+	return "notice_status_history"
+}
+
 // BeforeCreate 钩子函数，在创建前设置创建人ID和部门IDc
 func (c *NoticesModel) BeforeCreate(db *gorm.DB) error {
 	type User struct {
@@ -2,20 +2,26 @@
 package models
 
 import (
+	"strings"
+	"time"
+
 	"github.com/zmqge/vireo-gin-admin/pkg/database"
 	"gorm.io/gorm"
 )
 
 // Permission 权限模型
 type Permission struct {
-	ID          uint   `json:"id" gorm:"primaryKey"`        // 权限 ID
-	Code        string `json:"code" gorm:"size:255"`        // 权限代码
-	Name        string `json:"name" gorm:"size:255"`        // 权限名称
-	Description string `json:"description" gorm:"size:255"` // 权限描述
-	Type        string `json:"type" gorm:"size:50"`         // 权限类型（如 menu）
-	Icon        string `json:"icon" gorm:"size:50"`         // 图标
-	Module      string `json:"module" gorm:"size:50"`       // 模块（如 admin, user 等）
-	ParentID    *uint  `json:"parent_id" gorm:"index"`      // 父权限 ID
+	ID          uint           `json:"id" gorm:"primaryKey"`        // 权限 ID
+	Code        string         `json:"code" gorm:"size:255"`        // 权限代码
+	Name        string         `json:"name" gorm:"size:255"`        // 权限名称
+	Description string         `json:"description" gorm:"size:255"` // 权限描述
+	Type        string         `json:"type" gorm:"size:50"`         // 权限类型（如 menu）
+	Icon        string         `json:"icon" gorm:"size:50"`         // 图标
+	Module      string         `json:"module" gorm:"size:50"`       // 模块（如 admin, user 等）
+	ParentID    *uint          `json:"parent_id" gorm:"index"`      // 上级节点 ID：接口权限指向资源节点，资源节点指向模块节点
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"` // 代码中已删除的权限由 permgen -deprecate 软删除，保留角色授权
 }
 
 type RolePermission struct {
@@ -31,20 +37,48 @@ type UserPermission struct {
 	PermissionID uint `gorm:"not null"`
 }
 
-func BuildPermissionTree(parentID uint) []map[string]interface{} {
-	var permissions []Permission
-	database.DB.Where("parent_id = ?", parentID).Find(&permissions)
+// 权限节点类型：接口权限来自 @Permission 注解，模块和资源节点由 permgen 按模块名称和权限码生成
+const (
+	PermissionTypeAPI      = "api"
+	PermissionTypeModule   = "module"
+	PermissionTypeResource = "resource"
+)
+
+// PermissionGroupTypes 权限树中的分组节点类型，分组节点没有权限码
+var PermissionGroupTypes = []string{PermissionTypeModule, PermissionTypeResource}
+
+// OnlyPermissionCodes 只查询可授权的权限，排除模块、资源节点
+func OnlyPermissionCodes(db *gorm.DB) *gorm.DB {
+	return db.Where("type IS NULL OR type NOT IN ?", PermissionGroupTypes)
+}
 
-	tree := make([]map[string]interface{}, 0)
-	for _, p := range permissions {
-		node := map[string]interface{}{
-			"code":     p.Code,
-			"name":     p.Name,
-			"children": BuildPermissionTree(p.ID), // 递归构建子树
+// PermissionResource 权限码中的资源部分，即去掉最后一段操作：sys:user:edit -> sys:user，没有冒号时为空
+func PermissionResource(code string) string {
+	if i := strings.LastIndex(code, ":"); i > 0 {
+		return code[:i]
+	}
+	return ""
+}
+
+// BuildPermissionTree 按 parent_id 将一次查询得到的权限节点组装为树，同级节点保持 list 中的顺序
+// 上级不存在（如尚未执行 permgen 生成模块、资源节点）的节点作为根节点
+func BuildPermissionTree(list []Permission) []*PermissionNode {
+	nodes := make(map[uint]*PermissionNode, len(list))
+	for _, p := range list {
+		nodes[p.ID] = &PermissionNode{ID: p.ID, Code: p.Code, Name: p.Name, Type: p.Type}
+	}
+	roots := make([]*PermissionNode, 0)
+	for _, p := range list {
+		node := nodes[p.ID]
+		if p.ParentID != nil {
+			if parent, ok := nodes[*p.ParentID]; ok && parent != node {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
 		}
-		tree = append(tree, node)
+		roots = append(roots, node)
 	}
-	return tree
+	return roots
 }
 
 func CheckUserPermission(userID uint, permissionCode string) bool {
@@ -73,10 +107,13 @@ func GetUserPermissionsFromDB(userID uint) []string {
 	return permissions
 }
 
+// PermissionNode 权限树节点，模块、资源节点没有 code
 type PermissionNode struct {
-	ID       uint             `json:"id"`
-	Name     string           `json:"name"`
-	Children []PermissionNode `json:"children,omitempty"`
+	ID       uint              `json:"id"`
+	Code     string            `json:"code,omitempty"`
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Children []*PermissionNode `json:"children,omitempty"`
 }
 
 type OptionPermLong struct {
@@ -0,0 +1,76 @@
+package models
+
+// 导入模式
+const (
+	UserImportModeAll  = "all"  // 全部成功才提交，任一行失败则整体回滚
+	UserImportModeSkip = "skip" // 跳过失败行，其余行正常导入
+)
+
+// 导入行状态
+const (
+	UserImportRowOK      = "ok"      // 校验通过（预检查）
+	UserImportRowCreated = "created" // 已创建
+	UserImportRowFailed  = "failed"  // 校验失败
+	UserImportRowSkipped = "skipped" // 因整体回滚未导入
+)
+
+// UserImportColumn 导入模板列定义
+type UserImportColumn struct {
+	Key      string // 字段标识，表头也可直接使用
+	Title    string // 模板表头
+	Required bool   // 是否必填
+	Example  string // 模板示例值
+}
+
+// UserImportColumns 用户导入模板列（顺序即模板列顺序）
+var UserImportColumns = []UserImportColumn{
+	{Key: "username", Title: "用户名", Required: true, Example: "zhangsan"},
+	{Key: "nickname", Title: "昵称", Required: true, Example: "张三"},
+	{Key: "gender", Title: "性别", Example: "男"},
+	{Key: "mobile", Title: "手机号", Example: "13800000000"},
+	{Key: "email", Title: "邮箱", Example: "zhangsan@example.com"},
+	{Key: "deptCode", Title: "部门编码", Example: "RD001"},
+	{Key: "roleCodes", Title: "角色编码", Example: "admin,guest"},
+	{Key: "status", Title: "状态", Example: "1"},
+	{Key: "password", Title: "初始密码", Example: ""},
+}
+
+// UserImportRow 导入文件中的一行数据
+type UserImportRow struct {
+	RowNum    int      `json:"rowNum"` // 在文件中的行号（含表头，从1开始）
+	Username  string   `json:"username"`
+	Nickname  string   `json:"nickname"`
+	Gender    string   `json:"gender"`
+	Mobile    string   `json:"mobile"`
+	Email     string   `json:"email"`
+	DeptCode  string   `json:"deptCode"`
+	RoleCodes []string `json:"roleCodes"`
+	Status    string   `json:"status"`
+	Password  string   `json:"-"`
+}
+
+// UserImportOptions 导入选项
+type UserImportOptions struct {
+	DryRun    bool   // 仅校验不写库
+	Mode      string // all | skip
+	RequestID string // 请求ID，写入失败时记录日志，报告中只给出请求ID
+}
+
+// UserImportRowResult 单行导入结果
+type UserImportRowResult struct {
+	RowNum   int      `json:"rowNum"`
+	Username string   `json:"username"`
+	Status   string   `json:"status"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// UserImportReport 导入报告
+type UserImportReport struct {
+	ReportID string                `json:"reportId"`
+	DryRun   bool                  `json:"dryRun"`
+	Mode     string                `json:"mode"`
+	Total    int                   `json:"total"`
+	Success  int                   `json:"success"`
+	Failed   int                   `json:"failed"`
+	Rows     []UserImportRowResult `json:"rows"`
+}
@@ -19,7 +19,7 @@ type User struct {
 	Avatar   string `json:"avatar"`
 	Nickname string `json:"nickname"`
 	Mobile   string `json:"mobile"`
-	Gender   string `json:"gender"`
+	Gender   string `json:"gender" dict:"gender"`
 	Email    string `json:"email"`
 	OpenId   string `json:"open_id"` // 微信小程序或公众号的 OpenId
 
@@ -49,7 +49,7 @@ type UserPageVO struct {
 	Username   string `json:"username"`
 	Nickname   string `json:"nickname"`
 	Mobile     string `json:"mobile"`
-	Gender     string `json:"gender"`
+	Gender     string `json:"gender" dict:"gender"`
 	Avatar     string `json:"avatar"`
 	Email      string `json:"email"`
 	Status     int    `json:"status"`
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/scopes"
 	"gorm.io/gorm"
 )
@@ -18,6 +19,12 @@ type ConfigRepository interface {
 	UpdateConfig(ctx *gin.Context, entity *models.ConfigModel) error
 	DeleteConfig(ctx *gin.Context, id uint) error // 使用uint类型
 	PageConfigs(ctx *gin.Context, keywords string, pageNum, pageSize int) ([]*models.ConfigModel, int64, error)
+	ListAllConfigs(ctx context.Context) ([]models.ConfigModel, error)
+	CreateConfigWithHistory(ctx *gin.Context, entity *models.ConfigModel, history *models.ConfigHistory) error
+	UpdateConfigWithHistory(ctx *gin.Context, entity *models.ConfigModel, history *models.ConfigHistory) error
+	DeleteConfigWithHistory(ctx *gin.Context, id uint, history *models.ConfigHistory) error
+	ListConfigHistory(ctx *gin.Context, configID uint) ([]*models.ConfigHistory, error)
+	GetConfigHistory(ctx *gin.Context, configID uint, version int) (*models.ConfigHistory, error)
 }
 
 // ConfigRepositoryImpl Config数据访问实现
@@ -67,7 +74,7 @@ func (r *ConfigRepositoryImpl) UpdateConfig(ctx *gin.Context, entity *models.Con
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("Config not found")
+		return apperr.ErrConfigNotFound
 	}
 	return nil
 }
@@ -80,7 +87,7 @@ func (r *ConfigRepositoryImpl) DeleteConfig(ctx *gin.Context, id uint) error {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("Config not found")
+		return apperr.ErrConfigNotFound
 	}
 	return nil
 }
@@ -105,3 +112,83 @@ func (r *ConfigRepositoryImpl) PageConfigs(ctx *gin.Context, keywords string, pa
 	}
 	return entities, total, nil
 }
+
+// ListAllConfigs 加载全部配置（系统配置热加载使用，不受数据权限限制）
+func (r *ConfigRepositoryImpl) ListAllConfigs(ctx context.Context) ([]models.ConfigModel, error) {
+	var entities []models.ConfigModel
+	err := r.db.WithContext(ctx).
+		Select("config_key", "config_value", "is_secret").
+		Find(&entities).Error
+	return entities, err
+}
+
+// CreateConfigWithHistory 创建配置并记录首个版本
+func (r *ConfigRepositoryImpl) CreateConfigWithHistory(ctx *gin.Context, entity *models.ConfigModel, history *models.ConfigHistory) error {
+	return r.db.WithContext(context.WithValue(ctx.Request.Context(), "ginContext", ctx)).
+		Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(entity).Error; err != nil {
+				return err
+			}
+			history.ConfigID = entity.ID
+			return tx.Create(history).Error
+		})
+}
+
+// UpdateConfigWithHistory 更新配置，history 不为空时记录新版本
+func (r *ConfigRepositoryImpl) UpdateConfigWithHistory(ctx *gin.Context, entity *models.ConfigModel, history *models.ConfigHistory) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Scopes(scopes.DataPermissionScope(ctx)).Save(entity)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperr.ErrConfigNotFound
+		}
+		if history == nil {
+			return nil
+		}
+		return tx.Create(history).Error
+	})
+}
+
+// DeleteConfigWithHistory 删除配置并记录删除前的版本
+func (r *ConfigRepositoryImpl) DeleteConfigWithHistory(ctx *gin.Context, id uint, history *models.ConfigHistory) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Scopes(scopes.DataPermissionScope(ctx)).
+			Delete(&models.ConfigModel{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return apperr.ErrConfigNotFound
+		}
+		return tx.Create(history).Error
+	})
+}
+
+// ListConfigHistory 配置的版本历史，最新版本在前
+func (r *ConfigRepositoryImpl) ListConfigHistory(ctx *gin.Context, configID uint) ([]*models.ConfigHistory, error) {
+	var list []*models.ConfigHistory
+	err := r.db.WithContext(ctx).
+		Table("config_histories h").
+		Select("h.*, u.nickname AS operator").
+		Joins("LEFT JOIN users u ON u.id = h.operator_id").
+		Where("h.config_id = ?", configID).
+		Order("h.version DESC").
+		Find(&list).Error
+	return list, err
+}
+
+// GetConfigHistory 获取配置的指定版本
+func (r *ConfigRepositoryImpl) GetConfigHistory(ctx *gin.Context, configID uint, version int) (*models.ConfigHistory, error) {
+	var history models.ConfigHistory
+	if err := r.db.WithContext(ctx).
+		Where("config_id = ? AND version = ?", configID, version).
+		First(&history).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &history, nil
+}
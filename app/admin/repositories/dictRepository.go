@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"gorm.io/gorm"
 )
 
@@ -21,6 +22,11 @@ type DictRepository interface {
 	CreateDictItem(item *models.DictItemModel) error
 	UpdateDictItem(item *models.DictItemModel) error
 	DeleteDictItem(id uint) error // 使用uint类型
+	ListDictsByCodes(codes []string) ([]*models.DictModel, error)
+	ListDictItemsByCodes(codes []string) ([]*models.DictItemModel, error)
+	SaveDictsAndItems(dicts []*models.DictModel, items []*models.DictItemModel) error
+	ListDictItemI18n(dictCode string) ([]*models.DictItemI18n, error)
+	ReplaceDictItemI18n(dictCode string, list []*models.DictItemI18n) error
 }
 
 // DictRepositoryImpl Dict数据访问实现
@@ -66,7 +72,7 @@ func (r *DictRepositoryImpl) UpdateDict(entity *models.DictModel) error {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("Dict not found")
+		return apperr.ErrDictNotFound
 	}
 	return nil
 }
@@ -78,7 +84,7 @@ func (r *DictRepositoryImpl) DeleteDict(id uint) error {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("Dict not found")
+		return apperr.ErrDictNotFound
 	}
 	return nil
 }
@@ -150,14 +156,14 @@ func (r *DictRepositoryImpl) CreateDictItem(item *models.DictItemModel) error {
 // UpdateDictItem 更新字典项
 func (r *DictRepositoryImpl) UpdateDictItem(item *models.DictItemModel) error {
 	if item.ID == 0 {
-		return errors.New("ID is required")
+		return apperr.ErrInvalidID
 	}
 	result := r.db.Save(item)
 	if result.Error != nil {
 		return result.Error
 	}
 	// if result.RowsAffected == 0 {
-	// 	return errors.New("DictItem not found")
+	// 	return apperr.ErrDictItemNotFound
 	// }
 	return nil
 }
@@ -169,7 +175,82 @@ func (r *DictRepositoryImpl) DeleteDictItem(id uint) error {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("DictItem not found")
+		return apperr.ErrDictItemNotFound
 	}
 	return nil
 }
+
+// ListDictsByCodes 根据编码查询字典，codes 为空时返回全部
+func (r *DictRepositoryImpl) ListDictsByCodes(codes []string) ([]*models.DictModel, error) {
+	var list []*models.DictModel
+	query := r.db.Order("sort asc, id asc")
+	if len(codes) > 0 {
+		query = query.Where("dict_code IN ?", codes)
+	}
+	err := query.Find(&list).Error
+	return list, err
+}
+
+// ListDictItemsByCodes 根据字典编码查询字典项
+func (r *DictRepositoryImpl) ListDictItemsByCodes(codes []string) ([]*models.DictItemModel, error) {
+	var list []*models.DictItemModel
+	if len(codes) == 0 {
+		return list, nil
+	}
+	err := r.db.Where("dict_code IN ?", codes).
+		Order("sort asc, id asc").
+		Find(&list).Error
+	return list, err
+}
+
+// SaveDictsAndItems 在一个事务中保存字典和字典项（ID 为 0 时新增，否则更新）
+// 新增时 status 为 0 会被字段默认值覆盖，因此单独更新一次
+func (r *DictRepositoryImpl) SaveDictsAndItems(dicts []*models.DictModel, items []*models.DictItemModel) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, d := range dicts {
+			created := d.ID == 0
+			if err := tx.Save(d).Error; err != nil {
+				return err
+			}
+			if created && d.Status == 0 {
+				if err := tx.Model(d).Update("status", 0).Error; err != nil {
+					return err
+				}
+			}
+		}
+		for _, item := range items {
+			created := item.ID == 0
+			if err := tx.Save(item).Error; err != nil {
+				return err
+			}
+			if created && item.Status == 0 {
+				if err := tx.Model(item).Update("status", 0).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// ListDictItemI18n 查询字典编码下全部字典项的翻译
+func (r *DictRepositoryImpl) ListDictItemI18n(dictCode string) ([]*models.DictItemI18n, error) {
+	var list []*models.DictItemI18n
+	err := r.db.Where("dict_code = ?", dictCode).
+		Order("value asc, locale asc").
+		Find(&list).Error
+	return list, err
+}
+
+// ReplaceDictItemI18n 在一个事务中替换字典编码下的全部翻译
+func (r *DictRepositoryImpl) ReplaceDictItemI18n(dictCode string, list []*models.DictItemI18n) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("dict_code = ?", dictCode).Delete(&models.DictItemI18n{}).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		return tx.Create(&list).Error
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"gorm.io/gorm"
 )
 
@@ -65,30 +66,33 @@ func (r *MenuRepository) DeleteMenu(id string) error {
 	var menu models.Menu
 	if err := r.db.Table("menu").Where("id = ?", id).First(&menu).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("菜单不存在或已被删除")
+			return apperr.ErrMenuNotFound
 		}
-		return fmt.Errorf("查询菜单失败: %v", err)
+		return fmt.Errorf("查询菜单失败: %w", err)
 	}
 	var childCount int64
 	if err := r.db.Table("menu").Where("parent_id = ?", id).Count(&childCount).Error; err != nil {
-		return fmt.Errorf("检查子菜单失败: %v", err)
+		return fmt.Errorf("检查子菜单失败: %w", err)
 	}
 	if childCount > 0 {
-		return fmt.Errorf("无法删除菜单，仍有 %d 个子菜单存在", childCount)
+		return apperr.ErrMenuHasChildren.WithArgs(childCount)
 	}
 	var roleCount int64
 	if err := r.db.Table("role_menu").Where("menu_id = ?", id).Count(&roleCount).Error; err != nil {
-		return fmt.Errorf("检查菜单关联角色失败: %v", err)
+		return fmt.Errorf("检查菜单关联角色失败: %w", err)
 	}
 	if roleCount > 0 {
-		return fmt.Errorf("无法删除菜单，仍有 %d 个角色关联此菜单", roleCount)
+		return apperr.ErrMenuInUse.WithArgs(roleCount)
 	}
 	result := r.db.Table("menu").Where("id = ?", id).Delete(&models.Menu{})
 	if result.Error != nil {
-		return fmt.Errorf("删除菜单失败: %v", result.Error)
+		return fmt.Errorf("删除菜单失败: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("菜单不存在或已被删除")
+		return apperr.ErrMenuNotFound
+	}
+	if err := r.db.Where("menu_id = ?", id).Delete(&models.MenuI18n{}).Error; err != nil {
+		return fmt.Errorf("删除菜单翻译失败: %w", err)
 	}
 	return nil
 }
@@ -125,3 +129,38 @@ func (r *MenuRepository) ListMenusByRoleIDs(roleIDs []uint) ([]models.Menu, erro
 	}
 	return menus, nil
 }
+
+// ListMenuI18n 查询菜单的全部翻译
+func (r *MenuRepository) ListMenuI18n(menuID uint) ([]models.MenuI18n, error) {
+	var list []models.MenuI18n
+	if err := r.db.Where("menu_id = ?", menuID).Order("locale ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// ListMenuTitles 查询指定语言的菜单标题（菜单ID -> 标题）
+func (r *MenuRepository) ListMenuTitles(locale string) (map[uint]string, error) {
+	var list []models.MenuI18n
+	if err := r.db.Where("locale = ?", locale).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	titles := make(map[uint]string, len(list))
+	for _, t := range list {
+		titles[t.MenuID] = t.Title
+	}
+	return titles, nil
+}
+
+// ReplaceMenuI18n 在一个事务中替换菜单的全部翻译
+func (r *MenuRepository) ReplaceMenuI18n(menuID uint, list []models.MenuI18n) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("menu_id = ?", menuID).Delete(&models.MenuI18n{}).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		return tx.Create(&list).Error
+	})
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"gorm.io/gorm"
+)
+
+// NoticeAttachmentRepository 通知附件数据访问接口
+type NoticeAttachmentRepository interface {
+	CreateAttachment(ctx *gin.Context, entity *models.NoticeAttachment) error
+	GetAttachmentByID(ctx *gin.Context, id uint) (*models.NoticeAttachment, error)
+	ListByNotice(ctx *gin.Context, noticeID uint) ([]models.NoticeAttachment, error)
+
+	// 与通知的保存在同一事务内同步附件
+	ListByNoticeTx(tx *gorm.DB, noticeID uint) ([]models.NoticeAttachment, error)
+	BindToNoticeTx(tx *gorm.DB, noticeID uint, ids []uint, uploaderID uint) error
+	ListUnboundTx(tx *gorm.DB, noticeID uint, keepIDs []uint) ([]models.NoticeAttachment, error)
+	DeleteByIDsTx(tx *gorm.DB, ids []uint) error
+}
+
+// NoticeAttachmentRepositoryImpl 通知附件数据访问实现
+type NoticeAttachmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewNoticeAttachmentRepository 创建通知附件数据访问
+func NewNoticeAttachmentRepository(db *gorm.DB) NoticeAttachmentRepository {
+	return &NoticeAttachmentRepositoryImpl{db: db}
+}
+
+// CreateAttachment 保存附件记录
+func (r *NoticeAttachmentRepositoryImpl) CreateAttachment(ctx *gin.Context, entity *models.NoticeAttachment) error {
+	return r.db.WithContext(ctx).Create(entity).Error
+}
+
+// GetAttachmentByID 根据ID获取附件
+func (r *NoticeAttachmentRepositoryImpl) GetAttachmentByID(ctx *gin.Context, id uint) (*models.NoticeAttachment, error) {
+	var entity models.NoticeAttachment
+	if err := r.db.WithContext(ctx).First(&entity, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// ListByNotice 查询通知的附件
+func (r *NoticeAttachmentRepositoryImpl) ListByNotice(ctx *gin.Context, noticeID uint) ([]models.NoticeAttachment, error) {
+	return r.ListByNoticeTx(r.db.WithContext(ctx), noticeID)
+}
+
+// ListByNoticeTx 在事务中查询通知的附件
+func (r *NoticeAttachmentRepositoryImpl) ListByNoticeTx(tx *gorm.DB, noticeID uint) ([]models.NoticeAttachment, error) {
+	var list []models.NoticeAttachment
+	err := tx.
+		Where("notice_id = ?", noticeID).
+		Order("id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+// BindToNoticeTx 绑定附件：只能绑定本人上传且未绑定的附件，或已属于该通知的附件
+func (r *NoticeAttachmentRepositoryImpl) BindToNoticeTx(tx *gorm.DB, noticeID uint, ids []uint, uploaderID uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.
+		Model(&models.NoticeAttachment{}).
+		Where("id IN ? AND ((notice_id = 0 AND uploader_id = ?) OR notice_id = ?)", ids, uploaderID, noticeID).
+		Update("notice_id", noticeID).Error
+}
+
+// ListUnboundTx 查询通知中不在保留列表内的附件（编辑时被移除的附件）
+func (r *NoticeAttachmentRepositoryImpl) ListUnboundTx(tx *gorm.DB, noticeID uint, keepIDs []uint) ([]models.NoticeAttachment, error) {
+	var list []models.NoticeAttachment
+	query := tx.Where("notice_id = ?", noticeID)
+	if len(keepIDs) > 0 {
+		query = query.Where("id NOT IN ?", keepIDs)
+	}
+	err := query.Find(&list).Error
+	return list, err
+}
+
+// DeleteByIDsTx 删除附件记录
+func (r *NoticeAttachmentRepositoryImpl) DeleteByIDsTx(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Delete(&models.NoticeAttachment{}, ids).Error
+}
@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NoticeDeliveryRepository 通知渠道投递数据访问接口
+type NoticeDeliveryRepository interface {
+	// 入队（在发布事务内调用）
+	ListNotifyRecipientsTx(tx *gorm.DB, userIDs []uint) ([]models.NotifyRecipient, error)
+	MapPreferencesTx(tx *gorm.DB, userIDs []uint) (map[uint]*models.UserNotifyPreference, error)
+	CreateDeliveriesTx(tx *gorm.DB, deliveries []models.NoticeDelivery) error
+	ResetDeliveriesTx(tx *gorm.DB, noticeID uint, userIDs []uint) error
+
+	// 投递队列
+	ListDueDeliveries(ctx context.Context, now time.Time, staleBefore time.Time, limit int) ([]*models.NoticeDelivery, error)
+	ClaimDelivery(ctx context.Context, d *models.NoticeDelivery) (bool, error)
+	UpdateDelivery(ctx context.Context, id uint, fields map[string]interface{}) error
+	MapNotices(ctx context.Context, ids []uint) (map[uint]*models.NoticesModel, error)
+	MapNotifyRecipients(ctx context.Context, userIDs []uint) (map[uint]models.NotifyRecipient, error)
+	MapPreferences(ctx context.Context, userIDs []uint) (map[uint]*models.UserNotifyPreference, error)
+	ListDeliveries(ctx *gin.Context, noticeID uint) ([]*models.NoticeDelivery, error)
+
+	// 用户偏好
+	GetPreference(ctx *gin.Context, userID uint) (*models.UserNotifyPreference, error)
+	SavePreference(ctx *gin.Context, pref *models.UserNotifyPreference) error
+}
+
+// NoticeDeliveryRepositoryImpl 通知渠道投递数据访问实现
+type NoticeDeliveryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewNoticeDeliveryRepository 创建通知渠道投递数据访问
+func NewNoticeDeliveryRepository(db *gorm.DB) NoticeDeliveryRepository {
+	return &NoticeDeliveryRepositoryImpl{db: db}
+}
+
+// ListNotifyRecipientsTx 查询接收人的联系方式
+func (r *NoticeDeliveryRepositoryImpl) ListNotifyRecipientsTx(tx *gorm.DB, userIDs []uint) ([]models.NotifyRecipient, error) {
+	var list []models.NotifyRecipient
+	if len(userIDs) == 0 {
+		return list, nil
+	}
+	err := tx.Table("users").
+		Select("id, nickname, email, mobile").
+		Where("id IN ? AND deleted_at IS NULL", userIDs).
+		Scan(&list).Error
+	return list, err
+}
+
+// MapPreferencesTx 查询用户渠道偏好（未设置的用户不在结果中）
+func (r *NoticeDeliveryRepositoryImpl) MapPreferencesTx(tx *gorm.DB, userIDs []uint) (map[uint]*models.UserNotifyPreference, error) {
+	result := make(map[uint]*models.UserNotifyPreference, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+	var list []*models.UserNotifyPreference
+	if err := tx.Where("user_id IN ?", userIDs).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range list {
+		result[p.UserID] = p
+	}
+	return result, nil
+}
+
+// CreateDeliveriesTx 批量写入投递记录，重新发布时已存在的记录保持不变
+func (r *NoticeDeliveryRepositoryImpl) CreateDeliveriesTx(tx *gorm.DB, deliveries []models.NoticeDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(deliveries, 500).Error
+}
+
+// ResetDeliveriesTx 将已结束的投递记录重新入队（再次提醒时使用）
+func (r *NoticeDeliveryRepositoryImpl) ResetDeliveriesTx(tx *gorm.DB, noticeID uint, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return tx.Model(&models.NoticeDelivery{}).
+		Where("notice_id = ? AND user_id IN ? AND status IN ?", noticeID, userIDs,
+			[]string{models.DeliveryStatusSuccess, models.DeliveryStatusFailed}).
+		Updates(map[string]interface{}{
+			"status":        models.DeliveryStatusPending,
+			"attempts":      0,
+			"last_error":    "",
+			"next_retry_at": time.Now(),
+		}).Error
+}
+
+// ListDueDeliveries 查询到期待发送的记录，以及发送中超时（进程中断遗留）的记录，附带接收人的个性化内容
+func (r *NoticeDeliveryRepositoryImpl) ListDueDeliveries(ctx context.Context, now time.Time, staleBefore time.Time, limit int) ([]*models.NoticeDelivery, error) {
+	var list []*models.NoticeDelivery
+	err := r.db.WithContext(ctx).
+		Select("notice_deliveries.*, notice_receiver.content AS receiver_content").
+		Joins("LEFT JOIN notice_receiver ON notice_receiver.notice_id = notice_deliveries.notice_id AND notice_receiver.user_id = notice_deliveries.user_id").
+		Where("(notice_deliveries.status = ? AND notice_deliveries.next_retry_at <= ?) OR (notice_deliveries.status = ? AND notice_deliveries.updated_at <= ?)",
+			models.DeliveryStatusPending, now, models.DeliveryStatusSending, staleBefore).
+		Order("notice_deliveries.next_retry_at ASC").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+// ClaimDelivery 将记录标记为发送中，状态已被其他实例修改时返回 false
+func (r *NoticeDeliveryRepositoryImpl) ClaimDelivery(ctx context.Context, d *models.NoticeDelivery) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.NoticeDelivery{}).
+		Where("id = ? AND status = ? AND attempts = ?", d.ID, d.Status, d.Attempts).
+		Updates(map[string]interface{}{
+			"status":     models.DeliveryStatusSending,
+			"attempts":   d.Attempts + 1,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateDelivery 更新投递结果
+func (r *NoticeDeliveryRepositoryImpl) UpdateDelivery(ctx context.Context, id uint, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&models.NoticeDelivery{}).
+		Where("id = ?", id).
+		Updates(fields).Error
+}
+
+// MapNotices 按ID批量查询通知（后台任务使用，不带数据权限）
+func (r *NoticeDeliveryRepositoryImpl) MapNotices(ctx context.Context, ids []uint) (map[uint]*models.NoticesModel, error) {
+	result := make(map[uint]*models.NoticesModel, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var list []*models.NoticesModel
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for _, n := range list {
+		result[n.ID] = n
+	}
+	return result, nil
+}
+
+// MapNotifyRecipients 按用户ID批量查询联系方式
+func (r *NoticeDeliveryRepositoryImpl) MapNotifyRecipients(ctx context.Context, userIDs []uint) (map[uint]models.NotifyRecipient, error) {
+	list, err := r.ListNotifyRecipientsTx(r.db.WithContext(ctx), userIDs)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]models.NotifyRecipient, len(list))
+	for _, u := range list {
+		result[u.UserID] = u
+	}
+	return result, nil
+}
+
+// MapPreferences 批量查询用户渠道偏好
+func (r *NoticeDeliveryRepositoryImpl) MapPreferences(ctx context.Context, userIDs []uint) (map[uint]*models.UserNotifyPreference, error) {
+	return r.MapPreferencesTx(r.db.WithContext(ctx), userIDs)
+}
+
+// ListDeliveries 查询通知的投递记录
+func (r *NoticeDeliveryRepositoryImpl) ListDeliveries(ctx *gin.Context, noticeID uint) ([]*models.NoticeDelivery, error) {
+	var list []*models.NoticeDelivery
+	err := r.db.WithContext(ctx).
+		Select("notice_deliveries.*, users.nickname").
+		Joins("LEFT JOIN users ON users.id = notice_deliveries.user_id").
+		Where("notice_deliveries.notice_id = ?", noticeID).
+		Order("notice_deliveries.id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+// GetPreference 查询用户渠道偏好，未设置时返回 nil
+func (r *NoticeDeliveryRepositoryImpl) GetPreference(ctx *gin.Context, userID uint) (*models.UserNotifyPreference, error) {
+	var pref models.UserNotifyPreference
+	if err := r.db.WithContext(ctx).First(&pref, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &pref, nil
+}
+
+// SavePreference 保存用户渠道偏好
+func (r *NoticeDeliveryRepositoryImpl) SavePreference(ctx *gin.Context, pref *models.UserNotifyPreference) error {
+	return r.db.WithContext(ctx).Save(pref).Error
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/scopes"
 	"gorm.io/gorm"
 )
@@ -70,7 +71,7 @@ func (r *NoticeReceiverRepositoryImpl) UpdateNoticeReceiver(ctx *gin.Context, en
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("NoticeReceiver not found")
+		return apperr.ErrNoticeReceiverNotFound
 	}
 	return nil
 }
@@ -83,7 +84,7 @@ func (r *NoticeReceiverRepositoryImpl) DeleteNoticeReceiver(ctx *gin.Context, id
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("NoticeReceiver not found")
+		return apperr.ErrNoticeReceiverNotFound
 	}
 	return nil
 }
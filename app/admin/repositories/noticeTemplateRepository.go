@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/scopes"
+	"gorm.io/gorm"
+)
+
+// NoticeTemplateRepository 通知模板数据访问接口
+type NoticeTemplateRepository interface {
+	GetNoticeTemplateByID(ctx *gin.Context, id uint) (*models.NoticeTemplateModel, error)
+	CreateNoticeTemplate(ctx *gin.Context, entity *models.NoticeTemplateModel) error
+	UpdateNoticeTemplate(ctx *gin.Context, entity *models.NoticeTemplateModel) error
+	DeleteNoticeTemplate(ctx *gin.Context, id uint) error
+	PageNoticeTemplates(ctx *gin.Context, keywords string, pageNum, pageSize int) ([]*models.NoticeTemplateModel, int64, error)
+	GetTemplateRecipient(ctx *gin.Context, userID uint) (*models.NoticeTemplateRecipient, error)
+}
+
+// NoticeTemplateRepositoryImpl 通知模板数据访问实现
+type NoticeTemplateRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewNoticeTemplateRepository 创建通知模板数据访问
+func NewNoticeTemplateRepository(db *gorm.DB) NoticeTemplateRepository {
+	return &NoticeTemplateRepositoryImpl{db: db}
+}
+
+// GetNoticeTemplateByID 根据ID获取通知模板
+func (r *NoticeTemplateRepositoryImpl) GetNoticeTemplateByID(ctx *gin.Context, id uint) (*models.NoticeTemplateModel, error) {
+	var entity models.NoticeTemplateModel
+	if err := r.db.Scopes(scopes.DataPermissionScope(ctx)).
+		First(&entity, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// CreateNoticeTemplate 创建通知模板
+func (r *NoticeTemplateRepositoryImpl) CreateNoticeTemplate(ctx *gin.Context, entity *models.NoticeTemplateModel) error {
+	return r.db.WithContext(context.WithValue(ctx.Request.Context(), "ginContext", ctx)).
+		Create(entity).Error
+}
+
+// UpdateNoticeTemplate 更新通知模板
+func (r *NoticeTemplateRepositoryImpl) UpdateNoticeTemplate(ctx *gin.Context, entity *models.NoticeTemplateModel) error {
+	result := r.db.Scopes(scopes.DataPermissionScope(ctx)).
+		Save(entity)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperr.ErrTemplateNotFound
+	}
+	return nil
+}
+
+// DeleteNoticeTemplate 删除通知模板
+func (r *NoticeTemplateRepositoryImpl) DeleteNoticeTemplate(ctx *gin.Context, id uint) error {
+	result := r.db.Scopes(scopes.DataPermissionScope(ctx)).
+		Delete(&models.NoticeTemplateModel{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperr.ErrTemplateNotFound
+	}
+	return nil
+}
+
+// PageNoticeTemplates 分页获取通知模板列表
+func (r *NoticeTemplateRepositoryImpl) PageNoticeTemplates(ctx *gin.Context, keywords string, pageNum, pageSize int) ([]*models.NoticeTemplateModel, int64, error) {
+	var entities []*models.NoticeTemplateModel
+	var total int64
+	query := r.db.Scopes(scopes.DataPermissionScope(ctx)).
+		Model(&models.NoticeTemplateModel{})
+	if keywords != "" {
+		query = query.Where("(name LIKE ? OR title LIKE ?)", "%"+keywords+"%", "%"+keywords+"%")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("id DESC").Offset((pageNum - 1) * pageSize).Limit(pageSize).Find(&entities).Error; err != nil {
+		return nil, 0, err
+	}
+	return entities, total, nil
+}
+
+// GetTemplateRecipient 查询用户及部门信息（模板预览使用）
+func (r *NoticeTemplateRepositoryImpl) GetTemplateRecipient(ctx *gin.Context, userID uint) (*models.NoticeTemplateRecipient, error) {
+	var recipient models.NoticeTemplateRecipient
+	err := r.db.WithContext(ctx).
+		Table("users AS u").
+		Select("u.id AS user_id, u.username, u.nickname, u.email, u.mobile, u.dept_id, "+
+			"COALESCE(d.name, '') AS dept_name, COALESCE(d.code, '') AS dept_code").
+		Joins("LEFT JOIN depts d ON d.id = u.dept_id").
+		Where("u.id = ?", userID).
+		Take(&recipient).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &recipient, nil
+}
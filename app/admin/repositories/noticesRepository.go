@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/scopes"
 	"gorm.io/gorm"
 )
@@ -21,21 +22,39 @@ type NoticesRepository interface {
 	UpdateNotices(ctx *gin.Context, entity *models.NoticesModel) error
 	DeleteNotices(ctx *gin.Context, id uint) error // 使用uint类型
 	PageNotices(ctx *gin.Context, keywords string, publishStatus string, pageNum, pageSize int) ([]*models.NoticesModel, int64, error)
-	RevokeNotice(ctx *gin.Context, id uint) error  // 使用uint类型
-	PublishNotice(ctx *gin.Context, id uint) error // 使用uint类型
 	MarkNoticeAsRead(ctx *gin.Context, userID uint, noticeID uint) error
 
 	GetNoticeWithReceivers(ctx *gin.Context, id uint) (*models.NoticesModel, error)
-	UpdateNoticeStatusTx(tx *gorm.DB, id uint, status uint) error
 
 	// 新增事务支持
 	CreateNoticesTx(tx *gorm.DB, entity *models.NoticesModel) error
+	UpdateNoticesTx(ctx *gin.Context, tx *gorm.DB, entity *models.NoticesModel) error
 	BeginTx(ctx *gin.Context) *gorm.DB
-	PublishNoticesTx(ctx *gin.Context, tx *gorm.DB, id uint) error
-	RevokeNoticesTx(tx *gorm.DB, id uint) error
+	Transaction(ctx *gin.Context, fn func(tx *gorm.DB) error) error
 	MyPageNotices(ctx *gin.Context, userID uint, keywords string, isRead uint, pageNum, pageSize int) ([]*models.NoticesModel, int64, error)
 	GetMyNoticesByID(ctx *gin.Context, userID uint, noticeID uint) (*models.NoticesModel, error)
 	MarkAllAsRead(ctx *gin.Context, userID uint) error
+
+	// 状态流转（流转规则由服务层控制，这里只做带条件的更新）与定时任务
+	TransitionStatusTx(tx *gorm.DB, id uint, from []int, to int, fields map[string]interface{}) (bool, error)
+	CreateStatusHistoryTx(tx *gorm.DB, history *models.NoticeStatusHistory) error
+	ListStatusHistory(ctx *gin.Context, noticeID uint) ([]*models.NoticeStatusHistory, error)
+	ListDueScheduledNoticeIDs(ctx context.Context, now time.Time, limit int) ([]uint, error)
+	ListExpiredNoticeIDs(ctx context.Context, now time.Time, limit int) ([]uint, error)
+
+	// 推送相关
+	ListReceiverUserIDs(ctx context.Context, noticeID uint) ([]uint, error)
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+
+	// 阅读回执统计
+	CountReceivers(ctx *gin.Context, noticeID uint) (total int64, read int64, err error)
+	ListReadTimeline(ctx *gin.Context, noticeID uint, granularity string) ([]models.NoticeReadPoint, error)
+	ListDeptReadStats(ctx *gin.Context, noticeID uint) ([]models.NoticeDeptReadStat, error)
+	PageUnreadUsers(ctx *gin.Context, noticeID uint, deptID uint, keywords string, pageNum, pageSize int) ([]models.NoticeUnreadUser, int64, error)
+	ListUnreadUserIDs(ctx *gin.Context, noticeID uint) ([]uint, error)
+
+	// 模板渲染
+	ListTemplateRecipientsTx(tx *gorm.DB, userIDs []uint) ([]models.NoticeTemplateRecipient, error)
 }
 
 // NoticesRepositoryImpl Notices数据访问实现
@@ -69,10 +88,11 @@ func (r *NoticesRepositoryImpl) GetMyNoticesByID(ctx *gin.Context, userID uint,
 
 	// 构建查询：通过notice_receiver表关联，确保用户有权限访问该通知
 	err := r.db.WithContext(ctx).
-		Select("notices.*, users.nickname as publisher_name, notice_receiver.is_read").
+		Select("notices.*, users.nickname as publisher_name, notice_receiver.is_read, notice_receiver.content as receiver_content").
 		Joins("JOIN notice_receiver ON notices.id = notice_receiver.notice_id").
 		Joins("LEFT JOIN users ON users.id = notices.creator_id").
 		Where("notice_receiver.user_id = ? AND notice_receiver.notice_id = ?", userID, noticeID).
+		Where("notices.expires_at IS NULL OR notices.expires_at > ?", time.Now()).
 		First(&notice).Error
 
 	if err != nil {
@@ -82,6 +102,7 @@ func (r *NoticesRepositoryImpl) GetMyNoticesByID(ctx *gin.Context, userID uint,
 		return nil, fmt.Errorf("查询用户通知失败: %w", err)
 	}
 
+	notice.UseReceiverContent()
 	return &notice, nil
 }
 
@@ -91,15 +112,16 @@ func (r *NoticesRepositoryImpl) MarkNoticeAsRead(ctx *gin.Context, userID uint,
 		Model(&models.NoticeReceiver{}).
 		Where("user_id = ? AND notice_id = ?", userID, noticeID).
 		Updates(map[string]interface{}{
-			"is_read":   1,
-			"read_time": time.Now(),
+			"is_read": 1,
+			// 重复打开不覆盖首次阅读时间，保证阅读趋势统计准确
+			"read_time": gorm.Expr("CASE WHEN is_read = 1 THEN read_time ELSE ? END", time.Now()),
 		})
 
 	if result.Error != nil {
 		return fmt.Errorf("更新已读状态失败: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("未找到对应的通知接收记录")
+		return apperr.ErrNoticeReceiverNotFound
 	}
 
 	return nil
@@ -138,13 +160,18 @@ func (r *NoticesRepositoryImpl) CreateNotices(ctx *gin.Context, entity *models.N
 
 // UpdateNotices 更新Notices
 func (r *NoticesRepositoryImpl) UpdateNotices(ctx *gin.Context, entity *models.NoticesModel) error {
-	result := r.db.Scopes(scopes.DataPermissionScope(ctx)).
+	return r.UpdateNoticesTx(ctx, r.db, entity)
+}
+
+// UpdateNoticesTx 在事务中按数据权限更新Notices
+func (r *NoticesRepositoryImpl) UpdateNoticesTx(ctx *gin.Context, tx *gorm.DB, entity *models.NoticesModel) error {
+	result := tx.Scopes(scopes.DataPermissionScope(ctx)).
 		Save(entity)
 	if result.Error != nil {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("Notices not found")
+		return apperr.ErrNoticeNotFound
 	}
 	return nil
 }
@@ -157,7 +184,7 @@ func (r *NoticesRepositoryImpl) DeleteNotices(ctx *gin.Context, id uint) error {
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		return errors.New("Notices not found")
+		return apperr.ErrNoticeNotFound
 	}
 	return nil
 }
@@ -189,28 +216,6 @@ func (r *NoticesRepositoryImpl) PageNotices(ctx *gin.Context, keywords string, p
 	return entities, total, nil
 }
 
-// RevokeNotice 撤销公告
-func (r *NoticesRepositoryImpl) RevokeNotice(ctx *gin.Context, id uint) error {
-	now := time.Now()
-	return r.db.WithContext(ctx).Model(&models.NoticesModel{}).
-		Where("id = ?", id).
-		Updates(map[string]interface{}{
-			"status":     2,
-			"revoked_at": now,
-		}).Error
-}
-
-// PublishNotice 发布公告
-func (r *NoticesRepositoryImpl) PublishNotice(ctx *gin.Context, id uint) error {
-	now := time.Now()
-	return r.db.WithContext(ctx).Model(&models.NoticesModel{}).
-		Where("id = ?", id).
-		Updates(map[string]interface{}{
-			"status":       1,
-			"published_at": now,
-		}).Error
-}
-
 // CreateNoticesTx 创建事务
 func (r *NoticesRepositoryImpl) CreateNoticesTx(tx *gorm.DB, entity *models.NoticesModel) error {
 	return tx.Create(entity).Error
@@ -221,26 +226,9 @@ func (r *NoticesRepositoryImpl) BeginTx(ctx *gin.Context) *gorm.DB {
 	return r.db.WithContext(ctx).Begin()
 }
 
-// PublishNoticesTx 发布事务
-func (r *NoticesRepositoryImpl) PublishNoticesTx(ctx *gin.Context, tx *gorm.DB, id uint) error {
-	now := time.Now()
-	return tx.Model(&models.NoticesModel{}).
-		Where("id = ?", id).
-		Updates(map[string]interface{}{
-			"status":       1,
-			"published_at": now,
-		}).Error
-}
-
-// RevokeNoticesTx 撤销事务
-func (r *NoticesRepositoryImpl) RevokeNoticesTx(tx *gorm.DB, id uint) error {
-	now := time.Now()
-	return tx.Model(&models.NoticesModel{}).
-		Where("id = ?", id).
-		Updates(map[string]interface{}{
-			"status":     2,
-			"revoked_at": now,
-		}).Error
+// Transaction 在事务中执行 fn，返回错误时回滚；事务携带 gin.Context，创建钩子可取得当前用户
+func (r *NoticesRepositoryImpl) Transaction(ctx *gin.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(context.WithValue(ctx.Request.Context(), "ginContext", ctx)).Transaction(fn)
 }
 
 // MyPageNotices 获取我的公告列表
@@ -252,7 +240,7 @@ func (r *NoticesRepositoryImpl) MyPageNotices(ctx *gin.Context, userID uint, key
 	// 构建基础查询
 	query := r.db.WithContext(ctx).
 		Model(&models.NoticesModel{}).
-		Select("notices.*, notice_receiver.is_read").
+		Select("notices.*, notice_receiver.is_read, notice_receiver.content as receiver_content").
 		Joins("JOIN notice_receiver ON notices.id = notice_receiver.notice_id").
 		Where("notice_receiver.user_id = ?", userID)
 
@@ -267,6 +255,8 @@ func (r *NoticesRepositoryImpl) MyPageNotices(ctx *gin.Context, userID uint, key
 	}
 	// 必须是已发布的消息，其他的状态不展示给用户
 	query = query.Where("notices.status = 1")
+	// 已过期的消息不再展示（定时任务会将其置为已过期，这里保证及时生效）
+	query = query.Where("notices.expires_at IS NULL OR notices.expires_at > ?", time.Now())
 
 	// 获取总数
 	if err := query.Count(&total).Error; err != nil {
@@ -283,6 +273,9 @@ func (r *NoticesRepositoryImpl) MyPageNotices(ctx *gin.Context, userID uint, key
 	if err != nil {
 		return nil, 0, fmt.Errorf("查询通知列表失败: %w", err)
 	}
+	for _, n := range notices {
+		n.UseReceiverContent()
+	}
 
 	return notices, total, nil
 }
@@ -299,9 +292,180 @@ func (r *NoticesRepositoryImpl) GetNoticeWithReceivers(ctx *gin.Context, id uint
 	return &notice, nil
 }
 
-// 更新通知状态（事务版本）
-func (r *NoticesRepositoryImpl) UpdateNoticeStatusTx(tx *gorm.DB, id uint, status uint) error {
-	return tx.Model(&models.NoticesModel{}).
-		Where("id = ?", id).
-		Update("status", status).Error
+// TransitionStatusTx 按条件更新通知状态（仅当当前状态属于 from 时才更新），返回是否更新成功
+func (r *NoticesRepositoryImpl) TransitionStatusTx(tx *gorm.DB, id uint, from []int, to int, fields map[string]interface{}) (bool, error) {
+	updates := map[string]interface{}{"status": to}
+	for k, v := range fields {
+		updates[k] = v
+	}
+	result := tx.Model(&models.NoticesModel{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListDueScheduledNoticeIDs 查询已到发布时间的定时通知
+func (r *NoticesRepositoryImpl) ListDueScheduledNoticeIDs(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&models.NoticesModel{}).
+		Where("status = ? AND scheduled_at <= ?", models.NoticeStatusScheduled, now).
+		Order("scheduled_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// CreateStatusHistoryTx 记录状态流转
+func (r *NoticesRepositoryImpl) CreateStatusHistoryTx(tx *gorm.DB, history *models.NoticeStatusHistory) error {
+	return tx.Create(history).Error
+}
+
+// ListStatusHistory 查询通知的状态流转记录
+func (r *NoticesRepositoryImpl) ListStatusHistory(ctx *gin.Context, noticeID uint) ([]*models.NoticeStatusHistory, error) {
+	var list []*models.NoticeStatusHistory
+	err := r.db.WithContext(ctx).
+		Select("notice_status_history.*, users.nickname as operator_name").
+		Joins("LEFT JOIN users ON users.id = notice_status_history.operator_id").
+		Where("notice_status_history.notice_id = ?", noticeID).
+		Order("notice_status_history.id ASC").
+		Find(&list).Error
+	return list, err
+}
+
+// ListExpiredNoticeIDs 查询已过期但仍处于发布状态的通知
+func (r *NoticesRepositoryImpl) ListExpiredNoticeIDs(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).
+		Model(&models.NoticesModel{}).
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", models.NoticeStatusPublished, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ListReceiverUserIDs 查询通知的接收用户ID
+func (r *NoticesRepositoryImpl) ListReceiverUserIDs(ctx context.Context, noticeID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.WithContext(ctx).
+		Model(&models.NoticeReceiver{}).
+		Where("notice_id = ?", noticeID).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// CountUnread 统计用户未读通知数（口径与 MyPageNotices 一致）
+func (r *NoticesRepositoryImpl) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.NoticesModel{}).
+		Joins("JOIN notice_receiver ON notices.id = notice_receiver.notice_id").
+		Where("notice_receiver.user_id = ? AND notice_receiver.is_read = 0", userID).
+		Where("notices.status = ?", models.NoticeStatusPublished).
+		Where("notices.expires_at IS NULL OR notices.expires_at > ?", time.Now()).
+		Count(&count).Error
+	return count, err
+}
+
+// CountReceivers 统计通知的接收人数和已读人数
+func (r *NoticesRepositoryImpl) CountReceivers(ctx *gin.Context, noticeID uint) (int64, int64, error) {
+	var row struct {
+		Total int64
+		Read  int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.NoticeReceiver{}).
+		Select("COUNT(*) AS total, COALESCE(SUM(is_read = 1), 0) AS `read`").
+		Where("notice_id = ?", noticeID).
+		Scan(&row).Error
+	return row.Total, row.Read, err
+}
+
+// ListReadTimeline 按小时或天统计已读人数（按首次阅读时间）
+func (r *NoticesRepositoryImpl) ListReadTimeline(ctx *gin.Context, noticeID uint, granularity string) ([]models.NoticeReadPoint, error) {
+	format := "%Y-%m-%d %H:00"
+	if granularity == models.ReadStatsGranularityDay {
+		format = "%Y-%m-%d"
+	}
+	var list []models.NoticeReadPoint
+	err := r.db.WithContext(ctx).
+		Model(&models.NoticeReceiver{}).
+		Select("DATE_FORMAT(read_time, ?) AS time, COUNT(*) AS count", format).
+		Where("notice_id = ? AND is_read = 1 AND read_time IS NOT NULL", noticeID).
+		Group("time").
+		Order("time ASC").
+		Scan(&list).Error
+	return list, err
+}
+
+// ListDeptReadStats 按接收人所属部门统计阅读情况
+func (r *NoticesRepositoryImpl) ListDeptReadStats(ctx *gin.Context, noticeID uint) ([]models.NoticeDeptReadStat, error) {
+	var list []models.NoticeDeptReadStat
+	err := r.db.WithContext(ctx).
+		Table("notice_receiver AS nr").
+		Select("u.dept_id, COALESCE(d.name, '') AS dept_name, COUNT(*) AS total, COALESCE(SUM(nr.is_read = 1), 0) AS read_count").
+		Joins("JOIN users u ON u.id = nr.user_id").
+		Joins("LEFT JOIN depts d ON d.id = u.dept_id").
+		Where("nr.notice_id = ?", noticeID).
+		Group("u.dept_id, d.name").
+		Order("total DESC").
+		Scan(&list).Error
+	return list, err
+}
+
+// PageUnreadUsers 分页查询未读用户
+func (r *NoticesRepositoryImpl) PageUnreadUsers(ctx *gin.Context, noticeID uint, deptID uint, keywords string, pageNum, pageSize int) ([]models.NoticeUnreadUser, int64, error) {
+	query := r.db.WithContext(ctx).
+		Table("notice_receiver AS nr").
+		Joins("JOIN users u ON u.id = nr.user_id").
+		Joins("LEFT JOIN depts d ON d.id = u.dept_id").
+		Where("nr.notice_id = ? AND nr.is_read = 0", noticeID)
+	if deptID > 0 {
+		query = query.Where("u.dept_id = ?", deptID)
+	}
+	if keywords != "" {
+		query = query.Where("(u.username LIKE ? OR u.nickname LIKE ?)", "%"+keywords+"%", "%"+keywords+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []models.NoticeUnreadUser
+	err := query.
+		Select("u.id AS user_id, u.username, u.nickname, u.dept_id, COALESCE(d.name, '') AS dept_name, nr.created_at AS receive_time").
+		Order("u.id ASC").
+		Offset((pageNum - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&list).Error
+	return list, total, err
+}
+
+// ListUnreadUserIDs 查询通知的全部未读用户ID
+func (r *NoticesRepositoryImpl) ListUnreadUserIDs(ctx *gin.Context, noticeID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.WithContext(ctx).
+		Model(&models.NoticeReceiver{}).
+		Where("notice_id = ? AND is_read = 0", noticeID).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// ListTemplateRecipientsTx 查询渲染模板所需的接收人及其部门信息
+func (r *NoticesRepositoryImpl) ListTemplateRecipientsTx(tx *gorm.DB, userIDs []uint) ([]models.NoticeTemplateRecipient, error) {
+	var list []models.NoticeTemplateRecipient
+	if len(userIDs) == 0 {
+		return list, nil
+	}
+	err := tx.Table("users AS u").
+		Select("u.id AS user_id, u.username, u.nickname, u.email, u.mobile, u.dept_id, "+
+			"COALESCE(d.name, '') AS dept_name, COALESCE(d.code, '') AS dept_code").
+		Joins("LEFT JOIN depts d ON d.id = u.dept_id").
+		Where("u.id IN ?", userIDs).
+		Scan(&list).Error
+	return list, err
 }
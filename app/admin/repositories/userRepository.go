@@ -27,6 +27,10 @@ type UserRepository interface {
 	UpdateUserProfile(id uint, updateMap map[string]interface{}) error
 	ListUserOptions(ctx *gin.Context) ([]models.UserOption, error)
 	UpdateLastLogin(userID uint, ClientIP string, loginTime time.Time, userAgent string, device, browser, os string) error
+	FindExistingUsernames(usernames []string) ([]string, error)
+	MapDeptIDsByCode(codes []string) (map[string]uint, error)
+	MapRoleIDsByCode(codes []string) (map[string]uint, error)
+	CreateUserTx(tx *gorm.DB, user *models.User, roleIDs []uint) error
 }
 
 type UserRepositoryImpl struct {
@@ -290,3 +294,60 @@ func (r *UserRepositoryImpl) UpdateLastLogin(userID uint, ClientIP string, login
 		"last_login_os":         os,
 	}).Error
 }
+
+// FindExistingUsernames 返回已存在于数据库中的用户名
+func (r *UserRepositoryImpl) FindExistingUsernames(usernames []string) ([]string, error) {
+	var existing []string
+	if len(usernames) == 0 {
+		return existing, nil
+	}
+	err := r.db.Model(&models.User{}).Where("username IN ?", usernames).Pluck("username", &existing).Error
+	return existing, err
+}
+
+// MapDeptIDsByCode 按部门编码查询部门ID
+func (r *UserRepositoryImpl) MapDeptIDsByCode(codes []string) (map[string]uint, error) {
+	result := make(map[string]uint, len(codes))
+	if len(codes) == 0 {
+		return result, nil
+	}
+	var depts []models.Dept
+	if err := r.db.Model(&models.Dept{}).Select("id", "code").Where("code IN ?", codes).Find(&depts).Error; err != nil {
+		return nil, err
+	}
+	for _, d := range depts {
+		result[d.Code] = d.ID
+	}
+	return result, nil
+}
+
+// MapRoleIDsByCode 按角色编码查询角色ID
+func (r *UserRepositoryImpl) MapRoleIDsByCode(codes []string) (map[string]uint, error) {
+	result := make(map[string]uint, len(codes))
+	if len(codes) == 0 {
+		return result, nil
+	}
+	var roles []models.Role
+	if err := r.db.Model(&models.Role{}).Select("id", "code").Where("code IN ?", codes).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		result[role.Code] = role.ID
+	}
+	return result, nil
+}
+
+// CreateUserTx 在事务中创建用户（密码需已加密）并绑定角色
+func (r *UserRepositoryImpl) CreateUserTx(tx *gorm.DB, user *models.User, roleIDs []uint) error {
+	if err := tx.Create(user).Error; err != nil {
+		return err
+	}
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	roles := make([]models.Role, 0, len(roleIDs))
+	for _, rid := range roleIDs {
+		roles = append(roles, models.Role{ID: rid})
+	}
+	return tx.Model(user).Association("RoleList").Replace(roles)
+}
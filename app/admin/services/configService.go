@@ -1,11 +1,19 @@
 package services
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"log"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/config"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/secretbox"
+	"github.com/zmqge/vireo-gin-admin/pkg/sysconfig"
+	"github.com/zmqge/vireo-gin-admin/pkg/textdiff"
 )
 
 // ConfigService Config服务接口
@@ -17,16 +25,39 @@ type ConfigService interface {
 	DeleteConfig(ctx *gin.Context, id uint) error // 使用uint类型
 	PageConfigs(ctx *gin.Context, keywords string, pageNum, pageSize int) ([]*models.ConfigModel, int64, error)
 	GetConfigForm(ctx *gin.Context, id uint) (*models.ConfigModel, error) // 使用uint类型
+	ListConfigHistory(ctx *gin.Context, id uint) ([]*models.ConfigHistory, error)
+	DiffConfigVersion(ctx *gin.Context, id uint, version int) (*models.ConfigDiff, error)
+	RollbackConfig(ctx *gin.Context, id uint, version int) (*models.ConfigModel, error)
+	LoadValues(ctx context.Context) (map[string]string, error)
 }
 
 // ConfigServiceImpl Config服务实现
 type ConfigServiceImpl struct {
-	repo repositories.ConfigRepository
+	repo     repositories.ConfigRepository
+	dictRepo repositories.DictRepository
 }
 
 // NewConfigService 创建Config服务
-func NewConfigService(repo repositories.ConfigRepository) ConfigService {
-	return &ConfigServiceImpl{repo: repo}
+func NewConfigService(repo repositories.ConfigRepository, dictRepo repositories.DictRepository) ConfigService {
+	return &ConfigServiceImpl{repo: repo, dictRepo: dictRepo}
+}
+
+var (
+	configBoxOnce sync.Once
+	configBox     *secretbox.Box
+	configBoxErr  error
+)
+
+// secretBox 密文配置的加解密器，密钥取自配置，未配置时使用 JWT 访问密钥
+func secretBox() (*secretbox.Box, error) {
+	configBoxOnce.Do(func() {
+		key := config.App.SysConfig.SecretKey
+		if key == "" {
+			key = config.App.JWT.AccessSecret
+		}
+		configBox, configBoxErr = secretbox.New(key)
+	})
+	return configBox, configBoxErr
 }
 
 // GetConfigByID 根据ID获取Config
@@ -36,43 +67,332 @@ func (s *ConfigServiceImpl) GetConfigByID(ctx *gin.Context, id uint) (*models.Co
 		return nil, err
 	}
 	if entity == nil {
-		return nil, errors.New("Config not found")
+		return nil, apperr.ErrConfigNotFound
 	}
-	return entity, nil
+	return maskConfig(entity), nil
 }
 
 // ListConfigs 获取Config列表
 func (s *ConfigServiceImpl) ListConfigs(ctx *gin.Context) ([]*models.ConfigModel, error) {
-	return s.repo.ListConfigs(ctx)
+	list, err := s.repo.ListConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range list {
+		maskConfig(e)
+	}
+	return list, nil
 }
 
 // CreateConfig 创建Config
 func (s *ConfigServiceImpl) CreateConfig(ctx *gin.Context, entity *models.ConfigModel) error {
 	if entity.ConfigName == "" {
-		return errors.New("name is required")
+		return apperr.ErrValidation.WithField("configName", "不能为空")
+	}
+	if err := s.validateValue(entity); err != nil {
+		return err
+	}
+	if err := sealConfig(entity); err != nil {
+		return err
+	}
+	entity.Version = 1
+	if err := s.repo.CreateConfigWithHistory(ctx, entity, newConfigHistory(ctx, entity, models.ConfigActionCreate, "")); err != nil {
+		return err
 	}
-	return s.repo.CreateConfig(ctx, entity)
+	maskConfig(entity)
+	s.notifyChanged(ctx)
+	return nil
 }
 
-// UpdateConfig 更新Config
+// UpdateConfig 更新Config，值、类型或密文标记变化时记录新版本
+// 密文配置提交掩码时表示不修改原值
 func (s *ConfigServiceImpl) UpdateConfig(ctx *gin.Context, entity *models.ConfigModel) error {
 	if entity.ConfigName == "" {
-		return errors.New("name is required")
+		return apperr.ErrValidation.WithField("configName", "不能为空")
+	}
+	existing, err := s.repo.GetConfigByID(ctx, entity.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return apperr.ErrConfigNotFound
 	}
-	return s.repo.UpdateConfig(ctx, entity)
+	oldValue, err := openConfigValue(existing)
+	if err != nil {
+		return err
+	}
+	if existing.IsSecret && entity.ConfigValue == sysconfig.SecretMask {
+		entity.ConfigValue = oldValue
+	}
+	if err := s.validateValue(entity); err != nil {
+		return err
+	}
+
+	changed := entity.ConfigValue != oldValue || entity.ValueType != existing.ValueType ||
+		entity.DictCode != existing.DictCode || entity.IsSecret != existing.IsSecret ||
+		entity.ConfigKey != existing.ConfigKey
+	if err := sealConfig(entity); err != nil {
+		return err
+	}
+	entity.Version = existing.Version
+	entity.CreatorID = existing.CreatorID
+	entity.DeptID = existing.DeptID
+	entity.CreatedAt = existing.CreatedAt
+	var history *models.ConfigHistory
+	if changed {
+		entity.Version++
+		history = newConfigHistory(ctx, entity, models.ConfigActionUpdate, "")
+	}
+	if err := s.repo.UpdateConfigWithHistory(ctx, entity, history); err != nil {
+		return err
+	}
+	maskConfig(entity)
+	s.notifyChanged(ctx)
+	return nil
 }
 
 // DeleteConfig 删除Config
 func (s *ConfigServiceImpl) DeleteConfig(ctx *gin.Context, id uint) error {
-	return s.repo.DeleteConfig(ctx, id)
+	existing, err := s.repo.GetConfigByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return apperr.ErrConfigNotFound
+	}
+	existing.Version++
+	if err := s.repo.DeleteConfigWithHistory(ctx, id, newConfigHistory(ctx, existing, models.ConfigActionDelete, "")); err != nil {
+		return err
+	}
+	s.notifyChanged(ctx)
+	return nil
 }
 
 // PageConfigs 分页获取Config列表
 func (s *ConfigServiceImpl) PageConfigs(ctx *gin.Context, keywords string, pageNum, pageSize int) ([]*models.ConfigModel, int64, error) {
-	return s.repo.PageConfigs(ctx, keywords, pageNum, pageSize)
+	list, total, err := s.repo.PageConfigs(ctx, keywords, pageNum, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, e := range list {
+		maskConfig(e)
+	}
+	return list, total, nil
 }
 
 // GetConfigForm 表单
 func (s *ConfigServiceImpl) GetConfigForm(ctx *gin.Context, id uint) (*models.ConfigModel, error) {
-	return s.repo.GetConfigByID(ctx, id)
+	entity, err := s.repo.GetConfigByID(ctx, id)
+	if err != nil || entity == nil {
+		return entity, err
+	}
+	return maskConfig(entity), nil
+}
+
+// ListConfigHistory 配置的版本历史，密文配置的值以掩码返回
+func (s *ConfigServiceImpl) ListConfigHistory(ctx *gin.Context, id uint) ([]*models.ConfigHistory, error) {
+	if _, err := s.GetConfigByID(ctx, id); err != nil {
+		return nil, err
+	}
+	list, err := s.repo.ListConfigHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range list {
+		if h.IsSecret {
+			h.ConfigValue = sysconfig.SecretMask
+		}
+	}
+	return list, nil
+}
+
+// DiffConfigVersion 比较指定版本与当前值的差异
+// 涉及密文配置时不返回明文，仅标记是否有变化
+func (s *ConfigServiceImpl) DiffConfigVersion(ctx *gin.Context, id uint, version int) (*models.ConfigDiff, error) {
+	current, err := s.repo.GetConfigByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, apperr.ErrConfigNotFound
+	}
+	history, err := s.repo.GetConfigHistory(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		return nil, apperr.ErrConfigVersionNotFound.WithArgs(version)
+	}
+	from, err := openHistoryValue(history)
+	if err != nil {
+		return nil, err
+	}
+	to, err := openConfigValue(current)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.ConfigDiff{FromVersion: version, ToVersion: current.Version}
+	lines := textdiff.Lines(sysconfig.FormatValue(history.ValueType, from), sysconfig.FormatValue(current.ValueType, to))
+	diff.Changed = textdiff.Changed(lines) || history.ValueType != current.ValueType
+	if history.IsSecret || current.IsSecret {
+		if diff.Changed {
+			diff.Lines = []textdiff.Line{
+				{Op: textdiff.OpDelete, Text: sysconfig.SecretMask},
+				{Op: textdiff.OpInsert, Text: sysconfig.SecretMask},
+			}
+		}
+		return diff, nil
+	}
+	diff.Lines = lines
+	return diff, nil
+}
+
+// RollbackConfig 回滚到指定版本（值、类型与密文标记），生成新版本
+func (s *ConfigServiceImpl) RollbackConfig(ctx *gin.Context, id uint, version int) (*models.ConfigModel, error) {
+	current, err := s.repo.GetConfigByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, apperr.ErrConfigNotFound
+	}
+	history, err := s.repo.GetConfigHistory(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		return nil, apperr.ErrConfigVersionNotFound.WithArgs(version)
+	}
+	value, err := openHistoryValue(history)
+	if err != nil {
+		return nil, err
+	}
+
+	current.ConfigValue = value
+	current.ValueType = history.ValueType
+	current.DictCode = history.DictCode
+	current.IsSecret = history.IsSecret
+	// 字典项可能已变化，回滚的值仍需通过当前的校验
+	if err := s.validateValue(current); err != nil {
+		return nil, fmt.Errorf("无法回滚到版本 %d: %w", version, err)
+	}
+	if err := sealConfig(current); err != nil {
+		return nil, err
+	}
+	current.Version++
+	remark := fmt.Sprintf("回滚到版本 %d", version)
+	if err := s.repo.UpdateConfigWithHistory(ctx, current, newConfigHistory(ctx, current, models.ConfigActionRollback, remark)); err != nil {
+		return nil, err
+	}
+	s.notifyChanged(ctx)
+	return maskConfig(current), nil
+}
+
+// LoadValues 加载全部配置的明文值，供系统配置热加载使用
+func (s *ConfigServiceImpl) LoadValues(ctx context.Context) (map[string]string, error) {
+	list, err := s.repo.ListAllConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(list))
+	for i := range list {
+		value, err := openConfigValue(&list[i])
+		if err != nil {
+			log.Printf("解密配置 %s 失败: %v", list[i].ConfigKey, err)
+			continue
+		}
+		values[list[i].ConfigKey] = value
+	}
+	return values, nil
+}
+
+// validateValue 按声明的类型校验配置值，enum 类型取值来自字典
+func (s *ConfigServiceImpl) validateValue(entity *models.ConfigModel) error {
+	if entity.ValueType == "" {
+		entity.ValueType = sysconfig.TypeString
+	}
+	if !sysconfig.ValidType(entity.ValueType) {
+		return apperr.ErrConfigInvalidType.WithArgs(entity.ValueType)
+	}
+	var enumValues []string
+	if entity.ValueType == sysconfig.TypeEnum {
+		if entity.DictCode == "" {
+			return apperr.ErrConfigEnumDictRequired
+		}
+		items, err := s.dictRepo.GetDictItemsByCode(entity.DictCode)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if item.Status == 1 {
+				enumValues = append(enumValues, item.Value)
+			}
+		}
+		if len(enumValues) == 0 {
+			return apperr.ErrConfigEnumDictEmpty.WithArgs(entity.DictCode)
+		}
+	}
+	return sysconfig.ValidateValue(entity.ValueType, entity.ConfigValue, enumValues)
+}
+
+// notifyChanged 通知所有副本重新加载系统配置，失败不影响本次保存
+func (s *ConfigServiceImpl) notifyChanged(ctx *gin.Context) {
+	if err := sysconfig.Default.Notify(ctx); err != nil {
+		log.Printf("通知系统配置变更失败: %v", err)
+	}
+}
+
+// sealConfig 密文配置在保存前加密
+func sealConfig(entity *models.ConfigModel) error {
+	if !entity.IsSecret {
+		return nil
+	}
+	box, err := secretBox()
+	if err != nil {
+		return err
+	}
+	entity.ConfigValue, err = box.Encrypt(entity.ConfigValue)
+	return err
+}
+
+// openConfigValue 读取配置的明文值
+func openConfigValue(entity *models.ConfigModel) (string, error) {
+	if !entity.IsSecret {
+		return entity.ConfigValue, nil
+	}
+	box, err := secretBox()
+	if err != nil {
+		return "", err
+	}
+	return box.Decrypt(entity.ConfigValue)
+}
+
+// openHistoryValue 读取历史版本的明文值
+func openHistoryValue(history *models.ConfigHistory) (string, error) {
+	return openConfigValue(&models.ConfigModel{ConfigValue: history.ConfigValue, IsSecret: history.IsSecret})
+}
+
+// maskConfig 密文配置在接口中以掩码返回
+func maskConfig(entity *models.ConfigModel) *models.ConfigModel {
+	if entity.IsSecret {
+		entity.ConfigValue = sysconfig.SecretMask
+	}
+	return entity
+}
+
+// newConfigHistory 以配置当前（已加密）的值生成历史记录
+func newConfigHistory(ctx *gin.Context, entity *models.ConfigModel, action, remark string) *models.ConfigHistory {
+	return &models.ConfigHistory{
+		ConfigID:    entity.ID,
+		Version:     entity.Version,
+		ConfigKey:   entity.ConfigKey,
+		ConfigValue: entity.ConfigValue,
+		ValueType:   entity.ValueType,
+		DictCode:    entity.DictCode,
+		IsSecret:    entity.IsSecret,
+		Action:      action,
+		Remark:      remark,
+		OperatorID:  operatorID(ctx),
+	}
 }
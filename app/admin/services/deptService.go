@@ -9,6 +9,7 @@ import (
 	"github.com/patrickmn/go-cache"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"gorm.io/gorm"
 )
 
@@ -42,16 +43,16 @@ func (s *DeptService) CreateDept(dept *models.Dept) error {
 // UpdateDept 更新部门信息
 func (s *DeptService) UpdateDept(ctx *gin.Context, dept *models.Dept) error {
 	if dept.ParentID == dept.ID {
-		return errors.New("上级部门不能是本部门")
+		return apperr.ErrDeptParentSelf
 	}
 	// 检查部门是否存在
 	if _, err := s.repo.GetDeptByID(dept.ID); err != nil {
-		return errors.New("部门不存在")
+		return apperr.ErrDeptNotFound.Wrap(err)
 	}
 	// 检查父部门是否存在（如果ParentID不为0）
 	if dept.ParentID != 0 {
 		if _, err := s.repo.GetDeptByID(dept.ParentID); err != nil {
-			return errors.New("指定的上级部门不存在")
+			return apperr.ErrDeptParentNotFound.Wrap(err)
 		}
 	}
 	if err := s.checkCircularReference(ctx, dept.ID, dept.ParentID); err != nil {
@@ -83,7 +84,7 @@ func (s *DeptService) checkCircularReference(ctx *gin.Context, deptID, newParent
 	currentParent := newParentID
 	for {
 		if currentParent == deptID {
-			return errors.New("修改会导致循环引用：指定的上级部门已经是本部门的子部门")
+			return apperr.ErrDeptCycle
 		}
 		if currentParent == 0 {
 			break
@@ -151,23 +152,23 @@ func contains(s, substr string) bool {
 func (s *DeptService) DeleteDept(ctx *gin.Context, id uint) error {
 	if _, err := s.repo.GetDeptByID(id); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("部门不存在")
+			return apperr.ErrDeptNotFound
 		}
-		return fmt.Errorf("查询部门失败: %v", err)
+		return fmt.Errorf("查询部门失败: %w", err)
 	}
 	depts, err := s.repo.ListDepts(ctx)
 	if err != nil {
-		return fmt.Errorf("查询子部门失败: %v", err)
+		return fmt.Errorf("查询子部门失败: %w", err)
 	}
 	for _, d := range depts {
 		if d.ParentID == id {
-			return errors.New("该部门下有子部门，请先删除或转移子部门")
+			return apperr.ErrDeptHasChildren
 		}
 	}
 	// 检查是否有用户关联（此处建议在repo层实现更优）
 	// ...如有UserRepository可调用...
 	if err := s.repo.DeleteDept(id); err != nil {
-		return fmt.Errorf("删除部门失败: %v", err)
+		return fmt.Errorf("删除部门失败: %w", err)
 	}
 	s.cache.Delete(deptCacheKey)
 	return nil
@@ -217,7 +218,7 @@ func BuildDeptTree(depts []models.Dept) ([]models.DeptV0, error) {
 
 func buildTreeFromMap(childMap map[uint][]models.Dept, parentID uint, depth int) ([]models.DeptV0, error) {
 	if depth > maxTreeDepth {
-		return nil, errors.New("部门层级过深，可能存在循环引用")
+		return nil, apperr.ErrDeptTooDeep
 	}
 
 	var result []models.DeptV0
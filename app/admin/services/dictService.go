@@ -1,10 +1,14 @@
 package services
 
 import (
-	"errors"
+	"context"
+	"strings"
 
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/dictbundle"
+	"github.com/zmqge/vireo-gin-admin/pkg/dictcache"
 )
 
 // DictService Dict服务接口
@@ -21,6 +25,13 @@ type DictService interface {
 	UpdateDictItem(item *models.DictItemModel) error
 	DeleteDictItem(id uint) error                                                // 使用uint类型
 	GetDictItemForm(dictCode string, itemId uint) (*models.DictItemModel, error) // 新增
+	GetDictOptions(ctx context.Context, dictCode string) (*dictcache.Entry, error)
+	GetDictOptionsBatch(ctx context.Context, dictCodes []string) (map[string][]dictcache.Option, string, error)
+	LoadDictOptions(ctx context.Context, dictCode string) ([]dictcache.Option, error)
+	ExportDicts(codes []string) (*dictbundle.Bundle, error)
+	ImportDicts(bundle *dictbundle.Bundle, dryRun bool) (*dictbundle.Plan, error)
+	GetDictItemI18n(dictCode string) ([]*models.DictItemI18n, error)
+	SaveDictItemI18n(ctx context.Context, dictCode string, list []*models.DictItemI18n) error
 }
 
 // DictServiceImpl Dict服务实现
@@ -40,7 +51,7 @@ func (s *DictServiceImpl) GetDictByID(id uint) (*models.DictModel, error) {
 		return nil, err
 	}
 	if entity == nil {
-		return nil, errors.New("Dict not found")
+		return nil, apperr.ErrDictNotFound
 	}
 	return entity, nil
 }
@@ -53,22 +64,50 @@ func (s *DictServiceImpl) ListDicts() ([]*models.DictModel, error) {
 // CreateDict 创建Dict
 func (s *DictServiceImpl) CreateDict(entity *models.DictModel) error {
 	if entity.Name == "" {
-		return errors.New("name is required")
+		return apperr.ErrValidation.WithField("name", "不能为空")
 	}
-	return s.repo.CreateDict(entity)
+	if err := s.repo.CreateDict(entity); err != nil {
+		return err
+	}
+	// 创建前按该编码查询过的请求可能已缓存结果
+	dictcache.Default.Invalidate(context.Background(), entity.DictCode)
+	return nil
 }
 
 // UpdateDict 更新Dict
 func (s *DictServiceImpl) UpdateDict(entity *models.DictModel) error {
 	if entity.Name == "" {
-		return errors.New("name is required")
+		return apperr.ErrValidation.WithField("name", "不能为空")
+	}
+	old, err := s.repo.GetDictByID(entity.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdateDict(entity); err != nil {
+		return err
+	}
+	// 字典编码可能被修改，新旧编码都需失效
+	codes := []string{entity.DictCode}
+	if old != nil {
+		codes = append(codes, old.DictCode)
 	}
-	return s.repo.UpdateDict(entity)
+	dictcache.Default.Invalidate(context.Background(), codes...)
+	return nil
 }
 
 // DeleteDict 删除Dict
 func (s *DictServiceImpl) DeleteDict(id uint) error {
-	return s.repo.DeleteDict(id)
+	old, err := s.repo.GetDictByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteDict(id); err != nil {
+		return err
+	}
+	if old != nil {
+		dictcache.Default.Invalidate(context.Background(), old.DictCode)
+	}
+	return nil
 }
 
 // PageDicts 分页查询
@@ -88,32 +127,58 @@ func (s *DictServiceImpl) PageDictItems(dictCode, keywords string, pageNum, page
 
 // CreateDictItem 新增字典项
 func (s *DictServiceImpl) CreateDictItem(item *models.DictItemModel) error {
-	return s.repo.CreateDictItem(item)
+	if err := s.repo.CreateDictItem(item); err != nil {
+		return err
+	}
+	dictcache.Default.Invalidate(context.Background(), item.DictCode)
+	return nil
 }
 
 // UpdateDictItem 更新字典项
 func (s *DictServiceImpl) UpdateDictItem(item *models.DictItemModel) error {
 	if item.ID == 0 {
-		return errors.New("ID is required")
+		return apperr.ErrInvalidID
 	}
 	if item.DictCode == "" {
-		return errors.New("dictCode is required")
+		return apperr.ErrValidation.WithField("dictCode", "不能为空")
 	}
 	if item.Value == "" {
-		return errors.New("value is required")
+		return apperr.ErrValidation.WithField("value", "不能为空")
 	}
 	if item.Label == "" {
-		return errors.New("label is required")
+		return apperr.ErrValidation.WithField("label", "不能为空")
 	}
-	return s.repo.UpdateDictItem(item)
+	old, err := s.repo.GetDictItemByID(item.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdateDictItem(item); err != nil {
+		return err
+	}
+	codes := []string{item.DictCode}
+	if old != nil {
+		codes = append(codes, old.DictCode)
+	}
+	dictcache.Default.Invalidate(context.Background(), codes...)
+	return nil
 }
 
 // 删除DictItem
 func (s *DictServiceImpl) DeleteDictItem(id uint) error {
 	if id == 0 {
-		return errors.New("ID is required")
+		return apperr.ErrInvalidID
 	}
-	return s.repo.DeleteDictItem(id)
+	old, err := s.repo.GetDictItemByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteDictItem(id); err != nil {
+		return err
+	}
+	if old != nil {
+		dictcache.Default.Invalidate(context.Background(), old.DictCode)
+	}
+	return nil
 }
 
 // GetDictItemForm 获取字典项表单数据
@@ -127,3 +192,217 @@ func (s *DictServiceImpl) GetDictItemForm(dictCode string, itemId uint) (*models
 	}
 	return item, nil
 }
+
+// GetDictOptions 获取下拉使用的字典项（走缓存）
+func (s *DictServiceImpl) GetDictOptions(ctx context.Context, dictCode string) (*dictcache.Entry, error) {
+	return dictcache.Default.Get(ctx, dictCode)
+}
+
+// maxDictBatchCodes 批量查询一次最多的字典编码数
+const maxDictBatchCodes = 50
+
+// GetDictOptionsBatch 批量获取多个字典编码的字典项（走缓存），同时返回整体 ETag
+// 编码去重后最多 maxDictBatchCodes 个，任一编码不存在时返回 ErrDictCodeNotFound
+func (s *DictServiceImpl) GetDictOptionsBatch(ctx context.Context, dictCodes []string) (map[string][]dictcache.Option, string, error) {
+	codes := dictcache.UniqueCodes(dictCodes)
+	if len(codes) > maxDictBatchCodes {
+		return nil, "", apperr.ErrDictBatchTooMany.WithArgs(maxDictBatchCodes)
+	}
+	return dictcache.Default.GetMany(ctx, codes)
+}
+
+// LoadDictOptions 从数据库加载字典项，作为字典缓存的加载函数
+// 字典不存在时返回错误而不是空列表，避免任意编码都被写入缓存
+func (s *DictServiceImpl) LoadDictOptions(ctx context.Context, dictCode string) ([]dictcache.Option, error) {
+	items, err := s.repo.GetDictItemsByCode(dictCode)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		dicts, err := s.repo.ListDictsByCodes([]string{dictCode})
+		if err != nil {
+			return nil, err
+		}
+		if len(dicts) == 0 {
+			return nil, apperr.ErrDictCodeNotFound.WithArgs(dictCode)
+		}
+	}
+	translations, err := s.repo.ListDictItemI18n(dictCode)
+	if err != nil {
+		return nil, err
+	}
+	labels := make(map[string]map[string]string)
+	for _, t := range translations {
+		if labels[t.Value] == nil {
+			labels[t.Value] = make(map[string]string)
+		}
+		labels[t.Value][t.Locale] = t.Label
+	}
+	options := make([]dictcache.Option, 0, len(items))
+	for _, item := range items {
+		options = append(options, dictcache.Option{
+			Value:   item.Value,
+			Label:   item.Label,
+			TagType: item.TagType,
+			Labels:  labels[item.Value],
+		})
+	}
+	return options, nil
+}
+
+// ExportDicts 导出字典及其字典项，codes 为空时导出全部
+func (s *DictServiceImpl) ExportDicts(codes []string) (*dictbundle.Bundle, error) {
+	dicts, items, err := s.loadDicts(codes)
+	if err != nil {
+		return nil, err
+	}
+	if len(dicts) == 0 {
+		return nil, apperr.ErrDictNothingToExport
+	}
+	bundle := &dictbundle.Bundle{Version: dictbundle.BundleVersion}
+	for _, d := range dicts {
+		bundle.Dicts = append(bundle.Dicts, toBundleDict(d, items[d.DictCode]))
+	}
+	return bundle, nil
+}
+
+// ImportDicts 导入字典包：按 DictCode / Value 新增或更新，不删除现有数据
+// dryRun 为 true 时只返回变更预览
+func (s *DictServiceImpl) ImportDicts(bundle *dictbundle.Bundle, dryRun bool) (*dictbundle.Plan, error) {
+	codes := bundle.Codes()
+	dicts, items, err := s.loadDicts(codes)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]dictbundle.Dict, len(dicts))
+	dictByCode := make(map[string]*models.DictModel, len(dicts))
+	for _, d := range dicts {
+		existing[d.DictCode] = toBundleDict(d, items[d.DictCode])
+		dictByCode[d.DictCode] = d
+	}
+	plan := dictbundle.BuildPlan(bundle, existing)
+	if dryRun || !plan.HasChanges() {
+		return plan, nil
+	}
+
+	var saveDicts []*models.DictModel
+	var saveItems []*models.DictItemModel
+	for _, d := range bundle.Dicts {
+		model, ok := dictByCode[d.DictCode]
+		if !ok {
+			model = &models.DictModel{DictCode: d.DictCode}
+		}
+		old := existing[d.DictCode]
+		if !ok || old.Name != d.Name || old.Status != d.Status || old.Remark != d.Remark || old.Sort != d.Sort {
+			model.Name, model.Status, model.Remark, model.Sort = d.Name, d.Status, d.Remark, d.Sort
+			saveDicts = append(saveDicts, model)
+		}
+
+		itemByValue := make(map[string]*models.DictItemModel, len(items[d.DictCode]))
+		for _, item := range items[d.DictCode] {
+			itemByValue[item.Value] = item
+		}
+		for _, item := range d.Items {
+			model, ok := itemByValue[item.Value]
+			if ok && model.Label == item.Label && model.TagType == item.TagType && model.Status == item.Status && model.Sort == item.Sort {
+				continue
+			}
+			if !ok {
+				model = &models.DictItemModel{DictCode: d.DictCode, Value: item.Value}
+			}
+			model.Label, model.TagType, model.Status, model.Sort = item.Label, item.TagType, item.Status, item.Sort
+			saveItems = append(saveItems, model)
+		}
+	}
+	if err := s.repo.SaveDictsAndItems(saveDicts, saveItems); err != nil {
+		return nil, err
+	}
+	dictcache.Default.Invalidate(context.Background(), codes...)
+	return plan, nil
+}
+
+// loadDicts 查询字典及按编码分组的字典项
+func (s *DictServiceImpl) loadDicts(codes []string) ([]*models.DictModel, map[string][]*models.DictItemModel, error) {
+	dicts, err := s.repo.ListDictsByCodes(codes)
+	if err != nil {
+		return nil, nil, err
+	}
+	dictCodes := make([]string, 0, len(dicts))
+	for _, d := range dicts {
+		dictCodes = append(dictCodes, d.DictCode)
+	}
+	list, err := s.repo.ListDictItemsByCodes(dictCodes)
+	if err != nil {
+		return nil, nil, err
+	}
+	items := make(map[string][]*models.DictItemModel, len(dicts))
+	for _, item := range list {
+		items[item.DictCode] = append(items[item.DictCode], item)
+	}
+	return dicts, items, nil
+}
+
+func toBundleDict(d *models.DictModel, items []*models.DictItemModel) dictbundle.Dict {
+	dict := dictbundle.Dict{
+		DictCode: d.DictCode,
+		Name:     d.Name,
+		Status:   d.Status,
+		Remark:   d.Remark,
+		Sort:     d.Sort,
+		Items:    make([]dictbundle.Item, 0, len(items)),
+	}
+	for _, item := range items {
+		dict.Items = append(dict.Items, dictbundle.Item{
+			Value:   item.Value,
+			Label:   item.Label,
+			TagType: item.TagType,
+			Status:  item.Status,
+			Sort:    item.Sort,
+		})
+	}
+	return dict
+}
+
+// GetDictItemI18n 获取字典编码下字典项标签的翻译
+func (s *DictServiceImpl) GetDictItemI18n(dictCode string) ([]*models.DictItemI18n, error) {
+	return s.repo.ListDictItemI18n(dictCode)
+}
+
+// SaveDictItemI18n 整体替换字典编码下字典项标签的翻译，标签为空的条目视为删除
+// 翻译按值关联，删除字典项后保留的翻译在同值的字典项重新创建时仍然生效
+func (s *DictServiceImpl) SaveDictItemI18n(ctx context.Context, dictCode string, list []*models.DictItemI18n) error {
+	items, err := s.repo.GetDictItemsByCode(dictCode)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]bool, len(items))
+	for _, item := range items {
+		values[item.Value] = true
+	}
+	seen := make(map[string]bool, len(list))
+	result := make([]*models.DictItemI18n, 0, len(list))
+	for _, t := range list {
+		label := strings.TrimSpace(t.Label)
+		if label == "" {
+			continue
+		}
+		if !values[t.Value] {
+			return apperr.ErrDictItemValueNotFound.WithArgs(dictCode, t.Value)
+		}
+		locale, err := translationLocale(t.Locale)
+		if err != nil {
+			return err
+		}
+		key := t.Value + "|" + locale
+		if seen[key] {
+			return apperr.ErrDictTranslationDuplicate.WithArgs(t.Value, locale)
+		}
+		seen[key] = true
+		result = append(result, &models.DictItemI18n{DictCode: dictCode, Value: t.Value, Locale: locale, Label: label})
+	}
+	if err := s.repo.ReplaceDictItemI18n(dictCode, result); err != nil {
+		return err
+	}
+	dictcache.Default.Invalidate(ctx, dictCode)
+	return nil
+}
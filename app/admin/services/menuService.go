@@ -1,8 +1,14 @@
 package services
 
 import (
+	"fmt"
+	"log"
+	"strings"
+
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/i18n"
 	"gorm.io/gorm"
 )
 
@@ -15,8 +21,8 @@ func NewMenuService(db *gorm.DB) *MenuService {
 	return &MenuService{db: db, repo: repositories.NewMenuRepository(db)}
 }
 
-// GetCurrentUserRoutes 获取当前用户的路由列表（分级结构）
-func (s *MenuService) GetCurrentUserRoutes(userID string) ([]models.RouteVO, error) {
+// GetCurrentUserRoutes 获取当前用户的路由列表（分级结构），标题使用 locale 对应的翻译
+func (s *MenuService) GetCurrentUserRoutes(userID, locale string) ([]models.RouteVO, error) {
 	roleIDs, err := s.repo.ListUserRoleIDs(userID)
 	if err != nil {
 		return nil, err
@@ -25,6 +31,7 @@ func (s *MenuService) GetCurrentUserRoutes(userID string) ([]models.RouteVO, err
 	if err != nil {
 		return nil, err
 	}
+	s.localizeTitles(menus, locale)
 	return s.buildRouteTree(menus, 0), nil
 }
 
@@ -185,11 +192,12 @@ func buildMenuOptions(menus []models.Menu, parentID uint) []models.OptionLong {
 	return options
 }
 
-func (s *MenuService) GetMenuOptions(onlyParent bool) ([]models.OptionLong, error) {
+func (s *MenuService) GetMenuOptions(onlyParent bool, locale string) ([]models.OptionLong, error) {
 	menus, err := s.repo.ListMenuOptions()
 	if err != nil {
 		return nil, err
 	}
+	s.localizeTitles(menus, locale)
 	return buildMenuOptions(menus, 0), nil
 }
 
@@ -212,3 +220,67 @@ func (s *MenuService) UpdateMenu(menu *models.Menu) error {
 func (s *MenuService) DeleteMenu(id string) error {
 	return s.repo.DeleteMenu(id)
 }
+
+// localizeTitles 将菜单标题替换为指定语言的翻译，缺少翻译的菜单保留默认语言的标题
+func (s *MenuService) localizeTitles(menus []models.Menu, locale string) {
+	if i18n.IsDefault(locale) || len(menus) == 0 {
+		return
+	}
+	titles, err := s.repo.ListMenuTitles(locale)
+	if err != nil {
+		log.Printf("[MenuService] 查询菜单翻译失败: %v", err)
+		return
+	}
+	for i := range menus {
+		if title := titles[menus[i].ID]; title != "" {
+			menus[i].Title = title
+		}
+	}
+}
+
+// GetMenuI18n 获取菜单标题的翻译（语言 -> 标题）
+func (s *MenuService) GetMenuI18n(menuID uint) (map[string]string, error) {
+	list, err := s.repo.ListMenuI18n(menuID)
+	if err != nil {
+		return nil, err
+	}
+	titles := make(map[string]string, len(list))
+	for _, t := range list {
+		titles[t.Locale] = t.Title
+	}
+	return titles, nil
+}
+
+// SaveMenuI18n 保存菜单标题的翻译（语言 -> 标题），整体替换，标题为空的语言视为删除
+func (s *MenuService) SaveMenuI18n(menuID uint, titles map[string]string) error {
+	if _, err := s.repo.GetMenuByID(fmt.Sprint(menuID)); err != nil {
+		return apperr.ErrMenuNotFound
+	}
+	list := make([]models.MenuI18n, 0, len(titles))
+	for locale, title := range titles {
+		title = strings.TrimSpace(title)
+		if title == "" {
+			continue
+		}
+		locale, err := translationLocale(locale)
+		if err != nil {
+			return err
+		}
+		list = append(list, models.MenuI18n{MenuID: menuID, Locale: locale, Title: title})
+	}
+	return s.repo.ReplaceMenuI18n(menuID, list)
+}
+
+// translationLocale 校验翻译的语言并返回支持列表中的写法
+// 语言必须在支持列表中，且不能是默认语言（默认语言的文本保存在原表）
+func translationLocale(locale string) (string, error) {
+	if i18n.IsDefault(locale) {
+		return "", apperr.ErrDefaultLocaleText.WithArgs(locale)
+	}
+	for _, l := range i18n.Supported() {
+		if strings.EqualFold(l, locale) {
+			return l, nil
+		}
+	}
+	return "", apperr.ErrUnsupportedLocale.WithArgs(locale)
+}
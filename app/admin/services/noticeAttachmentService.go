@@ -0,0 +1,257 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/config"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/storage"
+	"gorm.io/gorm"
+)
+
+// noticeAttachmentPath 附件下载地址（不含签名），正文中统一保存为该形式
+const noticeAttachmentPath = "/api/v1/notices/attachments/%d/download"
+
+// attachmentURLPattern 匹配正文中的附件地址（可能带有旧的签名参数，HTML 中 & 可能被转义为 &amp;）
+var attachmentURLPattern = regexp.MustCompile(`/api/v1/notices/attachments/(\d+)/download(?:\?[^"'\s)<>]*)?`)
+
+var imageExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true}
+
+// ErrInvalidSignature 下载链接无效或已过期
+var ErrInvalidSignature = apperr.ErrAttachmentLinkInvalid
+
+// NoticeAttachmentService 通知附件服务
+type NoticeAttachmentService interface {
+	Upload(ctx *gin.Context, file *multipart.FileHeader, inline bool) (*models.NoticeAttachment, error)
+	Open(ctx *gin.Context, id uint, expires int64, sign string) (*models.NoticeAttachment, io.ReadSeekCloser, error)
+	SyncNoticeAttachmentsTx(ctx *gin.Context, tx *gorm.DB, notice *models.NoticesModel, keepExisting bool) ([]models.NoticeAttachment, error)
+	DeleteFiles(list []models.NoticeAttachment)
+	FillAttachments(ctx *gin.Context, notice *models.NoticesModel) error
+	NormalizeURLs(content string) string
+	SignURLs(content string) string
+}
+
+// NoticeAttachmentServiceImpl 通知附件服务实现
+type NoticeAttachmentServiceImpl struct {
+	repo        repositories.NoticeAttachmentRepository
+	storage     storage.Storage
+	allowedExts map[string]bool
+	urlTTL      time.Duration
+	secret      []byte
+}
+
+// NewNoticeAttachmentService 创建通知附件服务，存储目录、大小和类型限制取自配置
+func NewNoticeAttachmentService(repo repositories.NoticeAttachmentRepository) NoticeAttachmentService {
+	cfg := config.App.Upload
+	dir := cfg.Dir
+	if dir == "" {
+		dir = "uploads"
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(config.ProjectRoot(), dir)
+	}
+	maxSize := cfg.MaxSizeMB
+	if maxSize <= 0 {
+		maxSize = 20
+	}
+	secret := cfg.SignSecret
+	if secret == "" {
+		secret = config.App.JWT.AccessSecret
+	}
+	s := &NoticeAttachmentServiceImpl{
+		repo:        repo,
+		storage:     storage.NewLocalStorage(dir, maxSize<<20),
+		allowedExts: make(map[string]bool, len(cfg.AllowedExts)),
+		urlTTL:      cfg.URLTTL,
+		secret:      []byte(secret),
+	}
+	for _, ext := range cfg.AllowedExts {
+		s.allowedExts[strings.ToLower(ext)] = true
+	}
+	if s.urlTTL <= 0 {
+		s.urlTTL = time.Hour
+	}
+	return s
+}
+
+// Upload 上传附件，inline 为 true 时只允许图片（用于正文内嵌）
+func (s *NoticeAttachmentServiceImpl) Upload(ctx *gin.Context, file *multipart.FileHeader, inline bool) (*models.NoticeAttachment, error) {
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if !s.allowedExts[ext] {
+		return nil, apperr.ErrAttachmentTypeNotAllowed.WithArgs(ext)
+	}
+	if inline && !imageExts[ext] {
+		return nil, apperr.ErrAttachmentImageOnly
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	key, size, err := s.storage.Save(src, ext)
+	if err != nil {
+		var tooLarge *storage.ErrTooLarge
+		if errors.As(err, &tooLarge) {
+			return nil, apperr.ErrAttachmentTooLarge.WithArgs(tooLarge.Max >> 20)
+		}
+		return nil, err
+	}
+	mimeType := mime.TypeByExtension(ext)
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	entity := &models.NoticeAttachment{
+		FileName:   filepath.Base(file.Filename),
+		StorageKey: key,
+		Size:       size,
+		MimeType:   mimeType,
+		Inline:     inline,
+		UploaderID: operatorID(ctx),
+	}
+	if err := s.repo.CreateAttachment(ctx, entity); err != nil {
+		s.storage.Delete(key)
+		return nil, err
+	}
+	entity.URL = s.signedURL(entity.ID)
+	return entity, nil
+}
+
+// Open 校验签名后打开附件
+func (s *NoticeAttachmentServiceImpl) Open(ctx *gin.Context, id uint, expires int64, sign string) (*models.NoticeAttachment, io.ReadSeekCloser, error) {
+	if time.Now().Unix() > expires || !hmac.Equal([]byte(sign), []byte(s.sign(id, expires))) {
+		return nil, nil, ErrInvalidSignature
+	}
+	entity, err := s.repo.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if entity == nil {
+		return nil, nil, apperr.ErrAttachmentNotFound
+	}
+	f, err := s.storage.Open(entity.StorageKey)
+	if err != nil {
+		return nil, nil, apperr.ErrAttachmentFileMissing.Wrap(err)
+	}
+	return entity, f, nil
+}
+
+// SyncNoticeAttachmentsTx 在保存通知的事务内同步附件：绑定提交的附件和正文中引用的图片，删除被移除附件的记录
+// keepExisting 为 true 时（编辑时未提交附件列表）保留原有的非内嵌附件
+// 返回被移除的附件，事务提交后再调用 DeleteFiles 删除文件，回滚时文件仍可用
+func (s *NoticeAttachmentServiceImpl) SyncNoticeAttachmentsTx(ctx *gin.Context, tx *gorm.DB, notice *models.NoticesModel, keepExisting bool) ([]models.NoticeAttachment, error) {
+	ids := append([]uint{}, notice.AttachmentIDs...)
+	ids = append(ids, referencedAttachmentIDs(notice.Content)...)
+	if keepExisting {
+		existing, err := s.repo.ListByNoticeTx(tx, notice.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range existing {
+			if !a.Inline {
+				ids = append(ids, a.ID)
+			}
+		}
+	}
+	if err := s.repo.BindToNoticeTx(tx, notice.ID, ids, operatorID(ctx)); err != nil {
+		return nil, fmt.Errorf("绑定附件失败: %w", err)
+	}
+
+	removed, err := s.repo.ListUnboundTx(tx, notice.ID, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	removedIDs := make([]uint, 0, len(removed))
+	for _, a := range removed {
+		removedIDs = append(removedIDs, a.ID)
+	}
+	if err := s.repo.DeleteByIDsTx(tx, removedIDs); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// DeleteFiles 删除附件文件，失败只记录日志
+func (s *NoticeAttachmentServiceImpl) DeleteFiles(list []models.NoticeAttachment) {
+	for _, a := range list {
+		if err := s.storage.Delete(a.StorageKey); err != nil {
+			log.Printf("删除附件文件失败（%s）：%v", a.StorageKey, err)
+		}
+	}
+}
+
+// FillAttachments 填充通知的附件列表并为正文和附件生成带签名的下载地址
+func (s *NoticeAttachmentServiceImpl) FillAttachments(ctx *gin.Context, notice *models.NoticesModel) error {
+	list, err := s.repo.ListByNotice(ctx, notice.ID)
+	if err != nil {
+		return err
+	}
+	notice.Attachments = make([]models.NoticeAttachment, 0, len(list))
+	for _, a := range list {
+		if a.Inline {
+			continue
+		}
+		a.URL = s.signedURL(a.ID)
+		notice.Attachments = append(notice.Attachments, a)
+	}
+	notice.Content = s.SignURLs(notice.Content)
+	notice.ContentHTML = s.SignURLs(notice.ContentHTML)
+	return nil
+}
+
+// NormalizeURLs 去掉正文中附件地址的签名参数，保存为稳定的地址
+func (s *NoticeAttachmentServiceImpl) NormalizeURLs(content string) string {
+	return attachmentURLPattern.ReplaceAllStringFunc(content, func(m string) string {
+		id, _ := strconv.ParseUint(attachmentURLPattern.FindStringSubmatch(m)[1], 10, 64)
+		return fmt.Sprintf(noticeAttachmentPath, id)
+	})
+}
+
+// SignURLs 为正文中的附件地址加上签名（<img> 无法携带 Authorization 头）
+func (s *NoticeAttachmentServiceImpl) SignURLs(content string) string {
+	return attachmentURLPattern.ReplaceAllStringFunc(content, func(m string) string {
+		id, _ := strconv.ParseUint(attachmentURLPattern.FindStringSubmatch(m)[1], 10, 64)
+		return s.signedURL(uint(id))
+	})
+}
+
+func (s *NoticeAttachmentServiceImpl) signedURL(id uint) string {
+	expires := time.Now().Add(s.urlTTL).Unix()
+	return fmt.Sprintf(noticeAttachmentPath+"?expires=%d&sign=%s", id, expires, s.sign(id, expires))
+}
+
+func (s *NoticeAttachmentServiceImpl) sign(id uint, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// referencedAttachmentIDs 正文中引用的附件ID
+func referencedAttachmentIDs(content string) []uint {
+	var ids []uint
+	for _, m := range attachmentURLPattern.FindAllStringSubmatch(content, -1) {
+		if id, err := strconv.ParseUint(m[1], 10, 64); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/config"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/notify"
+	"gorm.io/gorm"
+)
+
+const (
+	deliveryBatchSize   = 100
+	deliverySendTimeout = 30 * time.Second
+	deliveryStaleAfter  = 10 * time.Minute // 发送中超过该时长视为进程中断，重新入队
+	deliveryErrorMaxLen = 500
+)
+
+// NoticeDeliveryService 通知外部渠道投递服务（邮件/短信/Webhook）
+type NoticeDeliveryService interface {
+	EnqueueTx(tx *gorm.DB, notice *models.NoticesModel, userIDs []uint) error
+	RequeueTx(tx *gorm.DB, notice *models.NoticesModel, userIDs []uint) error
+	ProcessDue(ctx context.Context, now time.Time) (int, error)
+	ListDeliveries(ctx *gin.Context, noticeID uint) ([]*models.NoticeDelivery, error)
+	GetPreference(ctx *gin.Context, userID uint) (*models.UserNotifyPreference, error)
+	SavePreference(ctx *gin.Context, pref *models.UserNotifyPreference) error
+}
+
+// NoticeDeliveryServiceImpl 通知外部渠道投递服务实现
+type NoticeDeliveryServiceImpl struct {
+	repo           repositories.NoticeDeliveryRepository
+	registry       *notify.Registry
+	levelChannels  map[string][]string
+	defaultWebhook string
+	maxAttempts    int
+	backoff        time.Duration
+}
+
+// NewNoticeDeliveryService 创建通知投递服务，渠道和重试策略取自配置
+func NewNoticeDeliveryService(repo repositories.NoticeDeliveryRepository, registry *notify.Registry) NoticeDeliveryService {
+	cfg := config.App.Notify
+	s := &NoticeDeliveryServiceImpl{
+		repo:           repo,
+		registry:       registry,
+		levelChannels:  cfg.LevelChannels,
+		defaultWebhook: cfg.Webhook.URL,
+		maxAttempts:    cfg.MaxAttempts,
+		backoff:        cfg.RetryBackoff,
+	}
+	if s.maxAttempts <= 0 {
+		s.maxAttempts = 5
+	}
+	if s.backoff <= 0 {
+		s.backoff = time.Minute
+	}
+	return s
+}
+
+// NewNotifyRegistry 根据配置启用渠道：邮件需配置 SMTP 地址，短信需配置网关地址，Webhook 始终可用（可使用用户自己的地址）
+func NewNotifyRegistry() *notify.Registry {
+	cfg := config.App.Notify
+	var channels []notify.Channel
+	if cfg.SMTP.Host != "" {
+		channels = append(channels, notify.NewEmailChannel(notify.EmailConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		}))
+	}
+	if cfg.SMS.URL != "" {
+		channels = append(channels, notify.NewSMSChannel(notify.SMSConfig{
+			URL:    cfg.SMS.URL,
+			APIKey: cfg.SMS.APIKey,
+			Sign:   cfg.SMS.Sign,
+		}))
+	}
+	channels = append(channels, notify.NewWebhookChannel(notify.WebhookConfig{
+		URL:    cfg.Webhook.URL,
+		Secret: cfg.Webhook.Secret,
+	}))
+	return notify.NewRegistry(channels...)
+}
+
+// EnqueueTx 按通知级别和用户偏好生成投递记录，与发布在同一事务内
+func (s *NoticeDeliveryServiceImpl) EnqueueTx(tx *gorm.DB, notice *models.NoticesModel, userIDs []uint) error {
+	var channels []string
+	for _, ch := range notify.ChannelsForLevel(s.levelChannels, notice.Level) {
+		if s.registry.Enabled(ch) {
+			channels = append(channels, ch)
+		}
+	}
+	if len(channels) == 0 || len(userIDs) == 0 {
+		return nil
+	}
+
+	recipients, err := s.repo.ListNotifyRecipientsTx(tx, userIDs)
+	if err != nil {
+		return err
+	}
+	prefs, err := s.repo.MapPreferencesTx(tx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []models.NoticeDelivery
+	for _, u := range recipients {
+		pref := prefs[u.UserID]
+		if pref == nil {
+			pref = models.DefaultNotifyPreference(u.UserID)
+		}
+		for _, ch := range channels {
+			if !pref.Allows(ch) || !s.hasAddress(ch, u, pref) {
+				continue
+			}
+			deliveries = append(deliveries, models.NoticeDelivery{
+				NoticeID:    notice.ID,
+				UserID:      u.UserID,
+				Channel:     ch,
+				Status:      models.DeliveryStatusPending,
+				NextRetryAt: now,
+			})
+		}
+	}
+	return s.repo.CreateDeliveriesTx(tx, deliveries)
+}
+
+// RequeueTx 再次投递给指定用户：已结束的记录重新入队，缺失的记录（如用户新开启了渠道）补充生成
+func (s *NoticeDeliveryServiceImpl) RequeueTx(tx *gorm.DB, notice *models.NoticesModel, userIDs []uint) error {
+	if err := s.repo.ResetDeliveriesTx(tx, notice.ID, userIDs); err != nil {
+		return err
+	}
+	return s.EnqueueTx(tx, notice, userIDs)
+}
+
+// hasAddress 接收人是否具备该渠道的地址，缺少地址的不入队
+func (s *NoticeDeliveryServiceImpl) hasAddress(channel string, u models.NotifyRecipient, pref *models.UserNotifyPreference) bool {
+	switch channel {
+	case notify.ChannelEmail:
+		return u.Email != ""
+	case notify.ChannelSMS:
+		return u.Mobile != ""
+	case notify.ChannelWebhook:
+		return pref.WebhookURL != "" || s.defaultWebhook != ""
+	}
+	return false
+}
+
+// ProcessDue 发送到期的投递记录，返回本次处理的条数
+func (s *NoticeDeliveryServiceImpl) ProcessDue(ctx context.Context, now time.Time) (int, error) {
+	list, err := s.repo.ListDueDeliveries(ctx, now, now.Add(-deliveryStaleAfter), deliveryBatchSize)
+	if err != nil || len(list) == 0 {
+		return 0, err
+	}
+
+	var noticeIDs, userIDs []uint
+	for _, d := range list {
+		noticeIDs = append(noticeIDs, d.NoticeID)
+		userIDs = append(userIDs, d.UserID)
+	}
+	notices, err := s.repo.MapNotices(ctx, noticeIDs)
+	if err != nil {
+		return 0, err
+	}
+	recipients, err := s.repo.MapNotifyRecipients(ctx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+	prefs, err := s.repo.MapPreferences(ctx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, d := range list {
+		ok, err := s.repo.ClaimDelivery(ctx, d)
+		if err != nil {
+			return processed, err
+		}
+		if !ok {
+			continue // 已被其他实例处理
+		}
+		d.Attempts++
+		processed++
+		sendErr := s.send(ctx, d, notices[d.NoticeID], recipients[d.UserID], prefs[d.UserID])
+		if err := s.repo.UpdateDelivery(ctx, d.ID, s.resultFields(d, sendErr)); err != nil {
+			log.Printf("[NoticeDelivery] 更新投递结果失败（ID：%d）：%v", d.ID, err)
+		}
+	}
+	return processed, nil
+}
+
+// errNoticeUnavailable 通知已撤回或删除，不再投递
+var errNoticeUnavailable = errors.New("通知已撤回或删除")
+
+func (s *NoticeDeliveryServiceImpl) send(ctx context.Context, d *models.NoticeDelivery, notice *models.NoticesModel,
+	u models.NotifyRecipient, pref *models.UserNotifyPreference) error {
+	if notice == nil || notice.Status != models.NoticeStatusPublished {
+		return errNoticeUnavailable
+	}
+	ch, err := s.registry.Get(d.Channel)
+	if err != nil {
+		return err
+	}
+	to := notify.Recipient{UserID: d.UserID, Nickname: u.Nickname, Email: u.Email, Mobile: u.Mobile}
+	if pref != nil {
+		to.WebhookURL = pref.WebhookURL
+		to.WebhookSecret = pref.WebhookSecret
+	}
+
+	content := notice.HTML()
+	if d.Content != nil {
+		content = *d.Content
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, deliverySendTimeout)
+	defer cancel()
+	return ch.Send(sendCtx, notify.Message{
+		NoticeID: notice.ID,
+		Title:    notice.Title,
+		Content:  content,
+		Level:    notice.Level,
+		To:       to,
+	})
+}
+
+// resultFields 根据发送结果计算状态：成功、可重试（指数退避）或最终失败
+func (s *NoticeDeliveryServiceImpl) resultFields(d *models.NoticeDelivery, sendErr error) map[string]interface{} {
+	now := time.Now()
+	if sendErr == nil {
+		return map[string]interface{}{
+			"status":     models.DeliveryStatusSuccess,
+			"last_error": "",
+			"sent_at":    now,
+		}
+	}
+	msg := sendErr.Error()
+	if len(msg) > deliveryErrorMaxLen {
+		msg = msg[:deliveryErrorMaxLen]
+	}
+	fields := map[string]interface{}{"last_error": msg}
+	retryable := !errors.Is(sendErr, notify.ErrNoAddress) && !errors.Is(sendErr, notify.ErrForbiddenAddress) &&
+		!errors.Is(sendErr, errNoticeUnavailable)
+	if retryable && d.Attempts < s.maxAttempts {
+		fields["status"] = models.DeliveryStatusPending
+		fields["next_retry_at"] = now.Add(notify.Backoff(s.backoff, d.Attempts))
+	} else {
+		fields["status"] = models.DeliveryStatusFailed
+	}
+	return fields
+}
+
+// ListDeliveries 查询通知的投递记录
+func (s *NoticeDeliveryServiceImpl) ListDeliveries(ctx *gin.Context, noticeID uint) ([]*models.NoticeDelivery, error) {
+	return s.repo.ListDeliveries(ctx, noticeID)
+}
+
+// GetPreference 查询用户渠道偏好，未设置时返回默认值
+func (s *NoticeDeliveryServiceImpl) GetPreference(ctx *gin.Context, userID uint) (*models.UserNotifyPreference, error) {
+	pref, err := s.repo.GetPreference(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if pref == nil {
+		pref = models.DefaultNotifyPreference(userID)
+	}
+	return pref, nil
+}
+
+// SavePreference 保存用户渠道偏好
+// 自定义 Webhook 地址只允许公网地址；地址变更时生成新的签名密钥，用户以该密钥校验签名
+func (s *NoticeDeliveryServiceImpl) SavePreference(ctx *gin.Context, pref *models.UserNotifyPreference) error {
+	pref.WebhookSecret = ""
+	if pref.WebhookURL != "" {
+		if err := notify.ValidateWebhookURL(ctx, pref.WebhookURL); err != nil {
+			return apperr.ErrWebhookURLInvalid.WithArgs(pref.WebhookURL)
+		}
+		existing, err := s.repo.GetPreference(ctx, pref.UserID)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.WebhookURL == pref.WebhookURL && existing.WebhookSecret != "" {
+			pref.WebhookSecret = existing.WebhookSecret
+		} else if pref.WebhookSecret, err = notify.NewWebhookSecret(); err != nil {
+			return err
+		}
+	}
+	return s.repo.SavePreference(ctx, pref)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/zmqge/vireo-gin-admin/pkg/redis"
+)
+
+const noticeDeliveryLockKey = "notice:delivery:lock"
+
+// NoticeDeliveryWorker 通知投递任务：扫描投递队列，发送邮件/短信/Webhook 并按退避策略重试
+// 与 NoticeScheduler 相同，队列保存在数据库中，多副本时通过 Redis 锁避免并发扫描
+type NoticeDeliveryWorker struct {
+	service  NoticeDeliveryService
+	interval time.Duration
+}
+
+// NewNoticeDeliveryWorker 创建通知投递任务
+func NewNoticeDeliveryWorker(service NoticeDeliveryService, interval time.Duration) *NoticeDeliveryWorker {
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	return &NoticeDeliveryWorker{service: service, interval: interval}
+}
+
+// Run 启动投递任务，ctx 取消时退出
+func (w *NoticeDeliveryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.tick(ctx)
+		}
+	}
+}
+
+func (w *NoticeDeliveryWorker) tick(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[NoticeDelivery] 执行异常: %v", r)
+		}
+	}()
+
+	// 单批发送可能较慢，锁的有效期覆盖一批的最长耗时
+	lock, err := redis.TryLock(ctx, noticeDeliveryLockKey, deliveryStaleAfter)
+	if err != nil {
+		log.Printf("[NoticeDelivery] 获取锁失败: %v", err)
+		return
+	}
+	if lock == nil {
+		return
+	}
+	defer lock.Unlock(context.Background())
+
+	// 队列积压时连续处理，直到本批不足一页
+	for {
+		n, err := w.service.ProcessDue(ctx, time.Now())
+		if err != nil {
+			log.Printf("[NoticeDelivery] 处理投递队列失败: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("[NoticeDelivery] 处理投递 %d 条", n)
+		}
+		if n < deliveryBatchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/push"
+	"github.com/zmqge/vireo-gin-admin/pkg/redis"
+)
+
+const (
+	noticeRemindKeyPrefix = "notice:remind:"
+	noticeRemindCooldown  = 10 * time.Minute // 同一通知两次提醒的最小间隔，避免打扰
+)
+
+// readRate 计算已读率（百分比，保留两位小数）
+func readRate(read, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(read)*10000/float64(total)) / 100
+}
+
+// loadVisibleNotice 按数据权限获取通知
+func (s *NoticesServiceImpl) loadVisibleNotice(ctx *gin.Context, id uint) (*models.NoticesModel, error) {
+	entity, err := s.repo.GetNoticesByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+		return nil, apperr.ErrNoticeNotFound
+	}
+	return entity, nil
+}
+
+// GetNoticeReadStats 通知阅读回执统计：总数、已读/未读、阅读趋势和部门分布
+func (s *NoticesServiceImpl) GetNoticeReadStats(ctx *gin.Context, id uint, granularity string) (*models.NoticeReadStats, error) {
+	if granularity == "" {
+		granularity = models.ReadStatsGranularityHour
+	}
+	if granularity != models.ReadStatsGranularityHour && granularity != models.ReadStatsGranularityDay {
+		return nil, apperr.ErrNoticeInvalidGranularity.WithArgs(granularity)
+	}
+	if _, err := s.loadVisibleNotice(ctx, id); err != nil {
+		return nil, err
+	}
+
+	total, read, err := s.repo.CountReceivers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("统计接收人数失败: %w", err)
+	}
+	timeline, err := s.repo.ListReadTimeline(ctx, id, granularity)
+	if err != nil {
+		return nil, fmt.Errorf("统计阅读趋势失败: %w", err)
+	}
+	depts, err := s.repo.ListDeptReadStats(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("统计部门阅读情况失败: %w", err)
+	}
+
+	var cumulative int64
+	for i := range timeline {
+		cumulative += timeline[i].Count
+		timeline[i].Total = cumulative
+		timeline[i].ReadRate = readRate(cumulative, total)
+	}
+	for i := range depts {
+		depts[i].Unread = depts[i].Total - depts[i].Read
+		depts[i].ReadRate = readRate(depts[i].Read, depts[i].Total)
+	}
+	if timeline == nil {
+		timeline = []models.NoticeReadPoint{}
+	}
+	if depts == nil {
+		depts = []models.NoticeDeptReadStat{}
+	}
+	return &models.NoticeReadStats{
+		NoticeID: id,
+		Total:    total,
+		Read:     read,
+		Unread:   total - read,
+		ReadRate: readRate(read, total),
+		Timeline: timeline,
+		Depts:    depts,
+	}, nil
+}
+
+// PageNoticeUnreadUsers 分页查询通知的未读用户
+func (s *NoticesServiceImpl) PageNoticeUnreadUsers(ctx *gin.Context, id uint, deptID uint, keywords string, pageNum, pageSize int) ([]models.NoticeUnreadUser, int64, error) {
+	if _, err := s.loadVisibleNotice(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.PageUnreadUsers(ctx, id, deptID, keywords, pageNum, pageSize)
+}
+
+// RemindUnread 再次提醒未读用户：站内实时推送，并按级别重新投递邮件/短信/Webhook
+// 返回提醒的人数
+func (s *NoticesServiceImpl) RemindUnread(ctx *gin.Context, id uint) (int, error) {
+	entity, err := s.loadVisibleNotice(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if entity.Status != models.NoticeStatusPublished {
+		return 0, apperr.ErrNoticeRemindNotAllowed.WithArgs(noticeStatusText(entity.Status))
+	}
+	userIDs, err := s.repo.ListUnreadUserIDs(ctx, id)
+	if err != nil {
+		return 0, fmt.Errorf("查询未读用户失败: %w", err)
+	}
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	// 冷却期内不允许重复提醒（Redis 不可用时不限制）
+	lock, err := redis.TryLock(ctx, fmt.Sprintf("%s%d", noticeRemindKeyPrefix, id), noticeRemindCooldown)
+	if err == nil && lock == nil {
+		return 0, apperr.ErrNoticeRemindTooFrequent.WithArgs(int(noticeRemindCooldown.Minutes()))
+	}
+
+	tx := s.repo.BeginTx(ctx)
+	if tx.Error != nil {
+		return 0, fmt.Errorf("开启事务失败: %w", tx.Error)
+	}
+	if err := s.delivery.RequeueTx(tx, entity, userIDs); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf("生成投递任务失败: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return 0, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	data := noticePushData(entity)
+	data["publishTime"] = entity.PublishedAt
+	data["remind"] = true
+	if err := push.Publish(ctx, push.EventNotice, userIDs, data); err != nil {
+		log.Printf("推送提醒失败（通知ID：%d）：%v", entity.ID, err)
+	}
+	return len(userIDs), nil
+}
@@ -1,9 +1,9 @@
 package services
 
 import (
-    "errors"
     "github.com/zmqge/vireo-gin-admin/app/admin/models"
     "github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+    "github.com/zmqge/vireo-gin-admin/pkg/apperr"
     "github.com/gin-gonic/gin"
 )
 
@@ -35,7 +35,7 @@ func (s *NoticeReceiverServiceImpl) GetNoticeReceiverByID(ctx *gin.Context,id ui
         return nil, err
     }
     if entity == nil {
-        return nil, errors.New("NoticeReceiver not found")
+        return nil, apperr.ErrNoticeReceiverNotFound
     }
     return entity, nil
 }
@@ -48,7 +48,7 @@ func (s *NoticeReceiverServiceImpl) ListNoticeReceivers(ctx *gin.Context,) ([]*m
 // CreateNoticeReceiver 创建NoticeReceiver
 func (s *NoticeReceiverServiceImpl) CreateNoticeReceiver(ctx *gin.Context,entity *models.NoticeReceiverModel) error {
     if entity.Name == "" {
-        return errors.New("name is required")
+        return apperr.ErrValidation.WithField("name", "不能为空")
     }
     return s.repo.CreateNoticeReceiver(ctx,entity)
 }
@@ -56,7 +56,7 @@ func (s *NoticeReceiverServiceImpl) CreateNoticeReceiver(ctx *gin.Context,entity
 // UpdateNoticeReceiver 更新NoticeReceiver
 func (s *NoticeReceiverServiceImpl) UpdateNoticeReceiver(ctx *gin.Context,entity *models.NoticeReceiverModel) error {
     if entity.Name == "" {
-        return errors.New("name is required")
+        return apperr.ErrValidation.WithField("name", "不能为空")
     }
     return s.repo.UpdateNoticeReceiver(ctx,entity)
 }
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/pkg/redis"
+)
+
+const noticeSchedulerLockKey = "notice:scheduler:lock"
+
+// NoticeScheduler 通知定时任务：到点发布定时通知、下线过期通知
+// 任务状态全部保存在数据库（scheduled_at/expires_at），重启后可继续执行；
+// 多副本部署时通过 Redis 锁保证同一时刻只有一个实例扫描，执行期间持续续期，
+// 批次耗时超过扫描间隔时其他实例也不会拿到锁重复发布、推送
+type NoticeScheduler struct {
+	service  NoticesService
+	interval time.Duration
+}
+
+// NewNoticeScheduler 创建通知定时任务
+func NewNoticeScheduler(service NoticesService, interval time.Duration) *NoticeScheduler {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &NoticeScheduler{service: service, interval: interval}
+}
+
+// Run 启动定时任务，ctx 取消时退出
+func (s *NoticeScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	// 启动时立即执行一次，补偿停机期间错过的任务
+	s.tick(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+func (s *NoticeScheduler) tick(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[NoticeScheduler] 执行异常: %v", r)
+		}
+	}()
+
+	lock, err := redis.TryLock(ctx, noticeSchedulerLockKey, s.interval)
+	if err != nil {
+		log.Printf("[NoticeScheduler] 获取锁失败: %v", err)
+		return
+	}
+	if lock == nil {
+		return // 其他实例正在执行
+	}
+	defer lock.Unlock(context.Background())
+
+	// 续期失败说明锁已丢失，取消本次任务，剩余通知由持有锁的实例处理
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go lock.KeepAlive(runCtx, s.interval, func() {
+		log.Printf("[NoticeScheduler] 锁续期失败，停止本次执行")
+		cancel()
+	})
+
+	taskCtx := newTaskContext(runCtx)
+	now := time.Now()
+	if n, err := s.service.PublishDueNotices(taskCtx, now); err != nil {
+		log.Printf("[NoticeScheduler] 定时发布失败: %v", err)
+	} else if n > 0 {
+		log.Printf("[NoticeScheduler] 定时发布通知 %d 条", n)
+	}
+	if n, err := s.service.ExpireDueNotices(taskCtx, now); err != nil {
+		log.Printf("[NoticeScheduler] 过期处理失败: %v", err)
+	} else if n > 0 {
+		log.Printf("[NoticeScheduler] 过期通知 %d 条", n)
+	}
+}
+
+// newTaskContext 为后台任务构造 gin.Context，以便复用依赖 gin.Context 的服务方法
+func newTaskContext(ctx context.Context) *gin.Context {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "/internal/task", nil)
+	return &gin.Context{Request: req}
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/noticetpl"
+	"github.com/zmqge/vireo-gin-admin/pkg/richtext"
+)
+
+// NoticeTemplateService 通知模板服务接口
+type NoticeTemplateService interface {
+	GetNoticeTemplateByID(ctx *gin.Context, id uint) (*models.NoticeTemplateModel, error)
+	CreateNoticeTemplate(ctx *gin.Context, entity *models.NoticeTemplateModel) error
+	UpdateNoticeTemplate(ctx *gin.Context, entity *models.NoticeTemplateModel) error
+	DeleteNoticeTemplate(ctx *gin.Context, id uint) error
+	PageNoticeTemplates(ctx *gin.Context, keywords string, pageNum, pageSize int) ([]*models.NoticeTemplateModel, int64, error)
+	PreviewNoticeTemplate(ctx *gin.Context, id uint, userID uint, vars map[string]string) (map[string]string, error)
+	CreateNoticeFromTemplate(ctx *gin.Context, req *models.NoticeFromTemplateRequest) (*models.NoticesModel, error)
+}
+
+// NoticeTemplateServiceImpl 通知模板服务实现
+type NoticeTemplateServiceImpl struct {
+	repo          repositories.NoticeTemplateRepository
+	noticeService NoticesService
+}
+
+// NewNoticeTemplateService 创建通知模板服务
+func NewNoticeTemplateService(repo repositories.NoticeTemplateRepository, noticeService NoticesService) NoticeTemplateService {
+	return &NoticeTemplateServiceImpl{repo: repo, noticeService: noticeService}
+}
+
+// GetNoticeTemplateByID 根据ID获取通知模板
+func (s *NoticeTemplateServiceImpl) GetNoticeTemplateByID(ctx *gin.Context, id uint) (*models.NoticeTemplateModel, error) {
+	entity, err := s.repo.GetNoticeTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entity == nil {
+		return nil, apperr.ErrTemplateNotFound
+	}
+	return entity, nil
+}
+
+// validateTemplate 保存前校验变量定义和模板语法
+func validateTemplate(entity *models.NoticeTemplateModel) error {
+	entity.Name = strings.TrimSpace(entity.Name)
+	if entity.Name == "" {
+		return apperr.ErrTemplateNameRequired
+	}
+	if strings.TrimSpace(entity.Title) == "" {
+		return apperr.ErrTemplateTitleRequired
+	}
+	seen := make(map[string]bool, len(entity.Variables))
+	names := make([]string, 0, len(entity.Variables))
+	for _, v := range entity.Variables {
+		if seen[v.Name] {
+			return apperr.ErrTemplateVarDuplicate.WithArgs(v.Name)
+		}
+		seen[v.Name] = true
+		names = append(names, v.Name)
+	}
+	// 标题对所有接收人相同，只能引用自定义变量
+	if strings.Contains(entity.Title, ".User") || strings.Contains(entity.Title, ".Dept") {
+		return apperr.ErrTemplateTitleVarsOnly
+	}
+	if err := noticetpl.Validate(entity.Title, names); err != nil {
+		return apperr.ErrTemplateTitleSyntax.WithArgs(err.Error())
+	}
+	if entity.ContentFormat == "" {
+		entity.ContentFormat = richtext.FormatHTML
+	}
+	if !richtext.ValidFormat(entity.ContentFormat) {
+		return apperr.ErrNoticeUnsupportedFormat.WithArgs(entity.ContentFormat)
+	}
+	if entity.ContentFormat == richtext.FormatHTML {
+		entity.Content = richtext.Sanitize(entity.Content)
+	}
+	// 发布时按渲染后的 HTML 执行模板，因此校验渲染结果
+	if err := noticetpl.Validate(richtext.Render(entity.ContentFormat, entity.Content), names); err != nil {
+		return apperr.ErrTemplateContentSyntax.WithArgs(err.Error())
+	}
+	return nil
+}
+
+// CreateNoticeTemplate 创建通知模板
+func (s *NoticeTemplateServiceImpl) CreateNoticeTemplate(ctx *gin.Context, entity *models.NoticeTemplateModel) error {
+	if err := validateTemplate(entity); err != nil {
+		return err
+	}
+	return s.repo.CreateNoticeTemplate(ctx, entity)
+}
+
+// UpdateNoticeTemplate 更新通知模板（已创建的通知不受影响）
+func (s *NoticeTemplateServiceImpl) UpdateNoticeTemplate(ctx *gin.Context, entity *models.NoticeTemplateModel) error {
+	existing, err := s.GetNoticeTemplateByID(ctx, entity.ID)
+	if err != nil {
+		return err
+	}
+	if err := validateTemplate(entity); err != nil {
+		return err
+	}
+	entity.CreatorID = existing.CreatorID
+	entity.DeptID = existing.DeptID
+	entity.CreatedAt = existing.CreatedAt
+	return s.repo.UpdateNoticeTemplate(ctx, entity)
+}
+
+// DeleteNoticeTemplate 删除通知模板
+func (s *NoticeTemplateServiceImpl) DeleteNoticeTemplate(ctx *gin.Context, id uint) error {
+	return s.repo.DeleteNoticeTemplate(ctx, id)
+}
+
+// PageNoticeTemplates 分页获取通知模板列表
+func (s *NoticeTemplateServiceImpl) PageNoticeTemplates(ctx *gin.Context, keywords string, pageNum, pageSize int) ([]*models.NoticeTemplateModel, int64, error) {
+	return s.repo.PageNoticeTemplates(ctx, keywords, pageNum, pageSize)
+}
+
+// resolveVars 合并默认值并检查必填变量，忽略模板未声明的变量
+func resolveVars(tpl *models.NoticeTemplateModel, input map[string]string) (map[string]string, error) {
+	vars := make(map[string]string, len(tpl.Variables))
+	for _, v := range tpl.Variables {
+		value, ok := input[v.Name]
+		if !ok || value == "" {
+			value = v.Default
+		}
+		if value == "" && v.Required {
+			label := v.Label
+			if label == "" {
+				label = v.Name
+			}
+			return nil, apperr.ErrTemplateVarRequired.WithArgs(label)
+		}
+		vars[v.Name] = value
+	}
+	return vars, nil
+}
+
+// renderTitle 标题只使用自定义变量，所有接收人相同
+func renderTitle(text string, vars map[string]string) (string, error) {
+	tpl, err := noticetpl.Compile(text)
+	if err != nil {
+		return "", err
+	}
+	return tpl.Render(noticetpl.Data{Vars: vars})
+}
+
+// PreviewNoticeTemplate 以指定用户（默认当前用户）预览模板渲染结果
+func (s *NoticeTemplateServiceImpl) PreviewNoticeTemplate(ctx *gin.Context, id uint, userID uint, vars map[string]string) (map[string]string, error) {
+	tpl, err := s.GetNoticeTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := resolveVars(tpl, vars)
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := s.repo.GetTemplateRecipient(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if recipient == nil {
+		return nil, apperr.ErrUserNotFound
+	}
+
+	title, err := renderTitle(tpl.Title, resolved)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := noticetpl.Compile(richtext.Render(tpl.ContentFormat, tpl.Content))
+	if err != nil {
+		return nil, err
+	}
+	content, err := compiled.Render(noticetpl.Data{
+		User: noticetpl.User{ID: recipient.UserID, Username: recipient.Username, Nickname: recipient.Nickname, Email: recipient.Email, Mobile: recipient.Mobile},
+		Dept: noticetpl.Dept{ID: recipient.DeptID, Name: recipient.DeptName, Code: recipient.DeptCode},
+		Vars: resolved,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"title": title, "content": content}, nil
+}
+
+// CreateNoticeFromTemplate 从模板创建通知草稿
+// 标题在创建时渲染；内容保留模板原文，发布时为每个接收人单独渲染
+func (s *NoticeTemplateServiceImpl) CreateNoticeFromTemplate(ctx *gin.Context, req *models.NoticeFromTemplateRequest) (*models.NoticesModel, error) {
+	tpl, err := s.GetNoticeTemplateByID(ctx, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := resolveVars(tpl, req.Vars)
+	if err != nil {
+		return nil, err
+	}
+	title, err := renderTitle(tpl.Title, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	templateID := tpl.ID
+	notice := &models.NoticesModel{
+		Title:         title,
+		Content:       tpl.Content,
+		ContentFormat: tpl.ContentFormat,
+		Type:          tpl.Type,
+		Level:         tpl.Level,
+		TargetType:    req.TargetType,
+		TargetIDs:     req.TargetIDs,
+		ScheduledAt:   req.ScheduledAt,
+		ExpiresAt:     req.ExpiresAt,
+		TemplateID:    &templateID,
+		TemplateVars:  vars,
+	}
+	if err := s.noticeService.CreateNotices(ctx, notice); err != nil {
+		return nil, err
+	}
+	return notice, nil
+}
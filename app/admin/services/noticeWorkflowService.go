@@ -0,0 +1,426 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/config"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/middleware"
+	"github.com/zmqge/vireo-gin-admin/pkg/push"
+	"github.com/zmqge/vireo-gin-admin/utils"
+	"gorm.io/gorm"
+)
+
+// noticeRule 状态流转规则：允许的起始状态与目标状态
+type noticeRule struct {
+	from []int
+	to   int
+}
+
+// noticeRules 通知状态机，所有状态变更都必须经过这里定义的动作
+//
+//	草稿 --提交--> 待审核 --通过--> 审核通过 --发布--> 已发布 --撤回--> 已撤回
+//	                  \--驳回--> 已驳回 --提交--> 待审核       \--过期--> 已过期
+//	审核通过 --定时--> 定时待发布 --到点--> 已发布
+//	审核通过/已撤回/已过期 --编辑--> 草稿（开启审核时，修改后的内容需重新审核）
+var noticeRules = map[string]noticeRule{
+	models.NoticeActionSubmit:  {from: []int{models.NoticeStatusDraft, models.NoticeStatusRejected}, to: models.NoticeStatusPending},
+	models.NoticeActionApprove: {from: []int{models.NoticeStatusPending}, to: models.NoticeStatusApproved},
+	models.NoticeActionReject:  {from: []int{models.NoticeStatusPending}, to: models.NoticeStatusRejected},
+	models.NoticeActionPublish: {
+		from: []int{models.NoticeStatusApproved, models.NoticeStatusRevoked, models.NoticeStatusExpired},
+		to:   models.NoticeStatusPublished,
+	},
+	models.NoticeActionSchedule: {
+		from: []int{models.NoticeStatusApproved, models.NoticeStatusRevoked, models.NoticeStatusExpired},
+		to:   models.NoticeStatusScheduled,
+	},
+	models.NoticeActionCancelSchedule: {from: []int{models.NoticeStatusScheduled}, to: models.NoticeStatusApproved},
+	models.NoticeActionRevoke:         {from: []int{models.NoticeStatusPublished}, to: models.NoticeStatusRevoked},
+	models.NoticeActionExpire:         {from: []int{models.NoticeStatusPublished}, to: models.NoticeStatusExpired},
+	models.NoticeActionEdit: {
+		from: []int{models.NoticeStatusApproved, models.NoticeStatusRevoked, models.NoticeStatusExpired},
+		to:   models.NoticeStatusDraft,
+	},
+}
+
+// noticeEditableStatuses 允许编辑的状态
+var noticeEditableStatuses = []int{
+	models.NoticeStatusDraft,
+	models.NoticeStatusRejected,
+	models.NoticeStatusApproved,
+	models.NoticeStatusRevoked,
+	models.NoticeStatusExpired,
+}
+
+// noticeStatusText 状态名称，用于错误提示
+func noticeStatusText(status int) string {
+	switch status {
+	case models.NoticeStatusDraft:
+		return "草稿"
+	case models.NoticeStatusPublished:
+		return "已发布"
+	case models.NoticeStatusRevoked:
+		return "已撤回"
+	case models.NoticeStatusPending:
+		return "待审核"
+	case models.NoticeStatusScheduled:
+		return "定时待发布"
+	case models.NoticeStatusExpired:
+		return "已过期"
+	case models.NoticeStatusApproved:
+		return "审核通过"
+	case models.NoticeStatusRejected:
+		return "已驳回"
+	default:
+		return fmt.Sprintf("未知(%d)", status)
+	}
+}
+
+func containsStatus(list []int, status int) bool {
+	for _, s := range list {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleFor 返回动作对应的流转规则，部分规则与审核开关和创建人权限相关
+func (s *NoticesServiceImpl) ruleFor(action string, entity *models.NoticesModel) noticeRule {
+	return buildNoticeRule(action, func() bool { return s.canPublishDraft(entity) })
+}
+
+// buildNoticeRule 按审核开关和草稿能否直接发布调整流转规则
+func buildNoticeRule(action string, canPublishDraft func() bool) noticeRule {
+	rule := noticeRules[action]
+	switch action {
+	case models.NoticeActionPublish, models.NoticeActionSchedule:
+		// 未开启审核，或创建人本身拥有发布权限时，草稿可直接发布
+		if canPublishDraft() {
+			rule.from = append([]int{models.NoticeStatusDraft}, rule.from...)
+		}
+	case models.NoticeActionCancelSchedule:
+		if !config.App.Notice.ApprovalEnabled {
+			rule.to = models.NoticeStatusDraft
+		}
+	}
+	return rule
+}
+
+// editRule 编辑通知时的状态流转，第二个返回值表示是否需要流转
+// 开启审核时，审核通过、已撤回、已过期的通知编辑后退回草稿，避免未经审核的内容被重新发布
+func editRule(status int) (noticeRule, bool) {
+	rule := noticeRules[models.NoticeActionEdit]
+	return rule, config.App.Notice.ApprovalEnabled && containsStatus(rule.from, status)
+}
+
+// canPublishDraft 草稿是否可跳过审核直接发布
+func (s *NoticesServiceImpl) canPublishDraft(entity *models.NoticesModel) bool {
+	if !config.App.Notice.ApprovalEnabled {
+		return true
+	}
+	return middleware.HasPermission(s.userRepo.GetDB(), entity.CreatorID, "sys:notice:publish")
+}
+
+// operatorID 当前操作人，后台任务为0
+func operatorID(ctx *gin.Context) uint {
+	id, err := utils.ParseUintID(ctx.GetString("userID"))
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// applyTransition 在一个事务内完成：按条件更新状态、记录流转历史、执行附加操作
+// 条件更新保证并发（含多副本定时任务）下同一流转只会成功一次
+func (s *NoticesServiceImpl) applyTransition(ctx *gin.Context, entity *models.NoticesModel, action string, rule noticeRule,
+	comment string, fields map[string]interface{}, extra func(tx *gorm.DB) error) (err error) {
+	if !containsStatus(rule.from, entity.Status) {
+		if action == models.NoticeActionPublish && entity.Status == models.NoticeStatusDraft {
+			return apperr.ErrNoticeNeedsApproval
+		}
+		return apperr.ErrNoticeActionNotAllowed.WithArgs(noticeStatusText(entity.Status))
+	}
+
+	tx := s.repo.BeginTx(ctx)
+	if tx.Error != nil {
+		return fmt.Errorf("开启事务失败: %w", tx.Error)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			log.Printf("[PANIC] 事务回滚: %v", r)
+			err = fmt.Errorf("更新通知状态失败: %v", r)
+			return
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	ok, err := s.repo.TransitionStatusTx(tx, entity.ID, rule.from, rule.to, fields)
+	if err != nil {
+		return fmt.Errorf("更新通知状态失败: %w", err)
+	}
+	if !ok {
+		return apperr.ErrNoticeStatusChanged
+	}
+	if err = s.repo.CreateStatusHistoryTx(tx, &models.NoticeStatusHistory{
+		NoticeID:   entity.ID,
+		FromStatus: entity.Status,
+		ToStatus:   rule.to,
+		Action:     action,
+		Comment:    comment,
+		OperatorID: operatorID(ctx),
+	}); err != nil {
+		return fmt.Errorf("记录状态流转失败: %w", err)
+	}
+	if extra != nil {
+		if err = extra(tx); err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+	entity.Status = rule.to
+	return nil
+}
+
+// loadNotice 获取通知，不存在时返回错误
+func (s *NoticesServiceImpl) loadNotice(ctx *gin.Context, id uint) (*models.NoticesModel, error) {
+	entity, err := s.repo.GetNoticeWithReceivers(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.ErrNoticeNotFound
+		}
+		return nil, fmt.Errorf("获取通知详情失败: %w", err)
+	}
+	return entity, nil
+}
+
+// SubmitNotice 提交审核
+func (s *NoticesServiceImpl) SubmitNotice(ctx *gin.Context, id uint, comment string) error {
+	entity, err := s.loadNotice(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := validateNoticeForPublish(entity); err != nil {
+		return err
+	}
+	return s.applyTransition(ctx, entity, models.NoticeActionSubmit, s.ruleFor(models.NoticeActionSubmit, entity), comment, nil, nil)
+}
+
+// ApproveNotice 审核通过
+func (s *NoticesServiceImpl) ApproveNotice(ctx *gin.Context, id uint, comment string) error {
+	entity, err := s.loadNotice(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.applyTransition(ctx, entity, models.NoticeActionApprove, s.ruleFor(models.NoticeActionApprove, entity), comment, nil, nil)
+}
+
+// RejectNotice 审核驳回，必须填写驳回意见
+func (s *NoticesServiceImpl) RejectNotice(ctx *gin.Context, id uint, comment string) error {
+	if strings.TrimSpace(comment) == "" {
+		return apperr.ErrNoticeRejectCommentRequired
+	}
+	entity, err := s.loadNotice(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.applyTransition(ctx, entity, models.NoticeActionReject, s.ruleFor(models.NoticeActionReject, entity), comment, nil, nil)
+}
+
+// GetNoticeStatusHistory 查询状态流转记录，通知需在当前用户的数据权限范围内
+func (s *NoticesServiceImpl) GetNoticeStatusHistory(ctx *gin.Context, id uint) ([]*models.NoticeStatusHistory, error) {
+	if _, err := s.loadVisibleNotice(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStatusHistory(ctx, id)
+}
+
+// PublishNotice 发布通知
+func (s *NoticesServiceImpl) PublishNotice(ctx *gin.Context, id uint) error {
+	return s.PublishNoticeWithReceivers(ctx, id)
+}
+
+// PublishNoticeWithReceivers 发布通知并生成接收记录
+// 若设置了未来的定时发布时间，则进入定时待发布状态，由定时任务到点发布
+func (s *NoticesServiceImpl) PublishNoticeWithReceivers(ctx *gin.Context, id uint) error {
+	// 1. 参数校验
+	if ctx == nil {
+		return errors.New("gin context cannot be nil")
+	}
+
+	// 获取通知详情（包括接收者关系）
+	entity, err := s.loadNotice(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// 2. 校验通知状态
+	if entity.Status == models.NoticeStatusPublished {
+		return apperr.ErrNoticeAlreadyPublished
+	}
+
+	// 3. 校验基本字段
+	if err := validateNoticeForPublish(entity); err != nil {
+		return err
+	}
+
+	if entity.ScheduledAt != nil && entity.ScheduledAt.After(time.Now()) {
+		return s.applyTransition(ctx, entity, models.NoticeActionSchedule, s.ruleFor(models.NoticeActionSchedule, entity), "", nil, nil)
+	}
+	return s.publishNow(ctx, entity, s.ruleFor(models.NoticeActionPublish, entity))
+}
+
+// validateNoticeForPublish 发布前校验
+func validateNoticeForPublish(entity *models.NoticesModel) error {
+	if entity.Title == "" {
+		return apperr.ErrNoticeTitleRequired
+	}
+	if entity.TargetType < 1 || entity.TargetType > 4 {
+		return apperr.ErrNoticeInvalidTarget
+	}
+	if entity.TargetType == 4 && len(entity.TargetIDs) == 0 {
+		return apperr.ErrNoticeTargetUsersRequired
+	}
+	if entity.ExpiresAt != nil {
+		start := time.Now()
+		if entity.ScheduledAt != nil && entity.ScheduledAt.After(start) {
+			start = *entity.ScheduledAt
+		}
+		if !entity.ExpiresAt.After(start) {
+			return apperr.ErrNoticeExpireBeforePublish
+		}
+	}
+	return nil
+}
+
+// publishNow 立即发布：在同一事务内更新状态、记录历史、生成接收记录和渠道投递任务
+func (s *NoticesServiceImpl) publishNow(ctx *gin.Context, entity *models.NoticesModel, rule noticeRule) error {
+	var userIDs []uint
+	err := s.applyTransition(ctx, entity, models.NoticeActionPublish, rule, "", map[string]interface{}{
+		"published_at": time.Now(),
+	}, func(tx *gorm.DB) error {
+		// 处理接收者（支持重新发布）
+		ids, err := s.processNoticeReceiversForPublish(ctx, tx, entity)
+		if err != nil {
+			return fmt.Errorf("处理接收者失败: %w", err)
+		}
+		userIDs = ids
+		// 按级别生成邮件/短信/Webhook 投递任务，由投递任务异步发送
+		if err := s.delivery.EnqueueTx(tx, entity, ids); err != nil {
+			return fmt.Errorf("生成投递任务失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 实时推送给在线用户（推送失败不影响发布结果）
+	if err := push.Publish(ctx, push.EventNotice, userIDs, noticePushData(entity)); err != nil {
+		log.Printf("推送新通知失败（通知ID：%d）：%v", entity.ID, err)
+	}
+	return nil
+}
+
+// RevokeNotice 撤回通知
+func (s *NoticesServiceImpl) RevokeNotice(ctx *gin.Context, id uint) error {
+	entity, err := s.loadNotice(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = s.applyTransition(ctx, entity, models.NoticeActionRevoke, s.ruleFor(models.NoticeActionRevoke, entity), "", map[string]interface{}{
+		"revoked_at": time.Now(),
+	}, nil)
+	if err != nil {
+		return err
+	}
+	s.pushRevoke(ctx, id)
+	return nil
+}
+
+// pushRevoke 通知在线接收者移除该通知
+func (s *NoticesServiceImpl) pushRevoke(ctx *gin.Context, id uint) {
+	userIDs, err := s.repo.ListReceiverUserIDs(ctx, id)
+	if err != nil {
+		log.Printf("查询通知接收者失败（通知ID：%d）：%v", id, err)
+		return
+	}
+	if err := push.Publish(ctx, push.EventRevoke, userIDs, map[string]interface{}{"id": id}); err != nil {
+		log.Printf("推送撤回通知失败（通知ID：%d）：%v", id, err)
+	}
+}
+
+// CancelScheduledNotice 取消定时发布
+func (s *NoticesServiceImpl) CancelScheduledNotice(ctx *gin.Context, id uint) error {
+	entity, err := s.loadNotice(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.applyTransition(ctx, entity, models.NoticeActionCancelSchedule, s.ruleFor(models.NoticeActionCancelSchedule, entity), "",
+		map[string]interface{}{"scheduled_at": nil}, nil)
+}
+
+// PublishDueNotices 发布所有已到时间的定时通知，返回成功发布的数量
+// 发布前通过状态条件更新抢占，多实例同时执行也不会重复发布
+func (s *NoticesServiceImpl) PublishDueNotices(ctx *gin.Context, now time.Time) (int, error) {
+	ids, err := s.repo.ListDueScheduledNoticeIDs(ctx, now, 100)
+	if err != nil {
+		return 0, err
+	}
+	rule := noticeRule{from: []int{models.NoticeStatusScheduled}, to: models.NoticeStatusPublished}
+	published := 0
+	for _, id := range ids {
+		if ctx.Request.Context().Err() != nil {
+			break // 定时任务已取消（如调度锁丢失）
+		}
+		entity, err := s.loadNotice(ctx, id)
+		if err != nil {
+			log.Printf("[NoticeScheduler] 获取通知 %d 失败: %v", id, err)
+			continue
+		}
+		if err := s.publishNow(ctx, entity, rule); err != nil {
+			log.Printf("[NoticeScheduler] 定时发布通知 %d 失败: %v", id, err)
+			continue
+		}
+		published++
+	}
+	return published, nil
+}
+
+// ExpireDueNotices 将已过期的通知置为已过期状态，返回处理数量
+func (s *NoticesServiceImpl) ExpireDueNotices(ctx *gin.Context, now time.Time) (int, error) {
+	ids, err := s.repo.ListExpiredNoticeIDs(ctx, now, 100)
+	if err != nil {
+		return 0, err
+	}
+	expired := 0
+	for _, id := range ids {
+		if ctx.Request.Context().Err() != nil {
+			break
+		}
+		entity, err := s.loadNotice(ctx, id)
+		if err != nil {
+			log.Printf("[NoticeScheduler] 获取通知 %d 失败: %v", id, err)
+			continue
+		}
+		if err := s.applyTransition(ctx, entity, models.NoticeActionExpire, s.ruleFor(models.NoticeActionExpire, entity), "", nil, nil); err != nil {
+			log.Printf("[NoticeScheduler] 通知 %d 过期处理失败: %v", id, err)
+			continue
+		}
+		s.pushRevoke(ctx, id)
+		expired++
+	}
+	return expired, nil
+}
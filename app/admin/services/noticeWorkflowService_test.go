@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/config"
+)
+
+func TestEditAfterRevokeNeedsApprovalBeforePublish(t *testing.T) {
+	old := config.App.Notice.ApprovalEnabled
+	defer func() { config.App.Notice.ApprovalEnabled = old }()
+	config.App.Notice.ApprovalEnabled = true
+	noPublishRight := func() bool { return false }
+
+	for _, status := range []int{models.NoticeStatusApproved, models.NoticeStatusRevoked, models.NoticeStatusExpired} {
+		rule, ok := editRule(status)
+		if !ok || rule.to != models.NoticeStatusDraft {
+			t.Fatalf("%s 的通知编辑后应退回草稿", noticeStatusText(status))
+		}
+		publish := buildNoticeRule(models.NoticeActionPublish, noPublishRight)
+		if containsStatus(publish.from, rule.to) {
+			t.Fatalf("%s 的通知编辑后，无发布权限的创建人不能直接发布", noticeStatusText(status))
+		}
+		schedule := buildNoticeRule(models.NoticeActionSchedule, noPublishRight)
+		if containsStatus(schedule.from, rule.to) {
+			t.Fatalf("%s 的通知编辑后，无发布权限的创建人不能定时发布", noticeStatusText(status))
+		}
+	}
+
+	// 未编辑的已撤回通知内容已审核过，可重新发布
+	if !containsStatus(buildNoticeRule(models.NoticeActionPublish, noPublishRight).from, models.NoticeStatusRevoked) {
+		t.Fatal("未编辑的已撤回通知应可重新发布")
+	}
+	if _, ok := editRule(models.NoticeStatusDraft); ok {
+		t.Fatal("草稿编辑不应流转状态")
+	}
+
+	config.App.Notice.ApprovalEnabled = false
+	if _, ok := editRule(models.NoticeStatusRevoked); ok {
+		t.Fatal("未开启审核时编辑不应流转状态")
+	}
+}
@@ -1,7 +1,7 @@
 package services
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -9,6 +9,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/noticetpl"
+	"github.com/zmqge/vireo-gin-admin/pkg/push"
+	"github.com/zmqge/vireo-gin-admin/pkg/richtext"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -28,6 +32,17 @@ type NoticesService interface {
 	MyPageNotices(ctx *gin.Context, userID uint, keywords string, isRead uint, pageNum, pageSize int) ([]*models.NoticesModel, int64, error)
 	GetMyNoticesByID(ctx *gin.Context, userID uint, id uint) (*models.NoticesModel, error)
 	MarkAllAsRead(ctx *gin.Context, userID uint) error
+	CancelScheduledNotice(ctx *gin.Context, id uint) error
+	PublishDueNotices(ctx *gin.Context, now time.Time) (int, error)
+	ExpireDueNotices(ctx *gin.Context, now time.Time) (int, error)
+	SubmitNotice(ctx *gin.Context, id uint, comment string) error
+	ApproveNotice(ctx *gin.Context, id uint, comment string) error
+	RejectNotice(ctx *gin.Context, id uint, comment string) error
+	GetNoticeStatusHistory(ctx *gin.Context, id uint) ([]*models.NoticeStatusHistory, error)
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	GetNoticeReadStats(ctx *gin.Context, id uint, granularity string) (*models.NoticeReadStats, error)
+	PageNoticeUnreadUsers(ctx *gin.Context, id uint, deptID uint, keywords string, pageNum, pageSize int) ([]models.NoticeUnreadUser, int64, error)
+	RemindUnread(ctx *gin.Context, id uint) (int, error)
 }
 
 // NoticesServiceImpl Notices服务实现
@@ -35,6 +50,8 @@ type NoticesServiceImpl struct {
 	repo         repositories.NoticesRepository
 	receiverRepo repositories.NoticeReceiverRepository
 	userRepo     repositories.UserRepository
+	delivery     NoticeDeliveryService
+	attachments  NoticeAttachmentService
 }
 
 // NewNoticesService 创建Notices服务
@@ -42,11 +59,15 @@ func NewNoticesService(
 	repo repositories.NoticesRepository,
 	userRepo repositories.UserRepository,
 	receiverRepo repositories.NoticeReceiverRepository,
+	delivery NoticeDeliveryService,
+	attachments NoticeAttachmentService,
 ) NoticesService {
 	return &NoticesServiceImpl{
 		repo:         repo,
 		userRepo:     userRepo,
 		receiverRepo: receiverRepo,
+		delivery:     delivery,
+		attachments:  attachments,
 	}
 }
 
@@ -57,7 +78,10 @@ func (s *NoticesServiceImpl) GetNoticesByID(ctx *gin.Context, id uint) (*models.
 		return nil, err
 	}
 	if entity == nil {
-		return nil, errors.New("Notices not found")
+		return nil, apperr.ErrNoticeNotFound
+	}
+	if err := s.attachments.FillAttachments(ctx, entity); err != nil {
+		return nil, err
 	}
 	return entity, nil
 }
@@ -69,12 +93,17 @@ func (s *NoticesServiceImpl) GetMyNoticesByID(ctx *gin.Context, userID uint, id
 		return nil, err
 	}
 	if entity == nil {
-		return nil, errors.New("Notices not found")
+		return nil, apperr.ErrNoticeNotFound
 	}
 	// 2. 标记为已读（无论当前是否已读都更新，确保最后查看时间准确）
 	if err := s.repo.MarkNoticeAsRead(ctx, userID, id); err != nil {
 		// 可以根据业务需求决定是否返回错误
 		log.Printf("警告：标记通知为已读失败（通知ID：%d，用户ID：%d），错误：%v", id, userID, err)
+	} else {
+		s.pushUnread(ctx, userID)
+	}
+	if err := s.attachments.FillAttachments(ctx, entity); err != nil {
+		return nil, err
 	}
 	return entity, nil
 }
@@ -84,17 +113,102 @@ func (s *NoticesServiceImpl) ListNoticess(ctx *gin.Context) ([]*models.NoticesMo
 	return s.repo.ListNoticess(ctx)
 }
 
-// CreateNotices 创建Notices
+// CreateNotices 创建Notices，新建的通知一律为草稿
 func (s *NoticesServiceImpl) CreateNotices(ctx *gin.Context, entity *models.NoticesModel) error {
-	return s.repo.CreateNotices(ctx, entity)
+	entity.Status = models.NoticeStatusDraft
+	if err := s.prepareContent(entity); err != nil {
+		return err
+	}
+	// 通知与附件绑定在同一事务内，任一失败都不会留下缺少附件的通知
+	var removed []models.NoticeAttachment
+	err := s.repo.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := s.repo.CreateNoticesTx(tx, entity); err != nil {
+			return err
+		}
+		var err error
+		removed, err = s.attachments.SyncNoticeAttachmentsTx(ctx, tx, entity, false)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	s.attachments.DeleteFiles(removed)
+	return nil
+}
+
+// prepareContent 规范化正文：过滤 HTML 源内容并生成用于展示的 content_html
+func (s *NoticesServiceImpl) prepareContent(entity *models.NoticesModel) error {
+	if entity.ContentFormat == "" {
+		entity.ContentFormat = richtext.FormatHTML
+	}
+	if !richtext.ValidFormat(entity.ContentFormat) {
+		return apperr.ErrNoticeUnsupportedFormat.WithArgs(entity.ContentFormat)
+	}
+	entity.Content = s.attachments.NormalizeURLs(entity.Content)
+	if entity.ContentFormat == richtext.FormatHTML {
+		entity.Content = richtext.Sanitize(entity.Content)
+	}
+	entity.ContentHTML = richtext.Render(entity.ContentFormat, entity.Content)
+	return nil
 }
 
 // UpdateNotices 更新Notices
+// 状态只能通过流转动作变更，编辑时沿用原状态；审核通过、已撤回、已过期后再编辑需重新审核
 func (s *NoticesServiceImpl) UpdateNotices(ctx *gin.Context, entity *models.NoticesModel) error {
 	if entity.Title == "" {
-		return errors.New("name is required")
+		return apperr.ErrValidation.WithField("title", "不能为空")
+	}
+	existing, err := s.repo.GetNoticesByID(ctx, entity.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return apperr.ErrNoticeNotFound
 	}
-	return s.repo.UpdateNotices(ctx, entity)
+	if !containsStatus(noticeEditableStatuses, existing.Status) {
+		return apperr.ErrNoticeNotEditable.WithArgs(noticeStatusText(existing.Status))
+	}
+	// 来源模板不可修改；由模板创建的通知，内容仍按模板语法校验
+	entity.TemplateID = existing.TemplateID
+	if entity.TemplateVars == nil {
+		entity.TemplateVars = existing.TemplateVars
+	}
+	if err := s.prepareContent(entity); err != nil {
+		return err
+	}
+	if entity.TemplateID != nil {
+		if err := noticetpl.Validate(entity.ContentHTML, templateVarNames(entity.TemplateVars)); err != nil {
+			return err
+		}
+	}
+	entity.Status = existing.Status
+	entity.CreatorID = existing.CreatorID
+	entity.DeptID = existing.DeptID
+	entity.PublishedAt = existing.PublishedAt
+	entity.RevokedAt = existing.RevokedAt
+	entity.CreatedAt = existing.CreatedAt
+	// 内容、附件和状态流转在同一事务内；未提交附件列表时保留原有附件
+	var removed []models.NoticeAttachment
+	save := func(tx *gorm.DB) error {
+		if err := s.repo.UpdateNoticesTx(ctx, tx, entity); err != nil {
+			return err
+		}
+		var err error
+		removed, err = s.attachments.SyncNoticeAttachmentsTx(ctx, tx, entity, entity.AttachmentIDs == nil)
+		return err
+	}
+	if rule, ok := editRule(existing.Status); ok {
+		// 退回草稿与内容更新在同一事务内，保存的状态为流转后的状态
+		entity.Status = rule.to
+		err = s.applyTransition(ctx, existing, models.NoticeActionEdit, rule, "", nil, save)
+	} else {
+		err = s.repo.Transaction(ctx, save)
+	}
+	if err != nil {
+		return err
+	}
+	s.attachments.DeleteFiles(removed)
+	return nil
 }
 
 // DeleteNotices 删除Notices
@@ -109,115 +223,112 @@ func (s *NoticesServiceImpl) PageNotices(ctx *gin.Context, keywords string, publ
 
 // GetNoticesForm 表单
 func (s *NoticesServiceImpl) GetNoticesForm(ctx *gin.Context, id uint) (*models.NoticesModel, error) {
-	return s.repo.GetNoticesByID(ctx, id)
-}
-
-// RevokeNotice 撤回通知
-func (s *NoticesServiceImpl) RevokeNotice(ctx *gin.Context, id uint) error {
-	return s.repo.RevokeNotice(ctx, id)
-}
-
-// PublishNotice 发布通知
-func (s *NoticesServiceImpl) PublishNotice(ctx *gin.Context, id uint) error {
-	return s.repo.PublishNotice(ctx, id)
-}
-
-func (s *NoticesServiceImpl) PublishNoticeWithReceivers(ctx *gin.Context, id uint) error {
-	// 1. 参数校验
-	if ctx == nil {
-		return errors.New("gin context cannot be nil")
-	}
-
-	// 获取通知详情（包括接收者关系）
-	entity, err := s.repo.GetNoticeWithReceivers(ctx, id)
-	if err != nil {
-		return fmt.Errorf("获取通知详情失败: %w", err)
-	}
-	if entity == nil {
-		return errors.New("通知不存在")
-	}
-
-	// 2. 校验通知状态
-	if entity.Status == models.NoticeStatusPublished {
-		return errors.New("通知已发布，无需重复操作")
-	}
-
-	// 3. 校验基本字段
-	if entity.Title == "" {
-		return errors.New("标题不能为空")
-	}
-	if entity.TargetType < 1 || entity.TargetType > 4 {
-		return errors.New("目标类型无效")
-	}
-	if entity.TargetType == 4 && len(entity.TargetIDs) == 0 {
-		return errors.New("指定用户发布时，目标用户ID不能为空")
-	}
-
-	// 4. 开启事务
-	tx := s.repo.BeginTx(ctx)
-	if tx.Error != nil {
-		return fmt.Errorf("开启事务失败: %w", tx.Error)
-	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-			log.Printf("[PANIC] 事务回滚: %v", r)
-		}
-	}()
-
-	// 5. 更新通知状态为已发布
-	if err := s.repo.UpdateNoticeStatusTx(tx, id, models.NoticeStatusPublished); err != nil {
-		return fmt.Errorf("更新通知状态失败: %w", err)
+	entity, err := s.repo.GetNoticesByID(ctx, id)
+	if err != nil || entity == nil {
+		return entity, err
 	}
-
-	// 6. 处理接收者（支持重新发布）
-	if err := s.processNoticeReceiversForPublish(ctx, tx, entity); err != nil {
-		return fmt.Errorf("处理接收者失败: %w", err)
+	if err := s.attachments.FillAttachments(ctx, entity); err != nil {
+		return nil, err
 	}
+	return entity, nil
+}
 
-	// 7. 提交事务
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("提交事务失败: %w", err)
+// noticePushData 推送给客户端的通知摘要
+func noticePushData(entity *models.NoticesModel) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          entity.ID,
+		"title":       entity.Title,
+		"type":        entity.Type,
+		"level":       entity.Level,
+		"publishTime": time.Now(),
 	}
-
-	return nil
 }
 
 // 专门处理发布时的接收者逻辑
-func (s *NoticesServiceImpl) processNoticeReceiversForPublish(ctx *gin.Context, tx *gorm.DB, notice *models.NoticesModel) error {
+// 返回本次发布的接收用户ID
+func (s *NoticesServiceImpl) processNoticeReceiversForPublish(ctx *gin.Context, tx *gorm.DB, notice *models.NoticesModel) ([]uint, error) {
 	// 1. 获取目标用户ID
 	targetUserIDs, err := s.getTargetUserIDs(ctx, notice)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// 2. 由模板创建的通知，为每个接收人渲染个性化内容
+	contents, err := s.renderReceiverContents(tx, notice, targetUserIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	// 2. 批量处理接收者关系
+	// 3. 批量处理接收者关系
 	receivers := make([]models.NoticeReceiver, 0, len(targetUserIDs))
 	for _, userID := range targetUserIDs {
 		receivers = append(receivers, models.NoticeReceiver{
 			NoticeID:  notice.ID,
 			UserID:    userID,
 			IsRead:    0,
+			Content:   contents[userID],
 			CreatedAt: time.Now(),
 		})
 	}
 
-	// 3. 使用事务批量插入（存在则更新）
+	// 4. 使用事务批量插入（存在则更新）
 	if err := tx.Clauses(clause.OnConflict{
 		Columns: []clause.Column{
 			{Name: "notice_id"},
 			{Name: "user_id"},
 		},
-		DoUpdates: clause.Assignments(map[string]interface{}{
+		// 重新发布时内容可能已修改，个性化内容以本次渲染为准
+		DoUpdates: append(clause.Assignments(map[string]interface{}{
 			"is_read":    0,
 			"read_time":  nil,
 			"updated_at": time.Now(),
-		}),
+		}), clause.AssignmentColumns([]string{"content"})...),
 	}).CreateInBatches(receivers, 500).Error; err != nil {
-		return fmt.Errorf("批量处理接收者失败: %w", err)
+		return nil, fmt.Errorf("批量处理接收者失败: %w", err)
 	}
 
-	return nil
+	return targetUserIDs, nil
+}
+
+// renderReceiverContents 按模板为每个接收人渲染内容，非模板通知返回 nil
+// 渲染结果再过滤一次 HTML，模板中的注释、变量拼接都不能产生白名单外的内容
+func (s *NoticesServiceImpl) renderReceiverContents(tx *gorm.DB, notice *models.NoticesModel, userIDs []uint) (map[uint]*string, error) {
+	if notice.TemplateID == nil || len(userIDs) == 0 {
+		return nil, nil
+	}
+	tpl, err := noticetpl.Compile(notice.HTML())
+	if err != nil {
+		return nil, err
+	}
+	recipients, err := s.repo.ListTemplateRecipientsTx(tx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("查询接收人信息失败: %w", err)
+	}
+	now := time.Now()
+	contents := make(map[uint]*string, len(recipients))
+	for _, r := range recipients {
+		content, err := tpl.Render(noticetpl.Data{
+			User: noticetpl.User{ID: r.UserID, Username: r.Username, Nickname: r.Nickname, Email: r.Email, Mobile: r.Mobile},
+			Dept: noticetpl.Dept{ID: r.DeptID, Name: r.DeptName, Code: r.DeptCode},
+			Vars: notice.TemplateVars,
+			Now:  now,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("渲染用户 %s 的通知内容失败: %w", r.Username, err)
+		}
+		content = richtext.Sanitize(content)
+		contents[r.UserID] = &content
+	}
+	return contents, nil
+}
+
+// templateVarNames 自定义变量名列表
+func templateVarNames(vars map[string]string) []string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	return names
 }
 
 // 获取目标用户ID（根据通知类型）
@@ -229,16 +340,16 @@ func (s *NoticesServiceImpl) getTargetUserIDs(ctx *gin.Context, notice *models.N
 		return notice.TargetIDs, nil
 	case 3: // 指定部门
 		if len(notice.TargetIDs) == 0 {
-			return nil, errors.New("部门ID不能为空")
+			return nil, apperr.ErrNoticeDeptRequired
 		}
 		return s.getUserIDsByDepartments(ctx, notice.TargetIDs)
 	case 4: // 指定角色
 		if len(notice.TargetIDs) == 0 {
-			return nil, errors.New("角色ID不能为空")
+			return nil, apperr.ErrNoticeRoleRequired
 		}
 		return s.getUserIDsByRoles(ctx, notice.TargetIDs)
 	default:
-		return nil, errors.New("不支持的目标类型")
+		return nil, apperr.ErrNoticeInvalidTarget
 	}
 }
 
@@ -364,5 +475,26 @@ func (s *NoticesServiceImpl) MyPageNotices(ctx *gin.Context, userID uint, keywor
 
 // MarkAllAsRead 标记全部为已读
 func (s *NoticesServiceImpl) MarkAllAsRead(ctx *gin.Context, userID uint) error {
-	return s.repo.MarkAllAsRead(ctx, userID)
+	if err := s.repo.MarkAllAsRead(ctx, userID); err != nil {
+		return err
+	}
+	s.pushUnread(ctx, userID)
+	return nil
+}
+
+// CountUnread 统计用户未读通知数
+func (s *NoticesServiceImpl) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// pushUnread 推送用户最新未读数（同一用户的多个终端同步）
+func (s *NoticesServiceImpl) pushUnread(ctx *gin.Context, userID uint) {
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		log.Printf("查询未读数失败（用户ID：%d）：%v", userID, err)
+		return
+	}
+	if err := push.Publish(ctx, push.EventUnread, []uint{userID}, map[string]int64{"count": count}); err != nil {
+		log.Printf("推送未读数失败（用户ID：%d）：%v", userID, err)
+	}
 }
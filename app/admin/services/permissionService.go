@@ -6,8 +6,8 @@ import (
 
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/config"
-	"github.com/zmqge/vireo-gin-admin/pkg/annotations"
 	"github.com/zmqge/vireo-gin-admin/pkg/database"
+	"github.com/zmqge/vireo-gin-admin/pkg/routemeta"
 	"gorm.io/gorm"
 )
 
@@ -33,39 +33,22 @@ func (s *PermissionService) CheckPermission(userID, permission string) bool {
 	return count > 0
 }
 
-// 定义权限树节点结构
-type PermissionNode struct {
-	ID          uint             `json:"id"`
-	Name        string           `json:"name"`
-	Description string           `json:"description"`
-	Children    []PermissionNode `json:"children"`
-}
-
-func (s *PermissionService) GetPermissionTree() ([]models.PermissionNode, error) {
-	// 实现权限树逻辑
-	return nil, nil
+// GetPermissionTree 权限树：模块 -> 资源 -> 权限，一次查询后按 parent_id 组装
+func (s *PermissionService) GetPermissionTree() ([]*models.PermissionNode, error) {
+	var permissions []models.Permission
+	if err := s.DB.Order("module ASC, id ASC").Find(&permissions).Error; err != nil {
+		return nil, fmt.Errorf("查询权限树失败: %v", err)
+	}
+	return models.BuildPermissionTree(permissions), nil
 }
 
-// SyncPermissions 同步权限
-func (s *PermissionService) SyncPermissions() error {
-	// 扫描多个目录
-	permissions, err := annotations.ScanDirectories()
-	if err != nil {
-		return fmt.Errorf("扫描目录失败: %v", err)
+// MissingRoutePermissions 已注册路由的 RBAC 中引用、但 permissions 表中不存在的权限码
+func (s *PermissionService) MissingRoutePermissions() ([]string, error) {
+	var codes []string
+	if err := s.DB.Model(&models.Permission{}).Scopes(models.OnlyPermissionCodes).Pluck("code", &codes).Error; err != nil {
+		return nil, fmt.Errorf("查询权限码失败: %v", err)
 	}
-
-	// 将权限同步到数据库
-	for _, perm := range permissions {
-		// 处理单权限
-		if err := database.DB.Create(&models.Permission{
-			Code:        perm.Code,
-			Name:        perm.Name,
-			Description: perm.Description,
-		}).Error; err != nil {
-			return fmt.Errorf("插入权限失败: %v", err)
-		}
-	}
-	return nil
+	return routemeta.Missing(codes), nil
 }
 
 // GetUserRolesAndPermissions 获取用户的角色和权限
@@ -98,7 +81,7 @@ func (s *PermissionService) GetUserRolesAndPermissions(userID string) ([]string,
 	var permissions []string
 	if isSuperAdmin {
 		// 如果是超级管理员，加载所有权限
-		err = s.DB.Model(&models.Permission{}).
+		err = s.DB.Model(&models.Permission{}).Scopes(models.OnlyPermissionCodes).
 			Pluck("code", &permissions).Error
 		fmt.Printf("超级管理员加载所有权限，权限数量: %d\n", len(permissions))
 	} else {
@@ -118,29 +101,31 @@ func (s *PermissionService) GetUserRolesAndPermissions(userID string) ([]string,
 	return roles, permissions, nil
 }
 
-// ListPermOptions 列出权限选项（按模块分组，OptionLong格式）
+// ListPermOptions 列出权限选项：模块 -> 资源 -> 权限的树，分组节点 value 为 0
 func (s *PermissionService) ListPermOptions() ([]models.OptionPermLong, error) {
-	var permissions []models.Permission
-	if err := s.DB.Order("module ASC, id ASC").Find(&permissions).Error; err != nil {
+	tree, err := s.GetPermissionTree()
+	if err != nil {
 		return nil, fmt.Errorf("查询权限选项失败: %v", err)
 	}
-	// 按 module 分组
-	groupMap := make(map[string][]models.OptionPermLong)
-	for _, perm := range permissions {
-		opt := models.OptionPermLong{
-			Value: perm.Code, // OptionPermLong.Value 类型应与定义一致
-			Label: perm.Name + "   【" + perm.Code + "】",
+	return permOptions(tree), nil
+}
+
+// permOptions 将权限树转换为下拉选项，权限节点显示名称和权限码
+func permOptions(nodes []*models.PermissionNode) []models.OptionPermLong {
+	result := make([]models.OptionPermLong, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Code != "" {
+			result = append(result, models.OptionPermLong{
+				Value: node.Code,
+				Label: node.Name + "   【" + node.Code + "】",
+			})
+			continue
 		}
-		groupMap[perm.Module] = append(groupMap[perm.Module], opt)
-	}
-	// 组装分组 OptionPermLong
-	var result []models.OptionPermLong
-	for module, opts := range groupMap {
 		result = append(result, models.OptionPermLong{
-			Value:    "0", // 分组节点 value 设为0
-			Label:    module,
-			Children: opts,
+			Value:    "0",
+			Label:    node.Name,
+			Children: permOptions(node.Children),
 		})
 	}
-	return result, nil
+	return result
 }
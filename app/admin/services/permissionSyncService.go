@@ -0,0 +1,257 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/pkg/annotations"
+	"gorm.io/gorm"
+)
+
+// PermissionSyncOptions 权限同步选项
+type PermissionSyncOptions struct {
+	Deprecate bool // 代码中已删除的权限软删除而非物理删除，注解恢复后重新启用
+	DryRun    bool // 只计算变更，不写数据库
+}
+
+// PermissionSyncReport 权限同步结果，各列表为权限码
+type PermissionSyncReport struct {
+	Added     []string `json:"added"`     // 新增
+	Updated   []string `json:"updated"`   // 名称、描述或模块有变化
+	Restored  []string `json:"restored"`  // 已软删除的权限重新出现在注解中
+	Removed   []string `json:"removed"`   // 代码中已删除，按选项软删除或物理删除
+	Unchanged int      `json:"unchanged"` // 无变化
+	Modules   int      `json:"modules"`   // 权限树中的模块节点数
+	Resources int      `json:"resources"` // 权限树中的资源节点数
+}
+
+// ScanPermissions 扫描控制器目录中的 @Permission 注解，返回去重后的权限和重复的权限码及其所在文件
+func ScanPermissions(dirs []string) ([]models.Permission, map[string][]string, error) {
+	metas, duplicates, err := annotations.ScanPermissionAnnotations(dirs)
+	if err != nil {
+		return nil, nil, err
+	}
+	perms := make([]models.Permission, 0, len(metas))
+	for _, m := range metas {
+		perms = append(perms, models.Permission{Code: m.Code, Name: m.Name, Description: m.Description, Module: m.Module})
+	}
+	return perms, duplicates, nil
+}
+
+// permissionSyncPlan 同步需要执行的写操作
+type permissionSyncPlan struct {
+	create  []models.Permission
+	update  []models.Permission // 含需要恢复的软删除记录
+	restore []uint
+	remove  []uint
+	report  PermissionSyncReport
+}
+
+// planPermissionSync 按权限码对比数据库中的权限（含软删除）与注解中的权限
+// 已有权限保留 ID，因此按 ID 或权限码关联的授权不受影响
+func planPermissionSync(existing, scanned []models.Permission, opts PermissionSyncOptions) permissionSyncPlan {
+	var plan permissionSyncPlan
+	byCode := make(map[string]models.Permission, len(existing))
+	for _, p := range existing {
+		byCode[p.Code] = p
+	}
+	seen := make(map[string]bool, len(scanned))
+	for _, p := range scanned {
+		if p.Code == "" || seen[p.Code] {
+			continue
+		}
+		seen[p.Code] = true
+		old, ok := byCode[p.Code]
+		if !ok {
+			plan.create = append(plan.create, p)
+			plan.report.Added = append(plan.report.Added, p.Code)
+			continue
+		}
+		deleted := old.DeletedAt.Valid
+		changed := old.Name != p.Name || old.Description != p.Description || old.Module != p.Module
+		if changed {
+			p.ID = old.ID
+			plan.update = append(plan.update, p)
+		}
+		switch {
+		case deleted:
+			plan.restore = append(plan.restore, old.ID)
+			plan.report.Restored = append(plan.report.Restored, p.Code)
+		case changed:
+			plan.report.Updated = append(plan.report.Updated, p.Code)
+		default:
+			plan.report.Unchanged++
+		}
+	}
+	for _, p := range existing {
+		if seen[p.Code] {
+			continue
+		}
+		// 已软删除的权限不再重复报告，物理删除模式下一并清理
+		if p.DeletedAt.Valid && opts.Deprecate {
+			continue
+		}
+		plan.remove = append(plan.remove, p.ID)
+		plan.report.Removed = append(plan.report.Removed, p.Code)
+	}
+	sort.Strings(plan.report.Added)
+	sort.Strings(plan.report.Updated)
+	sort.Strings(plan.report.Restored)
+	sort.Strings(plan.report.Removed)
+	return plan
+}
+
+// SyncPermissions 将注解中的权限按权限码同步到 permissions 表：新增、更新名称/描述/模块、删除或软删除代码中已不存在的权限
+// 不修改 role_permissions，已有权限的 ID 和权限码不变，角色授权在同步后保持不变
+func (s *PermissionService) SyncPermissions(scanned []models.Permission, opts PermissionSyncOptions) (*PermissionSyncReport, error) {
+	var existing []models.Permission
+	if err := s.DB.Unscoped().Scopes(models.OnlyPermissionCodes).
+		Select("id", "code", "name", "description", "module", "deleted_at").Find(&existing).Error; err != nil {
+		return nil, fmt.Errorf("查询权限失败: %v", err)
+	}
+	plan := planPermissionSync(existing, scanned, opts)
+	plan.report.Modules, plan.report.Resources = countPermissionGroups(scanned)
+	if opts.DryRun {
+		return &plan.report, nil
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		for _, p := range plan.create {
+			p.Type = "api"
+			if err := tx.Select("code", "name", "description", "module", "type", "created_at", "updated_at").Create(&p).Error; err != nil {
+				return fmt.Errorf("新增权限 %s 失败: %v", p.Code, err)
+			}
+		}
+		for _, p := range plan.update {
+			if err := tx.Unscoped().Model(&models.Permission{}).Where("id = ?", p.ID).
+				Updates(map[string]interface{}{"name": p.Name, "description": p.Description, "module": p.Module}).Error; err != nil {
+				return fmt.Errorf("更新权限 %s 失败: %v", p.Code, err)
+			}
+		}
+		if len(plan.restore) > 0 {
+			if err := tx.Unscoped().Model(&models.Permission{}).Where("id IN ?", plan.restore).
+				Update("deleted_at", nil).Error; err != nil {
+				return fmt.Errorf("恢复权限失败: %v", err)
+			}
+		}
+		if len(plan.remove) > 0 {
+			del := tx
+			if !opts.Deprecate {
+				del = tx.Unscoped()
+			}
+			if err := del.Where("id IN ?", plan.remove).Delete(&models.Permission{}).Error; err != nil {
+				return fmt.Errorf("删除权限失败: %v", err)
+			}
+		}
+		return syncPermissionTree(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &plan.report, nil
+}
+
+// ungroupedModule 未设置 modules 的权限所在的模块名称
+const ungroupedModule = "未分组"
+
+// permissionGroupKey 权限所在的模块和资源
+func permissionGroupKey(p models.Permission) (module, resource string) {
+	return p.Module, models.PermissionResource(p.Code)
+}
+
+// countPermissionGroups 权限对应的模块、资源节点数
+func countPermissionGroups(perms []models.Permission) (modules, resources int) {
+	moduleSet := make(map[string]bool)
+	resourceSet := make(map[string]bool)
+	for _, p := range perms {
+		module, resource := permissionGroupKey(p)
+		moduleSet[module] = true
+		if resource != "" {
+			resourceSet[module+"|"+resource] = true
+		}
+	}
+	return len(moduleSet), len(resourceSet)
+}
+
+// syncPermissionTree 按模块名称和权限码生成 模块 -> 资源 -> 权限 的层级：
+// 模块节点按 @Permission 的 modules，资源节点为权限码去掉最后一段（同一资源在不同模块下各有一个节点），
+// 权限的 parent_id 指向资源节点；不再使用的分组节点删除。分组节点没有权限码，不参与授权
+func syncPermissionTree(tx *gorm.DB) error {
+	var nodes []models.Permission
+	if err := tx.Order("id ASC").Find(&nodes).Error; err != nil {
+		return fmt.Errorf("查询权限树失败: %v", err)
+	}
+	modules := make(map[string]*models.Permission)   // 模块名称 -> 模块节点
+	resources := make(map[string]*models.Permission) // 模块节点ID|资源 -> 资源节点
+	var perms []models.Permission
+	for i := range nodes {
+		n := &nodes[i]
+		switch n.Type {
+		case models.PermissionTypeModule:
+			modules[n.Module] = n
+		case models.PermissionTypeResource:
+			if n.ParentID != nil {
+				resources[fmt.Sprintf("%d|%s", *n.ParentID, n.Name)] = n
+			}
+		default:
+			perms = append(perms, *n)
+		}
+	}
+
+	used := make(map[uint]bool)
+	create := func(node *models.Permission) error {
+		if err := tx.Select("name", "module", "type", "parent_id", "created_at", "updated_at").Create(node).Error; err != nil {
+			return fmt.Errorf("新增权限节点 %s 失败: %v", node.Name, err)
+		}
+		return nil
+	}
+	for _, p := range perms {
+		module, resource := permissionGroupKey(p)
+		m, ok := modules[module]
+		if !ok {
+			name := module
+			if name == "" {
+				name = ungroupedModule
+			}
+			m = &models.Permission{Name: name, Module: module, Type: models.PermissionTypeModule}
+			if err := create(m); err != nil {
+				return err
+			}
+			modules[module] = m
+		}
+		used[m.ID] = true
+		parent := m.ID
+		if resource != "" {
+			key := fmt.Sprintf("%d|%s", m.ID, resource)
+			r, ok := resources[key]
+			if !ok {
+				r = &models.Permission{Name: resource, Module: module, Type: models.PermissionTypeResource, ParentID: &m.ID}
+				if err := create(r); err != nil {
+					return err
+				}
+				resources[key] = r
+			}
+			used[r.ID] = true
+			parent = r.ID
+		}
+		if p.ParentID == nil || *p.ParentID != parent {
+			if err := tx.Model(&models.Permission{}).Where("id = ?", p.ID).Update("parent_id", parent).Error; err != nil {
+				return fmt.Errorf("更新权限 %s 的上级失败: %v", p.Code, err)
+			}
+		}
+	}
+
+	var unused []uint
+	for _, n := range nodes {
+		if (n.Type == models.PermissionTypeModule || n.Type == models.PermissionTypeResource) && !used[n.ID] {
+			unused = append(unused, n.ID)
+		}
+	}
+	if len(unused) > 0 {
+		if err := tx.Unscoped().Where("id IN ?", unused).Delete(&models.Permission{}).Error; err != nil {
+			return fmt.Errorf("删除权限节点失败: %v", err)
+		}
+	}
+	return nil
+}
@@ -1,11 +1,11 @@
 package services
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"gorm.io/gorm"
 )
 
@@ -28,7 +28,7 @@ func (s *RoleService) CreateRole(role *models.Role) error {
 	roles, _ = s.repo.ListRoles(role.Name, "", "", 1, 1)
 	for _, r := range roles {
 		if r.Name == role.Name || r.Code == role.Code {
-			return fmt.Errorf("角色名称 '%s' 或者角色编码 '%s'已存在", role.Name, role.Code)
+			return apperr.ErrRoleExists.WithArgs(role.Name, role.Code)
 		}
 	}
 	return s.repo.CreateRole(role)
@@ -53,7 +53,7 @@ func (s *RoleService) GetRoleDetails(id string) (interface{}, error) {
 	var roleID uint
 	_, err := fmt.Sscanf(id, "%d", &roleID)
 	if err != nil {
-		return nil, errors.New("无效的角色ID")
+		return nil, apperr.ErrRoleInvalidID
 	}
 	role, err := s.repo.GetRoleByID(roleID)
 	if err != nil {
@@ -69,7 +69,7 @@ func (s *RoleService) UpdateRole(role *models.Role) error {
 	roles, _ := s.repo.ListRoles(role.Name, "", "", 1, 1)
 	for _, r := range roles {
 		if (r.Name == role.Name || r.Code == role.Code) && r.ID != role.ID {
-			return fmt.Errorf("角色名称 '%s' 或者角色编码 '%s' 已存在", role.Name, role.Code)
+			return apperr.ErrRoleExists.WithArgs(role.Name, role.Code)
 		}
 	}
 	return s.repo.UpdateRole(role)
@@ -80,7 +80,7 @@ func (s *RoleService) DeleteRole(id string) error {
 	var roleID uint
 	_, err := fmt.Sscanf(id, "%d", &roleID)
 	if err != nil {
-		return errors.New("无效的角色ID")
+		return apperr.ErrRoleInvalidID
 	}
 	// 检查是否有关联用户/权限等，可扩展repo方法
 	return s.repo.DeleteRole(roleID)
@@ -91,7 +91,7 @@ func (s *RoleService) GetRoleMenus(roleID string) ([]uint, error) {
 	var rid uint
 	_, err := fmt.Sscanf(roleID, "%d", &rid)
 	if err != nil {
-		return nil, errors.New("无效的角色ID")
+		return nil, apperr.ErrRoleInvalidID
 	}
 	return s.repo.GetRoleMenus(rid)
 }
@@ -148,7 +148,7 @@ func (s *RoleService) GetRolePerms(roleID string) ([]string, error) {
 	var rid uint
 	_, err := fmt.Sscanf(roleID, "%d", &rid)
 	if err != nil {
-		return nil, errors.New("无效的角色ID")
+		return nil, apperr.ErrRoleInvalidID
 	}
 	return s.repo.GetRolePermCodes(rid)
 }
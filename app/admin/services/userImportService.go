@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/redis"
+	"github.com/zmqge/vireo-gin-admin/pkg/scopes"
+	"github.com/zmqge/vireo-gin-admin/pkg/sysconfig"
+	"gorm.io/gorm"
+)
+
+const (
+	userImportReportKeyPrefix = "user:import:report:"
+	userImportReportTTL       = time.Hour
+	userImportMaxRows         = 5000
+)
+
+var (
+	usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.@-]{2,50}$`)
+	mobilePattern   = regexp.MustCompile(`^1[3-9]\d{9}$`)
+)
+
+// ParseUserImportRows 将表格行（首行为表头）解析为导入行
+// 表头既可以使用模板中的中文标题，也可以使用字段标识
+func ParseUserImportRows(rows [][]string) ([]models.UserImportRow, error) {
+	if len(rows) == 0 {
+		return nil, apperr.ErrImportFileEmpty
+	}
+	index := make(map[string]int)
+	for i, h := range rows[0] {
+		h = strings.TrimSuffix(strings.TrimSpace(h), "*")
+		for _, col := range models.UserImportColumns {
+			if strings.EqualFold(h, col.Key) || h == col.Title {
+				index[col.Key] = i
+			}
+		}
+	}
+	for _, col := range models.UserImportColumns {
+		if _, ok := index[col.Key]; col.Required && !ok {
+			return nil, apperr.ErrImportMissingColumn.WithArgs(col.Title)
+		}
+	}
+	cell := func(row []string, key string) string {
+		i, ok := index[key]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var result []models.UserImportRow
+	for i, row := range rows[1:] {
+		if isBlankRow(row) {
+			continue
+		}
+		item := models.UserImportRow{
+			RowNum:   i + 2,
+			Username: cell(row, "username"),
+			Nickname: cell(row, "nickname"),
+			Gender:   cell(row, "gender"),
+			Mobile:   cell(row, "mobile"),
+			Email:    cell(row, "email"),
+			DeptCode: cell(row, "deptCode"),
+			Status:   cell(row, "status"),
+			Password: cell(row, "password"),
+		}
+		for _, code := range strings.FieldsFunc(cell(row, "roleCodes"), func(r rune) bool {
+			return r == ',' || r == '，' || r == ';' || r == '；'
+		}) {
+			if code = strings.TrimSpace(code); code != "" {
+				item.RoleCodes = append(item.RoleCodes, code)
+			}
+		}
+		result = append(result, item)
+	}
+	if len(result) == 0 {
+		return nil, apperr.ErrImportNoRows
+	}
+	if len(result) > userImportMaxRows {
+		return nil, apperr.ErrImportTooManyRows.WithArgs(userImportMaxRows)
+	}
+	return result, nil
+}
+
+func isBlankRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseImportGender 性别支持字典值或中文标签
+func parseImportGender(v string) (string, bool) {
+	switch v {
+	case "", "0", "未知":
+		return "0", true
+	case "1", "男":
+		return "1", true
+	case "2", "女":
+		return "2", true
+	}
+	return "", false
+}
+
+// parseImportStatus 状态支持 1/0 或 启用/禁用，默认启用
+func parseImportStatus(v string) (int, bool) {
+	switch v {
+	case "", "1", "启用", "正常":
+		return 1, true
+	case "0", "禁用", "停用":
+		return 0, true
+	}
+	return 0, false
+}
+
+// userImportPlan 校验通过后待创建的用户
+type userImportPlan struct {
+	result  *models.UserImportRowResult
+	user    *models.User
+	roleIDs []uint
+}
+
+// userImportChecks 逐行校验用到的批量查询结果和规则
+type userImportChecks struct {
+	seen         map[string]int  // 文件中已出现的用户名 -> 行号
+	existing     map[string]bool // 数据库中已存在的用户名
+	deptIDs      map[string]uint
+	roleIDs      map[string]uint
+	deptInScope  func(deptID uint) bool // 部门是否在导入人的数据权限范围内
+	policy       sysconfig.PasswordPolicy
+	initPassword string // 未填写密码时的初始密码，不符合密码策略时为空
+	initErr      error
+}
+
+// ImportUsers 批量导入用户，报告归属导入人，只有导入人可以下载
+func (s *UserServiceImpl) ImportUsers(ctx *gin.Context, rows []models.UserImportRow, opts models.UserImportOptions) (*models.UserImportReport, error) {
+	if opts.Mode == "" {
+		opts.Mode = models.UserImportModeAll
+	}
+	if opts.Mode != models.UserImportModeAll && opts.Mode != models.UserImportModeSkip {
+		return nil, apperr.ErrImportInvalidMode.WithArgs(opts.Mode)
+	}
+
+	// 批量查询已存在的用户名、部门和角色，避免逐行查库
+	var usernames, deptCodes, roleCodes []string
+	for _, row := range rows {
+		if row.Username != "" {
+			usernames = append(usernames, row.Username)
+		}
+		if row.DeptCode != "" {
+			deptCodes = append(deptCodes, row.DeptCode)
+		}
+		roleCodes = append(roleCodes, row.RoleCodes...)
+	}
+	existing, err := s.repo.FindExistingUsernames(usernames)
+	if err != nil {
+		return nil, err
+	}
+	existingSet := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		existingSet[name] = true
+	}
+	deptIDs, err := s.repo.MapDeptIDsByCode(deptCodes)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs, err := s.repo.MapRoleIDsByCode(roleCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.UserImportReport{
+		ReportID: uuid.NewString(),
+		DryRun:   opts.DryRun,
+		Mode:     opts.Mode,
+		Total:    len(rows),
+		Rows:     make([]models.UserImportRowResult, len(rows)),
+	}
+	checks := &userImportChecks{
+		seen:        make(map[string]int, len(rows)),
+		existing:    existingSet,
+		deptIDs:     deptIDs,
+		roleIDs:     roleIDs,
+		deptInScope: func(deptID uint) bool { return scopes.DeptInScope(ctx, deptID) },
+		policy:      sysconfig.GetPasswordPolicy(),
+	}
+	checks.initPassword, checks.initErr = sysconfig.GetInitPassword()
+	var plans []userImportPlan
+	for i, row := range rows {
+		res := &report.Rows[i]
+		res.RowNum = row.RowNum
+		res.Username = row.Username
+		plan := validateImportRow(row, res, checks)
+		if len(res.Errors) > 0 {
+			res.Status = models.UserImportRowFailed
+			continue
+		}
+		res.Status = models.UserImportRowOK
+		plans = append(plans, plan)
+	}
+
+	failed := len(rows) - len(plans)
+	switch {
+	case opts.DryRun:
+		// 预检查不写库
+	case opts.Mode == models.UserImportModeAll && failed > 0:
+		for _, p := range plans {
+			p.result.Status = models.UserImportRowSkipped
+		}
+	case opts.Mode == models.UserImportModeAll:
+		if err := s.hashImportPasswords(plans); err != nil {
+			return nil, err
+		}
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			for _, p := range plans {
+				if err := s.repo.CreateUserTx(tx, p.user, p.roleIDs); err != nil {
+					return fmt.Errorf("第 %d 行写入失败: %w", p.result.RowNum, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range plans {
+			p.result.Status = models.UserImportRowCreated
+		}
+	default:
+		if err := s.hashImportPasswords(plans); err != nil {
+			return nil, err
+		}
+		for _, p := range plans {
+			err := s.db.Transaction(func(tx *gorm.DB) error {
+				return s.repo.CreateUserTx(tx, p.user, p.roleIDs)
+			})
+			if err != nil {
+				// 数据库错误只记录日志，报告中给出请求ID便于排查
+				log.Printf("[UserImport] request=%s 第 %d 行写入失败: %v", opts.RequestID, p.result.RowNum, err)
+				p.result.Status = models.UserImportRowFailed
+				p.result.Errors = append(p.result.Errors, apperr.ErrImportRowWriteFailed.WithArgs(opts.RequestID).Message())
+				continue
+			}
+			p.result.Status = models.UserImportRowCreated
+		}
+	}
+
+	for _, r := range report.Rows {
+		switch r.Status {
+		case models.UserImportRowOK, models.UserImportRowCreated:
+			report.Success++
+		case models.UserImportRowFailed:
+			report.Failed++
+		}
+	}
+	s.saveImportReport(ctx.GetString("userID"), report)
+	return report, nil
+}
+
+// validateImportRow 校验单行数据，错误写入 res.Errors
+func validateImportRow(row models.UserImportRow, res *models.UserImportRowResult, checks *userImportChecks) userImportPlan {
+	addErr := func(format string, args ...interface{}) {
+		res.Errors = append(res.Errors, fmt.Sprintf(format, args...))
+	}
+
+	switch {
+	case row.Username == "":
+		addErr("用户名不能为空")
+	case !usernamePattern.MatchString(row.Username):
+		addErr("用户名格式不正确（2-50位字母、数字或 _ . @ -）")
+	case checks.existing[row.Username]:
+		addErr("用户名 '%s' 已存在", row.Username)
+	default:
+		if first, ok := checks.seen[row.Username]; ok {
+			addErr("用户名 '%s' 与第 %d 行重复", row.Username, first)
+		} else {
+			checks.seen[row.Username] = row.RowNum
+		}
+	}
+	if row.Nickname == "" {
+		addErr("昵称不能为空")
+	}
+	gender, ok := parseImportGender(row.Gender)
+	if !ok {
+		addErr("性别 '%s' 无效", row.Gender)
+	}
+	status, ok := parseImportStatus(row.Status)
+	if !ok {
+		addErr("状态 '%s' 无效", row.Status)
+	}
+	if row.Mobile != "" && !mobilePattern.MatchString(row.Mobile) {
+		addErr("手机号 '%s' 格式不正确", row.Mobile)
+	}
+	if row.Email != "" {
+		if addr, err := mail.ParseAddress(row.Email); err != nil || addr.Address != row.Email {
+			addErr("邮箱 '%s' 格式不正确", row.Email)
+		}
+	}
+	var deptID uint
+	if row.DeptCode != "" {
+		if deptID, ok = checks.deptIDs[row.DeptCode]; !ok {
+			addErr("部门编码 '%s' 不存在", row.DeptCode)
+		} else if !checks.deptInScope(deptID) {
+			addErr("部门编码 '%s' 不在数据权限范围内", row.DeptCode)
+		}
+	} else if !checks.deptInScope(0) {
+		addErr("部门编码不能为空，只能导入到数据权限范围内的部门")
+	}
+	var rids []uint
+	for _, code := range row.RoleCodes {
+		rid, ok := checks.roleIDs[code]
+		if !ok {
+			addErr("角色编码 '%s' 不存在", code)
+			continue
+		}
+		rids = append(rids, rid)
+	}
+
+	password := row.Password
+	if password == "" {
+		password = checks.initPassword
+		if checks.initErr != nil {
+			addErr("%s", importErrorMessage(checks.initErr))
+		}
+	} else if err := checks.policy.Validate(password); err != nil {
+		addErr("初始密码不符合密码策略：%s", importErrorMessage(err))
+	}
+	return userImportPlan{
+		result: res,
+		user: &models.User{
+			Username: row.Username,
+			Nickname: row.Nickname,
+			Gender:   gender,
+			Mobile:   row.Mobile,
+			Email:    row.Email,
+			Status:   status,
+			DeptID:   deptID,
+			Password: password,
+		},
+		roleIDs: rids,
+	}
+}
+
+// importErrorMessage 应用错误的消息，写入导入报告
+func importErrorMessage(err error) string {
+	if e, ok := apperr.As(err); ok {
+		return e.Message()
+	}
+	return err.Error()
+}
+
+// hashImportPasswords 写库前统一加密密码，缩短事务持有时间
+func (s *UserServiceImpl) hashImportPasswords(plans []userImportPlan) error {
+	for _, p := range plans {
+		salt := RandSalt()
+		hashed, err := models.HashPasswordWithSalt(p.user.Password, salt)
+		if err != nil {
+			return err
+		}
+		p.user.Password = hashed
+		p.user.Salt = salt
+	}
+	return nil
+}
+
+// storedImportReport 缓存中的导入报告，记录导入人
+type storedImportReport struct {
+	CreatorID string                   `json:"creatorId"`
+	Report    *models.UserImportReport `json:"report"`
+}
+
+// saveImportReport 缓存导入报告，供导入人下载
+func (s *UserServiceImpl) saveImportReport(creatorID string, report *models.UserImportReport) {
+	if redis.Client == nil {
+		return
+	}
+	data, err := json.Marshal(storedImportReport{CreatorID: creatorID, Report: report})
+	if err != nil {
+		return
+	}
+	redis.Client.Set(context.Background(), userImportReportKeyPrefix+report.ReportID, data, userImportReportTTL)
+}
+
+// GetImportReport 获取导入报告，只有导入人可以获取，其他用户与报告不存在时相同
+func (s *UserServiceImpl) GetImportReport(ctx *gin.Context, reportID string) (*models.UserImportReport, error) {
+	if redis.Client == nil {
+		return nil, apperr.ErrImportReportNotFound
+	}
+	data, err := redis.Client.Get(context.Background(), userImportReportKeyPrefix+reportID).Bytes()
+	if err != nil {
+		return nil, apperr.ErrImportReportNotFound
+	}
+	var stored storedImportReport
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	if stored.Report == nil || stored.CreatorID == "" || stored.CreatorID != ctx.GetString("userID") {
+		return nil, apperr.ErrImportReportNotFound
+	}
+	return stored.Report, nil
+}
+
+// UserImportTemplateRows 生成导入模板（表头 + 示例行）
+func UserImportTemplateRows() [][]string {
+	header := make([]string, 0, len(models.UserImportColumns))
+	example := make([]string, 0, len(models.UserImportColumns))
+	for _, col := range models.UserImportColumns {
+		title := col.Title
+		if col.Required {
+			title += "*"
+		}
+		header = append(header, title)
+		example = append(example, col.Example)
+	}
+	return [][]string{header, example}
+}
+
+// UserImportReportRows 将导入报告转换为表格行
+func UserImportReportRows(report *models.UserImportReport) [][]string {
+	rows := [][]string{{"行号", "用户名", "结果", "错误信息"}}
+	statusText := map[string]string{
+		models.UserImportRowOK:      "校验通过",
+		models.UserImportRowCreated: "已导入",
+		models.UserImportRowFailed:  "失败",
+		models.UserImportRowSkipped: "未导入",
+	}
+	for _, r := range report.Rows {
+		rows = append(rows, []string{
+			strconv.Itoa(r.RowNum),
+			r.Username,
+			statusText[r.Status],
+			strings.Join(r.Errors, "；"),
+		})
+	}
+	return rows
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/pkg/sysconfig"
+)
+
+func TestValidateImportRowChecksScopeAndPassword(t *testing.T) {
+	newChecks := func() *userImportChecks {
+		return &userImportChecks{
+			seen:         map[string]int{},
+			existing:     map[string]bool{},
+			deptIDs:      map[string]uint{"RD": 1, "HR": 2},
+			roleIDs:      map[string]uint{},
+			deptInScope:  func(deptID uint) bool { return deptID == 1 },
+			policy:       sysconfig.PasswordPolicy{MinLength: 8},
+			initPassword: "Init2026pass",
+		}
+	}
+	validate := func(row models.UserImportRow, checks *userImportChecks) []string {
+		var res models.UserImportRowResult
+		validateImportRow(row, &res, checks)
+		return res.Errors
+	}
+
+	ok := models.UserImportRow{RowNum: 2, Username: "zhangsan", Nickname: "张三", DeptCode: "RD"}
+	if errs := validate(ok, newChecks()); len(errs) != 0 {
+		t.Fatalf("范围内的部门和初始密码应通过校验: %v", errs)
+	}
+
+	outOfScope := ok
+	outOfScope.DeptCode = "HR"
+	if errs := validate(outOfScope, newChecks()); len(errs) != 1 {
+		t.Fatalf("数据权限范围外的部门应报错: %v", errs)
+	}
+	noDept := ok
+	noDept.DeptCode = ""
+	if errs := validate(noDept, newChecks()); len(errs) != 1 {
+		t.Fatalf("受限用户未填写部门应报错: %v", errs)
+	}
+
+	weak := ok
+	weak.Password = "123456"
+	if errs := validate(weak, newChecks()); len(errs) != 1 {
+		t.Fatalf("不符合密码策略的密码应报错: %v", errs)
+	}
+	checks := newChecks()
+	checks.initPassword, checks.initErr = "", errors.New("初始密码不符合密码策略")
+	if errs := validate(ok, checks); len(errs) != 1 {
+		t.Fatalf("初始密码不符合策略时未填写密码的行应报错: %v", errs)
+	}
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/app/admin/models"
 	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/database"
+	"github.com/zmqge/vireo-gin-admin/pkg/sysconfig"
 	"github.com/zmqge/vireo-gin-admin/utils"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -35,6 +37,8 @@ type UserService interface {
 	ResetPassword(userID string, password string) error
 	VerifyUser(username, password string) (*models.User, error)
 	UpdateLastLogin(userID uint, ClientIP string, loginTime time.Time, userAgent string) error
+	ImportUsers(ctx *gin.Context, rows []models.UserImportRow, opts models.UserImportOptions) (*models.UserImportReport, error)
+	GetImportReport(ctx *gin.Context, reportID string) (*models.UserImportReport, error)
 }
 type UserServiceImpl struct {
 	db   *gorm.DB
@@ -72,6 +76,9 @@ func RandSalt() string {
 }
 
 func (s *UserServiceImpl) CreateUser(username, password string) error {
+	if err := sysconfig.GetPasswordPolicy().Validate(password); err != nil {
+		return err
+	}
 	salt := RandSalt()
 	hashedPassword, err := models.HashPasswordWithSalt(password, salt)
 	if err != nil {
@@ -92,7 +99,7 @@ func (s *UserServiceImpl) VerifyUser(username, password string) (*models.User, e
 	}
 	// 校验状态
 	if user.Status != 1 {
-		return nil, fmt.Errorf("用户已被禁用")
+		return nil, apperr.ErrUserDisabled
 	}
 	// 校验密码
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password+user.Salt)); err != nil {
@@ -165,8 +172,16 @@ func (s *UserServiceImpl) GetUserRoles(userID string) ([]models.Role, error) {
 	return user.RoleList, nil
 }
 
-// CreateUserFull 创建用户及角色
+// CreateUserFull 创建用户及角色，未填写密码时使用系统配置的初始密码，密码需符合密码策略
 func (s *UserServiceImpl) CreateUserFull(username, nickname, mobile string, gender string, avatar, email string, status int, deptId uint, roleIds []int64, openId, password string) error {
+	if password == "" {
+		var err error
+		if password, err = sysconfig.GetInitPassword(); err != nil {
+			return err
+		}
+	} else if err := sysconfig.GetPasswordPolicy().Validate(password); err != nil {
+		return err
+	}
 	return s.repo.CreateUserFull(username, nickname, mobile, gender, avatar, email, status, deptId, roleIds, openId, password)
 }
 
@@ -176,6 +191,9 @@ func (s *UserServiceImpl) ResetPassword(userID string, password string) error {
 	if err != nil {
 		return err
 	}
+	if err := sysconfig.GetPasswordPolicy().Validate(password); err != nil {
+		return err
+	}
 	salt := RandSalt()
 	hash, err := models.HashPasswordWithSalt(password, salt)
 	if err != nil {
@@ -192,14 +210,17 @@ func (s *UserServiceImpl) ChangePassword(userID, oldPassword, newPassword string
 	}
 	user, err := s.repo.GetByID(uint(uid))
 	if err != nil {
-		return fmt.Errorf("用户不存在")
+		return apperr.ErrUserNotFound.Wrap(err)
 	}
 	// 校验原密码
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword+user.Salt)); err != nil {
-		return fmt.Errorf("原密码错误")
+		return apperr.ErrPasswordIncorrect
 	}
 	if oldPassword == newPassword {
-		return fmt.Errorf("新密码不能与原密码相同")
+		return apperr.ErrPasswordUnchanged
+	}
+	if err := sysconfig.GetPasswordPolicy().Validate(newPassword); err != nil {
+		return err
 	}
 	salt := RandSalt()
 	hash, err := models.HashPasswordWithSalt(newPassword, salt)
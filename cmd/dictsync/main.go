@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/config"
+	"github.com/zmqge/vireo-gin-admin/pkg/database"
+	"github.com/zmqge/vireo-gin-admin/pkg/dictbundle"
+	"github.com/zmqge/vireo-gin-admin/pkg/redis"
+)
+
+func main() {
+	apply := flag.String("apply", "", "要导入的字典包文件（.json/.yaml/.yml）")
+	export := flag.String("export", "", "导出字典包到文件（.json/.yaml/.yml）")
+	codes := flag.String("codes", "", "导出的字典编码，逗号分隔，留空导出全部")
+	dryRun := flag.Bool("dry-run", false, "仅预览导入的变更，不写入数据库")
+	flag.Parse()
+
+	if (*apply == "") == (*export == "") {
+		fmt.Println("请指定 -apply 或 -export 其中之一")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// 初始化配置和数据库
+	config.Init()
+	db := database.InitDB()
+	defer database.Close()
+	// 导入后需通知运行中的服务清除字典缓存，Redis 不可用时仅提示
+	if err := redis.Init(); err != nil {
+		fmt.Printf("警告：Redis 连接失败，运行中的服务需等待字典缓存过期: %v\n", err)
+		redis.Client = nil
+	}
+	service := services.NewDictService(repositories.NewDictRepository(db))
+
+	if *export != "" {
+		if err := exportBundle(service, *export, *codes); err != nil {
+			fmt.Println("导出失败:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := applyBundle(service, *apply, *dryRun); err != nil {
+		fmt.Println("导入失败:", err)
+		os.Exit(1)
+	}
+}
+
+func exportBundle(service services.DictService, path, codes string) error {
+	format, err := dictbundle.FormatOf(path)
+	if err != nil {
+		return err
+	}
+	var list []string
+	if codes != "" {
+		list = strings.Split(codes, ",")
+	}
+	bundle, err := service.ExportDicts(list)
+	if err != nil {
+		return err
+	}
+	data, err := dictbundle.Encode(bundle, format)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("已导出 %d 个字典到 %s\n", len(bundle.Dicts), path)
+	return nil
+}
+
+func applyBundle(service services.DictService, path string, dryRun bool) error {
+	format, err := dictbundle.FormatOf(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	bundle, err := dictbundle.Decode(data, format)
+	if err != nil {
+		return err
+	}
+	plan, err := service.ImportDicts(bundle, dryRun)
+	if err != nil {
+		return err
+	}
+	for _, c := range plan.Changes {
+		if c.Action == dictbundle.ActionUnchanged {
+			continue
+		}
+		target := c.DictCode
+		if c.Value != "" {
+			target += "/" + c.Value
+		}
+		fmt.Printf("%-7s %s\n", c.Action, target)
+		for _, f := range c.Fields {
+			fmt.Printf("        %s: %q -> %q\n", f.Field, f.From, f.To)
+		}
+	}
+	if dryRun {
+		fmt.Println("预览（未写入）：", plan)
+	} else {
+		fmt.Println("导入完成：", plan)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Field 生成代码使用的字段描述
+type Field struct {
+	Name       string // Go 字段名
+	Column     string // 列名
+	JSONName   string // JSON 字段名（小驼峰）
+	GoType     string
+	GormTag    string
+	Binding    string // 请求参数的 binding 规则
+	Comment    string
+	Primary    bool
+	Searchable bool   // 参与关键词模糊查询
+	Sortable   bool   // 允许排序
+	Dict       string // 字典编码
+	Writable   bool   // 出现在新增/编辑的请求参数中
+	Hidden     bool   // 不出现在响应中
+}
+
+// auditColumns 由框架维护的列，不出现在请求参数中
+var auditColumns = map[string]bool{
+	"id":         true,
+	"creator_id": true,
+	"dept_id":    true,
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+}
+
+// buildFields 将表结构转为字段描述
+func buildFields(t *Table) ([]Field, error) {
+	var fields []Field
+	hasID := false
+	for _, col := range t.Columns {
+		if col.IsPrimary() && col.Name != "id" {
+			return nil, fmt.Errorf("表 %s 的主键为 %s，仅支持以 id 为主键的表", t.Name, col.Name)
+		}
+		f := Field{
+			Name:     toPascalCase(col.Name),
+			Column:   col.Name,
+			JSONName: toLowerCamelCase(col.Name),
+			GoType:   goType(col),
+			Comment:  col.Comment,
+			Primary:  col.IsPrimary(),
+			Writable: !auditColumns[col.Name],
+			Hidden:   col.Name == "deleted_at",
+		}
+		if f.Primary {
+			hasID = true
+			f.GoType = "uint"
+		}
+		f.GormTag = gormTag(col)
+		if f.Writable {
+			f.Binding = binding(col, f.GoType)
+			f.Searchable = isStringColumn(col.DataType) && !isTextColumn(col.DataType)
+		}
+		fields = append(fields, f)
+	}
+	if !hasID {
+		return nil, fmt.Errorf("表 %s 没有 id 主键", t.Name)
+	}
+	return fields, nil
+}
+
+// goType 列类型对应的 Go 类型
+func goType(col Column) string {
+	unsigned := strings.Contains(col.FullType, "unsigned")
+	switch col.DataType {
+	case "tinyint":
+		if col.FullType == "tinyint(1)" {
+			return "bool"
+		}
+		fallthrough
+	case "smallint", "mediumint", "int", "integer":
+		if unsigned {
+			return "uint"
+		}
+		return "int"
+	case "bigint":
+		if unsigned {
+			return "uint64"
+		}
+		return "int64"
+	case "float", "double", "decimal":
+		return "float64"
+	case "date", "datetime", "timestamp":
+		if col.Name == "deleted_at" {
+			return "gorm.DeletedAt"
+		}
+		// created_at/updated_at 由 GORM 自动填充，不需要区分 NULL
+		if col.IsNullable() && col.Name != "created_at" && col.Name != "updated_at" {
+			return "*time.Time"
+		}
+		return "time.Time"
+	case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob":
+		return "[]byte"
+	}
+	return "string"
+}
+
+// gormTag 生成 gorm 标签，保留列名、主键、长度、非空、默认值和注释
+func gormTag(col Column) string {
+	parts := []string{"column:" + col.Name}
+	if col.IsPrimary() {
+		parts = append(parts, "primaryKey")
+	}
+	if col.IsAutoIncrement() {
+		parts = append(parts, "autoIncrement")
+	}
+	switch {
+	case (col.DataType == "varchar" || col.DataType == "char") && col.MaxLength != nil:
+		parts = append(parts, "size:"+strconv.FormatInt(*col.MaxLength, 10))
+	case isTextColumn(col.DataType), col.DataType == "decimal", col.DataType == "json", col.DataType == "enum":
+		parts = append(parts, "type:"+col.FullType)
+	}
+	if !col.IsNullable() && !col.IsPrimary() {
+		parts = append(parts, "not null")
+	}
+	if col.Key == "UNI" {
+		parts = append(parts, "uniqueIndex")
+	} else if col.Key == "MUL" {
+		parts = append(parts, "index")
+	}
+	if col.Default != nil && *col.Default != "" && !strings.Contains(strings.ToUpper(*col.Default), "CURRENT_TIMESTAMP") {
+		parts = append(parts, "default:"+*col.Default)
+	}
+	if col.Comment != "" {
+		parts = append(parts, "comment:"+sanitizeTag(col.Comment))
+	}
+	return strings.Join(parts, ";")
+}
+
+// binding 由非空约束和长度生成请求参数的校验规则
+// 非空且没有默认值的列为必填（bool 的零值有意义，不做必填校验）
+func binding(col Column, goType string) string {
+	var rules []string
+	if !col.IsNullable() && col.Default == nil && !col.IsAutoIncrement() && goType != "bool" {
+		rules = append(rules, "required")
+	}
+	if (col.DataType == "varchar" || col.DataType == "char") && col.MaxLength != nil {
+		rules = append(rules, "max="+strconv.FormatInt(*col.MaxLength, 10))
+	}
+	return strings.Join(rules, ",")
+}
+
+func isStringColumn(dataType string) bool {
+	switch dataType {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set":
+		return true
+	}
+	return false
+}
+
+// isTextColumn 大文本列不参与关键词查询
+func isTextColumn(dataType string) bool {
+	switch dataType {
+	case "tinytext", "text", "mediumtext", "longtext":
+		return true
+	}
+	return false
+}
+
+// sanitizeTag 去掉会破坏结构体标签的字符
+func sanitizeTag(s string) string {
+	return strings.NewReplacer("`", "'", `"`, "'", ";", "，", "\n", " ", "\r", "").Replace(s)
+}
+
+// commonInitialisms 按 Go 命名习惯全部大写的缩写
+var commonInitialisms = map[string]string{
+	"id": "ID", "ids": "IDs", "url": "URL", "ip": "IP", "api": "API", "uuid": "UUID", "html": "HTML", "json": "JSON",
+}
+
+// toPascalCase 将下划线命名转为大驼峰，如 dept_id -> DeptID
+func toPascalCase(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		if v, ok := commonInitialisms[strings.ToLower(part)]; ok {
+			b.WriteString(v)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// toLowerCamelCase 将下划线命名转为小驼峰，如 dept_id -> deptId
+func toLowerCamelCase(s string) string {
+	parts := strings.Split(s, "_")
+	var b strings.Builder
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		if i == 0 {
+			b.WriteString(strings.ToLower(part))
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zmqge/vireo-gin-admin/pkg/annotations"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// productTable information_schema 查询结果的内存夹具
+func productTable() *Table {
+	return &Table{
+		Name:    "biz_product",
+		Comment: "商品表",
+		Columns: []Column{
+			{Name: "id", DataType: "bigint", FullType: "bigint unsigned", Nullable: "NO", Key: "PRI", Extra: "auto_increment"},
+			{Name: "name", DataType: "varchar", FullType: "varchar(64)", Nullable: "NO", MaxLength: ptr(int64(64)), Comment: "商品名称"},
+			{Name: "code", DataType: "varchar", FullType: "varchar(32)", Nullable: "NO", MaxLength: ptr(int64(32)), Key: "UNI", Comment: "商品编码"},
+			{Name: "price", DataType: "decimal", FullType: "decimal(10,2)", Nullable: "NO", Default: ptr("0.00"), Comment: "价格"},
+			{Name: "on_sale", DataType: "tinyint", FullType: "tinyint(1)", Nullable: "NO", Default: ptr("1"), Comment: "是否上架"},
+			{Name: "stock", DataType: "int", FullType: "int", Nullable: "NO", Comment: "库存"},
+			{Name: "description", DataType: "text", FullType: "text", Nullable: "YES", Comment: "描述"},
+			{Name: "launched_at", DataType: "datetime", FullType: "datetime", Nullable: "YES", Comment: "上架时间"},
+			{Name: "creator_id", DataType: "bigint", FullType: "bigint unsigned", Nullable: "YES", Key: "MUL"},
+			{Name: "dept_id", DataType: "int", FullType: "int unsigned", Nullable: "YES", Key: "MUL"},
+			{Name: "created_at", DataType: "datetime", FullType: "datetime(3)", Nullable: "YES", Default: ptr("CURRENT_TIMESTAMP(3)")},
+			{Name: "updated_at", DataType: "datetime", FullType: "datetime(3)", Nullable: "YES"},
+			{Name: "deleted_at", DataType: "datetime", FullType: "datetime(3)", Nullable: "YES", Key: "MUL"},
+		},
+	}
+}
+
+func TestBuildFields(t *testing.T) {
+	fields, err := buildFields(productTable())
+	if err != nil {
+		t.Fatal(err)
+	}
+	byColumn := make(map[string]Field)
+	for _, f := range fields {
+		byColumn[f.Column] = f
+	}
+	cases := []struct {
+		column, name, goType, gormTag, binding string
+		writable, searchable                   bool
+	}{
+		{"id", "ID", "uint", "column:id;primaryKey;autoIncrement", "", false, false},
+		{"name", "Name", "string", "column:name;size:64;not null;comment:商品名称", "required,max=64", true, true},
+		{"code", "Code", "string", "column:code;size:32;not null;uniqueIndex;comment:商品编码", "required,max=32", true, true},
+		{"price", "Price", "float64", "column:price;type:decimal(10,2);not null;default:0.00;comment:价格", "", true, false},
+		{"on_sale", "OnSale", "bool", "column:on_sale;not null;default:1;comment:是否上架", "", true, false},
+		{"stock", "Stock", "int", "column:stock;not null;comment:库存", "required", true, false},
+		{"description", "Description", "string", "column:description;type:text;comment:描述", "", true, false},
+		{"launched_at", "LaunchedAt", "*time.Time", "column:launched_at;comment:上架时间", "", true, false},
+		{"creator_id", "CreatorID", "uint64", "column:creator_id;index", "", false, false},
+		{"created_at", "CreatedAt", "time.Time", "column:created_at", "", false, false},
+		{"deleted_at", "DeletedAt", "gorm.DeletedAt", "column:deleted_at;index", "", false, false},
+	}
+	for _, c := range cases {
+		f := byColumn[c.column]
+		if f.Name != c.name || f.GoType != c.goType || f.GormTag != c.gormTag || f.Binding != c.binding ||
+			f.Writable != c.writable || f.Searchable != c.searchable {
+			t.Errorf("列 %s 生成的字段不正确: %+v", c.column, f)
+		}
+	}
+	if !byColumn["deleted_at"].Hidden || byColumn["created_at"].Hidden {
+		t.Error("仅 deleted_at 不应出现在响应中")
+	}
+}
+
+func TestBuildFieldsRequiresID(t *testing.T) {
+	table := &Table{Name: "t", Columns: []Column{{Name: "code", DataType: "varchar", Key: "PRI"}}}
+	if _, err := buildFields(table); err == nil {
+		t.Fatal("主键不是 id 的表应报错")
+	}
+}
+
+func TestRenderTemplates(t *testing.T) {
+	data, err := newTemplateData(productTable(), toPascalCase("biz_product"), "github.com/zmqge/vireo-gin-admin", "app/admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Entity != "BizProduct" || data.Title != "商品" || !data.DataScope {
+		t.Fatalf("模板数据不正确: %+v", data)
+	}
+	want := map[string][]string{
+		"model.tmpl": {
+			`return "biz_product"`,
+			"type BizProductRequest struct",
+			`Name        string     ` + "`" + `json:"name" binding:"required,max=64"` + "`",
+			"c.CreatorID = uint64(UserID)",
+			"c.DeptID = deptID",
+		},
+		"repository.tmpl": {"\"`name` LIKE ? OR `code` LIKE ?\", like, like", "scopes.DataPermissionScope(ctx)"},
+		"service.tmpl":    {"req.Apply(entity)", "apperr.ErrNotFound"},
+		"controller.tmpl": {
+			`@Group(path="/api/v1/", name="商品管理", middlewares=["jwt"])`,
+			`@Route(method=GET, path="/bizproduct/page", middlewares=["dataperm"])`,
+			`@Permission(code="sys:bizproduct:add",name="新建商品",modules="商品管理", desc="创建商品")`,
+			"apperr.FromBinding(err)",
+		},
+	}
+	for _, gf := range genFiles {
+		src, err := render(filepath.Join("templates", gf.template), data)
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", gf.template, err, src)
+		}
+		for _, s := range want[gf.template] {
+			if !strings.Contains(string(src), s) {
+				t.Errorf("%s 生成的代码缺少 %q", gf.template, s)
+			}
+		}
+	}
+}
+
+func TestRenderDefaultTable(t *testing.T) {
+	data, err := newTemplateData(defaultTable("Widget"), "Widget", "github.com/zmqge/vireo-gin-admin", "app/admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, gf := range genFiles {
+		if src, err := render(filepath.Join("templates", gf.template), data); err != nil {
+			t.Fatalf("%s: %v\n%s", gf.template, err, src)
+		}
+	}
+}
+
+func TestNaming(t *testing.T) {
+	cases := map[string][2]string{
+		"dept_id":     {"DeptID", "deptId"},
+		"avatar_url":  {"AvatarURL", "avatarUrl"},
+		"target_ids":  {"TargetIDs", "targetIds"},
+		"name":        {"Name", "name"},
+		"biz_product": {"BizProduct", "bizProduct"},
+	}
+	for in, want := range cases {
+		if got := toPascalCase(in); got != want[0] {
+			t.Errorf("toPascalCase(%q) = %q, want %q", in, got, want[0])
+		}
+		if got := toLowerCamelCase(in); got != want[1] {
+			t.Errorf("toLowerCamelCase(%q) = %q, want %q", in, got, want[1])
+		}
+	}
+}
+
+func TestLoadSpec(t *testing.T) {
+	spec, err := loadSpec("examples/article.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := newTemplateDataFromSpec(spec, "github.com/zmqge/vireo-gin-admin", "app/admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Tree || !data.DataScope || data.Label == nil || data.Label.Column != "title" {
+		t.Fatalf("模板数据不正确: %+v", data)
+	}
+	if len(data.BelongsTo) != 1 || data.BelongsTo[0].ForeignKey != "CategoryID" || data.BelongsTo[0].LabelJSON != "categoryName" {
+		t.Errorf("belongsTo 不正确: %+v", data.BelongsTo)
+	}
+	if len(data.ManyToMany) != 1 || data.ManyToMany[0].JoinTable != "biz_article_tag" || data.ManyToMany[0].IDsName != "TagIDs" {
+		t.Errorf("manyToMany 不正确: %+v", data.ManyToMany)
+	}
+	want := map[string][]string{
+		"model.tmpl": {
+			`gorm:"many2many:biz_article_tag;joinForeignKey:article_id;joinReferences:tag_id"`,
+			`CategoryID  uint           ` + "`" + `json:"categoryId" gorm:"column:category_id;not null;index;default:0;comment:CategoryID"` + "`",
+			`Status    string ` + "`" + `form:"status"`,
+			`dict:"article_status"`,
+			"vo.CategoryName = m.Category.Name",
+		},
+		"repository.tmpl": {
+			`"publishedAt": "published_at"`,
+			`.Preload("Category").Preload("Tags")`,
+			`Association("Tags").Replace(items)`,
+			".Omit(clause.Associations)",
+		},
+		"service.tmpl":    {"s.repo.ReplaceArticleTags(ctx, entity, req.TagIDs)", "return s.GetArticleByID(ctx, id)"},
+		"controller.tmpl": {`path="/article/options"`, "ctx.ShouldBindQuery(&query)"},
+	}
+	for _, gf := range genFiles {
+		src, err := render(filepath.Join("templates", gf.template), data)
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", gf.template, err, src)
+		}
+		for _, s := range want[gf.template] {
+			if !strings.Contains(string(src), s) {
+				t.Errorf("%s 生成的代码缺少 %q", gf.template, s)
+			}
+		}
+	}
+}
+
+func TestSpecTree(t *testing.T) {
+	spec, err := loadSpec("examples/category.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := newTemplateDataFromSpec(spec, "github.com/zmqge/vireo-gin-admin", "app/admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !data.Tree || data.DataScope || data.ListOrder() != "sort asc, id asc" {
+		t.Fatalf("模板数据不正确: %+v", data)
+	}
+	want := map[string][]string{
+		"model.tmpl":      {"[]*CategoryVO `json:\"children,omitempty\"`", "[]*CategoryOption `json:\"children,omitempty\"`", `binding:"max=50,alphanum"`},
+		"repository.tmpl": {`Where("parent_id = ?", id)`},
+		"service.tmpl":    {"apperr.ErrInvalidParent", "apperr.ErrHasChildren", "GetCategoryTree(ctx *gin.Context) ([]*models.CategoryVO, error)"},
+		"controller.tmpl": {`path="/category/tree"`, `code="sys:category:tree"`, `path="/category/options"`},
+	}
+	for _, gf := range genFiles {
+		src, err := render(filepath.Join("templates", gf.template), data)
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", gf.template, err, src)
+		}
+		for _, s := range want[gf.template] {
+			if !strings.Contains(string(src), s) {
+				t.Errorf("%s 生成的代码缺少 %q", gf.template, s)
+			}
+		}
+	}
+}
+
+func TestSpecNormalizeErrors(t *testing.T) {
+	cases := map[string]Spec{
+		"缺少实体名":     {},
+		"无效列名":      {Entity: "Foo", Fields: []FieldSpec{{Name: "Bad-Name"}}},
+		"重复列":       {Entity: "Foo", Fields: []FieldSpec{{Name: "code"}, {Name: "code"}}},
+		"与审计列冲突":    {Entity: "Foo", Fields: []FieldSpec{{Name: "created_at"}}},
+		"树形冲突":      {Entity: "Foo", Tree: true, Fields: []FieldSpec{{Name: "parent_id"}}},
+		"不支持的类型":    {Entity: "Foo", Fields: []FieldSpec{{Name: "code", Type: "uuid"}}},
+		"不支持的关联":    {Entity: "Foo", Relations: []RelationSpec{{Name: "Bar", Type: "hasMany"}}},
+		"label 不存在": {Entity: "Foo", Label: "name"},
+	}
+	for name, spec := range cases {
+		if err := spec.normalize(); err == nil {
+			t.Errorf("%s: 应报错", name)
+		}
+	}
+	spec := Spec{Entity: "productTag"}
+	if err := spec.normalize(); err != nil || spec.Entity != "ProductTag" || spec.Table != "product_tag" {
+		t.Errorf("默认值不正确: %+v, %v", spec, err)
+	}
+}
+
+func TestBuildMigration(t *testing.T) {
+	spec, err := loadSpec("examples/article.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := newTemplateDataFromSpec(spec, "github.com/zmqge/vireo-gin-admin", "app/admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 权限取自生成的控制器注解
+	src, err := render(filepath.Join("templates", "controller.tmpl"), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	controllerFile := filepath.Join(t.TempDir(), "articleController.go")
+	if err := os.WriteFile(controllerFile, src, 0644); err != nil {
+		t.Fatal(err)
+	}
+	perms, err := annotations.ParsePermissionAnnotations(controllerFile)
+	if err != nil || len(perms) != 7 {
+		t.Fatalf("解析权限注解: %d, %v", len(perms), err)
+	}
+
+	m := buildMigration(spec.table(), data, perms, SeedOptions{CreateTable: true, MenuParent: "System", Roles: []string{"ADMIN"}})
+	sql := m.SQL()
+	for _, s := range []string{
+		"CREATE TABLE IF NOT EXISTS `biz_article` (",
+		"  `id` int unsigned NOT NULL AUTO_INCREMENT,",
+		"  `status` varchar(20) NOT NULL DEFAULT 'draft' COMMENT '状态',",
+		"  `published_at` datetime DEFAULT NULL COMMENT '发布时间',",
+		"  KEY `idx_biz_article_category_id` (`category_id`)",
+		"COMMENT='文章';",
+		"CREATE TABLE IF NOT EXISTS `biz_article_tag` (",
+		"PRIMARY KEY (`article_id`, `tag_id`)",
+		"('sys:article:add', '新建文章', '文章管理', '创建文章', 'api', NOW(3), NOW(3))",
+		"ON DUPLICATE KEY UPDATE",
+		"SELECT COALESCE((SELECT p.`id` FROM (SELECT `id` FROM `menu` WHERE `name` = 'System'",
+		"'文章管理', 'Article', 'article', 'system/article/index'",
+		"WHERE NOT EXISTS (SELECT 1 FROM (SELECT `id` FROM `menu` WHERE `perm` = 'sys:article:delete' AND `type` = 4",
+		"INSERT IGNORE INTO `role_permissions`",
+		"INSERT IGNORE INTO `role_menu`",
+		"WHERE r.`code` IN ('ADMIN')",
+	} {
+		if !strings.Contains(sql, s) {
+			t.Errorf("迁移缺少 %q", s)
+		}
+	}
+	if strings.Count(sql, "INSERT INTO `menu`") != 1+len(perms) {
+		t.Errorf("菜单和按钮数量不正确:\n%s", sql)
+	}
+
+	// 按已有表生成时不建表，不授权时没有授权语句
+	m = buildMigration(spec.table(), data, perms, SeedOptions{})
+	if sql := m.SQL(); strings.Contains(sql, "CREATE TABLE") || strings.Contains(sql, "role_") {
+		t.Errorf("不应包含建表和授权:\n%s", sql)
+	}
+}
+
+func TestWriteMigration(t *testing.T) {
+	root := t.TempDir()
+	m := &Migration{Name: "20261019_biz_article", Table: "biz_article", Statements: []string{"SELECT 1"}}
+	file, created, err := writeMigration(root, m)
+	if err != nil || !created || filepath.Base(file) != "20261019_biz_article.sql" {
+		t.Fatalf("首次写入: %s %v %v", file, created, err)
+	}
+	// 内容相同时沿用已有版本，即使日期不同
+	m = &Migration{Name: "20261020_biz_article", Table: "biz_article", Statements: []string{"SELECT 1"}}
+	if file, created, err = writeMigration(root, m); err != nil || created || m.Name != "20261019_biz_article" {
+		t.Fatalf("内容相同应沿用: %s %v %v", file, created, err)
+	}
+	// 同名但内容不同时生成新版本
+	m = &Migration{Name: "20261019_biz_article", Table: "biz_article", Statements: []string{"SELECT 2"}}
+	if file, created, err = writeMigration(root, m); err != nil || !created || m.Name != "20261019_biz_article_2" {
+		t.Fatalf("内容不同应生成新版本: %s %v %v", file, created, err)
+	}
+	old, _ := os.ReadFile(filepath.Join(root, migrationDir, "20261019_biz_article.sql"))
+	if !strings.Contains(string(old), "SELECT 1") {
+		t.Error("不应覆盖已有版本")
+	}
+}
+
+func TestMergeRegions(t *testing.T) {
+	data, err := newTemplateData(defaultTable("Widget"), "Widget", "github.com/zmqge/vireo-gin-admin", "app/admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, err := render(filepath.Join("templates", "service.tmpl"), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 用户在区域内增加接口方法和实现，并导入 fmt
+	edited := strings.Replace(string(generated), "\t// user:begin interface\n", "\t// user:begin interface\n\tPing() string\n", 1)
+	edited = strings.Replace(edited, "// user:begin methods\n", "// user:begin methods\n\n// Ping 自定义方法\nfunc (s *WidgetServiceImpl) Ping() string {\n\treturn fmt.Sprint(\"pong\")\n}\n", 1)
+	edited = strings.Replace(edited, "import (\n", "import (\n\t\"fmt\"\n\t\"os\"\n", 1)
+
+	merged, dropped, err := mergeRegions(generated, []byte(edited))
+	if err != nil || len(dropped) > 0 {
+		t.Fatalf("合并失败: %v %v", dropped, err)
+	}
+	for _, s := range []string{"\tPing() string\n", "return fmt.Sprint(\"pong\")", "\"fmt\"\n"} {
+		if !strings.Contains(string(merged), s) {
+			t.Errorf("合并结果缺少 %q:\n%s", s, merged)
+		}
+	}
+	// 用户代码未使用的导入不保留
+	if strings.Contains(string(merged), "\"os\"") {
+		t.Error("不应保留用户代码未使用的导入")
+	}
+	if generatorHash(merged) != generatorHash(generated) {
+		t.Error("用户代码区域和导入不应影响生成器部分的哈希")
+	}
+
+	// 区域在新代码中不存在时报告
+	renamed := strings.ReplaceAll(edited, "user:begin methods", "user:begin extra")
+	renamed = strings.ReplaceAll(renamed, "user:end methods", "user:end extra")
+	if _, dropped, _ := mergeRegions(generated, []byte(renamed)); len(dropped) != 1 || dropped[0] != "extra" {
+		t.Errorf("应报告丢失的区域: %v", dropped)
+	}
+	if _, _, err := mergeRegions(generated, []byte("// user:begin a\n// user:end b\n")); err == nil {
+		t.Error("区域标记不匹配应报错")
+	}
+}
+
+func TestPlanFile(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "app/admin/services/widgetService.go")
+	generated := []byte("package services\n\n// user:begin methods\n// user:end methods\n")
+	write := func(s string) {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(s), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if p := planFile(root, file, generated, Manifest{}, false); p.Err != nil || !p.Changed() || p.Path != "app/admin/services/widgetService.go" {
+		t.Fatalf("新文件: %+v", p)
+	}
+	write(string(generated))
+	if p := planFile(root, file, generated, Manifest{}, false); p.Err == nil {
+		t.Error("不在清单中的文件应拒绝覆盖")
+	}
+	manifest := Manifest{"app/admin/services/widgetService.go": generatorHash(generated)}
+
+	// 区域内的修改保留
+	write("package services\n\n// user:begin methods\nfunc Extra() {}\n// user:end methods\n")
+	p := planFile(root, file, generated, manifest, false)
+	if p.Err != nil || p.Changed() {
+		t.Errorf("区域内的修改应保留且无变化: %+v", p)
+	}
+	// 区域外的修改拒绝覆盖，-force 时覆盖
+	write("package services\n\nfunc Hand() {}\n\n// user:begin methods\n// user:end methods\n")
+	if p := planFile(root, file, generated, manifest, false); p.Err == nil {
+		t.Error("区域外的修改应拒绝覆盖")
+	}
+	if p := planFile(root, file, generated, manifest, true); p.Err != nil || string(p.Content) != string(generated) {
+		t.Errorf("-force 应覆盖: %+v", p)
+	}
+	if p.unifiedDiff() != "" {
+		t.Error("无变化时不应有差异")
+	}
+}
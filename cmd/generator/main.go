@@ -8,6 +8,10 @@ import (
 	"path/filepath"
 	"strings"
 	"text/template"
+
+	"github.com/zmqge/vireo-gin-admin/config"
+	"github.com/zmqge/vireo-gin-admin/pkg/annotations"
+	"github.com/zmqge/vireo-gin-admin/pkg/database"
 )
 
 // 获取项目根目录绝对路径
@@ -44,16 +48,43 @@ func capitalizeFirstLetter(s string) string {
 }
 
 func main() {
-	entity := flag.String("entity", "", "实体名称（如User）")
+	entity := flag.String("entity", "", "实体名称（如User），指定 -table 时默认由表名生成")
+	table := flag.String("table", "", "数据库表名，读取 information_schema 中的列生成代码")
+	specFile := flag.String("spec", "", "实体描述文件（YAML），声明字段、校验、字典、关联和树形结构")
 	module := flag.String("module", "", "模块名称（留空则自动检测）")
 	outputPath := flag.String("path", "", "输出路径（如app/admin），相对于项目根目录")
+	migrate := flag.Bool("migrate", false, "生成迁移（建表、菜单和按钮、权限、角色授权）写入 db/migrations 并打印")
+	apply := flag.Bool("apply", false, "生成迁移并在数据库中执行，已执行的版本会跳过")
+	menuParent := flag.String("menu-parent", "", "上级菜单的路由名称（如System），为空时作为顶级菜单")
+	diff := flag.Bool("diff", false, "只显示重新生成后的差异，不写入文件")
+	force := flag.Bool("force", false, "强制覆盖在用户代码区域外被手工修改的文件")
+	roles := flag.String("roles", "ADMIN", "授予生成权限和菜单的角色编码，多个用逗号分隔，为空时不授权")
 	flag.Parse()
 
+	// 读取实体描述文件，实体名以描述文件为准
+	var spec *Spec
+	if *specFile != "" {
+		if *table != "" {
+			fmt.Println("-spec 与 -table 不能同时使用")
+			os.Exit(1)
+		}
+		var err error
+		spec, err = loadSpec(*specFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		*entity = spec.Entity
+	}
+
+	if *entity == "" && *table != "" {
+		*entity = toPascalCase(*table)
+	}
 	// 处理实体名称，确保首字母大写
 	*entity = capitalizeFirstLetter(*entity)
 
 	if *entity == "" {
-		fmt.Println("请指定实体名称")
+		fmt.Println("请指定实体名称、表名或描述文件")
 		return
 	}
 
@@ -81,30 +112,26 @@ func main() {
 		fmt.Printf("自动检测模块路径: %s\n", *module)
 	}
 
+	// 读取表结构，未指定表名时使用默认字段
+	schema := defaultTable(*entity)
+	if *table != "" {
+		config.Init()
+		db := database.InitDB()
+		schema, err = loadTable(db, *table)
+		database.Close()
+		if err != nil {
+			fmt.Printf("读取表结构失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("读取表 %s: %d 列\n", schema.Name, len(schema.Columns))
+	}
+
 	// 规范化输出路径（基于项目根目录）
 	normalizedPath := filepath.Join(projectRoot, *outputPath)
 	if *outputPath == "" {
 		normalizedPath = projectRoot // 默认输出到项目根目录
 	}
 
-	// 创建基础输出目录
-	if err := os.MkdirAll(normalizedPath, 0755); err != nil {
-		fmt.Printf("创建输出目录 %s 失败: %v\n", normalizedPath, err)
-		return
-	}
-
-	// 创建MVC目录结构
-	dirs := []string{
-		"models", "controllers", "services", "repositories",
-	}
-	for _, dir := range dirs {
-		dirPath := filepath.Join(normalizedPath, dir)
-		if err := os.MkdirAll(dirPath, 0755); err != nil {
-			fmt.Printf("创建目录 %s 失败: %v\n", dirPath, err)
-			return
-		}
-	}
-
 	// 计算相对路径（用于导入语句）
 	relPath, err := filepath.Rel(projectRoot, normalizedPath)
 	if err != nil {
@@ -112,83 +139,145 @@ func main() {
 	}
 	relPath = strings.ReplaceAll(relPath, "\\", "/")
 
-	// 生成路由路径（复数形式，小写）
-	// entityPath := strings.ToLower(pluralize(*entity))
-	entityPath := strings.ToLower(*entity)
-
-	// 生成权限代码前缀（小写）
-	entityPermission := strings.ToLower(*entity)
-
-	// 生成文件
-	data := struct {
-		Entity           string
-		Module           string
-		RelPath          string
-		EntityPath       string
-		EntityPermission string
-	}{
-		Entity:           *entity,
-		Module:           *module,
-		RelPath:          relPath,
-		EntityPath:       entityPath,
-		EntityPermission: entityPermission,
-	}
-
-	// 模型文件名添加Model后缀
-	controllerFileName := toCamelCase(*entity) + "Controller.go"
-	modelFileName := toCamelCase(*entity) + "Model.go"
-	serviceFileName := toCamelCase(*entity) + "Service.go"
-	repositoryFileName := toCamelCase(*entity) + "Repository.go"
-
-	// 定义模板文件路径（相对于项目根目录）
-	templateFiles := []struct {
-		outputRelativePath string // 输出文件相对路径（相对于normalizedPath）
-		templatePath       string // 模板文件相对路径（相对于项目根目录）
-	}{
-		{filepath.Join("models", modelFileName), "cmd/generator/templates/model.tmpl"},
-		{filepath.Join("controllers", controllerFileName), "cmd/generator/templates/controller.tmpl"},
-		{filepath.Join("services", serviceFileName), "cmd/generator/templates/service.tmpl"},
-		{filepath.Join("repositories", repositoryFileName), "cmd/generator/templates/repository.tmpl"},
-	}
-
-	for _, tf := range templateFiles {
+	var data *TemplateData
+	if spec != nil {
+		schema = spec.table()
+		data, err = newTemplateDataFromSpec(spec, *module, relPath)
+	} else {
+		data, err = newTemplateData(schema, *entity, *module, relPath)
+	}
+	if err != nil {
+		fmt.Printf("解析表结构失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	manifest, err := loadManifest(projectRoot)
+	if err != nil {
+		fmt.Printf("读取生成清单失败: %v\n", err)
+		os.Exit(1)
+	}
+	var plans []*filePlan
+	for _, gf := range genFiles {
 		// 输出文件完整路径（基于项目根目录）
-		outputFile := filepath.Join(normalizedPath, tf.outputRelativePath)
+		outputFile := filepath.Join(normalizedPath, gf.outputPath(*entity))
 		// 模板文件完整路径（基于项目根目录）
-		templateFile := filepath.Join(projectRoot, tf.templatePath)
+		templateFile := filepath.Join(projectRoot, "cmd/generator/templates", gf.template)
+
+		src, err := render(templateFile, data)
+		if err != nil {
+			fmt.Printf("生成文件 %s 失败: %v\n", outputFile, err)
+			if src == nil {
+				return
+			}
+		}
+		// 保留已有文件中的用户代码区域
+		plans = append(plans, planFile(projectRoot, outputFile, src, manifest, *force))
+	}
 
+	if *diff {
+		for _, p := range plans {
+			switch {
+			case p.Err != nil:
+				fmt.Printf("%s: 拒绝覆盖: %v\n", p.Path, p.Err)
+			case !p.Changed():
+				fmt.Printf("%s: 无变化\n", p.Path)
+			}
+			if p.Changed() {
+				fmt.Print(p.unifiedDiff())
+			}
+		}
+		return
+	}
+	refused := false
+	for _, p := range plans {
+		if p.Err != nil {
+			fmt.Printf("拒绝覆盖 %s: %v\n", p.Path, p.Err)
+			refused = true
+		}
+	}
+	if refused {
+		fmt.Println("未写入任何文件。可使用 -diff 查看差异，确认后使用 -force 强制覆盖")
+		os.Exit(1)
+	}
+
+	for _, p := range plans {
+		manifest[p.Path] = generatorHash(p.Content)
+		if !p.Changed() {
+			fmt.Printf("无变化: %s\n", p.Path)
+			continue
+		}
+		outputFile := filepath.Join(projectRoot, p.Path)
 		// 创建父目录（如果不存在）
 		if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
 			fmt.Printf("创建目录 %s 失败: %v\n", filepath.Dir(outputFile), err)
 			return
 		}
-
-		// 创建文件
-		f, err := os.Create(outputFile)
-		if err != nil {
-			fmt.Printf("创建文件 %s 失败: %v\n", outputFile, err)
+		if err := os.WriteFile(outputFile, p.Content, 0644); err != nil {
+			fmt.Printf("写入文件 %s 失败: %v\n", outputFile, err)
 			return
 		}
-		defer f.Close()
+		fmt.Printf("生成文件: %s\n", p.Path)
+	}
+	if err := manifest.save(projectRoot); err != nil {
+		fmt.Printf("写入生成清单失败: %v\n", err)
+		os.Exit(1)
+	}
 
-		// 加载并执行模板
-		tmpl, err := loadTemplate(templateFile)
+	fmt.Println("MVC文件生成完成!")
+
+	if *migrate || *apply {
+		controllerFile := filepath.Join(normalizedPath, genFiles[1].outputPath(*entity))
+		perms, err := annotations.ParsePermissionAnnotations(controllerFile)
 		if err != nil {
-			fmt.Printf("加载模板 %s 失败: %v\n", templateFile, err)
-			return
+			fmt.Printf("解析权限注解失败: %v\n", err)
+			os.Exit(1)
 		}
-
-		if err := tmpl.Execute(f, data); err != nil {
-			fmt.Printf("生成文件 %s 失败: %v\n", outputFile, err)
+		opts := SeedOptions{
+			CreateTable: *table == "", // 按已有表生成时不建表
+			MenuParent:  *menuParent,
+			Roles:       splitList(*roles),
+		}
+		m := buildMigration(schema, data, perms, opts)
+		file, created, err := writeMigration(projectRoot, m)
+		if err != nil {
+			fmt.Printf("写入迁移失败: %v\n", err)
+			os.Exit(1)
+		}
+		relFile, _ := filepath.Rel(projectRoot, file)
+		if created {
+			fmt.Printf("生成迁移: %s\n", relFile)
+		} else {
+			fmt.Printf("迁移未变化，沿用: %s\n", relFile)
+		}
+		if !*apply {
+			fmt.Println(m.SQL())
 			return
 		}
-
-		// 显示相对路径（相对于项目根目录）
-		relOutputPath, _ := filepath.Rel(projectRoot, outputFile)
-		fmt.Printf("生成文件: %s\n", relOutputPath)
+		config.Init()
+		db := database.InitDB()
+		applied, err := applyMigration(db, m)
+		database.Close()
+		if err != nil {
+			fmt.Printf("执行迁移 %s 失败: %v\n", m.Name, err)
+			os.Exit(1)
+		}
+		if applied {
+			fmt.Printf("已执行迁移 %s（%d 条语句）\n", m.Name, len(m.Statements))
+		} else {
+			fmt.Printf("迁移 %s 已执行过，跳过\n", m.Name)
+		}
 	}
+}
 
-	fmt.Println("MVC文件生成完成!")
+// splitList 拆分逗号分隔的列表，忽略空项
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
 }
 
 // pluralize 将单数名词转为复数（简化版）
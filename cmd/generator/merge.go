@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// 用户代码区域标记，区域内的代码在重新生成时保留，区域外的代码属于生成器
+const (
+	regionBegin = "// user:begin "
+	regionEnd   = "// user:end "
+)
+
+// manifestFile 生成清单（相对项目根目录），记录每个生成文件中生成器部分的哈希
+const manifestFile = ".generator-manifest.json"
+
+// parseRegions 解析用户代码区域，返回 区域名 -> 区域内容
+func parseRegions(src []byte) (map[string]string, error) {
+	regions := make(map[string]string)
+	var name string
+	var body []string
+	for i, line := range strings.Split(string(src), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, regionBegin):
+			if name != "" {
+				return nil, fmt.Errorf("第 %d 行: 区域 %s 未结束", i+1, name)
+			}
+			name = strings.TrimSpace(strings.TrimPrefix(trimmed, regionBegin))
+			if _, ok := regions[name]; ok {
+				return nil, fmt.Errorf("第 %d 行: 区域 %s 重复", i+1, name)
+			}
+			body = nil
+		case strings.HasPrefix(trimmed, regionEnd):
+			end := strings.TrimSpace(strings.TrimPrefix(trimmed, regionEnd))
+			if end != name {
+				return nil, fmt.Errorf("第 %d 行: 区域结束标记 %s 与开始标记 %q 不匹配", i+1, end, name)
+			}
+			regions[name] = strings.Join(body, "\n")
+			name = ""
+		case name != "":
+			body = append(body, line)
+		}
+	}
+	if name != "" {
+		return nil, fmt.Errorf("区域 %s 未结束", name)
+	}
+	return regions, nil
+}
+
+// mergeRegions 将已有文件中用户代码区域的内容填入新生成的代码
+// 返回合并结果和新代码中已不存在、但有内容的区域名
+func mergeRegions(generated, existing []byte) ([]byte, []string, error) {
+	old, err := parseRegions(existing)
+	if err != nil {
+		return nil, nil, err
+	}
+	var out []string
+	var name string
+	used := make(map[string]bool)
+	for _, line := range strings.Split(string(generated), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, regionBegin):
+			name = strings.TrimSpace(strings.TrimPrefix(trimmed, regionBegin))
+			out = append(out, line)
+			if body, ok := old[name]; ok {
+				used[name] = true
+				if body != "" {
+					out = append(out, body)
+				}
+			}
+			continue
+		case strings.HasPrefix(trimmed, regionEnd):
+			name = ""
+		case name != "" && used[name]:
+			// 已有内容的区域丢弃模板中的默认内容
+			continue
+		}
+		out = append(out, line)
+	}
+	var dropped []string
+	for n, body := range old {
+		if !used[n] && strings.TrimSpace(body) != "" {
+			dropped = append(dropped, n)
+		}
+	}
+	var userCode strings.Builder
+	for _, body := range old {
+		userCode.WriteString(body)
+	}
+	merged, err := mergeImports([]byte(strings.Join(out, "\n")), existing, userCode.String())
+	return merged, dropped, err
+}
+
+// mergeImports 保留已有文件中用户代码区域用到、新生成的代码中没有的导入
+// 导入块由 gofmt 排序，无法放入用户代码区域，因此按包名是否出现在用户代码中合并
+func mergeImports(generated, existing []byte, userCode string) ([]byte, error) {
+	fset := token.NewFileSet()
+	oldFile, err := parser.ParseFile(fset, "", existing, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+	newFile, err := parser.ParseFile(fset, "", generated, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+	have := make(map[string]bool)
+	for _, spec := range newFile.Imports {
+		have[importLine(spec)] = true
+	}
+	var missing []string
+	for _, spec := range oldFile.Imports {
+		if line := importLine(spec); !have[line] && strings.Contains(userCode, importName(spec)+".") {
+			missing = append(missing, "\t"+line+"\n")
+		}
+	}
+	if len(missing) == 0 {
+		return generated, nil
+	}
+	block := strings.Join(missing, "")
+	offset := fset.Position(newFile.Name.End()).Offset
+	insert := "\n\nimport (\n" + block + ")"
+	for _, decl := range newFile.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT && gen.Lparen.IsValid() {
+			offset = fset.Position(gen.Rparen).Offset
+			insert = "\n" + block
+			break
+		}
+	}
+	merged := string(generated[:offset]) + insert + string(generated[offset:])
+	if src, err := format.Source([]byte(merged)); err == nil {
+		return src, nil
+	}
+	return []byte(merged), nil
+}
+
+// importName 导入的包名，取别名或路径最后一段，跳过 /v2 这样的版本后缀
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	parts := strings.Split(strings.Trim(spec.Path.Value, `"`), "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && regexp.MustCompile(`^v[0-9]+$`).MatchString(name) {
+		name = parts[len(parts)-2]
+	}
+	return name
+}
+
+// importLine 导入声明，如 "fmt" 或 alias "example.com/pkg"
+func importLine(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name + " " + spec.Path.Value
+	}
+	return spec.Path.Value
+}
+
+// generatorHash 生成器部分的哈希：去掉用户代码区域的内容和导入块，忽略空白差异（区域内的字段会影响 gofmt 对齐）
+func generatorHash(src []byte) string {
+	h := sha256.New()
+	inRegion, inImports := false, false
+	for _, line := range strings.Split(string(src), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "import (":
+			inImports = true
+			continue
+		case inImports:
+			inImports = trimmed != ")"
+			continue
+		case strings.HasPrefix(trimmed, "import "):
+			continue
+		case strings.HasPrefix(trimmed, regionBegin):
+			inRegion = true
+		case strings.HasPrefix(trimmed, regionEnd):
+			inRegion = false
+		case inRegion:
+			continue
+		}
+		if fields := strings.Fields(trimmed); len(fields) > 0 {
+			h.Write([]byte(strings.Join(fields, " ")))
+			h.Write([]byte{'\n'})
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Manifest 生成清单：文件路径（相对项目根目录）-> 生成器部分的哈希
+type Manifest map[string]string
+
+// loadManifest 读取生成清单，不存在时返回空清单
+func loadManifest(root string) (Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(root, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return Manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := Manifest{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %v", manifestFile, err)
+	}
+	return m, nil
+}
+
+// save 写入生成清单
+func (m Manifest) save(root string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(root, manifestFile), append(data, '\n'), 0644)
+}
+
+// filePlan 一个生成文件的写入计划
+type filePlan struct {
+	Path    string // 相对项目根目录
+	Old     []byte // 已有内容，文件不存在时为 nil
+	Content []byte // 合并用户代码区域后的内容
+	Err     error  // 拒绝覆盖的原因
+}
+
+// Changed 内容是否有变化
+func (p *filePlan) Changed() bool {
+	return p.Old == nil || !bytes.Equal(p.Old, p.Content)
+}
+
+// planFile 计算重新生成后的内容：保留用户代码区域；
+// 文件不在清单中，或用户代码区域外被手工修改、或有内容的区域在新模板中已不存在时拒绝覆盖，force 为 true 时强制覆盖
+func planFile(root, file string, generated []byte, manifest Manifest, force bool) *filePlan {
+	rel, _ := filepath.Rel(root, file)
+	rel = filepath.ToSlash(rel)
+	p := &filePlan{Path: rel, Content: generated}
+	old, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return p
+	}
+	if err != nil {
+		p.Err = err
+		return p
+	}
+	p.Old = old
+	merged, dropped, err := mergeRegions(generated, old)
+	switch {
+	case err != nil:
+		if !force {
+			p.Err = fmt.Errorf("合并用户代码失败: %v", err)
+		}
+		return p
+	case len(dropped) > 0 && !force:
+		p.Err = fmt.Errorf("用户代码区域 %s 在新生成的代码中不存在，覆盖会丢失其中的代码", strings.Join(dropped, "、"))
+		return p
+	}
+	p.Content = merged
+	if force {
+		return p
+	}
+	hash, ok := manifest[rel]
+	switch {
+	case !ok:
+		p.Err = fmt.Errorf("文件不在生成清单 %s 中，可能不是由生成器生成", manifestFile)
+	case hash != generatorHash(old):
+		p.Err = fmt.Errorf("文件在用户代码区域外被手工修改")
+	}
+	return p
+}
+
+// unifiedDiff 已有内容与新内容的差异
+func (p *filePlan) unifiedDiff() string {
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(p.Old)),
+		B:        difflib.SplitLines(string(p.Content)),
+		FromFile: "a/" + p.Path,
+		ToFile:   "b/" + p.Path,
+		Context:  3,
+	})
+	return diff
+}
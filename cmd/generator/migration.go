@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/zmqge/vireo-gin-admin/pkg/annotations"
+	"gorm.io/gorm"
+)
+
+// migrationDir 迁移文件目录（相对项目根目录）
+const migrationDir = "db/migrations"
+
+// 菜单类型，与前端约定一致
+const (
+	menuTypeMenu   = 1
+	menuTypeButton = 4
+)
+
+// SeedOptions 迁移中菜单和授权的选项
+type SeedOptions struct {
+	CreateTable bool     // 是否包含建表语句，按已有表生成时为 false
+	MenuParent  string   // 上级菜单的路由名称，如 System，为空时作为顶级菜单
+	Roles       []string // 授权的角色编码
+}
+
+// Migration 一次生成对应的迁移：建表、菜单和按钮、权限、角色授权，每条语句均可重复执行
+type Migration struct {
+	Name       string // 版本名，如 20261019_biz_article，同时作为文件名和 migration_logs.name
+	Table      string
+	Statements []string
+}
+
+// SQL 迁移文件内容
+func (m *Migration) SQL() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- %s：由 cmd/generator 生成，所有语句可重复执行\n", m.Name)
+	for _, stmt := range m.Statements {
+		b.WriteString("\n")
+		b.WriteString(stmt)
+		b.WriteString(";\n")
+	}
+	return b.String()
+}
+
+// buildMigration 生成迁移，权限取自生成的控制器注解
+func buildMigration(t *Table, data *TemplateData, perms []annotations.PermissionMeta, opts SeedOptions) *Migration {
+	m := &Migration{Name: time.Now().Format("20060102") + "_" + t.Name, Table: t.Name}
+	if opts.CreateTable {
+		m.Statements = append(m.Statements, createTableSQL(t, data.Title))
+		for _, rel := range data.ManyToMany {
+			m.Statements = append(m.Statements, joinTableSQL(rel))
+		}
+	}
+	if len(perms) == 0 {
+		return m
+	}
+	m.Statements = append(m.Statements, permissionSQL(perms))
+	m.Statements = append(m.Statements, menuSQL(data, opts.MenuParent))
+	for i, p := range perms {
+		m.Statements = append(m.Statements, buttonSQL(data.Entity, p, i+1))
+	}
+	if len(opts.Roles) > 0 {
+		m.Statements = append(m.Statements, grantSQL(data.Entity, perms, opts.Roles)...)
+	}
+	return m
+}
+
+// createTableSQL 建表语句
+func createTableSQL(t *Table, title string) string {
+	var lines, keys []string
+	for _, col := range t.Columns {
+		lines = append(lines, "  "+columnSQL(col))
+		switch {
+		case col.IsPrimary():
+			keys = append(keys, "  PRIMARY KEY ("+quoteIdent(col.Name)+")")
+		case col.Key == "UNI":
+			keys = append(keys, fmt.Sprintf("  UNIQUE KEY %s (%s)", quoteIdent("uk_"+t.Name+"_"+col.Name), quoteIdent(col.Name)))
+		case col.Key == "MUL":
+			keys = append(keys, fmt.Sprintf("  KEY %s (%s)", quoteIdent("idx_"+t.Name+"_"+col.Name), quoteIdent(col.Name)))
+		}
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n%s\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT=%s",
+		quoteIdent(t.Name), strings.Join(append(lines, keys...), ",\n"), quoteString(title))
+}
+
+// columnSQL 列定义
+func columnSQL(col Column) string {
+	parts := []string{quoteIdent(col.Name), col.FullType}
+	if col.IsNullable() {
+		parts = append(parts, "DEFAULT NULL")
+	} else {
+		parts = append(parts, "NOT NULL")
+		if col.Default != nil {
+			parts = append(parts, "DEFAULT "+defaultSQL(*col.Default))
+		}
+	}
+	if col.IsAutoIncrement() {
+		parts = append(parts, "AUTO_INCREMENT")
+	}
+	if col.Comment != "" {
+		parts = append(parts, "COMMENT "+quoteString(col.Comment))
+	}
+	return strings.Join(parts, " ")
+}
+
+// defaultSQL 默认值，CURRENT_TIMESTAMP 等表达式不加引号
+func defaultSQL(v string) string {
+	if strings.HasPrefix(strings.ToUpper(v), "CURRENT_TIMESTAMP") {
+		return v
+	}
+	return quoteString(v)
+}
+
+// joinTableSQL 多对多中间表
+func joinTableSQL(rel Relation) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %[1]s (\n"+
+		"  %[2]s int unsigned NOT NULL,\n"+
+		"  %[3]s int unsigned NOT NULL,\n"+
+		"  PRIMARY KEY (%[2]s, %[3]s),\n"+
+		"  KEY %[4]s (%[3]s)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci",
+		quoteIdent(rel.JoinTable), quoteIdent(rel.JoinForeignKey), quoteIdent(rel.JoinReferences),
+		quoteIdent("idx_"+rel.JoinTable+"_"+rel.JoinReferences))
+}
+
+// permissionSQL 写入权限，已存在时更新名称、模块和描述
+func permissionSQL(perms []annotations.PermissionMeta) string {
+	var rows []string
+	for _, p := range perms {
+		rows = append(rows, fmt.Sprintf("(%s, %s, %s, %s, 'api', NOW(3), NOW(3))",
+			quoteString(p.Code), quoteString(p.Name), quoteString(p.Module), quoteString(p.Description)))
+	}
+	return "INSERT INTO `permissions` (`code`, `name`, `module`, `description`, `type`, `created_at`, `updated_at`) VALUES\n" +
+		strings.Join(rows, ",\n") +
+		"\nON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `module` = VALUES(`module`), `description` = VALUES(`description`), `deleted_at` = NULL"
+}
+
+// menuSQL 页面菜单，按路由名称判断是否已存在
+func menuSQL(data *TemplateData, parent string) string {
+	path := "/" + data.EntityPath
+	component := data.EntityPath + "/index"
+	parentID := "0"
+	if parent != "" {
+		path = data.EntityPath
+		component = strings.ToLower(parent) + "/" + data.EntityPath + "/index"
+		parentID = fmt.Sprintf("COALESCE((SELECT p.`id` FROM (SELECT `id` FROM `menu` WHERE `name` = %s AND `type` = %d AND `deleted_at` IS NULL LIMIT 1) p), 0)",
+			quoteString(parent), menuTypeMenu)
+	}
+	return fmt.Sprintf("INSERT INTO `menu` (`parent_id`, `title`, `name`, `path`, `component`, `icon`, `sort`, `visible`, `always_show`, `keep_alive`, `type`)\n"+
+		"SELECT %s, %s, %s, %s, %s, 'menu', 100, 1, 0, 1, %d FROM DUAL\n"+
+		"WHERE NOT EXISTS (SELECT 1 FROM (SELECT `id` FROM `menu` WHERE `name` = %s AND `type` = %d AND `deleted_at` IS NULL) m)",
+		parentID, quoteString(data.Title+"管理"), quoteString(data.Entity), quoteString(path), quoteString(component), menuTypeMenu,
+		quoteString(data.Entity), menuTypeMenu)
+}
+
+// buttonSQL 页面下的按钮，按权限标识判断是否已存在
+func buttonSQL(entity string, p annotations.PermissionMeta, sort int) string {
+	return fmt.Sprintf("INSERT INTO `menu` (`parent_id`, `title`, `perm`, `sort`, `visible`, `type`)\n"+
+		"SELECT m.`id`, %s, %s, %d, 1, %d FROM (SELECT `id` FROM `menu` WHERE `name` = %s AND `type` = %d AND `deleted_at` IS NULL LIMIT 1) m\n"+
+		"WHERE NOT EXISTS (SELECT 1 FROM (SELECT `id` FROM `menu` WHERE `perm` = %s AND `type` = %d AND `deleted_at` IS NULL) b)",
+		quoteString(p.Name), quoteString(p.Code), sort, menuTypeButton, quoteString(entity), menuTypeMenu,
+		quoteString(p.Code), menuTypeButton)
+}
+
+// grantSQL 为角色授予生成的权限和菜单
+func grantSQL(entity string, perms []annotations.PermissionMeta, roles []string) []string {
+	codes := make([]string, 0, len(perms))
+	for _, p := range perms {
+		codes = append(codes, quoteString(p.Code))
+	}
+	roleCodes := make([]string, 0, len(roles))
+	for _, r := range roles {
+		roleCodes = append(roleCodes, quoteString(r))
+	}
+	inCodes, inRoles := strings.Join(codes, ", "), strings.Join(roleCodes, ", ")
+	return []string{
+		fmt.Sprintf("INSERT IGNORE INTO `role_permissions` (`role_id`, `permission_code`)\n"+
+			"SELECT r.`id`, p.`code` FROM `roles` r JOIN `permissions` p ON p.`code` IN (%s)\n"+
+			"WHERE r.`code` IN (%s) AND r.`deleted_at` IS NULL", inCodes, inRoles),
+		fmt.Sprintf("INSERT IGNORE INTO `role_menu` (`role_id`, `menu_id`)\n"+
+			"SELECT r.`id`, m.`id` FROM `roles` r JOIN `menu` m\n"+
+			"  ON (m.`name` = %s AND m.`type` = %d) OR (m.`perm` IN (%s) AND m.`type` = %d)\n"+
+			"WHERE r.`code` IN (%s) AND r.`deleted_at` IS NULL AND m.`deleted_at` IS NULL",
+			quoteString(entity), menuTypeMenu, inCodes, menuTypeButton, inRoles),
+	}
+}
+
+func quoteIdent(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(s) + "'"
+}
+
+// writeMigration 写入迁移文件并返回路径，是否新写入
+// 已有内容相同的同表迁移时沿用原版本；同名文件内容不同时追加序号生成新版本，不覆盖可能已执行的迁移
+func writeMigration(root string, m *Migration) (string, bool, error) {
+	dir := filepath.Join(root, migrationDir)
+	content := m.SQL()
+	pattern := regexp.MustCompile(`^\d{8}_` + regexp.QuoteMeta(m.Table) + `(_\d+)?\.sql$`)
+	existing, _ := filepath.Glob(filepath.Join(dir, "*.sql"))
+	for _, f := range existing {
+		if !pattern.MatchString(filepath.Base(f)) {
+			continue
+		}
+		old, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		// 忽略首行注释中的版本名
+		if stripHeader(string(old)) == stripHeader(content) {
+			m.Name = strings.TrimSuffix(filepath.Base(f), ".sql")
+			return f, false, nil
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", false, err
+	}
+	base := m.Name
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(dir, m.Name+".sql")); os.IsNotExist(err) {
+			break
+		}
+		m.Name = fmt.Sprintf("%s_%d", base, i)
+	}
+	file := filepath.Join(dir, m.Name+".sql")
+	return file, true, os.WriteFile(file, []byte(m.SQL()), 0644)
+}
+
+func stripHeader(s string) string {
+	if i := strings.Index(s, "\n"); i >= 0 {
+		return s[i:]
+	}
+	return s
+}
+
+// applyMigration 逐条执行迁移并记录到 migration_logs，已执行过的版本直接跳过
+func applyMigration(db *gorm.DB, m *Migration) (bool, error) {
+	logged := db.Migrator().HasTable("migration_logs")
+	if logged {
+		var count int64
+		if err := db.Table("migration_logs").Where("name = ?", m.Name).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return false, nil
+		}
+	}
+	// DDL 会隐式提交，逐条执行；每条语句均可重复执行，失败后修正再运行即可
+	for _, stmt := range m.Statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return false, fmt.Errorf("执行失败: %v\n%s", err, stmt)
+		}
+	}
+	if logged {
+		if err := db.Exec("INSERT IGNORE INTO `migration_logs` (`name`) VALUES (?)", m.Name).Error; err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TemplateData 模板数据
+type TemplateData struct {
+	Entity           string
+	Module           string
+	RelPath          string
+	EntityPath       string
+	EntityPermission string
+	Title            string // 显示名称，取表注释，没有注释时为实体名
+	TableName        string
+	Fields           []Field
+	DataScope        bool   // 同时有 creator_id 和 dept_id 列时参与数据权限
+	Tree             bool   // 有 uint 类型的 parent_id 列时为树形结构
+	Label            *Field // 下拉选项的显示字段，为空时不生成选项接口
+	BelongsTo        []Relation
+	ManyToMany       []Relation
+}
+
+// Relation 关联
+type Relation struct {
+	Name           string // 模型中的关联字段名，如 Category、Tags
+	Model          string // 关联模型类型名，如 CategoryModel
+	Label          string // 关联模型的显示字段，响应中增加 <Name><Label>
+	LabelJSON      string // 如 categoryName
+	ForeignKey     string // belongsTo 外键字段名，如 CategoryID
+	JoinTable      string // manyToMany 中间表
+	JoinForeignKey string
+	JoinReferences string
+	IDsName        string // manyToMany 请求和响应中的 ID 列表字段，如 TagIDs
+	IDsJSON        string // 如 tagIds
+}
+
+// newTemplateData 由表结构生成模板数据
+func newTemplateData(t *Table, entity, module, relPath string) (*TemplateData, error) {
+	fields, err := buildFields(t)
+	if err != nil {
+		return nil, err
+	}
+	title := strings.TrimSuffix(strings.TrimSpace(t.Comment), "表")
+	if title == "" {
+		title = entity
+	}
+	data := &TemplateData{
+		Entity:           entity,
+		Module:           module,
+		RelPath:          relPath,
+		EntityPath:       strings.ToLower(entity),
+		EntityPermission: strings.ToLower(entity),
+		Title:            title,
+		TableName:        t.Name,
+		Fields:           fields,
+	}
+	columns := make(map[string]bool)
+	for _, f := range fields {
+		columns[f.Column] = true
+	}
+	data.DataScope = columns["creator_id"] && columns["dept_id"]
+	for _, f := range fields {
+		if f.Column == "parent_id" && f.GoType == "uint" {
+			data.Tree = true
+		}
+	}
+	for _, name := range []string{"name", "title", "label"} {
+		if data.setLabel(name) {
+			break
+		}
+	}
+	return data, nil
+}
+
+// setLabel 设置下拉选项的显示字段，只接受字符串字段
+func (d *TemplateData) setLabel(column string) bool {
+	for i := range d.Fields {
+		if d.Fields[i].Column == column && d.Fields[i].GoType == "string" {
+			d.Label = &d.Fields[i]
+			return true
+		}
+	}
+	return false
+}
+
+// ModelImports 模型文件需要的导入
+func (d *TemplateData) ModelImports() []string {
+	set := make(map[string]bool)
+	for _, f := range d.Fields {
+		if strings.Contains(f.GoType, "time.Time") {
+			set["time"] = true
+		}
+		if strings.Contains(f.GoType, "gorm.") {
+			set["gorm.io/gorm"] = true
+		}
+	}
+	if d.DataScope {
+		for _, p := range []string{"log", "strconv", "github.com/gin-gonic/gin", "gorm.io/gorm"} {
+			set[p] = true
+		}
+	}
+	// 标准库在前，第三方包在后，两组之间用空串分隔
+	var std, others []string
+	for p := range set {
+		if strings.Contains(strings.Split(p, "/")[0], ".") {
+			others = append(others, p)
+		} else {
+			std = append(std, p)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(others)
+	if len(std) > 0 && len(others) > 0 {
+		std = append(std, "")
+	}
+	return append(std, others...)
+}
+
+// WritableFields 新增/编辑请求中的字段
+func (d *TemplateData) WritableFields() []Field {
+	var list []Field
+	for _, f := range d.Fields {
+		if f.Writable {
+			list = append(list, f)
+		}
+	}
+	return list
+}
+
+// VisibleFields 响应中的字段
+func (d *TemplateData) VisibleFields() []Field {
+	var list []Field
+	for _, f := range d.Fields {
+		if !f.Hidden {
+			list = append(list, f)
+		}
+	}
+	return list
+}
+
+// SearchFields 参与关键词查询的字段
+func (d *TemplateData) SearchFields() []Field {
+	var list []Field
+	for _, f := range d.Fields {
+		if f.Searchable {
+			list = append(list, f)
+		}
+	}
+	return list
+}
+
+// SearchClause 关键词查询条件，如 "`name` LIKE ? OR `code` LIKE ?"
+func (d *TemplateData) SearchClause() string {
+	var parts []string
+	for _, f := range d.SearchFields() {
+		parts = append(parts, "`"+f.Column+"` LIKE ?")
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// SortFields 允许排序的字段
+func (d *TemplateData) SortFields() []Field {
+	var list []Field
+	for _, f := range d.Fields {
+		if f.Sortable {
+			list = append(list, f)
+		}
+	}
+	return list
+}
+
+// DictFields 字典字段，分页接口可按值筛选
+func (d *TemplateData) DictFields() []Field {
+	var list []Field
+	for _, f := range d.Fields {
+		if f.Dict != "" {
+			list = append(list, f)
+		}
+	}
+	return list
+}
+
+// HasRelations 是否有关联
+func (d *TemplateData) HasRelations() bool {
+	return len(d.BelongsTo) > 0 || len(d.ManyToMany) > 0
+}
+
+// EntityVar 实体名的小驼峰形式，用于包级变量名
+func (d *TemplateData) EntityVar() string {
+	return toCamelCase(d.Entity)
+}
+
+// HasColumn 是否存在指定列
+func (d *TemplateData) HasColumn(column string) bool {
+	for _, f := range d.Fields {
+		if f.Column == column {
+			return true
+		}
+	}
+	return false
+}
+
+// ListOrder 列表和树形查询的排序，有 sort 列时按 sort 升序
+func (d *TemplateData) ListOrder() string {
+	if d.HasColumn("sort") {
+		return "sort asc, id asc"
+	}
+	return "id asc"
+}
+
+// Convert 将 uint 类型的表达式转为指定列的 Go 类型
+func (d *TemplateData) Convert(column, expr string) string {
+	for _, f := range d.Fields {
+		if f.Column == column && f.GoType != "uint" {
+			return f.GoType + "(" + expr + ")"
+		}
+	}
+	return expr
+}
+
+// genFile 一个生成文件：输出子目录、文件名后缀和模板
+type genFile struct {
+	dir      string
+	suffix   string
+	template string
+}
+
+var genFiles = []genFile{
+	{"models", "Model.go", "model.tmpl"},
+	{"controllers", "Controller.go", "controller.tmpl"},
+	{"services", "Service.go", "service.tmpl"},
+	{"repositories", "Repository.go", "repository.tmpl"},
+}
+
+// outputPath 生成文件相对输出目录的路径
+func (g genFile) outputPath(entity string) string {
+	return filepath.Join(g.dir, toCamelCase(entity)+g.suffix)
+}
+
+// render 执行模板并格式化代码；格式化失败时返回未格式化的内容和错误，便于排查模板
+func render(templateFile string, data *TemplateData) ([]byte, error) {
+	tmpl, err := loadTemplate(templateFile)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("执行模板 %s 失败: %v", templateFile, err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return buf.Bytes(), fmt.Errorf("格式化 %s 生成的代码失败: %v", filepath.Base(templateFile), err)
+	}
+	return src, nil
+}
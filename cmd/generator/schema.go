@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Column information_schema 中的列定义
+type Column struct {
+	Name      string  `gorm:"column:COLUMN_NAME"`
+	DataType  string  `gorm:"column:DATA_TYPE"`   // 如 varchar、int、datetime
+	FullType  string  `gorm:"column:COLUMN_TYPE"` // 如 varchar(50)、int unsigned、decimal(10,2)
+	Nullable  string  `gorm:"column:IS_NULLABLE"` // YES / NO
+	Default   *string `gorm:"column:COLUMN_DEFAULT"`
+	MaxLength *int64  `gorm:"column:CHARACTER_MAXIMUM_LENGTH"`
+	Key       string  `gorm:"column:COLUMN_KEY"` // PRI / UNI / MUL
+	Extra     string  `gorm:"column:EXTRA"`      // 如 auto_increment
+	Comment   string  `gorm:"column:COLUMN_COMMENT"`
+}
+
+// Table 表结构
+type Table struct {
+	Name    string
+	Comment string
+	Columns []Column
+}
+
+// IsNullable 列是否允许为 NULL
+func (c Column) IsNullable() bool { return c.Nullable == "YES" }
+
+// IsPrimary 是否主键列
+func (c Column) IsPrimary() bool { return c.Key == "PRI" }
+
+// IsAutoIncrement 是否自增列
+func (c Column) IsAutoIncrement() bool { return c.Extra == "auto_increment" }
+
+// loadTable 通过 information_schema 读取当前库中指定表的结构
+func loadTable(db *gorm.DB, name string) (*Table, error) {
+	table := &Table{Name: name}
+	var tables []struct {
+		Comment string `gorm:"column:TABLE_COMMENT"`
+	}
+	if err := db.Raw(
+		"SELECT TABLE_COMMENT FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?",
+		name,
+	).Scan(&tables).Error; err != nil {
+		return nil, fmt.Errorf("查询表信息失败: %w", err)
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("表 %s 不存在", name)
+	}
+	table.Comment = tables[0].Comment
+
+	if err := db.Raw(
+		"SELECT COLUMN_NAME, DATA_TYPE, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT, CHARACTER_MAXIMUM_LENGTH, "+
+			"COLUMN_KEY, EXTRA, COLUMN_COMMENT FROM information_schema.COLUMNS "+
+			"WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION",
+		name,
+	).Scan(&table.Columns).Error; err != nil {
+		return nil, fmt.Errorf("查询列信息失败: %w", err)
+	}
+	return table, nil
+}
+
+// defaultTable 未指定 -table 时使用的默认表结构：名称字段加审计字段
+func defaultTable(entity string) *Table {
+	size := int64(50)
+	return &Table{
+		Name: entity,
+		Columns: []Column{
+			{Name: "id", DataType: "int", FullType: "int unsigned", Nullable: "NO", Key: "PRI", Extra: "auto_increment"},
+			{Name: "name", DataType: "varchar", FullType: "varchar(50)", Nullable: "NO", MaxLength: &size, Comment: entity + "名称"},
+			{Name: "creator_id", DataType: "int", FullType: "int unsigned", Nullable: "YES", Key: "MUL", Comment: "创建人ID"},
+			{Name: "dept_id", DataType: "int", FullType: "int unsigned", Nullable: "YES", Key: "MUL", Comment: "部门ID"},
+			{Name: "created_at", DataType: "datetime", FullType: "datetime(3)", Nullable: "YES", Comment: "创建时间"},
+			{Name: "updated_at", DataType: "datetime", FullType: "datetime(3)", Nullable: "YES", Comment: "更新时间"},
+			{Name: "deleted_at", DataType: "datetime", FullType: "datetime(3)", Nullable: "YES", Key: "MUL", Comment: "删除时间"},
+		},
+	}
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec 实体描述文件，用于表尚不存在时生成代码
+type Spec struct {
+	Entity    string         `yaml:"entity"`    // 实体名，如 Product
+	Table     string         `yaml:"table"`     // 表名，如 biz_product
+	Title     string         `yaml:"title"`     // 显示名称，如 商品
+	Label     string         `yaml:"label"`     // 下拉选项的显示列，默认取 name/title
+	DataScope bool           `yaml:"dataScope"` // 参与数据权限，自动增加 creator_id、dept_id
+	Tree      bool           `yaml:"tree"`      // 树形结构，自动增加 parent_id
+	Fields    []FieldSpec    `yaml:"fields"`
+	Relations []RelationSpec `yaml:"relations"`
+}
+
+// FieldSpec 字段描述
+type FieldSpec struct {
+	Name       string `yaml:"name"`      // 列名（下划线命名）
+	Type       string `yaml:"type"`      // string/text/int/uint/int64/uint64/float/decimal/bool/date/datetime/json
+	Size       int    `yaml:"size"`      // string 的长度，默认 255
+	Precision  string `yaml:"precision"` // decimal 的精度，默认 10,2
+	Required   bool   `yaml:"required"`
+	Validate   string `yaml:"validate"` // 追加的 binding 规则，如 email、min=2
+	Default    string `yaml:"default"`
+	Comment    string `yaml:"comment"`
+	Unique     bool   `yaml:"unique"`
+	Index      bool   `yaml:"index"`
+	Searchable bool   `yaml:"searchable"` // 参与关键词模糊查询
+	Sortable   bool   `yaml:"sortable"`   // 允许在分页接口中按此字段排序
+	Dict       string `yaml:"dict"`       // 字典编码，响应中补充 xxxLabel，分页接口可按值筛选
+}
+
+// RelationSpec 关联描述
+type RelationSpec struct {
+	Name           string `yaml:"name"`           // 关联字段名，如 Category、Tags
+	Type           string `yaml:"type"`           // belongsTo / manyToMany
+	Model          string `yaml:"model"`          // 关联模型类型名，默认 <Name>Model（manyToMany 去掉复数 s）
+	ForeignKey     string `yaml:"foreignKey"`     // belongsTo 本表外键列，默认 <name>_id
+	Label          string `yaml:"label"`          // 关联模型的显示字段（Go 字段名），响应中增加 <Name><Label>
+	JoinTable      string `yaml:"joinTable"`      // manyToMany 中间表，默认 <table>_<name 单数>
+	JoinForeignKey string `yaml:"joinForeignKey"` // 中间表中指向本表的列，默认 <entity>_id
+	JoinReferences string `yaml:"joinReferences"` // 中间表中指向关联表的列，默认 <name 单数>_id
+}
+
+// 关联类型
+const (
+	RelationBelongsTo  = "belongsTo"
+	RelationManyToMany = "manyToMany"
+)
+
+var identPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// loadSpec 读取并校验实体描述文件
+func loadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取描述文件失败: %v", err)
+	}
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("解析描述文件失败: %v", err)
+	}
+	if err := spec.normalize(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &spec, nil
+}
+
+// normalize 校验描述并补全默认值
+func (s *Spec) normalize() error {
+	s.Entity = capitalizeFirstLetter(s.Entity)
+	if s.Entity == "" {
+		return fmt.Errorf("entity 不能为空")
+	}
+	if s.Table == "" {
+		s.Table = toSnakeCase(s.Entity)
+	}
+	if !identPattern.MatchString(s.Table) {
+		return fmt.Errorf("无效的表名: %s", s.Table)
+	}
+	seen := map[string]bool{}
+	for k := range auditColumns {
+		seen[k] = true
+	}
+	seen["parent_id"] = s.Tree
+	for i := range s.Fields {
+		f := &s.Fields[i]
+		if !identPattern.MatchString(f.Name) {
+			return fmt.Errorf("字段 %d: 无效的列名 %q", i+1, f.Name)
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("字段 %s 重复或与自动生成的列冲突", f.Name)
+		}
+		seen[f.Name] = true
+		if f.Type == "" {
+			f.Type = "string"
+		}
+		if _, ok := specTypes[f.Type]; !ok {
+			return fmt.Errorf("字段 %s: 不支持的类型 %s", f.Name, f.Type)
+		}
+		if f.Type == "string" && f.Size <= 0 {
+			f.Size = 255
+		}
+		if f.Type == "decimal" && f.Precision == "" {
+			f.Precision = "10,2"
+		}
+	}
+	for i := range s.Relations {
+		r := &s.Relations[i]
+		r.Name = capitalizeFirstLetter(r.Name)
+		if r.Name == "" {
+			return fmt.Errorf("关联 %d: name 不能为空", i+1)
+		}
+		single := strings.TrimSuffix(r.Name, "s")
+		switch r.Type {
+		case RelationBelongsTo:
+			if r.Model == "" {
+				r.Model = r.Name + "Model"
+			}
+			if r.ForeignKey == "" {
+				r.ForeignKey = toSnakeCase(r.Name) + "_id"
+			}
+			if !seen[r.ForeignKey] {
+				// 外键列未在 fields 中声明时自动增加
+				s.Fields = append(s.Fields, FieldSpec{Name: r.ForeignKey, Type: "uint", Index: true, Comment: r.Name + "ID"})
+				seen[r.ForeignKey] = true
+			}
+		case RelationManyToMany:
+			if r.Model == "" {
+				r.Model = single + "Model"
+			}
+			if r.JoinTable == "" {
+				r.JoinTable = s.Table + "_" + toSnakeCase(single)
+			}
+			if r.JoinForeignKey == "" {
+				r.JoinForeignKey = toSnakeCase(s.Entity) + "_id"
+			}
+			if r.JoinReferences == "" {
+				r.JoinReferences = toSnakeCase(single) + "_id"
+			}
+		default:
+			return fmt.Errorf("关联 %s: 不支持的类型 %q（belongsTo/manyToMany）", r.Name, r.Type)
+		}
+	}
+	if s.Label != "" && !seen[s.Label] {
+		return fmt.Errorf("label 列 %s 不存在", s.Label)
+	}
+	return nil
+}
+
+// specTypes 描述文件中的类型对应的列类型
+var specTypes = map[string]struct{ dataType, fullType string }{
+	"string":   {"varchar", "varchar(%d)"},
+	"text":     {"text", "text"},
+	"int":      {"int", "int"},
+	"uint":     {"int", "int unsigned"},
+	"int64":    {"bigint", "bigint"},
+	"uint64":   {"bigint", "bigint unsigned"},
+	"float":    {"double", "double"},
+	"decimal":  {"decimal", "decimal(%s)"},
+	"bool":     {"tinyint", "tinyint(1)"},
+	"date":     {"date", "date"},
+	"datetime": {"datetime", "datetime"},
+	"json":     {"json", "json"},
+}
+
+// column 字段描述对应的列定义
+// 非必填的字符串、数字和布尔列使用 NOT NULL 加零值默认值，时间、大文本和 JSON 列允许 NULL
+func (f FieldSpec) column() Column {
+	t := specTypes[f.Type]
+	col := Column{Name: f.Name, DataType: t.dataType, FullType: t.fullType, Nullable: "NO", Comment: f.Comment}
+	switch f.Type {
+	case "string":
+		col.FullType = fmt.Sprintf(t.fullType, f.Size)
+		size := int64(f.Size)
+		col.MaxLength = &size
+	case "decimal":
+		col.FullType = fmt.Sprintf(t.fullType, f.Precision)
+	}
+	if f.Unique {
+		col.Key = "UNI"
+	} else if f.Index {
+		col.Key = "MUL"
+	}
+	switch {
+	case f.Default != "":
+		def := f.Default
+		col.Default = &def
+	case f.Required:
+	case f.Type == "text" || f.Type == "json" || f.Type == "date" || f.Type == "datetime":
+		col.Nullable = "YES"
+	default:
+		def := "0"
+		if f.Type == "string" {
+			def = ""
+		}
+		col.Default = &def
+	}
+	return col
+}
+
+// table 描述对应的表结构：id、树形的 parent_id、声明的字段、数据权限列和时间列
+func (s *Spec) table() *Table {
+	t := defaultTable(s.Entity)
+	t.Name = s.Table
+	t.Comment = s.Title
+	audit := t.Columns
+	columns := []Column{audit[0]}
+	if s.Tree {
+		zero := "0"
+		columns = append(columns, Column{Name: "parent_id", DataType: "int", FullType: "int unsigned", Nullable: "NO", Default: &zero, Key: "MUL", Comment: "上级ID"})
+	}
+	for _, f := range s.Fields {
+		columns = append(columns, f.column())
+	}
+	for _, col := range audit[2:] {
+		if !s.DataScope && (col.Name == "creator_id" || col.Name == "dept_id") {
+			continue
+		}
+		columns = append(columns, col)
+	}
+	t.Columns = columns
+	return t
+}
+
+// newTemplateDataFromSpec 由描述文件生成模板数据，在表结构的基础上补充查询、字典和关联信息
+func newTemplateDataFromSpec(s *Spec, module, relPath string) (*TemplateData, error) {
+	data, err := newTemplateData(s.table(), s.Entity, module, relPath)
+	if err != nil {
+		return nil, err
+	}
+	specs := make(map[string]FieldSpec, len(s.Fields))
+	for _, f := range s.Fields {
+		specs[f.Name] = f
+	}
+	for i := range data.Fields {
+		f := &data.Fields[i]
+		fs, ok := specs[f.Column]
+		if !ok {
+			continue
+		}
+		f.Searchable = fs.Searchable
+		f.Sortable = fs.Sortable
+		f.Dict = fs.Dict
+		if fs.Validate != "" {
+			f.Binding = strings.TrimPrefix(f.Binding+","+fs.Validate, ",")
+		}
+	}
+	if s.Label != "" {
+		data.setLabel(s.Label)
+	}
+	for _, r := range s.Relations {
+		rel := Relation{
+			Name:      r.Name,
+			Model:     r.Model,
+			Label:     r.Label,
+			LabelJSON: toCamelCase(r.Name) + r.Label,
+		}
+		if r.Type == RelationBelongsTo {
+			rel.ForeignKey = toPascalCase(r.ForeignKey)
+			data.BelongsTo = append(data.BelongsTo, rel)
+			continue
+		}
+		single := strings.TrimSuffix(r.Name, "s")
+		rel.JoinTable = r.JoinTable
+		rel.JoinForeignKey = r.JoinForeignKey
+		rel.JoinReferences = r.JoinReferences
+		rel.IDsName = single + "IDs"
+		rel.IDsJSON = toCamelCase(single) + "Ids"
+		data.ManyToMany = append(data.ManyToMany, rel)
+	}
+	return data, nil
+}
+
+// toSnakeCase 将驼峰命名转为下划线命名，如 ProductTag -> product_tag
+func toSnakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 && !(s[i-1] >= 'A' && s[i-1] <= 'Z') {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r + ('a' - 'A'))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
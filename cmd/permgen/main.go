@@ -1,21 +1,25 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 
-	"github.com/zmqge/vireo-gin-admin/app/admin/models"
+	"github.com/zmqge/vireo-gin-admin/app/admin/services"
 	"github.com/zmqge/vireo-gin-admin/config"
-	perm_parser "github.com/zmqge/vireo-gin-admin/pkg/annotations"
 	"github.com/zmqge/vireo-gin-admin/pkg/database"
 )
 
 func main() {
+	dryRun := flag.Bool("dry-run", false, "只输出变更，不写数据库")
+	deprecate := flag.Bool("deprecate", false, "代码中已删除的权限软删除（保留角色授权，注解恢复后重新启用），默认物理删除")
+	flag.Parse()
+
 	// 初始化配置和数据库
 	config.Init()
-	db := database.InitDB()
+	database.InitDB()
 	defer database.Close()
 
 	// 获取 controller 路径
@@ -24,71 +28,65 @@ func main() {
 		controllerDirs = []string{"app/admin/controllers"}
 	}
 
-	// 收集所有 go 文件
-	var files []string
-	for _, dir := range controllerDirs {
-		_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-			if err == nil && !info.IsDir() && strings.HasSuffix(path, ".go") {
-				files = append(files, path)
-			}
-			return nil
-		})
-	}
-
-	// 清空 permissions 表
-	db.Exec("TRUNCATE TABLE permissions")
-
 	// 扫描所有 controller 文件，提取权限注解
-	codeFilesMap := make(map[string][]string) // code -> []file
-	codePermMap := make(map[string]models.Permission)
-	var duplicateCodes []string
-
-	for _, file := range files {
-		perms, err := perm_parser.ParsePermissionAnnotations(file)
-		if err != nil {
-			fmt.Printf("Parse %s error: %v\n", file, err)
-			continue
-		}
-		for _, p := range perms {
-			if p.Code == "" {
-				continue
-			}
-			codeFilesMap[p.Code] = append(codeFilesMap[p.Code], file)
-			// 只保留第一个注解内容（合并重复项时使用第一个项的信息）
-			if _, exists := codePermMap[p.Code]; !exists {
-				codePermMap[p.Code] = models.Permission{
-					Code:        p.Code,
-					Name:        p.Name,
-					Description: p.Description,
-					Module:      p.Module,
-				}
-			}
-		}
+	permissions, duplicates, err := services.ScanPermissions(controllerDirs)
+	if err != nil {
+		fmt.Printf("扫描权限注解失败: %v\n", err)
+		os.Exit(1)
 	}
 
 	// 检查重复 code 并输出所有位置
-	for code, files := range codeFilesMap {
-		if len(files) > 1 {
-			duplicateCodes = append(duplicateCodes, code)
+	if len(duplicates) > 0 {
+		codes := make([]string, 0, len(duplicates))
+		for code := range duplicates {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
 			fmt.Printf("权限 code 重复: %s\n", code)
-			for _, f := range files {
+			for _, f := range duplicates[code] {
 				fmt.Printf("位置: %s\n", f)
 			}
 		}
+		fmt.Println("存在重复 code，已合并，仅保留第一条注解。请检查上方提示！")
 	}
-	if len(duplicateCodes) > 0 {
-		fmt.Println("存在重复 code，已合并，仅保留一条写入数据库。请检查上方提示！")
+	if len(permissions) == 0 {
+		// 没有注解时多半是目录配置错误，不做同步，避免删除全部权限
+		fmt.Println("未发现任何权限注解，未同步")
+		return
+	}
+
+	report, err := services.NewPermissionService().SyncPermissions(permissions, services.PermissionSyncOptions{
+		Deprecate: *deprecate,
+		DryRun:    *dryRun,
+	})
+	if err != nil {
+		fmt.Printf("同步权限失败: %v\n", err)
+		os.Exit(1)
 	}
-	// 合并后写入数据库
-	var permissions []models.Permission
-	for _, perm := range codePermMap {
-		permissions = append(permissions, perm)
+
+	removed := "删除"
+	if *deprecate {
+		removed = "软删除"
 	}
-	// 批量写入数据库，只写入 code、name、description 字段
-	if len(permissions) > 0 {
-		db.Model(&models.Permission{}).Select("code", "name", "description", "module").Create(&permissions)
-		fmt.Printf("写入权限 %d 条\n", len(permissions))
+	printCodes("新增", report.Added)
+	printCodes("更新", report.Updated)
+	printCodes("恢复", report.Restored)
+	printCodes(removed, report.Removed)
+	summary := fmt.Sprintf("注解权限 %d 条：新增 %d，更新 %d，恢复 %d，%s %d，无变化 %d",
+		len(permissions), len(report.Added), len(report.Updated), len(report.Restored), removed, len(report.Removed), report.Unchanged)
+	summary += fmt.Sprintf("；权限树 %d 个模块、%d 个资源", report.Modules, report.Resources)
+	if *dryRun {
+		fmt.Println("[dry-run] " + summary + "，未写入数据库")
 	} else {
-		fmt.Println("未发现任何权限注解")
+		fmt.Println(summary)
+	}
+}
+
+// printCodes 输出一类变更的权限码
+func printCodes(action string, codes []string) {
+	if len(codes) == 0 {
+		return
 	}
+	fmt.Printf("%s %d 条:\n  %s\n", action, len(codes), strings.Join(codes, "\n  "))
 }
@@ -3,14 +3,18 @@ package main
 import (
 	"fmt"
 	"go/ast"
+	"go/format"
 	"go/parser"
 	"go/token"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/spf13/viper"
 	"github.com/zmqge/vireo-gin-admin/pkg/annotations"
+	"github.com/zmqge/vireo-gin-admin/pkg/middleware"
 )
 
 type ControllerFolderInfo struct {
@@ -45,13 +49,37 @@ func main() {
 		}
 	}
 
-	// 先删除 routes 目录下所有 -api.go 路由文件
+	// 先扫描并校验全部模块，任何注解错误都不生成路由文件，保留原有文件
+	modules, err := scanModules(validDirs)
+	if err != nil {
+		fmt.Printf("Error scanning controllers: %v\n", err)
+		os.Exit(1)
+	}
+	var all []annotations.RouteMeta
+	for _, m := range modules {
+		all = append(all, m.routes...)
+	}
+	exitOnErrors(validateRoutes(all))
+
+	files := make(map[string][]byte)
+	for _, m := range modules {
+		src, errs := renderRouteFile(m.routes, m.folder)
+		exitOnErrors(errs)
+		code, err := format.Source([]byte(src))
+		if err != nil {
+			fmt.Printf("生成的路由代码 %s 无法解析: %v\n", m.apiFile, err)
+			os.Exit(1)
+		}
+		files[m.apiFile] = code
+	}
+
+	// 删除 routes 目录下所有 -api.go 路由文件后写入新文件
 	if err := cleanApiRouteFiles(); err != nil {
 		fmt.Printf("Error cleaning old route files: %v\n", err)
 	}
-
-	if err := generateRoutes(validDirs); err != nil {
+	if err := writeRouteFiles(files); err != nil {
 		fmt.Printf("Error generating routes: %v\n", err)
+		os.Exit(1)
 	}
 
 	// 生成 routes/route.go 统一导入注册
@@ -77,26 +105,31 @@ func cleanApiRouteFiles() error {
 	return nil
 }
 
-// 生成 routes/route.go 统一注册所有 RegisterXXXRoutes
+// 生成 routes/route.go 统一注册所有 RegisterXXXRoutes，并返回各模块创建的服务实例
 func generateRouteEntry() error {
 	dir := "routes"
 	files, err := os.ReadDir(dir)
 	if err != nil {
 		return err
 	}
-	var importStmts []string
+	var fieldStmts []string
 	var callStmts []string
 	for _, file := range files {
 		if !strings.HasSuffix(file.Name(), "-api.go") {
 			continue
 		}
-		base := strings.TrimSuffix(file.Name(), "-api.go")
-		importStmts = append(importStmts, fmt.Sprintf("\t\"github.com/zmqge/vireo-gin-admin/routes/%s-api\"", base))
-		funcName := fmt.Sprintf("Register%sRoutes", strings.Title(base))
-		callStmts = append(callStmts, fmt.Sprintf("\t%s(engine, db)", funcName))
+		module := strings.Title(strings.TrimSuffix(file.Name(), "-api.go"))
+		fieldStmts = append(fieldStmts, fmt.Sprintf("\t%s *%sServices", module, module))
+		callStmts = append(callStmts, fmt.Sprintf("\t\t%s: Register%sRoutes(engine, db),", module, module))
+	}
+	content := "package routes\n\nimport (\n\t\"github.com/gin-gonic/gin\"\n\t\"gorm.io/gorm\"\n" + "\n)\n\n" +
+		"// Services 各模块路由创建的服务实例，后台任务复用同一实例\ntype Services struct {\n" + strings.Join(fieldStmts, "\n") + "\n}\n\n" +
+		"func RegisterAllRoutes(engine *gin.Engine, db *gorm.DB) *Services {\n\treturn &Services{\n" + strings.Join(callStmts, "\n") + "\n\t}\n}\n"
+	code, err := format.Source([]byte(content))
+	if err != nil {
+		return err
 	}
-	content := "package routes\n\nimport (\n\t\"github.com/gin-gonic/gin\"\n\t\"gorm.io/gorm\"\n" + "\n)\n\nfunc RegisterAllRoutes(engine *gin.Engine, db *gorm.DB) {\n" + strings.Join(callStmts, "\n") + "\n}\n"
-	return os.WriteFile(filepath.Join(dir, "route.go"), []byte(content), 0644)
+	return os.WriteFile(filepath.Join(dir, "route.go"), code, 0644)
 }
 
 func initConfig() error {
@@ -114,7 +147,17 @@ func getControllerDirs() []string {
 	return []string{"app/admin"}
 }
 
-func generateRoutes(controllerDirs []string) error {
+// routeModule 一个控制器目录扫描出的路由，生成一个 -api.go 文件
+type routeModule struct {
+	dir     string
+	apiFile string
+	routes  []annotations.RouteMeta
+	folder  *ControllerFolderInfo
+}
+
+// scanModules 扫描所有控制器目录
+func scanModules(controllerDirs []string) ([]*routeModule, error) {
+	var modules []*routeModule
 	for _, dir := range controllerDirs {
 		controllersDir := filepath.Join(dir, "controllers")
 		if stat, err := os.Stat(controllersDir); err == nil && stat.IsDir() {
@@ -125,7 +168,7 @@ func generateRoutes(controllerDirs []string) error {
 		folderInfos := make(map[string]*ControllerFolderInfo)
 
 		if err := scanControllerDir(dir, &routes, folderInfos); err != nil {
-			return err
+			return nil, err
 		}
 
 		var folderInfo *ControllerFolderInfo
@@ -134,7 +177,7 @@ func generateRoutes(controllerDirs []string) error {
 			break
 		}
 		if folderInfo == nil {
-			return fmt.Errorf("no controller info found for dir: %s", dir)
+			return nil, fmt.Errorf("no controller info found for dir: %s", dir)
 		}
 
 		fmt.Printf("扫描目录 %s 完成，找到 %d 个路由\n", dir, len(routes))
@@ -142,15 +185,19 @@ func generateRoutes(controllerDirs []string) error {
 			fmt.Printf("警告: 在 %s 中未找到任何路由\n", dir)
 			continue
 		}
+		modules = append(modules, &routeModule{dir: dir, apiFile: genApiFileName(dir), routes: routes, folder: folderInfo})
+	}
+	return modules, nil
+}
 
-		if err := os.MkdirAll("routes", 0755); err != nil {
-			return fmt.Errorf("创建routes目录失败: %v", err)
-		}
-
-		code := renderRouteFile(routes, folderInfo)
-		apiFile := genApiFileName(dir)
+// writeRouteFiles 写入生成的路由文件
+func writeRouteFiles(files map[string][]byte) error {
+	if err := os.MkdirAll("routes", 0755); err != nil {
+		return fmt.Errorf("创建routes目录失败: %v", err)
+	}
+	for apiFile, code := range files {
 		fmt.Printf("正在生成路由文件: %s\n", apiFile)
-		if err := os.WriteFile(apiFile, []byte(code), 0644); err != nil {
+		if err := os.WriteFile(apiFile, code, 0644); err != nil {
 			return fmt.Errorf("写入路由文件失败: %v", err)
 		}
 		fmt.Printf("成功生成路由文件: %s\n", apiFile)
@@ -210,6 +257,7 @@ func processControllerFile(path string, routes *[]annotations.RouteMeta, folderI
 	folderInfos[importPath].ControllerMap[controllerType] = true
 
 	for _, route := range fileRoutes {
+		route.Method = strings.ToUpper(route.Method)
 		route.ControllerType = controllerType
 		route.ImportPath = importPath
 		route.FilePath = path
@@ -244,7 +292,7 @@ func getControllerType(filePath string) string {
 	return ""
 }
 
-func renderRouteFile(routes []annotations.RouteMeta, folderInfo *ControllerFolderInfo) string {
+func renderRouteFile(routes []annotations.RouteMeta, folderInfo *ControllerFolderInfo) (string, []error) {
 	var builder strings.Builder
 	baseDir := filepath.Dir(folderInfo.ImportPath)
 	packageName := filepath.Base(baseDir)
@@ -254,6 +302,7 @@ func renderRouteFile(routes []annotations.RouteMeta, folderInfo *ControllerFolde
 	builder.WriteString("import (\n")
 	builder.WriteString("\t\"github.com/gin-gonic/gin\"\n")
 	builder.WriteString("\t\"github.com/zmqge/vireo-gin-admin/pkg/middleware\"\n")
+	builder.WriteString("\t\"github.com/zmqge/vireo-gin-admin/pkg/routemeta\"\n")
 	builder.WriteString("\t\"gorm.io/gorm\"\n")
 
 	// 智能生成导入路径
@@ -270,35 +319,42 @@ func renderRouteFile(routes []annotations.RouteMeta, folderInfo *ControllerFolde
 	builder.WriteString(")\n\n")
 
 	// 生成路由注册函数
-	funcName := fmt.Sprintf("Register%sRoutes", strings.Title(packageName))
-	builder.WriteString(fmt.Sprintf("func %s(engine *gin.Engine, db *gorm.DB) {\n", funcName))
+	module := strings.Title(packageName)
+	builder.WriteString(fmt.Sprintf("func Register%sRoutes(engine *gin.Engine, db *gorm.DB) *%sServices {\n", module, module))
 
 	// 实例化所有控制器及其依赖
-	ctrlInstances := instantiateControllers(routes, folderInfo, &builder)
+	ctrlInstances, services, errs := instantiateControllers(routes, folderInfo, &builder)
+	if len(errs) > 0 {
+		return "", errs
+	}
 
-	// 检查权限但未加jwt的路由，自动加jwt并收集warning
+	// 检查权限但未加jwt的路由（分组中已有 jwt 的除外），自动加jwt并收集warning
 	var warnRoutes []string
 	for i, route := range routes {
-		if route.Permission != "" {
-			foundJwt := false
-			for _, m := range route.Middlewares {
-				if strings.EqualFold(m, "jwt") {
-					foundJwt = true
-					break
-				}
-			}
-			if !foundJwt {
-				// 自动加上 jwt
-				routes[i].Middlewares = append([]string{"jwt"}, routes[i].Middlewares...)
-				warnRoutes = append(warnRoutes, fmt.Sprintf("[WARNING] 路由 %s %s (文件: %s, 控制器: %s, 方法: %s) 设置了权限 '%s' 但未定义 jwt 中间件，已自动加上 jwt，建议显式声明！", route.Method, route.Path, route.FilePath, route.ControllerType, route.HandlerName, route.Permission))
-			}
+		if route.Permission != "" && !needsJWTDeclared(route) {
+			// 自动加上 jwt
+			routes[i].Middlewares = append([]string{"jwt"}, routes[i].Middlewares...)
+			warnRoutes = append(warnRoutes, fmt.Sprintf("[WARNING] 路由 %s %s (文件: %s:%d, 控制器: %s, 方法: %s) 设置了权限 '%s' 但未定义 jwt 中间件，已自动加上 jwt，建议显式声明！", route.Method, route.Path, route.FilePath, route.Line, route.ControllerType, route.HandlerName, route.Permission))
 		}
 	}
 
 	// 构建并渲染路由树
-	groupTree := buildGroupTree(routes)
-	renderGroupTree(&builder, groupTree, "engine", ctrlInstances)
+	renderGroups(&builder, buildGroups(routes), "engine", ctrlInstances)
+
+	// 登记路由元数据，供运行时查询接口与权限的对应关系
+	renderRouteMeta(&builder, routes)
 
+	// 返回创建的服务实例，定时任务等复用同一实例
+	builder.WriteString(fmt.Sprintf("\treturn &%sServices{\n", module))
+	for _, svc := range services {
+		builder.WriteString(fmt.Sprintf("\t\t%s: %s,\n", svc.name, svc.varName))
+	}
+	builder.WriteString("\t}\n}\n\n")
+	builder.WriteString(fmt.Sprintf("// %sServices Register%sRoutes 创建的服务实例\n", module, module))
+	builder.WriteString(fmt.Sprintf("type %sServices struct {\n", module))
+	for _, svc := range services {
+		builder.WriteString(fmt.Sprintf("\t%s %s\n", svc.name, svc.typ))
+	}
 	builder.WriteString("}\n")
 
 	// 输出warning到控制台
@@ -307,18 +363,71 @@ func renderRouteFile(routes []annotations.RouteMeta, folderInfo *ControllerFolde
 		for _, w := range warnRoutes {
 			fmt.Println(w)
 		}
-		fmt.Println("============================================\n")
+		fmt.Println("============================================")
+	}
+
+	return builder.String(), nil
+}
+
+// renderRouteMeta 生成 routemeta.Register 调用，路径为分组路径与路由路径拼接后的完整路径
+func renderRouteMeta(builder *strings.Builder, routes []annotations.RouteMeta) {
+	builder.WriteString("\troutemeta.Register(\n")
+	for _, route := range routes {
+		fields := []string{
+			fmt.Sprintf("Method: %q", route.Method),
+			fmt.Sprintf("Path: %q", route.FullPath()),
+		}
+		if route.Permission != "" {
+			fields = append(fields, fmt.Sprintf("Permission: %q", route.Permission))
+		}
+		if route.GroupMeta != nil && route.GroupMeta.Name != "" {
+			fields = append(fields, fmt.Sprintf("Group: %q", route.GroupMeta.Name))
+		}
+		fields = append(fields, fmt.Sprintf("Handler: %q", route.ControllerType+"."+route.HandlerName))
+		var mws []string
+		for _, m := range route.AllMiddlewares() {
+			if m != "" && !strings.EqualFold(m, "rbac") {
+				mws = append(mws, fmt.Sprintf("%q", normalizeMiddleware(m)))
+			}
+		}
+		if len(mws) > 0 {
+			fields = append(fields, fmt.Sprintf("Middlewares: []string{%s}", strings.Join(mws, ", ")))
+		}
+		builder.WriteString(fmt.Sprintf("\t\troutemeta.Route{%s},\n", strings.Join(fields, ", ")))
 	}
+	builder.WriteString("\t)\n")
+}
 
-	return builder.String()
+// needsJWTDeclared 路由或所在分组是否声明了 jwt
+func needsJWTDeclared(route annotations.RouteMeta) bool {
+	return hasJWT(route.AllMiddlewares())
+}
+
+// middlewareCall 注解中的中间件对应的调用，经 pkg/middleware 的注册表创建，写法已在校验时确认有效
+func middlewareCall(spec string) string {
+	return fmt.Sprintf("middleware.Use(%q)", normalizeMiddleware(spec))
+}
+
+// normalizeMiddleware 规范化中间件写法：名称小写，参数以 ", " 分隔，如 "rateLimit(10/s,20)" -> "ratelimit(10/s, 20)"
+func normalizeMiddleware(spec string) string {
+	name, args, err := middleware.ParseSpec(spec)
+	if err != nil {
+		return spec
+	}
+	if len(args) == 0 {
+		return name
+	}
+	return name + "(" + strings.Join(args, ", ") + ")"
 }
 
+// buildMiddlewares 路由自身的中间件：jwt、按权限生成的 RBAC、其他中间件，分组中已有的不再重复
 func buildMiddlewares(route annotations.RouteMeta) string {
 	var middlewares []string
-	// 优先处理jwt
+	inGroup := func(m string) bool { return groupHas(route, m) }
+	// 优先处理jwt，分组中已有 jwt 时不再重复
 	for _, m := range route.Middlewares {
-		if strings.EqualFold(m, "jwt") {
-			middlewares = append(middlewares, "middleware.JWT()")
+		if isJWT(m) && !hasJWT(groupMiddlewares(route)) {
+			middlewares = append(middlewares, middlewareCall(m))
 			break
 		}
 	}
@@ -330,12 +439,8 @@ func buildMiddlewares(route annotations.RouteMeta) string {
 
 	// 最后是其他中间件(包括dataperm)
 	for _, m := range route.Middlewares {
-		if m != "" && !strings.EqualFold(m, "jwt") && strings.ToUpper(m) != "RBAC" {
-			if strings.EqualFold(m, "dataperm") {
-				middlewares = append(middlewares, "middleware.DATAPERM()")
-			} else {
-				middlewares = append(middlewares, fmt.Sprintf("middleware.%s()", strings.ToUpper(m)))
-			}
+		if m != "" && !isJWT(m) && !strings.EqualFold(m, "rbac") && !inGroup(m) {
+			middlewares = append(middlewares, middlewareCall(m))
 		}
 	}
 
@@ -345,48 +450,70 @@ func buildMiddlewares(route annotations.RouteMeta) string {
 	return strings.Join(middlewares, ", ")
 }
 
-func buildGroupTree(routes []annotations.RouteMeta) map[string][]annotations.RouteMeta {
-	groupMap := make(map[string][]annotations.RouteMeta)
+// routeGroup 路径和分组中间件都相同的路由生成到同一个 gin 分组
+type routeGroup struct {
+	path        string
+	middlewares []string
+	routes      []annotations.RouteMeta
+}
+
+// buildGroups 按分组路径和分组中间件归类路由，保持扫描顺序
+func buildGroups(routes []annotations.RouteMeta) []*routeGroup {
+	var groups []*routeGroup
+	index := make(map[string]*routeGroup)
 	for _, route := range routes {
 		groupPath := "/"
-		if route.GroupMeta != nil && route.GroupMeta.Path != "" {
-			groupPath = route.GroupMeta.Path
+		var mws []string
+		if route.GroupMeta != nil {
+			if route.GroupMeta.Path != "" {
+				groupPath = route.GroupMeta.Path
+			}
+			mws = route.GroupMeta.Middlewares
 		}
 		// 规范化分组路径
 		if groupPath != "/" {
 			groupPath = strings.TrimRight(groupPath, "/")
 		}
-		groupMap[groupPath] = append(groupMap[groupPath], route)
+		var names []string
+		for _, m := range mws {
+			names = append(names, normalizeMiddleware(m))
+		}
+		key := groupPath + "|" + strings.Join(names, ",")
+		g, ok := index[key]
+		if !ok {
+			g = &routeGroup{path: groupPath, middlewares: names}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.routes = append(g.routes, route)
 	}
-	return groupMap
+	return groups
 }
 
-func renderGroupTree(builder *strings.Builder, groupTree map[string][]annotations.RouteMeta, engineVar string, ctrlInstances map[string]string) {
-	groupVars := make(map[string]string)
+var nonIdent = regexp.MustCompile(`[^A-Za-z0-9_]+`)
 
-	for groupPath, groupRoutes := range groupTree {
-		if groupPath == "/" {
+func renderGroups(builder *strings.Builder, groups []*routeGroup, engineVar string, ctrlInstances map[string]string) {
+	for _, g := range groups {
+		if g.path == "/" && len(g.middlewares) == 0 {
 			// 处理根路径路由
-			for _, route := range groupRoutes {
+			for _, route := range g.routes {
 				registerRoute(builder, engineVar, route, ctrlInstances)
 			}
-		} else {
-			// 处理分组路由
-			groupVar := "group" + strings.ReplaceAll(strings.Trim(groupPath, "/"), "/", "_")
-			if _, exists := groupVars[groupPath]; !exists {
-				builder.WriteString(fmt.Sprintf("\t%s := %s.Group(\"%s\")\n", groupVar, engineVar, groupPath))
-				groupVars[groupPath] = groupVar
-				builder.WriteString("\t{\n")
-			}
-
-			for _, route := range groupRoutes {
-				registerRoute(builder, groupVar, route, ctrlInstances)
-			}
-
-			if _, exists := groupVars[groupPath]; exists {
-				builder.WriteString("\t}\n")
-			}
+			continue
+		}
+		// 处理分组路由，分组中间件通过 Group 的参数作用于分组内所有路由
+		groupVar := "group" + nonIdent.ReplaceAllString(strings.Trim(g.path, "/"), "_")
+		args := []string{fmt.Sprintf("%q", g.path)}
+		for _, m := range g.middlewares {
+			groupVar += "_" + strings.Trim(nonIdent.ReplaceAllString(m, "_"), "_")
+			args = append(args, middlewareCall(m))
 		}
+		builder.WriteString(fmt.Sprintf("\t%s := %s.Group(%s)\n", groupVar, engineVar, strings.Join(args, ", ")))
+		builder.WriteString("\t{\n")
+		for _, route := range g.routes {
+			registerRoute(builder, groupVar, route, ctrlInstances)
+		}
+		builder.WriteString("\t}\n")
 	}
 }
 
@@ -416,118 +543,205 @@ func registerRoute(builder *strings.Builder, groupVar string, route annotations.
 	}
 }
 
-func instantiateControllers(routes []annotations.RouteMeta, folderInfo *ControllerFolderInfo, builder *strings.Builder) map[string]string {
+// injector 生成控制器及其依赖的实例化代码，按构造函数的参数类型注入：
+// *gorm.DB 注入 db，services.X 递归实例化服务，repositories.X 调用 repositories.NewX(db)，
+// 其他类型使用服务包中无参数且返回该类型的函数（如 NewNotifyRegistry）
+type injector struct {
+	builder     *strings.Builder
+	services    *funcIndex
+	repoVars    map[string]string
+	serviceVars map[string]string
+	created     []serviceInstance // 按实例化顺序记录的服务
+}
+
+// serviceInstance 生成代码中实例化的服务
+type serviceInstance struct {
+	name    string // 服务名，如 NoticesService
+	varName string // 变量名
+	typ     string // 构造函数的返回类型，如 services.NoticesService
+}
+
+// funcIndex 包中的函数签名
+type funcIndex struct {
+	params  map[string][]string // 函数名 -> 参数类型
+	results map[string][]string // 函数名 -> 返回值类型
+}
+
+// loadFuncIndex 解析目录下所有文件中的包级函数
+func loadFuncIndex(dir string) (*funcIndex, error) {
+	idx := &funcIndex{params: make(map[string][]string), results: make(map[string][]string)}
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		node, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range node.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil {
+				continue
+			}
+			idx.params[fn.Name.Name] = qualifyTypes(fieldTypes(fn.Type.Params), node.Name.Name)
+			idx.results[fn.Name.Name] = qualifyTypes(fieldTypes(fn.Type.Results), node.Name.Name)
+		}
+	}
+	return idx, nil
+}
+
+// fieldTypes 参数或返回值的类型，同类型的多个参数按个数展开
+func fieldTypes(list *ast.FieldList) []string {
+	if list == nil {
+		return nil
+	}
+	var types []string
+	for _, f := range list.List {
+		n := len(f.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			types = append(types, exprToString(f.Type))
+		}
+	}
+	return types
+}
+
+// qualifyTypes 为包内导出类型加上包名，如服务包中的 NoticesService 即 services.NoticesService
+func qualifyTypes(types []string, pkg string) []string {
+	for i, t := range types {
+		name := strings.TrimPrefix(t, "*")
+		if name != "" && !strings.Contains(name, ".") && ast.IsExported(name) {
+			types[i] = strings.TrimSuffix(t, name) + pkg + "." + name
+		}
+	}
+	return types
+}
+
+// provider 返回指定类型、无参数的函数
+func (idx *funcIndex) provider(typ string) string {
+	var names []string
+	for name, params := range idx.params {
+		if len(params) == 0 && len(idx.results[name]) == 1 && idx.results[name][0] == typ {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+func instantiateControllers(routes []annotations.RouteMeta, folderInfo *ControllerFolderInfo, builder *strings.Builder) (map[string]string, []serviceInstance, []error) {
 	ctrlInstances := make(map[string]string)
-	repoVars := make(map[string]string)
-	serviceVars := make(map[string]string)
+	var errs []error
 
-	// 收集所有需要实例化的控制器类型
-	ctrlTypes := make(map[string]bool)
-	for _, route := range routes {
-		ctrlTypes[route.ControllerType] = true
+	servicesDir := filepath.Join(filepath.Dir(folderInfo.ImportPath), "services")
+	services, err := loadFuncIndex(servicesDir)
+	if err != nil {
+		return nil, nil, []error{fmt.Errorf("解析服务目录 %s 失败: %v", servicesDir, err)}
 	}
+	inj := &injector{builder: builder, services: services, repoVars: make(map[string]string), serviceVars: make(map[string]string)}
 
-	// 实例化所有控制器及其依赖
-	for ctrlType := range ctrlTypes {
-		filePath := findControllerFile(routes, ctrlType)
-		if filePath == "" {
-			builder.WriteString(fmt.Sprintf("\t// 警告: 未找到控制器 %s 的源文件\n", ctrlType))
-			continue
+	// 收集所有需要实例化的控制器类型，按扫描顺序保证生成结果稳定
+	var ctrlRoutes []annotations.RouteMeta
+	seen := make(map[string]bool)
+	for _, route := range routes {
+		if !seen[route.ControllerType] {
+			seen[route.ControllerType] = true
+			ctrlRoutes = append(ctrlRoutes, route)
 		}
+	}
 
-		params, err := getControllerConstructorParams(filePath, ctrlType)
+	// 实例化所有控制器及其依赖
+	for _, route := range ctrlRoutes {
+		ctrlType := route.ControllerType
+		params, err := getControllerConstructorParams(route.FilePath, ctrlType)
 		if err != nil {
-			builder.WriteString(fmt.Sprintf("\t// 警告: 无法获取控制器 %s 的构造参数: %v\n", ctrlType, err))
+			errs = append(errs, newRouteError(route, "无法获取控制器 %s 的构造函数 New%s: %v", ctrlType, ctrlType, err))
 			continue
 		}
 
-		ctrlVar := strings.ToLower(ctrlType[:1]) + ctrlType[1:]
-		ctrlInstances[ctrlType] = ctrlVar
-
-		// 处理构造函数参数
 		var args []string
 		for _, param := range params {
-			switch {
-			case param == "*gorm.DB":
-				args = append(args, "db")
-
-			case strings.HasPrefix(param, "*services.") || strings.HasPrefix(param, "services."):
-				svcName := strings.TrimPrefix(strings.TrimPrefix(param, "*"), "services.")
-				svcVar := strings.ToLower(svcName[:1]) + svcName[1:]
-
-				if _, exists := serviceVars[svcName]; !exists {
-					// 实例化服务及其依赖
-					args := instantiateService(svcName, folderInfo.ImportPath, builder, repoVars, serviceVars)
-					builder.WriteString(fmt.Sprintf("\t%s := services.New%s(%s)\n", svcVar, svcName, strings.Join(args, ", ")))
-					serviceVars[svcName] = svcVar
-				}
-				args = append(args, serviceVars[svcName])
-
-			case strings.HasPrefix(param, "*repositories.") || strings.HasPrefix(param, "repositories."):
-				repoName := strings.TrimPrefix(strings.TrimPrefix(param, "*"), "repositories.")
-				repoVar := strings.ToLower(repoName[:1]) + repoName[1:]
-
-				if _, exists := repoVars[repoName]; !exists {
-					builder.WriteString(fmt.Sprintf("\t%s := repositories.New%s(db)\n", repoVar, repoName))
-					repoVars[repoName] = repoVar
-				}
-				args = append(args, repoVar)
+			arg, err := inj.resolve(param, nil)
+			if err != nil {
+				errs = append(errs, newRouteError(route, "控制器 %s 的构造参数: %v", ctrlType, err))
+				continue
 			}
+			args = append(args, arg)
 		}
 
 		// 实例化控制器
+		ctrlVar := strings.ToLower(ctrlType[:1]) + ctrlType[1:]
+		ctrlInstances[ctrlType] = ctrlVar
 		builder.WriteString(fmt.Sprintf("\t%s := controllers.New%s(%s)\n",
 			ctrlVar, ctrlType, strings.Join(args, ", ")))
 	}
 
-	return ctrlInstances
+	return ctrlInstances, inj.created, errs
 }
 
-func instantiateService(serviceName, importPath string, builder *strings.Builder, repoVars, serviceVars map[string]string) []string {
-	params, err := getServiceConstructorParams(serviceName, importPath)
-	if err != nil {
-		builder.WriteString(fmt.Sprintf("\t// 警告: 无法获取服务 %s 的构造参数: %v\n", serviceName, err))
-		return nil
-	}
+// resolve 生成构造参数的实参，必要时先输出依赖的实例化代码；path 为正在实例化的服务链，用于发现循环依赖
+func (inj *injector) resolve(param string, path []string) (string, error) {
+	name := strings.TrimPrefix(param, "*")
+	switch {
+	case param == "*gorm.DB":
+		return "db", nil
 
-	var args []string
-	for _, param := range params {
-		switch {
-		case strings.HasPrefix(param, "*repositories.") || strings.HasPrefix(param, "repositories."):
-			repoName := strings.TrimPrefix(strings.TrimPrefix(param, "*"), "repositories.")
+	case strings.HasPrefix(name, "repositories."):
+		repoName := strings.TrimPrefix(name, "repositories.")
+		if _, exists := inj.repoVars[repoName]; !exists {
 			repoVar := strings.ToLower(repoName[:1]) + repoName[1:]
+			inj.builder.WriteString(fmt.Sprintf("\t%s := repositories.New%s(db)\n", repoVar, repoName))
+			inj.repoVars[repoName] = repoVar
+		}
+		return inj.repoVars[repoName], nil
 
-			if _, exists := repoVars[repoName]; !exists {
-				builder.WriteString(fmt.Sprintf("\t%s := repositories.New%s(db)\n", repoVar, repoName))
-				repoVars[repoName] = repoVar
+	case strings.HasPrefix(name, "services."):
+		svcName := strings.TrimPrefix(name, "services.")
+		if v, exists := inj.serviceVars[svcName]; exists {
+			return v, nil
+		}
+		for _, p := range path {
+			if p == svcName {
+				return "", fmt.Errorf("服务循环依赖: %s -> %s", strings.Join(path, " -> "), svcName)
 			}
-			args = append(args, repoVar)
-
-		case strings.HasPrefix(param, "*services.") || strings.HasPrefix(param, "services."):
-			subSvcName := strings.TrimPrefix(strings.TrimPrefix(param, "*"), "services.")
-			subSvcVar := strings.ToLower(subSvcName[:1]) + subSvcName[1:]
-
-			if _, exists := serviceVars[subSvcName]; !exists {
-				subArgs := instantiateService(subSvcName, importPath, builder, repoVars, serviceVars)
-				builder.WriteString(fmt.Sprintf("\t%s := services.New%s(%s)\n", subSvcVar, subSvcName, strings.Join(subArgs, ", ")))
-				serviceVars[subSvcName] = subSvcVar
+		}
+		params, ok := inj.services.params["New"+svcName]
+		if !ok {
+			return "", fmt.Errorf("未找到服务 %s 的构造函数 services.New%s", svcName, svcName)
+		}
+		var args []string
+		for _, p := range params {
+			arg, err := inj.resolve(p, append(path, svcName))
+			if err != nil {
+				return "", err
 			}
-			args = append(args, serviceVars[subSvcName])
-
-		case param == "*gorm.DB":
-			args = append(args, "db")
+			args = append(args, arg)
+		}
+		svcVar := strings.ToLower(svcName[:1]) + svcName[1:]
+		inj.builder.WriteString(fmt.Sprintf("\t%s := services.New%s(%s)\n", svcVar, svcName, strings.Join(args, ", ")))
+		inj.serviceVars[svcName] = svcVar
+		typ := "services." + svcName
+		if results := inj.services.results["New"+svcName]; len(results) > 0 {
+			typ = results[0]
 		}
+		inj.created = append(inj.created, serviceInstance{name: svcName, varName: svcVar, typ: typ})
+		return svcVar, nil
 	}
-	return args
-}
 
-func findControllerFile(routes []annotations.RouteMeta, ctrlType string) string {
-	for _, route := range routes {
-		if route.ControllerType == ctrlType {
-			return route.FilePath
-		}
+	if fn := inj.services.provider(param); fn != "" {
+		return fmt.Sprintf("services.%s()", fn), nil
 	}
-	return ""
+	return "", fmt.Errorf("无法注入 %s 类型的参数，服务包中没有返回该类型的无参函数", param)
 }
 
 func getControllerConstructorParams(filePath, controllerType string) ([]string, error) {
@@ -538,40 +752,13 @@ func getControllerConstructorParams(filePath, controllerType string) ([]string,
 	}
 	constructorName := "New" + controllerType
 	for _, decl := range node.Decls {
-		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == constructorName {
-			var params []string
-			for _, param := range fn.Type.Params.List {
-				typeStr := exprToString(param.Type)
-				params = append(params, typeStr)
-			}
-			return params, nil
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == constructorName {
+			return fieldTypes(fn.Type.Params), nil
 		}
 	}
 	return nil, fmt.Errorf("constructor not found")
 }
 
-func getServiceConstructorParams(serviceType, importPath string) ([]string, error) {
-	baseDir := filepath.Dir(importPath)
-	serviceFile := filepath.Join(baseDir, "services", strings.ToLower(serviceType)+".go")
-	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, serviceFile, nil, parser.ParseComments)
-	if err != nil {
-		return nil, err
-	}
-	constructorName := "New" + serviceType
-	for _, decl := range node.Decls {
-		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == constructorName {
-			var params []string
-			for _, param := range fn.Type.Params.List {
-				typeStr := exprToString(param.Type)
-				params = append(params, typeStr)
-			}
-			return params, nil
-		}
-	}
-	return nil, fmt.Errorf("constructor not found for %s", serviceType)
-}
-
 func exprToString(expr ast.Expr) string {
 	switch t := expr.(type) {
 	case *ast.StarExpr:
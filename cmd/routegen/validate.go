@@ -0,0 +1,261 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/zmqge/vireo-gin-admin/pkg/annotations"
+	"github.com/zmqge/vireo-gin-admin/pkg/middleware"
+)
+
+// routeError 注解错误，带文件位置
+type routeError struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *routeError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+func newRouteError(route annotations.RouteMeta, format string, args ...interface{}) *routeError {
+	return &routeError{File: route.FilePath, Line: route.Line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// routeLocation 路由的文件位置，用于在错误中指出另一处定义
+func routeLocation(route annotations.RouteMeta) string {
+	return fmt.Sprintf("%s:%d", route.FilePath, route.Line)
+}
+
+// httpMethods gin RouterGroup 支持的方法
+var httpMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "DELETE": true, "PATCH": true, "HEAD": true, "OPTIONS": true,
+}
+
+// validateRoutes 校验所有模块的路由注解：HTTP 方法、处理函数签名、中间件名称和参数、重复路由和 gin 通配符冲突
+// 中间件按 pkg/middleware 的注册表校验，RBAC 由 @Permission 生成，不能在注解中直接使用
+func validateRoutes(routes []annotations.RouteMeta) []error {
+	var errs []*routeError
+	handlers := newHandlerChecker()
+	seenGroups := make(map[*annotations.GroupMeta]bool)
+	seenPaths := make(map[string]annotations.RouteMeta)
+	trees := make(map[string]*wildcardNode)
+
+	for _, route := range routes {
+		if !httpMethods[route.Method] {
+			errs = append(errs, newRouteError(route, "不支持的 HTTP 方法 %q", route.Method))
+			continue
+		}
+		if msg := handlers.check(route); msg != "" {
+			errs = append(errs, newRouteError(route, "%s", msg))
+		}
+
+		// 分组中间件只在分组注解处报告一次
+		if g := route.GroupMeta; g != nil && !seenGroups[g] {
+			seenGroups[g] = true
+			for _, mw := range g.Middlewares {
+				if err := middleware.Validate(mw); err != nil {
+					errs = append(errs, &routeError{File: route.FilePath, Line: g.Line, Msg: "@Group: " + err.Error()})
+				}
+			}
+		}
+		for _, mw := range route.Middlewares {
+			if strings.EqualFold(mw, "rbac") {
+				continue
+			}
+			if err := middleware.Validate(mw); err != nil {
+				errs = append(errs, newRouteError(route, "%v", err))
+			}
+		}
+		// 分组中间件先于路由中间件执行，路由上的 jwt 会排在分组的其他中间件之后
+		if needsJWT(route) && !hasJWT(groupMiddlewares(route)) && len(groupMiddlewares(route)) > 0 {
+			errs = append(errs, newRouteError(route, "路由需要 jwt，但分组中间件 %v 会在 jwt 之前执行，请将 jwt 加入 @Group 的 middlewares",
+				groupMiddlewares(route)))
+		}
+
+		fullPath := route.FullPath()
+		key := route.Method + " " + fullPath
+		if prev, ok := seenPaths[key]; ok {
+			errs = append(errs, newRouteError(route, "路由 %s 重复，已在 %s 定义", key, routeLocation(prev)))
+			continue
+		}
+		seenPaths[key] = route
+		if trees[route.Method] == nil {
+			trees[route.Method] = &wildcardNode{}
+		}
+		if err := trees[route.Method].insert(route, fullPath); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].File != errs[j].File {
+			return errs[i].File < errs[j].File
+		}
+		return errs[i].Line < errs[j].Line
+	})
+	list := make([]error, len(errs))
+	for i, err := range errs {
+		list[i] = err
+	}
+	return list
+}
+
+// needsJWT 路由声明了 jwt 或设置了权限（生成时自动加 jwt）
+func needsJWT(route annotations.RouteMeta) bool {
+	return route.Permission != "" || hasJWT(route.Middlewares)
+}
+
+// isJWT 中间件是否为 jwt，含带参数的 jwt(ticket)
+func isJWT(spec string) bool {
+	name, _, err := middleware.ParseSpec(spec)
+	return err == nil && name == "jwt"
+}
+
+// hasJWT 中间件列表中是否有 jwt
+func hasJWT(specs []string) bool {
+	for _, mw := range specs {
+		if isJWT(mw) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupMiddlewares 路由所在分组的中间件
+func groupMiddlewares(route annotations.RouteMeta) []string {
+	if route.GroupMeta == nil {
+		return nil
+	}
+	return route.GroupMeta.Middlewares
+}
+
+// groupHas 分组是否包含指定中间件（名称和参数都相同）
+func groupHas(route annotations.RouteMeta, spec string) bool {
+	for _, mw := range groupMiddlewares(route) {
+		if normalizeMiddleware(mw) == normalizeMiddleware(spec) {
+			return true
+		}
+	}
+	return false
+}
+
+// handlerChecker 检查处理函数签名，按文件缓存解析结果
+type handlerChecker struct {
+	files map[string]*ast.File
+}
+
+func newHandlerChecker() *handlerChecker {
+	return &handlerChecker{files: make(map[string]*ast.File)}
+}
+
+// check 处理函数应为控制器的方法，签名为 func(*gin.Context)，返回错误描述
+func (h *handlerChecker) check(route annotations.RouteMeta) string {
+	node, ok := h.files[route.FilePath]
+	if !ok {
+		var err error
+		node, err = parser.ParseFile(token.NewFileSet(), route.FilePath, nil, 0)
+		if err != nil {
+			return err.Error()
+		}
+		h.files[route.FilePath] = node
+	}
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != route.HandlerName {
+			continue
+		}
+		if fn.Recv == nil || len(fn.Recv.List) == 0 {
+			return fmt.Sprintf("处理函数 %s 不是控制器 %s 的方法", route.HandlerName, route.ControllerType)
+		}
+		if recv := strings.TrimPrefix(exprToString(fn.Recv.List[0].Type), "*"); recv != route.ControllerType {
+			return fmt.Sprintf("处理函数 %s 的接收者为 %s，路由按文件中的第一个类型 %s 注册，请将其移到单独的文件",
+				route.HandlerName, recv, route.ControllerType)
+		}
+		params := fn.Type.Params.List
+		if len(params) != 1 || len(params[0].Names) > 1 || exprToString(params[0].Type) != "*gin.Context" || fn.Type.Results.NumFields() != 0 {
+			return fmt.Sprintf("处理函数 %s 的签名应为 func(ctx *gin.Context)", route.HandlerName)
+		}
+		return ""
+	}
+	return fmt.Sprintf("未找到处理函数 %s", route.HandlerName)
+}
+
+// wildcardNode 按路径段构建的路由树，用于在生成时发现 gin 注册时会 panic 的通配符冲突
+// gin 允许同一位置的静态段和 :param 共存，但同一位置的 :param 名称必须一致，*catchAll 不能与其他段共存且必须在末尾
+type wildcardNode struct {
+	static     map[string]*wildcardNode
+	param      *wildcardNode
+	paramName  string
+	catchAll   string
+	owner      annotations.RouteMeta // 第一个经过该节点的路由
+	paramOwner annotations.RouteMeta
+	catchOwner annotations.RouteMeta
+}
+
+func (n *wildcardNode) insert(route annotations.RouteMeta, path string) *routeError {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	node := n
+	for i, seg := range segments {
+		switch {
+		case strings.HasPrefix(seg, ":"):
+			name := seg[1:]
+			if node.catchAll != "" {
+				return newRouteError(route, "路径参数 :%s 与 %s 的通配符 *%s 冲突", name, routeLocation(node.catchOwner), node.catchAll)
+			}
+			if node.param == nil {
+				node.param, node.paramName, node.paramOwner = &wildcardNode{owner: route}, name, route
+			} else if node.paramName != name {
+				return newRouteError(route, "路径参数 :%s 与 %s 同一位置的 :%s 名称不一致", name, routeLocation(node.paramOwner), node.paramName)
+			}
+			node = node.param
+		case strings.HasPrefix(seg, "*"):
+			name := seg[1:]
+			if i != len(segments)-1 {
+				return newRouteError(route, "通配符 *%s 必须位于路径末尾", name)
+			}
+			if node.param != nil {
+				return newRouteError(route, "通配符 *%s 与 %s 的路径参数 :%s 冲突", name, routeLocation(node.paramOwner), node.paramName)
+			}
+			for _, child := range node.static {
+				return newRouteError(route, "通配符 *%s 与 %s 的路径冲突", name, routeLocation(child.owner))
+			}
+			if node.catchAll != "" && node.catchAll != name {
+				return newRouteError(route, "通配符 *%s 与 %s 同一位置的 *%s 名称不一致", name, routeLocation(node.catchOwner), node.catchAll)
+			}
+			node.catchAll, node.catchOwner = name, route
+		default:
+			if node.catchAll != "" {
+				return newRouteError(route, "路径与 %s 的通配符 *%s 冲突", routeLocation(node.catchOwner), node.catchAll)
+			}
+			if node.static == nil {
+				node.static = make(map[string]*wildcardNode)
+			}
+			child, ok := node.static[seg]
+			if !ok {
+				child = &wildcardNode{owner: route}
+				node.static[seg] = child
+			}
+			node = child
+		}
+	}
+	return nil
+}
+
+// exitOnErrors 打印错误并以非零状态退出
+func exitOnErrors(errs []error) {
+	if len(errs) == 0 {
+		return
+	}
+	for _, err := range errs {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	fmt.Fprintf(os.Stderr, "路由注解校验失败（%d 个错误），未生成路由文件\n", len(errs))
+	os.Exit(1)
+}
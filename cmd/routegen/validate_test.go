@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zmqge/vireo-gin-admin/pkg/annotations"
+)
+
+const badController = `package controllers
+
+import "github.com/gin-gonic/gin"
+
+// @Group(path="/api/v1/", name="测试", middlewares=["jwt", "ratelimit(10/x)"])
+type DemoController struct{}
+
+// @Route(method=GET, path="/demo/:id")
+func (c *DemoController) Get(ctx *gin.Context) {}
+
+// @Route(method=GET, path="/demo/:id")
+func (c *DemoController) GetAgain(ctx *gin.Context) {}
+
+// @Route(method=GET, path="/demo/:demoId/items")
+func (c *DemoController) Items(ctx *gin.Context) {}
+
+// @Route(method=GET, path="/files/*path/raw")
+func (c *DemoController) Raw(ctx *gin.Context) {}
+
+// @Route(method=POST, path="/demo", middlewares=["audit(创建, 示例)", "cache"])
+func (c *DemoController) Create(ctx *gin.Context) {}
+
+// @Route(method=PUT, path="/demo/:id")
+func (c *DemoController) Update(id uint) error { return nil }
+
+// @Route(method=FETCH, path="/demo/fetch")
+func (c *DemoController) Fetch(ctx *gin.Context) {}
+`
+
+func TestValidateRoutes(t *testing.T) {
+	// 路由生成器按相对路径记录控制器位置，临时目录放在当前目录下
+	dir, err := os.MkdirTemp(".", "routegen-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "demo.go")
+	if err := os.WriteFile(file, []byte(badController), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var routes []annotations.RouteMeta
+	if err := processControllerFile(file, &routes, make(map[string]*ControllerFolderInfo)); err != nil {
+		t.Fatal(err)
+	}
+	errs := validateRoutes(routes)
+
+	want := []string{
+		`demo.go:5: @Group: 中间件 ratelimit 的参数无效: 单位 "x" 应为 s、m 或 h`,
+		`demo.go:11: 路由 GET /api/v1/demo/:id 重复，已在`,
+		`demo.go:14: 路径参数 :demoId 与`,
+		`demo.go:17: 通配符 *path 必须位于路径末尾`,
+		`demo.go:20: 中间件 audit 的参数无效: 最多 1 个参数`,
+		`demo.go:20: 未知的中间件 "cache"`,
+		`demo.go:23: 处理函数 Update 的签名应为 func(ctx *gin.Context)`,
+		`demo.go:26: 不支持的 HTTP 方法 "FETCH"`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("应有 %d 个错误，实际 %d 个: %v", len(want), len(errs), errs)
+	}
+	for i, w := range want {
+		if !strings.Contains(errs[i].Error(), w) {
+			t.Errorf("第 %d 个错误应包含 %q，实际为 %q", i+1, w, errs[i])
+		}
+	}
+}
+
+func TestValidateRoutesOK(t *testing.T) {
+	group := &annotations.GroupMeta{Path: "/api/v1/", Middlewares: []string{"jwt"}}
+	routes := []annotations.RouteMeta{
+		{Method: "GET", Path: "/users/:id", GroupMeta: group},
+		{Method: "GET", Path: "/users/me", GroupMeta: group},
+		{Method: "DELETE", Path: "/users/:id", GroupMeta: group, Middlewares: []string{"rateLimit(10/s,20)", "jwt"}},
+	}
+	// 分组中已有的 jwt 不重复，带参数的中间件规范化后经注册表创建
+	if got := buildMiddlewares(routes[2]); got != `middleware.Use("ratelimit(10/s, 20)")` {
+		t.Errorf("中间件调用不正确: %s", got)
+	}
+	tree := &wildcardNode{}
+	for _, r := range routes[:2] {
+		if err := tree.insert(r, r.FullPath()); err != nil {
+			t.Fatalf("静态段与路径参数可以共存: %v", err)
+		}
+	}
+	if got := buildGroups(routes); len(got) != 1 || len(got[0].routes) != 3 || got[0].middlewares[0] != "jwt" {
+		t.Fatalf("同一路径和中间件的路由应在同一分组: %+v", got)
+	}
+}
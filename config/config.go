@@ -37,10 +37,57 @@ type Config struct {
 		CacheTTL       int    `mapstructure:"CacheTTL"`
 		SuperAdminRole string `mapstructure:"SuperAdminRole"`
 		AdminRole      string `mapstructure:"AdminRole"`
+		// 启动时检查路由引用的权限是否都在 permissions 表中：warn（默认）记录警告，fatal 终止启动，off 不检查
+		PermissionCheck string `mapstructure:"PermissionCheck"`
 	} `mapstructure:"RBAC"`
 	ControllerDirs []string `mapstructure:"CONTROLLER_DIRS"`
 	DemoMode       bool     `mapstructure:"DEMO_MODE"`
 	AllowedOrigins []string `mapstructure:"ALLOWED_ORIGINS"`
+	Notice         struct {
+		SchedulerInterval time.Duration `mapstructure:"SCHEDULER_INTERVAL"` // 定时发布/过期扫描间隔
+		ApprovalEnabled   bool          `mapstructure:"APPROVAL_ENABLED"`   // 无发布权限用户的草稿需审核通过后才能发布
+	} `mapstructure:"NOTICE"`
+	Notify struct {
+		LevelChannels  map[string][]string `mapstructure:"LEVEL_CHANNELS"`  // 通知级别 -> 触发的渠道
+		MaxAttempts    int                 `mapstructure:"MAX_ATTEMPTS"`    // 单条投递最大尝试次数
+		RetryBackoff   time.Duration       `mapstructure:"RETRY_BACKOFF"`   // 首次重试间隔，之后指数递增
+		WorkerInterval time.Duration       `mapstructure:"WORKER_INTERVAL"` // 投递队列扫描间隔
+		SMTP           struct {
+			Host     string `mapstructure:"HOST"`
+			Port     int    `mapstructure:"PORT"`
+			Username string `mapstructure:"USERNAME"`
+			Password string `mapstructure:"PASSWORD"`
+			From     string `mapstructure:"FROM"`
+		} `mapstructure:"SMTP"`
+		SMS struct {
+			URL    string `mapstructure:"URL"`
+			APIKey string `mapstructure:"API_KEY"`
+			Sign   string `mapstructure:"SIGN"`
+		} `mapstructure:"SMS"`
+		Webhook struct {
+			URL    string `mapstructure:"URL"`
+			Secret string `mapstructure:"SECRET"`
+		} `mapstructure:"WEBHOOK"`
+	} `mapstructure:"NOTIFY"`
+	Upload struct {
+		Dir         string        `mapstructure:"DIR"`          // 本地存储目录（相对项目根目录或绝对路径）
+		MaxSizeMB   int64         `mapstructure:"MAX_SIZE_MB"`  // 单个文件大小上限
+		AllowedExts []string      `mapstructure:"ALLOWED_EXTS"` // 允许的扩展名
+		URLTTL      time.Duration `mapstructure:"URL_TTL"`      // 下载链接有效期
+		SignSecret  string        `mapstructure:"SIGN_SECRET"`  // 下载链接签名密钥，为空时使用 JWT 访问密钥
+	} `mapstructure:"UPLOAD"`
+	SysConfig struct {
+		SecretKey string `mapstructure:"SECRET_KEY"` // 密文配置的加密密钥，为空时使用 JWT 访问密钥；更换后已加密的值无法解密
+	} `mapstructure:"SYS_CONFIG"`
+	I18N struct {
+		DefaultLocale string   `mapstructure:"DEFAULT_LOCALE"` // 默认语言，数据表中的菜单标题、字典标签即为该语言
+		Locales       []string `mapstructure:"LOCALES"`        // 支持的语言，按 Accept-Language 协商
+	} `mapstructure:"I18N"`
+	Docs struct {
+		Enabled   bool   `mapstructure:"ENABLED"`    // 是否提供 /swagger 接口文档
+		File      string `mapstructure:"FILE"`       // cmd/apidoc 生成的 OpenAPI 文档，默认 docs/openapi.json
+		AssetsDir string `mapstructure:"ASSETS_DIR"` // Swagger UI 静态资源目录，默认 docs/swagger-ui
+	} `mapstructure:"DOCS"`
 }
 
 var App Config
@@ -98,3 +145,8 @@ func loadYamlConfig() {
 	// 显式检查 Port 字段
 	fmt.Printf("App.Port: %#v\n", App.Port)
 }
+
+// ProjectRoot 项目根目录（用于解析配置中的相对路径）
+func ProjectRoot() string {
+	return getProjectRoot()
+}
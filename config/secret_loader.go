@@ -23,4 +23,26 @@ func LoadSecrets() {
 		App.JWT.RefreshSecret = envSecret
 		log.Println("[Config] JWT_REFRESH_SECRET loaded from env")
 	}
+
+	// 通知渠道凭据
+	if v := os.Getenv("NOTIFY_SMTP_PASSWORD"); v != "" {
+		App.Notify.SMTP.Password = v
+		log.Println("[Config] NOTIFY_SMTP_PASSWORD loaded from env")
+	}
+	if v := os.Getenv("NOTIFY_SMS_API_KEY"); v != "" {
+		App.Notify.SMS.APIKey = v
+		log.Println("[Config] NOTIFY_SMS_API_KEY loaded from env")
+	}
+	if v := os.Getenv("NOTIFY_WEBHOOK_SECRET"); v != "" {
+		App.Notify.Webhook.Secret = v
+		log.Println("[Config] NOTIFY_WEBHOOK_SECRET loaded from env")
+	}
+	if v := os.Getenv("UPLOAD_SIGN_SECRET"); v != "" {
+		App.Upload.SignSecret = v
+		log.Println("[Config] UPLOAD_SIGN_SECRET loaded from env")
+	}
+	if v := os.Getenv("SYS_CONFIG_SECRET_KEY"); v != "" {
+		App.SysConfig.SecretKey = v
+		log.Println("[Config] SYS_CONFIG_SECRET_KEY loaded from env")
+	}
 }
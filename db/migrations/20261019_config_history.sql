@@ -0,0 +1,27 @@
+-- 系统配置：值类型、密文配置与版本历史
+ALTER TABLE `Config`
+  MODIFY COLUMN `config_value` text COMMENT '配置值（密文配置加密存储）',
+  ADD COLUMN `value_type` varchar(20) DEFAULT 'string' COMMENT '值类型(string/int/bool/json/enum)' AFTER `config_value`,
+  ADD COLUMN `dict_code` varchar(50) DEFAULT NULL COMMENT 'enum类型的取值字典编码' AFTER `value_type`,
+  ADD COLUMN `is_secret` tinyint(1) DEFAULT 0 COMMENT '是否密文配置' AFTER `dict_code`,
+  ADD COLUMN `version` int DEFAULT 1 COMMENT '版本号' AFTER `is_secret`;
+
+UPDATE `Config` SET `value_type` = 'bool' WHERE `config_key` = 'sys.captcha.enabled';
+UPDATE `Config` SET `value_type` = 'json' WHERE `config_key` = 'sys.password.policy';
+
+CREATE TABLE IF NOT EXISTS `config_histories` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `config_id` bigint(20) NOT NULL COMMENT '配置ID',
+  `version` int NOT NULL COMMENT '版本号',
+  `config_key` varchar(100) DEFAULT NULL COMMENT '配置键',
+  `config_value` text COMMENT '配置值（与配置表相同的存储形式）',
+  `value_type` varchar(20) DEFAULT NULL COMMENT '值类型',
+  `dict_code` varchar(50) DEFAULT NULL COMMENT 'enum类型的取值字典编码',
+  `is_secret` tinyint(1) DEFAULT 0 COMMENT '是否密文配置',
+  `action` varchar(20) DEFAULT NULL COMMENT '动作(create/update/delete/rollback)',
+  `remark` varchar(255) DEFAULT NULL COMMENT '备注',
+  `operator_id` bigint(20) DEFAULT NULL COMMENT '操作人ID',
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_config_version` (`config_id`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='系统配置版本历史';
@@ -0,0 +1,34 @@
+-- 多语言：菜单标题、字典项标签的翻译表，缺少翻译时回退到默认语言（原表中的文本）
+CREATE TABLE IF NOT EXISTS `menu_i18n` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `menu_id` bigint(20) NOT NULL COMMENT '菜单ID',
+  `locale` varchar(10) NOT NULL COMMENT '语言',
+  `title` varchar(50) NOT NULL COMMENT '标题译文',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_menu_locale` (`menu_id`, `locale`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='菜单标题翻译';
+
+CREATE TABLE IF NOT EXISTS `dict_item_i18n` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `dict_code` varchar(50) NOT NULL COMMENT '字典编码',
+  `value` varchar(50) NOT NULL COMMENT '字典项值',
+  `locale` varchar(10) NOT NULL COMMENT '语言',
+  `label` varchar(50) NOT NULL COMMENT '标签译文',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_dict_item_locale` (`dict_code`, `value`, `locale`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='字典项标签翻译';
+
+-- 内置字典的英文标签
+INSERT IGNORE INTO `dict_item_i18n` (`dict_code`, `value`, `locale`, `label`) VALUES
+('gender', '1', 'en-US', 'Male'),
+('gender', '2', 'en-US', 'Female'),
+('gender', '0', 'en-US', 'Unknown'),
+('notice_type', '1', 'en-US', 'System upgrade'),
+('notice_type', '2', 'en-US', 'Maintenance'),
+('notice_type', '3', 'en-US', 'Security alert'),
+('notice_type', '4', 'en-US', 'Holiday'),
+('notice_type', '5', 'en-US', 'Company news'),
+('notice_level', 'L', 'en-US', 'Low'),
+('notice_level', 'M', 'en-US', 'Medium'),
+('notice_level', 'H', 'en-US', 'High');
+
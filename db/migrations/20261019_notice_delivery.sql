@@ -0,0 +1,27 @@
+-- 通知多渠道投递：投递队列与用户渠道偏好
+CREATE TABLE IF NOT EXISTS `notice_deliveries` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `notice_id` bigint(20) NOT NULL COMMENT '通知ID',
+  `user_id` bigint(20) NOT NULL COMMENT '接收人ID',
+  `channel` varchar(20) NOT NULL COMMENT '渠道(email/sms/webhook)',
+  `status` varchar(20) NOT NULL DEFAULT 'pending' COMMENT '状态(pending/sending/success/failed)',
+  `attempts` int(11) NOT NULL DEFAULT '0' COMMENT '已尝试次数',
+  `last_error` varchar(500) DEFAULT NULL COMMENT '最后一次错误',
+  `next_retry_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '下次尝试时间',
+  `sent_at` datetime DEFAULT NULL COMMENT '发送成功时间',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_notice_deliveries` (`notice_id`, `user_id`, `channel`),
+  KEY `idx_notice_deliveries_due` (`status`, `next_retry_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='通知渠道投递记录';
+
+CREATE TABLE IF NOT EXISTS `user_notify_preferences` (
+  `user_id` bigint(20) NOT NULL COMMENT '用户ID',
+  `email` tinyint(1) NOT NULL DEFAULT '1' COMMENT '接收邮件',
+  `sms` tinyint(1) NOT NULL DEFAULT '1' COMMENT '接收短信',
+  `webhook` tinyint(1) NOT NULL DEFAULT '0' COMMENT '接收Webhook',
+  `webhook_url` varchar(255) DEFAULT NULL COMMENT '自定义Webhook地址',
+  `updated_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='用户通知渠道偏好';
@@ -0,0 +1,24 @@
+-- 通知富文本内容与附件
+ALTER TABLE `notices`
+  MODIFY COLUMN `content` longtext COMMENT '通知内容（HTML 或 Markdown 原文）',
+  ADD COLUMN `content_format` varchar(10) NOT NULL DEFAULT 'html' COMMENT '内容格式(html/markdown)' AFTER `content`,
+  ADD COLUMN `content_html` longtext COMMENT '过滤后的HTML内容' AFTER `content_format`;
+
+ALTER TABLE `notice_templates`
+  MODIFY COLUMN `content` longtext COMMENT '通知内容模板',
+  ADD COLUMN `content_format` varchar(10) NOT NULL DEFAULT 'html' COMMENT '内容格式(html/markdown)' AFTER `content`;
+
+CREATE TABLE IF NOT EXISTS `notice_attachments` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `notice_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '通知ID（0为未绑定）',
+  `file_name` varchar(255) NOT NULL COMMENT '原始文件名',
+  `storage_key` varchar(255) NOT NULL COMMENT '存储路径',
+  `size` bigint(20) NOT NULL DEFAULT '0' COMMENT '文件大小（字节）',
+  `mime_type` varchar(100) DEFAULT NULL COMMENT '文件类型',
+  `inline` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否为正文内嵌图片',
+  `uploader_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '上传人ID',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_notice_attachments_notice` (`notice_id`),
+  KEY `idx_notice_attachments_uploader` (`uploader_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='通知附件';
@@ -0,0 +1,6 @@
+-- 通知定时发布与过期
+ALTER TABLE `notices`
+  ADD COLUMN `scheduled_at` datetime DEFAULT NULL COMMENT '定时发布时间' AFTER `revoked_at`,
+  ADD COLUMN `expires_at` datetime DEFAULT NULL COMMENT '过期时间' AFTER `scheduled_at`,
+  ADD KEY `idx_notices_scheduled_at` (`scheduled_at`),
+  ADD KEY `idx_notices_expires_at` (`expires_at`);
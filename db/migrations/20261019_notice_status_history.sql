@@ -0,0 +1,16 @@
+-- 通知审核流程：状态流转记录
+CREATE TABLE IF NOT EXISTS `notice_status_history` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `notice_id` bigint(20) NOT NULL COMMENT '通知ID',
+  `from_status` tinyint(4) NOT NULL COMMENT '原状态',
+  `to_status` tinyint(4) NOT NULL COMMENT '新状态',
+  `action` varchar(20) NOT NULL COMMENT '动作(submit/approve/reject/publish/schedule/cancel_schedule/revoke/expire/edit)',
+  `comment` varchar(500) DEFAULT NULL COMMENT '审核意见/备注',
+  `operator_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '操作人ID（0为系统任务）',
+  `created_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '操作时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_notice_status_history_notice` (`notice_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='通知状态流转记录';
+
+-- 状态说明：0-草稿 1-已发布 2-已撤回 4-待审核 5-定时待发布 6-已过期 7-审核通过 8-审核驳回
+ALTER TABLE `notices` MODIFY COLUMN `status` tinyint(4) DEFAULT '0' COMMENT '状态(0-草稿 1-发布 2-撤回 4-待审核 5-定时 6-过期 7-审核通过 8-驳回)';
@@ -0,0 +1,27 @@
+-- 通知模板：模板表，通知记录来源模板，接收人个性化内容
+CREATE TABLE IF NOT EXISTS `notice_templates` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL COMMENT '模板名称',
+  `title` varchar(50) DEFAULT NULL COMMENT '通知标题（仅支持自定义变量）',
+  `content` text COMMENT '通知内容模板',
+  `type` varchar(20) DEFAULT '0' COMMENT '类型',
+  `level` varchar(20) DEFAULT '0' COMMENT '级别',
+  `variables` json DEFAULT NULL COMMENT '自定义变量定义',
+  `remark` varchar(500) DEFAULT NULL COMMENT '备注',
+  `creator_id` bigint(20) DEFAULT NULL COMMENT '创建人ID',
+  `dept_id` bigint(20) DEFAULT NULL COMMENT '部门ID',
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  `deleted_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_notice_templates_creator_id` (`creator_id`),
+  KEY `idx_notice_templates_dept_id` (`dept_id`),
+  KEY `idx_notice_templates_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='通知模板';
+
+ALTER TABLE `notices`
+  ADD COLUMN `template_id` bigint(20) DEFAULT NULL COMMENT '来源模板ID' AFTER `expires_at`,
+  ADD COLUMN `template_vars` json DEFAULT NULL COMMENT '模板自定义变量' AFTER `template_id`;
+
+ALTER TABLE `notice_receiver`
+  ADD COLUMN `content` text COMMENT '按模板为该接收人渲染的内容，为空时使用通知内容';
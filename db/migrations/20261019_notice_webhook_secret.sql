@@ -0,0 +1,8 @@
+-- 用户自定义 Webhook 使用各自的签名密钥，不再以系统密钥签名
+ALTER TABLE `user_notify_preferences`
+  ADD COLUMN `webhook_secret` varchar(64) DEFAULT NULL COMMENT '自定义Webhook签名密钥' AFTER `webhook_url`;
+
+-- 已配置地址的用户生成密钥，用户可在通知渠道偏好中查看
+UPDATE `user_notify_preferences`
+SET `webhook_secret` = LOWER(HEX(RANDOM_BYTES(32)))
+WHERE `webhook_url` IS NOT NULL AND `webhook_url` <> '' AND `webhook_secret` IS NULL;
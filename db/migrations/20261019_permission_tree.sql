@@ -0,0 +1,6 @@
+-- 权限树：模块 -> 资源 -> 权限，模块和资源节点由 permgen 同步时生成（没有权限码，不参与授权）
+-- 执行后运行 go run ./cmd/permgen 生成节点并填充 parent_id
+ALTER TABLE `permissions`
+  MODIFY COLUMN `type` enum('menu','button','api','module','resource') DEFAULT 'api' COMMENT '权限类型(api接口权限/module模块节点/resource资源节点)',
+  ADD COLUMN `parent_id` int(10) unsigned DEFAULT NULL COMMENT '上级节点ID' AFTER `icon`,
+  ADD KEY `idx_permissions_parent_id` (`parent_id`);
@@ -0,0 +1,5 @@
+-- 系统配置：内置的运行时配置项（修改后各副本热加载，无需重启）
+-- sys.demo_mode 不预置，未配置时沿用配置文件中的 DEMO_MODE
+INSERT IGNORE INTO `Config` (`config_name`, `config_key`, `config_value`, `remark`, `created_at`, `updated_at`) VALUES
+('登录验证码', 'sys.captcha.enabled', 'true', '登录是否需要验证码（true/false）', NOW(), NOW()),
+('密码策略', 'sys.password.policy', '{"minLength":6,"requireDigit":false,"requireLetter":false,"requireUpper":false,"requireSpecial":false}', '修改/重置密码时的校验规则（JSON）', NOW(), NOW());
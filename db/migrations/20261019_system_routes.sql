@@ -0,0 +1,8 @@
+-- 路由清单：GET /api/v1/system/routes 的权限，授予管理员角色
+INSERT INTO `permissions` (`code`, `name`, `module`, `description`, `type`, `created_at`, `updated_at`) VALUES
+('sys:system:routes', '路由清单', '系统管理', '查看接口路由与权限的对应关系', 'api', NOW(), NOW())
+ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `module` = VALUES(`module`), `description` = VALUES(`description`), `deleted_at` = NULL;
+
+INSERT IGNORE INTO `role_permissions` (`role_id`, `permission_code`)
+SELECT r.`id`, 'sys:system:routes' FROM `roles` r
+WHERE r.`code` = 'ADMIN' AND r.`deleted_at` IS NULL;
@@ -0,0 +1,5 @@
+-- 新增、导入用户未填写密码时的初始密码，需符合密码策略
+INSERT IGNORE INTO `Config` (`config_name`, `config_key`, `config_value`, `value_type`, `remark`, `created_at`, `updated_at`) VALUES
+('用户初始密码', 'sys.user.init_password', '123456', 'string', '新增、导入用户未填写密码时的初始密码，需符合密码策略', NOW(), NOW());
+
+UPDATE `Config` SET `remark` = '新增用户、修改/重置密码时的校验规则（JSON）' WHERE `config_key` = 'sys.password.policy';
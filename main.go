@@ -1,16 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/app/admin/services"
 	"github.com/zmqge/vireo-gin-admin/config"
 	"github.com/zmqge/vireo-gin-admin/pkg/database"
+	"github.com/zmqge/vireo-gin-admin/pkg/dictcache"
+	"github.com/zmqge/vireo-gin-admin/pkg/i18n"
 	"github.com/zmqge/vireo-gin-admin/pkg/middleware"
+	"github.com/zmqge/vireo-gin-admin/pkg/push"
 	"github.com/zmqge/vireo-gin-admin/pkg/redis"
+	"github.com/zmqge/vireo-gin-admin/pkg/routemeta"
+	"github.com/zmqge/vireo-gin-admin/pkg/sysconfig"
 	"github.com/zmqge/vireo-gin-admin/routes"
 	"go.uber.org/zap"
 )
@@ -24,12 +33,27 @@ func main() {
 
 	// 1. 初始化配置
 	config.Init()
+	i18n.Configure(config.App.I18N.DefaultLocale, config.App.I18N.Locales)
 
 	// 2. 初始化数据库
 	db := database.InitDB()
 	redis.InitRedis()
 	defer database.Close()
 
+	// 加载系统配置（config 表），变更时经 Redis 通知各副本热更新
+	sysconfig.Default.SetLoader(services.NewConfigService(
+		repositories.NewConfigRepository(db),
+		repositories.NewDictRepository(db),
+	).LoadValues)
+	if err := sysconfig.Default.Load(context.Background()); err != nil {
+		log.Printf("加载系统配置失败: %v", err)
+	}
+	go sysconfig.Default.Run(context.Background())
+
+	// 字典缓存：本地内存 + Redis，字典写操作后经 Redis 通知各副本失效
+	dictcache.Default.SetLoader(services.NewDictService(repositories.NewDictRepository(db)).LoadDictOptions)
+	go dictcache.Default.Run(context.Background())
+
 	// 创建 Gin 引擎
 	r := gin.Default()
 
@@ -49,18 +73,39 @@ func main() {
 		// 允许的 HTTP 方法
 		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		// 允许的请求头
-		AllowHeaders: []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders: []string{"Origin", "Content-Type", "Authorization", "If-None-Match", "Accept-Language", "X-Request-ID"},
+		// 允许前端读取的响应头（字典接口的 ETag、协商后的语言、请求 ID）
+		ExposeHeaders: []string{"ETag", "Content-Language", "X-Request-ID"},
 		// 是否允许携带凭证（如 Cookie）
 		AllowCredentials: true,
 		// 预检请求的缓存时间
 		MaxAge: 12 * time.Hour,
 	}))
+	r.Use(middleware.RequestID()) // 请求 ID，错误日志和错误响应中携带
+	r.Use(middleware.Locale())    // 语言协商，需在其他返回响应的中间件之前
 	r.Use(middleware.DemoMode())
-	r.Use(middleware.Logger())   // 日志中间件
-	r.Use(middleware.Recovery()) // 恢复中间件
+	r.Use(middleware.Logger())       // 日志中间件
+	r.Use(middleware.Recovery())     // 恢复中间件
+	r.Use(middleware.ErrorHandler()) // 统一渲染 c.Error 记录的错误
 
 	// 注册所有路由
-	routes.RegisterAllRoutes(r, db)
+	svcs := routes.RegisterAllRoutes(r, db)
+	checkRoutePermissions()
+	// 接口文档（由 cmd/apidoc 生成）
+	if config.App.Docs.Enabled {
+		routes.RegisterDocsRoutes(r)
+	}
+
+	// 启动通知定时任务（定时发布、过期下线），复用路由中创建的服务实例
+	noticesService := svcs.Admin.NoticesService
+	go services.NewNoticeScheduler(noticesService, config.App.Notice.SchedulerInterval).Run(context.Background())
+
+	// 启动通知投递任务（邮件、短信、Webhook）
+	go services.NewNoticeDeliveryWorker(svcs.Admin.NoticeDeliveryService, config.App.Notify.WorkerInterval).Run(context.Background())
+
+	// 启动实时推送：订阅 Redis 频道，将其他副本发布的事件投递到本实例的连接
+	push.Default.SetUnreadCounter(noticesService.CountUnread)
+	go push.Default.Run(context.Background())
 
 	// 显示欢迎画面
 	showWelcomeMessage()
@@ -69,6 +114,30 @@ func main() {
 	r.Run(":8080")
 }
 
+// checkRoutePermissions 检查路由 RBAC 引用的权限是否都在 permissions 表中，
+// 缺失的权限除超级管理员外无人可被授予，通常是新增接口后未执行 permgen
+func checkRoutePermissions() {
+	mode := strings.ToLower(config.App.RBAC.PermissionCheck)
+	if mode == "off" {
+		return
+	}
+	missing, err := services.NewPermissionService().MissingRoutePermissions()
+	if err != nil {
+		log.Printf("检查路由权限失败: %v", err)
+		return
+	}
+	if len(missing) == 0 {
+		return
+	}
+	perms := routemeta.Permissions()
+	for _, code := range missing {
+		log.Printf("[WARNING] 权限 %s 不在 permissions 表中，使用该权限的路由: %s", code, strings.Join(perms[code], ", "))
+	}
+	if mode == "fatal" {
+		log.Fatalf("%d 个路由权限不在 permissions 表中，请执行 go run ./cmd/permgen 同步权限", len(missing))
+	}
+}
+
 // showWelcomeMessage 显示欢迎画面
 func showWelcomeMessage() {
 	fmt.Printf(`
@@ -0,0 +1,898 @@
+package annotations
+
+import (
+	"fmt"
+	"go/ast"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+)
+
+// OpenAPI 3 文档，只包含本项目用到的部分
+
+// Document OpenAPI 文档
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Tags       []Tag                            `json:"tags,omitempty"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+// Info 文档信息
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag 接口分组，对应控制器上的 @Group
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Operation 接口
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Permission  string                `json:"x-permission,omitempty"`  // 访问所需的权限码
+	Middlewares []string              `json:"x-middlewares,omitempty"` // 路由中间件
+}
+
+// Parameter 路径或查询参数
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType 内容类型
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components 可复用的定义
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme 认证方式
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Schema 数据结构
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+}
+
+const (
+	bearerAuth   = "bearerAuth"
+	errorSchema  = "Error"
+	schemaPrefix = "#/components/schemas/"
+)
+
+// OpenAPIOptions 文档生成选项
+type OpenAPIOptions struct {
+	Title          string
+	Version        string
+	Description    string
+	ControllerDirs []string // 控制器目录
+	SourceDirs     []string // 请求和响应类型所在的源码目录，如 app、pkg
+}
+
+// BuildOpenAPI 根据控制器上的 @Group、@Route、@Permission 注解生成 OpenAPI 文档
+// 请求类型取处理函数中 ShouldBind* 绑定的变量，响应类型取 response.Success 的 data 参数，
+// 均在源码中静态推断，推断不出的部分以任意类型表示
+func BuildOpenAPI(opts OpenAPIOptions) (*Document, error) {
+	idx := NewTypeIndex()
+	for _, dir := range append(append([]string{}, opts.SourceDirs...), opts.ControllerDirs...) {
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		if err := idx.AddDir(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	b := &docBuilder{idx: idx, schemas: make(map[string]*Schema)}
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: opts.Title, Version: opts.Version, Description: opts.Description},
+		Paths:   make(map[string]map[string]*Operation),
+	}
+	tags := make(map[string]bool)
+	for _, dir := range opts.ControllerDirs {
+		files, err := scanDirectory(dir)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			routes, _, _, err := ParseRouteAndPermission(file)
+			if err != nil {
+				return nil, fmt.Errorf("解析 %s 失败: %v", file, err)
+			}
+			src, err := idx.AddFile(file)
+			if err != nil {
+				return nil, err
+			}
+			perms := make(map[string]PermissionMeta)
+			metas, _ := ParsePermissionAnnotations(file)
+			for _, p := range metas {
+				perms[p.Code] = p
+			}
+			for _, route := range routes {
+				fn := findHandler(src, route.HandlerName)
+				op := b.operation(src, route, fn, perms[route.Permission])
+				if route.GroupMeta != nil && route.GroupMeta.Name != "" && !tags[route.GroupMeta.Name] {
+					tags[route.GroupMeta.Name] = true
+					doc.Tags = append(doc.Tags, Tag{Name: route.GroupMeta.Name, Description: route.GroupMeta.Description})
+				}
+				path, params := openAPIPath(route.FullPath())
+				op.Parameters = append(params, op.Parameters...)
+				if doc.Paths[path] == nil {
+					doc.Paths[path] = make(map[string]*Operation)
+				}
+				doc.Paths[path][strings.ToLower(route.Method)] = op
+			}
+		}
+	}
+
+	b.schemas[errorSchema] = errorResponseSchema()
+	doc.Components = Components{
+		Schemas: b.schemas,
+		SecuritySchemes: map[string]*SecurityScheme{
+			bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		},
+	}
+	return doc, nil
+}
+
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// openAPIPath 将 gin 的 :id、*path 转为 {id}、{path}，并生成路径参数
+func openAPIPath(path string) (string, []*Parameter) {
+	var params []*Parameter
+	out := ginParam.ReplaceAllStringFunc(path, func(m string) string {
+		name := m[1:]
+		schema := &Schema{Type: "string"}
+		if name == "id" || strings.HasSuffix(name, "Id") || strings.HasSuffix(name, "ID") {
+			schema = &Schema{Type: "integer", Format: "int64"}
+		}
+		params = append(params, &Parameter{Name: name, In: "path", Required: true, Schema: schema})
+		return "{" + name + "}"
+	})
+	return out, params
+}
+
+// findHandler 在控制器文件中查找处理函数
+func findHandler(f *srcFile, name string) *ast.FuncDecl {
+	for _, decl := range f.ast.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil && fn.Name.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// errorResponseSchema 错误响应，业务码取自错误目录
+func errorResponseSchema() *Schema {
+	var codes []interface{}
+	var lines []string
+	for _, e := range apperr.Catalog() {
+		codes = append(codes, e.Code)
+		lines = append(lines, fmt.Sprintf("%d (HTTP %d): %s", e.Code, e.Status, e.Key))
+	}
+	return &Schema{
+		Type:     "object",
+		Required: []string{"code", "msg"},
+		Properties: map[string]*Schema{
+			"code":      {Type: "integer", Description: "业务码\n\n" + strings.Join(lines, "\n"), Enum: codes},
+			"msg":       {Type: "string", Description: "按请求语言翻译后的消息"},
+			"requestId": {Type: "string", Description: "请求 ID，排查问题时提供"},
+			"details": {
+				Type:        "array",
+				Description: "字段级错误",
+				Items: &Schema{
+					Type: "object",
+					Properties: map[string]*Schema{
+						"field":   {Type: "string"},
+						"message": {Type: "string"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// docBuilder 生成接口和数据结构
+type docBuilder struct {
+	idx     *TypeIndex
+	schemas map[string]*Schema // components/schemas
+}
+
+// operation 生成单个接口
+func (b *docBuilder) operation(file *srcFile, route RouteMeta, fn *ast.FuncDecl, perm PermissionMeta) *Operation {
+	op := &Operation{
+		OperationID: route.ControllerType + "." + route.HandlerName,
+		Permission:  route.Permission,
+		Middlewares: route.AllMiddlewares(),
+		Responses:   make(map[string]*Response),
+	}
+	if fn != nil && fn.Recv != nil {
+		op.OperationID = receiverType(fn.Recv.List[0].Type) + "." + route.HandlerName
+	}
+	if route.GroupMeta != nil && route.GroupMeta.Name != "" {
+		op.Tags = []string{route.GroupMeta.Name}
+	}
+	op.Summary, op.Description = handlerSummary(fn, route.HandlerName)
+	if op.Summary == "" {
+		op.Summary = perm.Name
+	}
+	if perm.Description != "" && perm.Description != op.Summary && op.Description == "" {
+		op.Description = perm.Description
+	}
+	for _, mw := range op.Middlewares {
+		// jwt 或 jwt(ticket)
+		if name, _, _ := strings.Cut(mw, "("); strings.EqualFold(strings.TrimSpace(name), "jwt") {
+			op.Security = []map[string][]string{{bearerAuth: {}}}
+		}
+	}
+	if route.Permission != "" {
+		note := "权限码: `" + route.Permission + "`"
+		if op.Description != "" {
+			note = op.Description + "\n\n" + note
+		}
+		op.Description = note
+	}
+
+	var data *Schema
+	if fn != nil {
+		h := b.analyzeHandler(file, fn)
+		op.Parameters = h.params
+		if h.form != nil {
+			op.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{"multipart/form-data": {Schema: h.form}}}
+		}
+		if h.body != nil {
+			if strings.EqualFold(route.Method, "GET") || strings.EqualFold(route.Method, "DELETE") {
+				op.Parameters = append(op.Parameters, b.queryParams(h.bodyType)...)
+			} else {
+				op.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{"application/json": {Schema: h.body}}}
+			}
+		}
+		data = h.data
+	}
+	op.Responses["200"] = &Response{
+		Description: "成功",
+		Content:     map[string]*MediaType{"application/json": {Schema: successSchema(data)}},
+	}
+	op.Responses["default"] = &Response{
+		Description: "失败",
+		Content:     map[string]*MediaType{"application/json": {Schema: &Schema{Ref: schemaPrefix + errorSchema}}},
+	}
+	return op
+}
+
+// successSchema 成功响应的统一结构 {code, data, msg}
+func successSchema(data *Schema) *Schema {
+	if data == nil {
+		data = &Schema{Nullable: true, Description: "无数据"}
+	}
+	return &Schema{
+		Type:     "object",
+		Required: []string{"code", "msg"},
+		Properties: map[string]*Schema{
+			"code": {Type: "integer", Enum: []interface{}{0}},
+			"msg":  {Type: "string"},
+			"data": data,
+		},
+	}
+}
+
+// handlerSummary 从处理函数的注释中取摘要：优先 @Summary，其次第一行非注解注释（去掉开头的函数名）
+func handlerSummary(fn *ast.FuncDecl, name string) (summary, description string) {
+	if fn == nil || fn.Doc == nil {
+		return "", ""
+	}
+	var lines []string
+	for _, c := range fn.Doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		switch {
+		case strings.HasPrefix(text, "@Summary"):
+			summary = strings.TrimSpace(strings.TrimPrefix(text, "@Summary"))
+		case strings.HasPrefix(text, "@Description"):
+			lines = append(lines, strings.TrimSpace(strings.TrimPrefix(text, "@Description")))
+		case strings.HasPrefix(text, "@"), text == "":
+		default:
+			lines = append(lines, strings.TrimSpace(strings.TrimPrefix(text, name)))
+		}
+	}
+	if summary == "" && len(lines) > 0 {
+		summary, lines = lines[0], lines[1:]
+	}
+	var desc []string
+	for _, line := range lines {
+		if line != summary {
+			desc = append(desc, line)
+		}
+	}
+	return summary, strings.Join(desc, "\n")
+}
+
+// handlerInfo 处理函数中推断出的请求和响应
+type handlerInfo struct {
+	params   []*Parameter // ctx.Query 等读取的查询参数
+	body     *Schema      // ShouldBind* 绑定的请求
+	bodyType typeRef
+	form     *Schema // multipart 表单（FormFile、PostForm）
+	data     *Schema // response.Success 的 data
+}
+
+// scope 处理函数中局部变量的类型
+type scope struct {
+	file *srcFile
+	vars map[string]typeRef
+}
+
+// analyzeHandler 按语句顺序遍历处理函数，记录局部变量类型，识别参数绑定和成功响应
+func (b *docBuilder) analyzeHandler(file *srcFile, fn *ast.FuncDecl) *handlerInfo {
+	info := &handlerInfo{}
+	if fn.Body == nil {
+		return info
+	}
+	s := &scope{file: file, vars: make(map[string]typeRef)}
+	for _, field := range fn.Recv.List {
+		for _, n := range field.Names {
+			s.vars[n.Name] = typeRef{expr: field.Type, file: file}
+		}
+	}
+	seenQuery := make(map[string]bool)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.DeclStmt:
+			gen, ok := node.Decl.(*ast.GenDecl)
+			if !ok {
+				return true
+			}
+			for _, spec := range gen.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for i, name := range vs.Names {
+					if vs.Type != nil {
+						s.vars[name.Name] = typeRef{expr: vs.Type, file: file}
+					} else if i < len(vs.Values) {
+						if t, ok := b.exprType(s, vs.Values[i]); ok {
+							s.vars[name.Name] = t
+						}
+					}
+				}
+			}
+		case *ast.AssignStmt:
+			b.assign(s, node.Lhs, node.Rhs)
+		case *ast.RangeStmt:
+			if t, ok := b.exprType(s, node.X); ok {
+				if v, ok := node.Value.(*ast.Ident); ok {
+					if elem, ok := b.idx.elem(t); ok {
+						s.vars[v.Name] = elem
+					}
+				}
+			}
+		case *ast.CallExpr:
+			b.inspectCall(s, node, info, seenQuery)
+		}
+		return true
+	})
+	return info
+}
+
+// assign 记录赋值语句左侧变量的类型
+func (b *docBuilder) assign(s *scope, lhs, rhs []ast.Expr) {
+	var types []typeRef
+	if len(rhs) == 1 && len(lhs) > 1 {
+		types = b.callResults(s, rhs[0])
+	} else {
+		for _, r := range rhs {
+			t, _ := b.exprType(s, r)
+			types = append(types, t)
+		}
+	}
+	for i, l := range lhs {
+		id, ok := l.(*ast.Ident)
+		if !ok || id.Name == "_" || i >= len(types) || types[i].expr == nil {
+			continue
+		}
+		s.vars[id.Name] = types[i]
+	}
+}
+
+// inspectCall 识别参数绑定、查询参数、表单文件和成功响应
+func (b *docBuilder) inspectCall(s *scope, call *ast.CallExpr, info *handlerInfo, seenQuery map[string]bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	if pkg, ok := sel.X.(*ast.Ident); ok && s.file.imports[pkg.Name] == "response" {
+		if (sel.Sel.Name == "Success" || sel.Sel.Name == "PageSuccess") && len(call.Args) >= 2 && info.data == nil {
+			info.data = b.exprSchema(s, call.Args[1])
+		}
+		return
+	}
+	switch sel.Sel.Name {
+	case "ShouldBind", "ShouldBindJSON", "ShouldBindQuery", "Bind", "BindJSON", "BindQuery":
+		if len(call.Args) == 0 {
+			return
+		}
+		arg := call.Args[0]
+		if u, ok := arg.(*ast.UnaryExpr); ok {
+			arg = u.X
+		}
+		t, ok := b.exprType(s, arg)
+		if !ok {
+			return
+		}
+		if strings.HasSuffix(sel.Sel.Name, "Query") {
+			info.params = append(info.params, b.queryParams(t)...)
+			return
+		}
+		info.bodyType = t
+		info.body = b.schemaOf(t)
+	case "Query", "DefaultQuery", "GetQuery":
+		name, ok := stringArg(call, 0)
+		if !ok || seenQuery[name] {
+			return
+		}
+		seenQuery[name] = true
+		p := &Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}}
+		if def, ok := stringArg(call, 1); ok && sel.Sel.Name == "DefaultQuery" {
+			p.Schema.Default = def
+			if _, err := strconv.Atoi(def); err == nil {
+				p.Schema.Type = "integer"
+			}
+		}
+		info.params = append(info.params, p)
+	case "FormFile", "PostForm", "DefaultPostForm":
+		name, ok := stringArg(call, 0)
+		if !ok {
+			return
+		}
+		if info.form == nil {
+			info.form = &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		}
+		if sel.Sel.Name == "FormFile" {
+			info.form.Properties[name] = &Schema{Type: "string", Format: "binary"}
+			info.form.Required = append(info.form.Required, name)
+		} else {
+			info.form.Properties[name] = &Schema{Type: "string"}
+		}
+	}
+}
+
+// stringArg 第 i 个参数为字符串字面量时返回其值
+func stringArg(call *ast.CallExpr, i int) (string, bool) {
+	if i >= len(call.Args) {
+		return "", false
+	}
+	lit, ok := call.Args[i].(*ast.BasicLit)
+	if !ok {
+		return "", false
+	}
+	v, err := strconv.Unquote(lit.Value)
+	return v, err == nil
+}
+
+// exprType 推断表达式的类型
+func (b *docBuilder) exprType(s *scope, e ast.Expr) (typeRef, bool) {
+	switch x := e.(type) {
+	case *ast.Ident:
+		t, ok := s.vars[x.Name]
+		return t, ok
+	case *ast.ParenExpr:
+		return b.exprType(s, x.X)
+	case *ast.CompositeLit:
+		if x.Type == nil {
+			return typeRef{}, false
+		}
+		return typeRef{expr: x.Type, file: s.file}, true
+	case *ast.UnaryExpr:
+		return b.exprType(s, x.X)
+	case *ast.StarExpr:
+		return b.exprType(s, x.X)
+	case *ast.SelectorExpr:
+		if t, ok := b.exprType(s, x.X); ok {
+			return b.idx.field(t, x.Sel.Name)
+		}
+	case *ast.IndexExpr:
+		if t, ok := b.exprType(s, x.X); ok {
+			return b.idx.elem(t)
+		}
+	case *ast.TypeAssertExpr:
+		if x.Type != nil {
+			return typeRef{expr: x.Type, file: s.file}, true
+		}
+	case *ast.CallExpr:
+		if results := b.callResults(s, x); len(results) > 0 && results[0].expr != nil {
+			return results[0], true
+		}
+	}
+	return typeRef{}, false
+}
+
+// callResults 推断函数调用的返回值类型
+func (b *docBuilder) callResults(s *scope, e ast.Expr) []typeRef {
+	call, ok := e.(*ast.CallExpr)
+	if !ok {
+		t, _ := b.exprType(s, e)
+		return []typeRef{t}
+	}
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		switch fun.Name {
+		case "make", "new":
+			if len(call.Args) > 0 {
+				return []typeRef{{expr: call.Args[0], file: s.file}}
+			}
+			return nil
+		case "append":
+			if len(call.Args) > 0 {
+				t, _ := b.exprType(s, call.Args[0])
+				return []typeRef{t}
+			}
+			return nil
+		}
+		if fn, ok := b.idx.funcs[s.file.pkg+"."+fun.Name]; ok {
+			return fn.results()
+		}
+	case *ast.SelectorExpr:
+		// 包级函数
+		if pkg, ok := fun.X.(*ast.Ident); ok {
+			if _, isVar := s.vars[pkg.Name]; !isVar {
+				if name, ok := s.file.imports[pkg.Name]; ok {
+					if fn, ok := b.idx.funcs[name+"."+fun.Sel.Name]; ok {
+						return fn.results()
+					}
+					// 类型转换，如 models.JSON(x)
+					if _, ok := b.idx.types[name+"."+fun.Sel.Name]; ok {
+						return []typeRef{{expr: fun, file: s.file}}
+					}
+					return nil
+				}
+			}
+		}
+		// 方法调用，按接收者的类型查找
+		if recv, ok := b.exprType(s, fun.X); ok {
+			if fn, ok := b.idx.method(recv, fun.Sel.Name); ok {
+				return fn.results()
+			}
+		}
+	case *ast.ArrayType, *ast.MapType:
+		return []typeRef{{expr: fun, file: s.file}}
+	}
+	return nil
+}
+
+// exprSchema 响应数据表达式的结构，gin.H 和 map 字面量按键展开
+func (b *docBuilder) exprSchema(s *scope, e ast.Expr) *Schema {
+	if id, ok := e.(*ast.Ident); ok && id.Name == "nil" {
+		return nil
+	}
+	if u, ok := e.(*ast.UnaryExpr); ok {
+		return b.exprSchema(s, u.X)
+	}
+	if lit, ok := e.(*ast.CompositeLit); ok && isMapLiteral(lit, s.file) {
+		obj := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.BasicLit)
+			if !ok {
+				continue
+			}
+			name, err := strconv.Unquote(key.Value)
+			if err != nil {
+				continue
+			}
+			obj.Properties[name] = b.exprSchema(s, kv.Value)
+			if obj.Properties[name] == nil {
+				obj.Properties[name] = &Schema{Nullable: true}
+			}
+		}
+		return obj
+	}
+	if lit, ok := e.(*ast.BasicLit); ok {
+		switch lit.Kind.String() {
+		case "INT":
+			return &Schema{Type: "integer"}
+		case "FLOAT":
+			return &Schema{Type: "number"}
+		case "STRING":
+			return &Schema{Type: "string"}
+		}
+	}
+	if t, ok := b.exprType(s, e); ok {
+		return b.schemaOf(t)
+	}
+	return &Schema{}
+}
+
+// isMapLiteral 是否为 gin.H 或键为字符串的 map 字面量
+func isMapLiteral(lit *ast.CompositeLit, f *srcFile) bool {
+	switch t := lit.Type.(type) {
+	case *ast.MapType:
+		return true
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			return f.imports[x.Name] == "gin" && t.Sel.Name == "H"
+		}
+	}
+	return false
+}
+
+// queryParams 将绑定查询参数的结构体展开为查询参数，参数名取 form 标签
+func (b *docBuilder) queryParams(t typeRef) []*Parameter {
+	var params []*Parameter
+	b.eachField(t, func(f *ast.Field, name string, file *srcFile) {
+		tag := fieldTag(f)
+		key := tagName(tag.Get("form"))
+		if key == "-" {
+			return
+		}
+		if key == "" {
+			key = name
+		}
+		params = append(params, &Parameter{
+			Name:        key,
+			In:          "query",
+			Description: fieldDoc(f),
+			Required:    strings.Contains(tag.Get("binding"), "required"),
+			Schema:      b.schemaOf(typeRef{expr: f.Type, file: file}),
+		})
+	})
+	return params
+}
+
+// eachField 遍历结构体的导出字段，嵌入的结构体展开
+func (b *docBuilder) eachField(t typeRef, fn func(f *ast.Field, name string, file *srcFile)) {
+	u, _ := b.idx.underlying(t)
+	st, ok := u.expr.(*ast.StructType)
+	if !ok {
+		return
+	}
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			if tagName(fieldTag(f).Get("json")) == "" {
+				b.eachField(typeRef{expr: f.Type, file: u.file}, fn)
+				continue
+			}
+			fn(f, embeddedName(f.Type), u.file)
+			continue
+		}
+		for _, n := range f.Names {
+			if ast.IsExported(n.Name) {
+				fn(f, n.Name, u.file)
+			}
+		}
+	}
+}
+
+// schemaOf 类型的结构，命名的结构体登记到 components/schemas 并返回引用
+func (b *docBuilder) schemaOf(t typeRef) *Schema {
+	t = t.deref()
+	switch x := t.expr.(type) {
+	case *ast.Ident:
+		if s, ok := builtinSchema(x.Name); ok {
+			return s
+		}
+	case *ast.SelectorExpr:
+		if s, ok := externalSchema(t.qualifiedName()); ok {
+			return s
+		}
+	case *ast.ArrayType:
+		if id, ok := x.Elt.(*ast.Ident); ok && id.Name == "byte" {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schemaOf(typeRef{expr: x.Elt, file: t.file})}
+	case *ast.MapType:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOf(typeRef{expr: x.Value, file: t.file})}
+	case *ast.InterfaceType:
+		return &Schema{}
+	case *ast.StructType:
+		return b.structSchema(t)
+	}
+
+	name := t.qualifiedName()
+	decl, ok := b.idx.types[name]
+	if !ok {
+		return &Schema{}
+	}
+	u := typeRef{expr: decl.spec.Type, file: decl.file}
+	if _, isStruct := u.expr.(*ast.StructType); !isStruct {
+		// 非结构体的命名类型（如 type Status int）直接展开
+		s := b.schemaOf(u)
+		if s.Ref == "" && s.Description == "" && decl.spec.Doc != nil {
+			c := *s
+			c.Description = docText(decl.spec.Doc, decl.spec.Name.Name)
+			return &c
+		}
+		return s
+	}
+	if _, ok := b.schemas[name]; !ok {
+		b.schemas[name] = &Schema{} // 先占位，避免自引用的类型无限递归
+		s := b.structSchema(u)
+		if decl.spec.Doc != nil {
+			s.Description = docText(decl.spec.Doc, decl.spec.Name.Name)
+		}
+		b.schemas[name] = s
+	}
+	return &Schema{Ref: schemaPrefix + name}
+}
+
+// structSchema 结构体的结构，字段名取 json 标签，binding:"required" 的字段为必填，
+// 带 dict 标签的字段补充只读的 xxxLabel（由 response.Success 填充）
+func (b *docBuilder) structSchema(t typeRef) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	b.eachField(t, func(f *ast.Field, name string, file *srcFile) {
+		tag := fieldTag(f)
+		key := tagName(tag.Get("json"))
+		if key == "-" {
+			return
+		}
+		if key == "" {
+			key = name
+		}
+		prop := b.schemaOf(typeRef{expr: f.Type, file: file})
+		if doc := fieldDoc(f); doc != "" {
+			if prop.Ref != "" {
+				prop = &Schema{Ref: prop.Ref}
+			} else {
+				c := *prop
+				c.Description = doc
+				prop = &c
+			}
+		}
+		s.Properties[key] = prop
+		if strings.Contains(tag.Get("binding"), "required") {
+			s.Required = append(s.Required, key)
+		}
+		if dict := tag.Get("dict"); dict != "" {
+			s.Properties[key+"Label"] = &Schema{Type: "string", Description: "字典 " + dict + " 的标签", ReadOnly: true}
+		}
+	})
+	return s
+}
+
+// builtinSchema 内置类型的结构
+func builtinSchema(name string) (*Schema, bool) {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}, true
+	case "bool":
+		return &Schema{Type: "boolean"}, true
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32", "byte", "rune":
+		return &Schema{Type: "integer"}, true
+	case "int64", "uint64":
+		return &Schema{Type: "integer", Format: "int64"}, true
+	case "float32":
+		return &Schema{Type: "number", Format: "float"}, true
+	case "float64":
+		return &Schema{Type: "number", Format: "double"}, true
+	case "any", "error":
+		return &Schema{}, true
+	}
+	return nil, false
+}
+
+// externalSchema 源码目录之外的常用类型
+func externalSchema(name string) (*Schema, bool) {
+	switch name {
+	case "time.Time":
+		return &Schema{Type: "string", Format: "date-time"}, true
+	case "time.Duration":
+		return &Schema{Type: "integer", Format: "int64"}, true
+	case "gorm.DeletedAt", "sql.NullTime":
+		return &Schema{Type: "string", Format: "date-time", Nullable: true}, true
+	case "sql.NullString":
+		return &Schema{Type: "string", Nullable: true}, true
+	case "sql.NullInt64", "sql.NullInt32":
+		return &Schema{Type: "integer", Nullable: true}, true
+	case "multipart.FileHeader":
+		return &Schema{Type: "string", Format: "binary"}, true
+	case "gin.H":
+		return &Schema{Type: "object"}, true
+	case "json.RawMessage", "datatypes.JSON":
+		return &Schema{}, true
+	case "decimal.Decimal":
+		return &Schema{Type: "string", Format: "decimal"}, true
+	}
+	return nil, false
+}
+
+func fieldTag(f *ast.Field) reflect.StructTag {
+	if f.Tag == nil {
+		return ""
+	}
+	tag, _ := strconv.Unquote(f.Tag.Value)
+	return reflect.StructTag(tag)
+}
+
+// tagName 标签中逗号前的名称
+func tagName(tag string) string {
+	return strings.SplitN(tag, ",", 2)[0]
+}
+
+// fieldDoc 字段的行尾注释或文档注释
+func fieldDoc(f *ast.Field) string {
+	if f.Comment != nil {
+		return strings.TrimSpace(f.Comment.Text())
+	}
+	if f.Doc != nil {
+		return strings.TrimSpace(f.Doc.Text())
+	}
+	return ""
+}
+
+// docText 类型注释，去掉开头的类型名
+func docText(doc *ast.CommentGroup, name string) string {
+	text := strings.TrimSpace(doc.Text())
+	return strings.TrimSpace(strings.TrimPrefix(text, name))
+}
+
+// embeddedName 嵌入字段的字段名
+func embeddedName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
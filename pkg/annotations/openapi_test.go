@@ -0,0 +1,95 @@
+package annotations
+
+import (
+	"testing"
+)
+
+func buildFixture(t *testing.T) *Document {
+	t.Helper()
+	doc, err := BuildOpenAPI(OpenAPIOptions{
+		Title:          "test",
+		Version:        "1.0.0",
+		ControllerDirs: []string{"testdata/api/controllers"},
+		SourceDirs:     []string{"testdata/api"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// dataSchema 成功响应中的 data
+func dataSchema(op *Operation) *Schema {
+	return op.Responses["200"].Content["application/json"].Schema.Properties["data"]
+}
+
+func TestBuildOpenAPI(t *testing.T) {
+	doc := buildFixture(t)
+
+	get := doc.Paths["/api/v1/articles/{id}"]["get"]
+	if get == nil {
+		t.Fatalf("缺少 GET /api/v1/articles/{id}: %v", doc.Paths)
+	}
+	if get.Permission != "sys:article:view" || len(get.Security) != 1 || get.Summary != "获取文章" {
+		t.Errorf("权限、认证或摘要不正确: %+v", get)
+	}
+	if len(get.Parameters) != 1 || get.Parameters[0].In != "path" || get.Parameters[0].Schema.Type != "integer" {
+		t.Errorf("路径参数不正确: %+v", get.Parameters)
+	}
+	if ref := dataSchema(get).Ref; ref != "#/components/schemas/models.Article" {
+		t.Errorf("响应应引用 models.Article，实际为 %q", ref)
+	}
+
+	article := doc.Components.Schemas["models.Article"]
+	if article == nil {
+		t.Fatal("缺少 models.Article")
+	}
+	if _, ok := article.Properties["password"]; ok {
+		t.Error(`json:"-" 的字段不应出现`)
+	}
+	if p := article.Properties["createdAt"]; p == nil || p.Format != "date-time" {
+		t.Errorf("time.Time 应为 date-time: %+v", p)
+	}
+	if p := article.Properties["statusLabel"]; p == nil || !p.ReadOnly {
+		t.Errorf("dict 字段应补充只读的 Label: %+v", p)
+	}
+	if p := article.Properties["author"]; p == nil || p.Ref != "#/components/schemas/models.Author" {
+		t.Errorf("嵌套结构体应引用 models.Author: %+v", p)
+	}
+}
+
+func TestBuildOpenAPIRequest(t *testing.T) {
+	doc := buildFixture(t)
+
+	list := doc.Paths["/api/v1/articles"]["get"]
+	names := make(map[string]*Parameter)
+	for _, p := range list.Parameters {
+		names[p.Name] = p
+	}
+	if p := names["keywords"]; p == nil || p.In != "query" || p.Description != "关键字" {
+		t.Errorf("查询参数应取 form 标签: %+v", list.Parameters)
+	}
+	data := dataSchema(list)
+	if items := data.Properties["list"]; items == nil || items.Type != "array" || items.Items.Ref != "#/components/schemas/models.Article" {
+		t.Errorf("list 应为 models.Article 数组: %+v", data.Properties["list"])
+	}
+	if total := data.Properties["total"]; total == nil || total.Format != "int64" {
+		t.Errorf("total 应为 int64: %+v", data.Properties["total"])
+	}
+
+	create := doc.Paths["/api/v1/articles"]["post"]
+	if create.Summary != "创建文章" || create.Security != nil {
+		t.Errorf("摘要应取 @Summary，无 jwt 中间件时不需要认证: %+v", create)
+	}
+	body := create.RequestBody.Content["application/json"].Schema
+	if body.Ref != "#/components/schemas/models.ArticleRequest" {
+		t.Fatalf("请求体应引用 models.ArticleRequest: %+v", body)
+	}
+	req := doc.Components.Schemas["models.ArticleRequest"]
+	if len(req.Required) != 1 || req.Required[0] != "title" || req.Properties["title"].Description != "标题" {
+		t.Errorf("必填字段或字段说明不正确: %+v", req)
+	}
+	if dataSchema(create).Type != "" || !dataSchema(create).Nullable {
+		t.Errorf("data 为 nil 时应为空: %+v", dataSchema(create))
+	}
+}
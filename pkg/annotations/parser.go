@@ -20,6 +20,7 @@ type RouteMeta struct {
 	Group          string     // 路由分组
 	GroupMeta      *GroupMeta // 路由分组元数据
 	FilePath       string     // 新增字段，记录控制器的文件路径
+	Line           int        // @Route 注解所在行
 }
 
 // PermissionAnnotation 权限注解
@@ -33,9 +34,41 @@ type PermissionAnnotation struct {
 
 // GroupMeta 分组元数据
 type GroupMeta struct {
-	Name        string // 分组名称
-	Path        string // 分组路径
-	Description string // 分组描述
+	Name        string   // 分组名称
+	Path        string   // 分组路径
+	Description string   // 分组描述
+	Middlewares []string // 分组中间件，作用于分组内所有路由
+	Line        int      // @Group 注解所在行
+}
+
+// FullPath 分组路径与路由路径拼接后的完整路径，与 gin 分组的拼接方式一致
+func (r RouteMeta) FullPath() string {
+	prefix := ""
+	if r.GroupMeta != nil {
+		prefix = strings.TrimRight(r.GroupMeta.Path, "/")
+	}
+	path := r.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return prefix + path
+}
+
+// AllMiddlewares 路由生效的中间件：分组中间件在前，路由中间件在后，重复的只保留一个
+func (r RouteMeta) AllMiddlewares() []string {
+	var list []string
+	seen := make(map[string]bool)
+	var groupMws []string
+	if r.GroupMeta != nil {
+		groupMws = r.GroupMeta.Middlewares
+	}
+	for _, mw := range append(append([]string{}, groupMws...), r.Middlewares...) {
+		if key := strings.ToLower(mw); !seen[key] {
+			seen[key] = true
+			list = append(list, mw)
+		}
+	}
+	return list
 }
 
 // ParseRouteAndPermission 解析文件中的路由、权限和分组注解
@@ -64,6 +97,7 @@ func ParseRouteAndPermission(filePath string) ([]RouteMeta, []PermissionAnnotati
 						for _, comment := range genDecl.Doc.List {
 							if strings.HasPrefix(comment.Text, "// @Group") {
 								groupMetaMap[controllerType] = parseGroupAnnotation(comment.Text)
+								groupMetaMap[controllerType].Line = fset.Position(comment.Pos()).Line
 								break // 只解析第一个类型声明上的 @Group 注解
 							}
 						}
@@ -74,6 +108,7 @@ func ParseRouteAndPermission(filePath string) ([]RouteMeta, []PermissionAnnotati
 						for _, comment := range typeSpec.Doc.List {
 							if strings.HasPrefix(comment.Text, "// @Group") {
 								groupMetaMap[controllerType] = parseGroupAnnotation(comment.Text)
+								groupMetaMap[controllerType].Line = fset.Position(comment.Pos()).Line
 							}
 						}
 					}
@@ -92,6 +127,7 @@ func ParseRouteAndPermission(filePath string) ([]RouteMeta, []PermissionAnnotati
 				// 解析 @Route 注解
 				if strings.HasPrefix(comment.Text, "// @Route") {
 					route = parseRouteAnnotation(comment.Text)
+					route.Line = fset.Position(comment.Pos()).Line
 					route.HandlerName = fn.Name.Name
 					route.ControllerType = getControllerType(node)
 					route.ImportPath = importPath
@@ -173,35 +209,13 @@ func parsePermissionAnnotation(comment string) PermissionAnnotation {
 
 // parseRouteAnnotation 解析 @Route 注解
 func parseRouteAnnotation(comment string) RouteMeta {
-	re := regexp.MustCompile(`@Route\(([^)]*)\)`)
+	re := regexp.MustCompile(`@Route\((.*)\)`)
 	matches := re.FindStringSubmatch(comment)
 	if len(matches) < 2 {
 		return RouteMeta{}
 	}
-	paramsStr := matches[1]
-	// 支持逗号分隔但中括号内不分割
-	var params []string
-	bracket := 0
-	start := 0
-	for i, ch := range paramsStr {
-		switch ch {
-		case '[':
-			bracket++
-		case ']':
-			bracket--
-		case ',':
-			if bracket == 0 {
-				params = append(params, strings.TrimSpace(paramsStr[start:i]))
-				start = i + 1
-			}
-		}
-	}
-	if start < len(paramsStr) {
-		params = append(params, strings.TrimSpace(paramsStr[start:]))
-	}
-
 	route := RouteMeta{}
-	for _, param := range params {
+	for _, param := range splitParams(matches[1]) {
 		kv := strings.SplitN(param, "=", 2)
 		if len(kv) != 2 {
 			continue
@@ -214,21 +228,7 @@ func parseRouteAnnotation(comment string) RouteMeta {
 		case "path":
 			route.Path = value
 		case "middlewares":
-			// 兼容各种写法 ["jwt",'dataPerm']、[ 'jwt' , "dataPerm" ]、["jwt" , 'dataPerm'] 等
-			value = strings.Trim(value, "[] ")
-			if value == "" {
-				route.Middlewares = nil
-			} else {
-				// 用正则提取所有被单引号或双引号包裹的内容
-				reMw := regexp.MustCompile(`['"][^'"]+['"]`)
-				matches := reMw.FindAllString(value, -1)
-				for _, m := range matches {
-					mw := strings.Trim(m, "'\"")
-					if mw != "" {
-						route.Middlewares = append(route.Middlewares, mw)
-					}
-				}
-			}
+			route.Middlewares = parseMiddlewares(value)
 		case "group":
 			route.Group = value
 		}
@@ -260,13 +260,57 @@ func getImportPath(filePath string) string {
 	return strings.TrimSuffix(importPath, ".go")
 }
 
+// splitParams 按逗号拆分注解参数，中括号和小括号内的逗号不拆分
+func splitParams(paramsStr string) []string {
+	var params []string
+	bracket := 0
+	start := 0
+	for i, ch := range paramsStr {
+		switch ch {
+		case '[', '(':
+			bracket++
+		case ']', ')':
+			bracket--
+		case ',':
+			if bracket == 0 {
+				params = append(params, strings.TrimSpace(paramsStr[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if start < len(paramsStr) {
+		params = append(params, strings.TrimSpace(paramsStr[start:]))
+	}
+	return params
+}
+
+// parseMiddlewares 解析中间件列表
+// 兼容各种写法 ["jwt",'dataPerm']、[ 'jwt' , "dataPerm" ]、["jwt" , 'dataPerm'] 等
+func parseMiddlewares(value string) []string {
+	value = strings.Trim(value, "[] ")
+	if value == "" {
+		return nil
+	}
+	var list []string
+	// 用正则提取所有被单引号或双引号包裹的内容
+	reMw := regexp.MustCompile(`['"][^'"]+['"]`)
+	for _, m := range reMw.FindAllString(value, -1) {
+		if mw := strings.Trim(m, "'\""); mw != "" {
+			list = append(list, mw)
+		}
+	}
+	return list
+}
+
 // 解析分组注解
 func parseGroupAnnotation(comment string) *GroupMeta {
 	groupMeta := &GroupMeta{}
-	comment = strings.TrimPrefix(comment, "// @Group(")
-	comment = strings.TrimSuffix(comment, ")")
-	pairs := strings.Split(comment, ",")
-	for _, pair := range pairs {
+	re := regexp.MustCompile(`@Group\((.*)\)`)
+	matches := re.FindStringSubmatch(comment)
+	if len(matches) < 2 {
+		return groupMeta
+	}
+	for _, pair := range splitParams(matches[1]) {
 		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
 		if len(kv) != 2 {
 			continue
@@ -278,8 +322,10 @@ func parseGroupAnnotation(comment string) *GroupMeta {
 			groupMeta.Name = value
 		case "path":
 			groupMeta.Path = value
-		case "description":
+		case "description", "desc":
 			groupMeta.Description = value
+		case "middlewares":
+			groupMeta.Middlewares = parseMiddlewares(kv[1])
 		}
 	}
 	return groupMeta
@@ -1,9 +1,12 @@
 package annotations
 
 import (
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
@@ -68,3 +71,45 @@ func parsePermissionMeta(comment string) PermissionMeta {
 	}
 	return perm
 }
+
+// ScanPermissionAnnotations 递归扫描目录下的 Go 文件，返回按权限码去重后的权限（重复时保留第一次出现的注解）
+// 以及重复的权限码及其所在文件
+func ScanPermissionAnnotations(dirs []string) ([]PermissionMeta, map[string][]string, error) {
+	var files []string
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var perms []PermissionMeta
+	codeFiles := make(map[string][]string)
+	for _, file := range files {
+		list, err := ParsePermissionAnnotations(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("解析 %s 失败: %w", file, err)
+		}
+		for _, p := range list {
+			if len(codeFiles[p.Code]) == 0 {
+				perms = append(perms, p)
+			}
+			codeFiles[p.Code] = append(codeFiles[p.Code], file)
+		}
+	}
+	duplicates := make(map[string][]string)
+	for code, list := range codeFiles {
+		if len(list) > 1 {
+			duplicates[code] = list
+		}
+	}
+	return perms, duplicates, nil
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"example.com/api/models"
+	"example.com/api/services"
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/pkg/response"
+)
+
+// @Group(path="/api/v1/", name="文章管理")
+type ArticleController struct {
+	service services.ArticleService
+}
+
+// GetArticle 获取文章
+// @Route(method=GET, path="/articles/:id", middlewares=["jwt"])
+// @Permission(code="sys:article:view", name="文章详情", modules="文章管理", desc="查看文章详情")
+func (c *ArticleController) GetArticle(ctx *gin.Context) {
+	article, err := c.service.GetArticle(ctx, 1)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, article)
+}
+
+// ListArticles 文章列表
+// @Route(method=GET, path="/articles", middlewares=["jwt"])
+// @Permission(code="sys:article:query", name="文章列表", modules="文章管理", desc="查看文章列表")
+func (c *ArticleController) ListArticles(ctx *gin.Context) {
+	var query models.ArticleQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	list, total, err := c.service.PageArticles(ctx, &query)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, gin.H{"list": list, "total": total})
+}
+
+// @Summary 创建文章
+// @Route(method=POST, path="/articles")
+func (c *ArticleController) CreateArticle(ctx *gin.Context) {
+	var req models.ArticleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, nil)
+}
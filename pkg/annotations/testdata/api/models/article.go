@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Article 文章
+type Article struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	Status    int       `json:"status" dict:"article_status"`
+	Author    *Author   `json:"author"`
+	Password  string    `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Author 作者
+type Author struct {
+	Name string `json:"name"`
+}
+
+// ArticleRequest 文章表单
+type ArticleRequest struct {
+	Title  string `json:"title" binding:"required"` // 标题
+	Status int    `json:"status"`
+}
+
+// ArticleQuery 文章查询条件
+type ArticleQuery struct {
+	PageNum  int    `form:"pageNum"`
+	Keywords string `form:"keywords"` // 关键字
+}
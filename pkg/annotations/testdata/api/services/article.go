@@ -0,0 +1,12 @@
+package services
+
+import (
+	"context"
+
+	"example.com/api/models"
+)
+
+type ArticleService interface {
+	GetArticle(ctx context.Context, id uint) (*models.Article, error)
+	PageArticles(ctx context.Context, query *models.ArticleQuery) ([]*models.Article, int64, error)
+}
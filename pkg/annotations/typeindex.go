@@ -0,0 +1,289 @@
+package annotations
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TypeIndex 源码中类型、函数和方法声明的索引，用于在不编译的情况下推断接口的请求和响应类型
+// 以包名（导入路径最后一段）区分包，要求被索引的包名不重复
+type TypeIndex struct {
+	fset  *token.FileSet
+	files map[string]*srcFile  // 文件路径 -> 文件
+	types map[string]*typeDecl // 包名.类型名 -> 类型声明
+	funcs map[string]*funcDecl // 包名.函数名、包名.类型名.方法名（含接口方法）-> 函数声明
+}
+
+// srcFile 已解析的源文件
+type srcFile struct {
+	path    string
+	pkg     string
+	ast     *ast.File
+	imports map[string]string // 导入名 -> 包名
+}
+
+// typeDecl 类型声明
+type typeDecl struct {
+	file *srcFile
+	spec *ast.TypeSpec
+}
+
+// funcDecl 函数或方法声明，接口方法没有 decl
+type funcDecl struct {
+	file  *srcFile
+	decl  *ast.FuncDecl
+	ftype *ast.FuncType
+}
+
+// typeRef 类型表达式及其所在的文件，未限定的标识符按所在文件的包解析
+type typeRef struct {
+	expr ast.Expr
+	file *srcFile
+}
+
+// NewTypeIndex 创建空索引
+func NewTypeIndex() *TypeIndex {
+	return &TypeIndex{
+		fset:  token.NewFileSet(),
+		files: make(map[string]*srcFile),
+		types: make(map[string]*typeDecl),
+		funcs: make(map[string]*funcDecl),
+	}
+}
+
+// AddDir 递归索引目录下的 Go 源文件，跳过测试文件、隐藏目录、vendor 和 testdata
+func (idx *TypeIndex) AddDir(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != dir && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor" || info.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		_, err = idx.AddFile(path)
+		return err
+	})
+}
+
+// AddFile 索引单个文件
+func (idx *TypeIndex) AddFile(path string) (*srcFile, error) {
+	if f, ok := idx.files[path]; ok {
+		return f, nil
+	}
+	node, err := parser.ParseFile(idx.fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	f := &srcFile{path: path, pkg: node.Name.Name, ast: node, imports: make(map[string]string)}
+	for _, imp := range node.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		name := packageName(p)
+		if imp.Name != nil {
+			f.imports[imp.Name.Name] = name
+		} else {
+			f.imports[name] = name
+		}
+	}
+	idx.files[path] = f
+	for _, decl := range node.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Doc == nil && len(d.Specs) == 1 {
+					ts.Doc = d.Doc
+				}
+				idx.types[f.pkg+"."+ts.Name.Name] = &typeDecl{file: f, spec: ts}
+				if iface, ok := ts.Type.(*ast.InterfaceType); ok {
+					for _, m := range iface.Methods.List {
+						if ft, ok := m.Type.(*ast.FuncType); ok && len(m.Names) > 0 {
+							idx.funcs[f.pkg+"."+ts.Name.Name+"."+m.Names[0].Name] = &funcDecl{file: f, ftype: ft}
+						}
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			key := f.pkg + "." + d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				key = f.pkg + "." + receiverType(d.Recv.List[0].Type) + "." + d.Name.Name
+			}
+			idx.funcs[key] = &funcDecl{file: f, decl: d, ftype: d.Type}
+		}
+	}
+	return f, nil
+}
+
+// packageName 导入路径对应的包名，跳过 /v2 这样的版本后缀
+func packageName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = parts[len(parts)-2]
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// receiverType 方法接收者的类型名
+func receiverType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverType(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		return receiverType(t.X)
+	}
+	return ""
+}
+
+// qualifiedName 命名类型的 包名.类型名，内置类型和非命名类型返回空串
+func (r typeRef) qualifiedName() string {
+	switch t := r.expr.(type) {
+	case *ast.Ident:
+		if isBuiltinType(t.Name) || r.file == nil {
+			return ""
+		}
+		return r.file.pkg + "." + t.Name
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok {
+			pkg := x.Name
+			if r.file != nil {
+				if name, ok := r.file.imports[x.Name]; ok {
+					pkg = name
+				}
+			}
+			return pkg + "." + t.Sel.Name
+		}
+	}
+	return ""
+}
+
+// deref 去掉指针
+func (r typeRef) deref() typeRef {
+	for {
+		star, ok := r.expr.(*ast.StarExpr)
+		if !ok {
+			return r
+		}
+		r.expr = star.X
+	}
+}
+
+// underlying 展开命名类型，返回底层类型表达式和最后一个命名类型的名称
+func (idx *TypeIndex) underlying(r typeRef) (typeRef, string) {
+	name := ""
+	for i := 0; i < 10; i++ {
+		r = r.deref()
+		qn := r.qualifiedName()
+		decl, ok := idx.types[qn]
+		if !ok {
+			return r, name
+		}
+		name = qn
+		r = typeRef{expr: decl.spec.Type, file: decl.file}
+	}
+	return r, name
+}
+
+// elem 切片、数组和 map 的元素类型
+func (idx *TypeIndex) elem(r typeRef) (typeRef, bool) {
+	u, _ := idx.underlying(r)
+	switch t := u.expr.(type) {
+	case *ast.ArrayType:
+		return typeRef{expr: t.Elt, file: u.file}, true
+	case *ast.MapType:
+		return typeRef{expr: t.Value, file: u.file}, true
+	}
+	return typeRef{}, false
+}
+
+// field 结构体字段（含嵌入结构体中的字段）的类型
+func (idx *TypeIndex) field(r typeRef, name string) (typeRef, bool) {
+	u, _ := idx.underlying(r)
+	st, ok := u.expr.(*ast.StructType)
+	if !ok {
+		return typeRef{}, false
+	}
+	for _, f := range st.Fields.List {
+		for _, n := range f.Names {
+			if n.Name == name {
+				return typeRef{expr: f.Type, file: u.file}, true
+			}
+		}
+	}
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			if t, ok := idx.field(typeRef{expr: f.Type, file: u.file}, name); ok {
+				return t, true
+			}
+		}
+	}
+	return typeRef{}, false
+}
+
+// method 命名类型（含接口）的方法
+func (idx *TypeIndex) method(r typeRef, name string) (*funcDecl, bool) {
+	r = r.deref()
+	qn := r.qualifiedName()
+	if qn == "" {
+		return nil, false
+	}
+	if fn, ok := idx.funcs[qn+"."+name]; ok {
+		return fn, true
+	}
+	// 嵌入类型的方法
+	u, _ := idx.underlying(r)
+	if st, ok := u.expr.(*ast.StructType); ok {
+		for _, f := range st.Fields.List {
+			if len(f.Names) == 0 {
+				if fn, ok := idx.method(typeRef{expr: f.Type, file: u.file}, name); ok {
+					return fn, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// results 函数的返回值类型，多个返回值按位置展开
+func (fn *funcDecl) results() []typeRef {
+	if fn.ftype.Results == nil {
+		return nil
+	}
+	var list []typeRef
+	for _, f := range fn.ftype.Results.List {
+		n := len(f.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			list = append(list, typeRef{expr: f.Type, file: fn.file})
+		}
+	}
+	return list
+}
+
+func isBuiltinType(name string) bool {
+	switch name {
+	case "bool", "string", "error", "any", "byte", "rune",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "complex64", "complex128":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package apperr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Error 应用错误：稳定的业务码、对应的 HTTP 状态和消息键
+// 消息键即消息目录（pkg/i18n）中的原文，可带 fmt 占位符，由 WithArgs 提供参数
+// 目录中定义的错误是模板，WithArgs/WithField/Wrap 均返回副本，errors.Is 按业务码比较
+type Error struct {
+	Code    int           // 业务码，发布后不再变更
+	Status  int           // HTTP 状态码
+	Key     string        // 消息键
+	Args    []interface{} // 消息参数
+	Details []FieldError  // 字段级错误
+	cause   error         // 原始错误，只记录日志，不返回给客户端
+}
+
+// FieldError 字段级错误，Message 同样是消息键
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+var (
+	mu      sync.RWMutex
+	catalog = make(map[int]*Error)
+)
+
+// New 定义错误并登记到目录，业务码重复时 panic
+func New(code, status int, key string) *Error {
+	mu.Lock()
+	defer mu.Unlock()
+	if existing, ok := catalog[code]; ok {
+		panic(fmt.Sprintf("apperr: 业务码 %d 重复定义（%s / %s）", code, existing.Key, key))
+	}
+	e := &Error{Code: code, Status: status, Key: key}
+	catalog[code] = e
+	return e
+}
+
+// Catalog 已登记的全部错误，按业务码排序
+func Catalog() []*Error {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]*Error, 0, len(catalog))
+	for _, e := range catalog {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
+
+// Message 未翻译的消息
+func (e *Error) Message() string {
+	if len(e.Args) == 0 {
+		return e.Key
+	}
+	return fmt.Sprintf(e.Key, e.Args...)
+}
+
+// Error 用于日志，包含原始错误
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message() + ": " + e.cause.Error()
+	}
+	return e.Message()
+}
+
+// Unwrap 返回原始错误
+func (e *Error) Unwrap() error { return e.cause }
+
+// Is 业务码相同即视为同一错误
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithArgs 填充消息参数
+func (e *Error) WithArgs(args ...interface{}) *Error {
+	c := e.clone()
+	c.Args = args
+	return c
+}
+
+// WithField 追加字段级错误
+func (e *Error) WithField(field, message string) *Error {
+	c := e.clone()
+	c.Details = append(c.Details, FieldError{Field: field, Message: message})
+	return c
+}
+
+// Wrap 附加原始错误（如数据库错误），原始错误只记录日志
+func (e *Error) Wrap(err error) *Error {
+	c := e.clone()
+	c.cause = err
+	return c
+}
+
+func (e *Error) clone() *Error {
+	c := *e
+	c.Args = append([]interface{}(nil), e.Args...)
+	c.Details = append([]FieldError(nil), e.Details...)
+	return &c
+}
+
+// As 从错误链中取出应用错误
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// FromBinding 将请求绑定（ShouldBind*）的错误转为带字段详情的参数错误
+func FromBinding(err error) *Error {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		e := ErrValidation.Wrap(err)
+		for _, fe := range verrs {
+			e.Details = append(e.Details, FieldError{Field: lowerFirst(fe.Field()), Message: validationMessage(fe.Tag())})
+		}
+		return e
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return ErrValidation.Wrap(err).WithField(typeErr.Field, "类型不正确")
+	}
+	return ErrBadRequest.Wrap(err)
+}
+
+func validationMessage(tag string) string {
+	switch tag {
+	case "required", "required_if", "required_with", "required_without":
+		return "不能为空"
+	case "email", "url", "uri", "ip", "numeric", "number", "alphanum", "datetime":
+		return "格式不正确"
+	case "min", "max", "len", "gt", "gte", "lt", "lte":
+		return "长度或大小超出范围"
+	case "oneof":
+		return "取值不在可选范围内"
+	}
+	return "校验未通过"
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,80 @@
+package apperr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestCatalogCodes(t *testing.T) {
+	list := Catalog()
+	if len(list) == 0 {
+		t.Fatal("目录不应为空")
+	}
+	for i, e := range list {
+		if i > 0 && list[i-1].Code >= e.Code {
+			t.Fatalf("目录未按业务码排序: %d, %d", list[i-1].Code, e.Code)
+		}
+		if e.Status < 400 || e.Status > 599 || e.Key == "" {
+			t.Errorf("错误 %d 的状态码或消息键无效: %d %q", e.Code, e.Status, e.Key)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("重复的业务码应 panic")
+		}
+	}()
+	New(ErrInternal.Code, 500, "重复")
+}
+
+func TestCloneAndIs(t *testing.T) {
+	cause := errors.New("db down")
+	e := ErrRoleExists.WithArgs("管理员", "admin").Wrap(cause)
+	if ErrRoleExists.Args != nil || ErrRoleExists.cause != nil {
+		t.Fatal("WithArgs/Wrap 不应修改目录中的错误")
+	}
+	if e.Message() != "角色名称 '管理员' 或者角色编码 'admin' 已存在" {
+		t.Fatalf("消息不正确: %s", e.Message())
+	}
+	wrapped := fmt.Errorf("create role: %w", e)
+	if !errors.Is(wrapped, ErrRoleExists) || errors.Is(wrapped, ErrRoleInvalidID) {
+		t.Fatal("errors.Is 应按业务码比较")
+	}
+	if !errors.Is(wrapped, cause) {
+		t.Fatal("应能通过 Unwrap 取到原始错误")
+	}
+	if got, ok := As(wrapped); !ok || got.Code != ErrRoleExists.Code {
+		t.Fatal("As 应取出应用错误")
+	}
+}
+
+func TestFromBinding(t *testing.T) {
+	var form struct {
+		UserName string `validate:"required"`
+		Email    string `validate:"email"`
+	}
+	form.Email = "x"
+	err := validator.New().Struct(form)
+	e := FromBinding(err)
+	if e.Code != ErrValidation.Code || len(e.Details) != 2 {
+		t.Fatalf("校验错误应转为参数错误并带字段详情: %+v", e)
+	}
+	if e.Details[0] != (FieldError{Field: "userName", Message: "不能为空"}) || e.Details[1].Message != "格式不正确" {
+		t.Fatalf("字段详情不正确: %+v", e.Details)
+	}
+
+	var v struct {
+		Age int `json:"age"`
+	}
+	e = FromBinding(json.Unmarshal([]byte(`{"age":"x"}`), &v))
+	if e.Code != ErrValidation.Code || len(e.Details) != 1 || e.Details[0].Field != "age" {
+		t.Fatalf("类型错误应带字段详情: %+v", e)
+	}
+
+	if FromBinding(errors.New("EOF")).Code != ErrBadRequest.Code {
+		t.Fatal("其他绑定错误应转为无效请求")
+	}
+}
@@ -0,0 +1,120 @@
+package apperr
+
+import "net/http"
+
+// 业务码分段：10xxx 通用，11xxx 用户与认证，12xxx 部门/角色/菜单，13xxx 字典与配置，14xxx 通知
+// 已发布的业务码不得修改含义，新增错误在对应分段内顺延
+
+// 通用
+var (
+	ErrBadRequest        = New(10000, http.StatusBadRequest, "无效的请求参数")
+	ErrValidation        = New(10001, http.StatusBadRequest, "参数错误")
+	ErrInvalidID         = New(10002, http.StatusBadRequest, "无效的ID")
+	ErrFileRequired      = New(10003, http.StatusBadRequest, "请选择要上传的文件")
+	ErrUnsupportedLocale = New(10004, http.StatusBadRequest, "不支持的语言: %s")
+	ErrDefaultLocaleText = New(10005, http.StatusBadRequest, "默认语言 %s 的文本请直接修改原数据")
+	ErrInvalidParent     = New(10006, http.StatusBadRequest, "上级不能是自身或自身的下级")
+	ErrIdempotencyKey    = New(10007, http.StatusBadRequest, "缺少请求头 Idempotency-Key")
+	ErrUnauthorized      = New(10100, http.StatusUnauthorized, "请先登录或登录信息无效")
+	ErrForbidden         = New(10300, http.StatusForbidden, "无权访问")
+	ErrDemoMode          = New(10301, http.StatusForbidden, "演示模式下禁止此操作")
+	ErrNotFound          = New(10400, http.StatusNotFound, "资源不存在")
+	ErrHasChildren       = New(10409, http.StatusConflict, "存在下级数据，无法删除")
+	ErrDuplicateRequest  = New(10410, http.StatusConflict, "请求已提交，请勿重复操作")
+	ErrTooManyRequests   = New(10429, http.StatusTooManyRequests, "操作过于频繁，请稍后再试")
+	ErrInternal          = New(10500, http.StatusInternalServerError, "内部服务错误")
+)
+
+// 用户与认证
+var (
+	ErrUserNotFound         = New(11001, http.StatusNotFound, "用户不存在")
+	ErrUserDisabled         = New(11002, http.StatusForbidden, "用户已被禁用")
+	ErrPasswordIncorrect    = New(11003, http.StatusBadRequest, "原密码错误")
+	ErrPasswordUnchanged    = New(11004, http.StatusBadRequest, "新密码不能与原密码相同")
+	ErrPasswordTooShort     = New(11005, http.StatusBadRequest, "密码长度不能少于 %d 位")
+	ErrPasswordWeak         = New(11006, http.StatusBadRequest, "密码必须包含%s")
+	ErrCaptchaInvalid       = New(11007, http.StatusBadRequest, "验证码错误")
+	ErrInitPasswordWeak     = New(11008, http.StatusBadRequest, "初始密码不符合密码策略，请填写密码或修改 sys.user.init_password")
+	ErrImportFileEmpty      = New(11101, http.StatusBadRequest, "导入文件为空")
+	ErrImportMissingColumn  = New(11102, http.StatusBadRequest, "缺少必填列: %s")
+	ErrImportNoRows         = New(11103, http.StatusBadRequest, "导入文件中没有数据行")
+	ErrImportTooManyRows    = New(11104, http.StatusBadRequest, "单次最多导入 %d 行")
+	ErrImportInvalidMode    = New(11105, http.StatusBadRequest, "无效的导入模式: %s")
+	ErrImportReportNotFound = New(11106, http.StatusNotFound, "导入报告不存在或已过期")
+	ErrImportFileInvalid    = New(11107, http.StatusBadRequest, "导入文件解析失败: %s")
+	ErrImportRowWriteFailed = New(11108, http.StatusInternalServerError, "写入失败，请联系管理员（请求ID: %s）")
+)
+
+// 部门、角色、菜单
+var (
+	ErrDeptNotFound       = New(12001, http.StatusNotFound, "部门不存在")
+	ErrDeptParentSelf     = New(12002, http.StatusBadRequest, "上级部门不能是本部门")
+	ErrDeptParentNotFound = New(12003, http.StatusBadRequest, "指定的上级部门不存在")
+	ErrDeptCycle          = New(12004, http.StatusBadRequest, "修改会导致循环引用：指定的上级部门已经是本部门的子部门")
+	ErrDeptHasChildren    = New(12005, http.StatusConflict, "该部门下有子部门，请先删除或转移子部门")
+	ErrDeptTooDeep        = New(12006, http.StatusBadRequest, "部门层级过深，可能存在循环引用")
+	ErrRoleInvalidID      = New(12101, http.StatusBadRequest, "无效的角色ID")
+	ErrRoleExists         = New(12102, http.StatusConflict, "角色名称 '%s' 或者角色编码 '%s' 已存在")
+	ErrMenuNotFound       = New(12201, http.StatusNotFound, "菜单不存在或已被删除")
+	ErrMenuHasChildren    = New(12202, http.StatusConflict, "无法删除菜单，仍有 %d 个子菜单存在")
+	ErrMenuInUse          = New(12203, http.StatusConflict, "无法删除菜单，仍有 %d 个角色关联此菜单")
+)
+
+// 字典与系统配置
+var (
+	ErrDictNotFound             = New(13001, http.StatusNotFound, "字典不存在")
+	ErrDictItemNotFound         = New(13002, http.StatusNotFound, "字典项不存在")
+	ErrDictNothingToExport      = New(13003, http.StatusBadRequest, "没有可导出的字典")
+	ErrDictItemValueNotFound    = New(13004, http.StatusBadRequest, "字典 %s 中不存在值为 %s 的字典项")
+	ErrDictTranslationDuplicate = New(13005, http.StatusBadRequest, "字典项 %s 的 %s 翻译重复")
+	ErrDictBundleInvalid        = New(13006, http.StatusBadRequest, "字典包格式错误: %s")
+	ErrDictCodeNotFound         = New(13007, http.StatusNotFound, "字典 %s 不存在")
+	ErrDictBatchTooMany         = New(13008, http.StatusBadRequest, "一次最多查询 %d 个字典")
+	ErrConfigNotFound           = New(13101, http.StatusNotFound, "配置不存在")
+	ErrConfigVersionNotFound    = New(13102, http.StatusNotFound, "版本 %d 不存在")
+	ErrConfigInvalidType        = New(13103, http.StatusBadRequest, "不支持的配置类型: %s")
+	ErrConfigEnumDictRequired   = New(13104, http.StatusBadRequest, "enum 类型必须指定字典编码")
+	ErrConfigEnumDictEmpty      = New(13105, http.StatusBadRequest, "字典 %s 没有可用的字典项")
+	ErrConfigInvalidInt         = New(13106, http.StatusBadRequest, "配置值必须是整数: %s")
+	ErrConfigInvalidBool        = New(13107, http.StatusBadRequest, "配置值必须是布尔值（true/false）: %s")
+	ErrConfigInvalidJSON        = New(13108, http.StatusBadRequest, "配置值不是有效的 JSON")
+	ErrConfigValueNotInEnum     = New(13109, http.StatusBadRequest, "配置值 '%s' 不在可选范围内（%s）")
+)
+
+// 通知、通知模板、附件
+var (
+	ErrNoticeNotFound              = New(14001, http.StatusNotFound, "通知不存在")
+	ErrNoticeActionNotAllowed      = New(14002, http.StatusConflict, "%s状态的通知不允许执行该操作")
+	ErrNoticeNotEditable           = New(14003, http.StatusConflict, "%s状态的通知不允许编辑")
+	ErrNoticeStatusChanged         = New(14004, http.StatusConflict, "通知状态已变更，请刷新后重试")
+	ErrNoticeNeedsApproval         = New(14005, http.StatusBadRequest, "草稿需提交审核，审核通过后才能发布")
+	ErrNoticeAlreadyPublished      = New(14006, http.StatusConflict, "通知已发布，无需重复操作")
+	ErrNoticeUnavailable           = New(14007, http.StatusNotFound, "通知已撤回或删除")
+	ErrNoticeTitleRequired         = New(14008, http.StatusBadRequest, "标题不能为空")
+	ErrNoticeInvalidTarget         = New(14009, http.StatusBadRequest, "目标类型无效")
+	ErrNoticeTargetUsersRequired   = New(14010, http.StatusBadRequest, "指定用户发布时，目标用户ID不能为空")
+	ErrNoticeExpireBeforePublish   = New(14011, http.StatusBadRequest, "过期时间必须晚于发布时间")
+	ErrNoticeRejectCommentRequired = New(14012, http.StatusBadRequest, "请填写驳回意见")
+	ErrNoticeRemindNotAllowed      = New(14013, http.StatusConflict, "%s状态的通知不能提醒")
+	ErrNoticeRemindTooFrequent     = New(14014, http.StatusTooManyRequests, "提醒过于频繁，请 %d 分钟后再试")
+	ErrNoticeInvalidGranularity    = New(14015, http.StatusBadRequest, "无效的统计粒度: %s")
+	ErrNoticeUnsupportedFormat     = New(14016, http.StatusBadRequest, "不支持的内容格式: %s")
+	ErrNoticeDeptRequired          = New(14017, http.StatusBadRequest, "部门ID不能为空")
+	ErrNoticeRoleRequired          = New(14018, http.StatusBadRequest, "角色ID不能为空")
+	ErrNoticeReceiverNotFound      = New(14019, http.StatusNotFound, "未找到对应的通知接收记录")
+	ErrTemplateNotFound            = New(14101, http.StatusNotFound, "通知模板不存在")
+	ErrTemplateNameRequired        = New(14102, http.StatusBadRequest, "模板名称不能为空")
+	ErrTemplateTitleRequired       = New(14103, http.StatusBadRequest, "通知标题不能为空")
+	ErrTemplateVarDuplicate        = New(14104, http.StatusBadRequest, "变量 '%s' 重复定义")
+	ErrTemplateTitleVarsOnly       = New(14105, http.StatusBadRequest, "通知标题只能使用自定义变量（{{.Vars.xxx}}）")
+	ErrTemplateTitleSyntax         = New(14106, http.StatusBadRequest, "标题模板有误: %s")
+	ErrTemplateContentSyntax       = New(14107, http.StatusBadRequest, "内容模板有误: %s")
+	ErrTemplateVarRequired         = New(14108, http.StatusBadRequest, "变量 '%s' 不能为空")
+	ErrAttachmentNotFound          = New(14201, http.StatusNotFound, "附件不存在")
+	ErrAttachmentFileMissing       = New(14202, http.StatusNotFound, "附件文件不存在")
+	ErrAttachmentTypeNotAllowed    = New(14203, http.StatusBadRequest, "不支持的文件类型: %s")
+	ErrAttachmentImageOnly         = New(14204, http.StatusBadRequest, "正文中只能插入图片")
+	ErrAttachmentLinkInvalid       = New(14205, http.StatusForbidden, "下载链接无效或已过期")
+	ErrAttachmentTooLarge          = New(14206, http.StatusBadRequest, "文件大小不能超过 %d MB")
+	ErrWebhookURLInvalid           = New(14301, http.StatusBadRequest, "Webhook 地址无效: %s")
+)
@@ -0,0 +1,149 @@
+package dictbundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 文件格式
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// BundleVersion 当前的字典包格式版本
+const BundleVersion = 1
+
+// ErrUnsupportedFormat 不支持的格式
+var ErrUnsupportedFormat = errors.New("仅支持 json、yaml 格式")
+
+// Bundle 字典包：若干字典及其字典项，用于在不同环境间同步
+type Bundle struct {
+	Version int    `json:"version" yaml:"version"`
+	Dicts   []Dict `json:"dicts" yaml:"dicts"`
+}
+
+// Dict 字典
+type Dict struct {
+	DictCode string `json:"dictCode" yaml:"dictCode"`
+	Name     string `json:"name" yaml:"name"`
+	Status   int    `json:"status" yaml:"status"`
+	Remark   string `json:"remark,omitempty" yaml:"remark,omitempty"`
+	Sort     int    `json:"sort" yaml:"sort"`
+	Items    []Item `json:"items" yaml:"items"`
+}
+
+// Item 字典项
+type Item struct {
+	Value   string `json:"value" yaml:"value"`
+	Label   string `json:"label" yaml:"label"`
+	TagType string `json:"tagType,omitempty" yaml:"tagType,omitempty"`
+	Status  int    `json:"status" yaml:"status"`
+	Sort    int    `json:"sort" yaml:"sort"`
+}
+
+// FormatOf 根据文件名判断格式
+func FormatOf(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// Encode 按格式编码字典包
+func Encode(bundle *Bundle, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(bundle, "", "  ")
+	case FormatYAML:
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(bundle); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), enc.Close()
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// Decode 按格式解码字典包并校验，format 为空时根据内容判断
+func Decode(data []byte, format string) (*Bundle, error) {
+	if format == "" {
+		format = FormatYAML
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			format = FormatJSON
+		}
+	}
+	var bundle Bundle
+	switch format {
+	case FormatJSON:
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			return nil, fmt.Errorf("解析 JSON 失败: %w", err)
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(data, &bundle); err != nil {
+			return nil, fmt.Errorf("解析 YAML 失败: %w", err)
+		}
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err := bundle.Validate(); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// Validate 校验字典包：编码、值、标签必填且不重复
+func (b *Bundle) Validate() error {
+	if b.Version > BundleVersion {
+		return fmt.Errorf("不支持的字典包版本: %d", b.Version)
+	}
+	if len(b.Dicts) == 0 {
+		return errors.New("字典包中没有字典")
+	}
+	codes := make(map[string]bool, len(b.Dicts))
+	for _, d := range b.Dicts {
+		if d.DictCode == "" {
+			return errors.New("字典编码不能为空")
+		}
+		if codes[d.DictCode] {
+			return fmt.Errorf("字典编码 %s 重复", d.DictCode)
+		}
+		codes[d.DictCode] = true
+		if d.Name == "" {
+			return fmt.Errorf("字典 %s 的名称不能为空", d.DictCode)
+		}
+		values := make(map[string]bool, len(d.Items))
+		for _, item := range d.Items {
+			if item.Value == "" || item.Label == "" {
+				return fmt.Errorf("字典 %s 的字典项值和标签不能为空", d.DictCode)
+			}
+			if values[item.Value] {
+				return fmt.Errorf("字典 %s 的字典项值 %s 重复", d.DictCode, item.Value)
+			}
+			values[item.Value] = true
+		}
+	}
+	return nil
+}
+
+// Codes 字典包中的字典编码
+func (b *Bundle) Codes() []string {
+	codes := make([]string, 0, len(b.Dicts))
+	for _, d := range b.Dicts {
+		codes = append(codes, d.DictCode)
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -0,0 +1,72 @@
+package dictbundle
+
+import "testing"
+
+func sampleBundle() *Bundle {
+	return &Bundle{Version: BundleVersion, Dicts: []Dict{{
+		DictCode: "gender", Name: "性别", Status: 1,
+		Items: []Item{
+			{Value: "1", Label: "男", TagType: "primary", Status: 1, Sort: 1},
+			{Value: "2", Label: "女", TagType: "primary", Status: 1, Sort: 2},
+		},
+	}}}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		data, err := Encode(sampleBundle(), format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		// 不指定格式时根据内容识别
+		got, err := Decode(data, "")
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if len(got.Dicts) != 1 || len(got.Dicts[0].Items) != 2 || got.Dicts[0].Items[1].Label != "女" {
+			t.Fatalf("%s 往返结果不正确: %+v", format, got)
+		}
+	}
+}
+
+func TestDecodeRejectsInvalidBundle(t *testing.T) {
+	cases := []string{
+		`{"version":1,"dicts":[]}`,
+		`{"version":1,"dicts":[{"dictCode":"a","name":"A"},{"dictCode":"a","name":"A"}]}`,
+		`{"version":1,"dicts":[{"dictCode":"a","name":"A","items":[{"value":"1","label":"x"},{"value":"1","label":"y"}]}]}`,
+		`{"version":9,"dicts":[{"dictCode":"a","name":"A"}]}`,
+	}
+	for _, c := range cases {
+		if _, err := Decode([]byte(c), FormatJSON); err == nil {
+			t.Errorf("应拒绝: %s", c)
+		}
+	}
+	if _, err := FormatOf("dicts.txt"); err != ErrUnsupportedFormat {
+		t.Fatal("应拒绝不支持的扩展名")
+	}
+}
+
+func TestBuildPlan(t *testing.T) {
+	bundle := sampleBundle()
+	bundle.Dicts = append(bundle.Dicts, Dict{DictCode: "level", Name: "级别", Items: []Item{{Value: "H", Label: "高"}}})
+
+	existing := map[string]Dict{"gender": {
+		DictCode: "gender", Name: "性别", Status: 1,
+		Items: []Item{{Value: "1", Label: "男性", TagType: "primary", Status: 1, Sort: 1}},
+	}}
+	plan := BuildPlan(bundle, existing)
+	// gender 未变化；1 更新 label；2 新增；level 与其字典项新增
+	if plan.Created != 3 || plan.Updated != 1 || plan.Unchanged != 1 {
+		t.Fatalf("计划统计不正确: %s", plan)
+	}
+	for _, c := range plan.Changes {
+		if c.DictCode == "gender" && c.Value == "1" {
+			if c.Action != ActionUpdate || len(c.Fields) != 1 || c.Fields[0].Field != "label" || c.Fields[0].To != "男" {
+				t.Fatalf("字典项变更不正确: %+v", c)
+			}
+		}
+	}
+	if !plan.HasChanges() {
+		t.Fatal("应存在变更")
+	}
+}
@@ -0,0 +1,119 @@
+package dictbundle
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// 变更动作
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionUnchanged = "unchanged"
+)
+
+// FieldChange 字段变化
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Change 一条字典或字典项的变更
+type Change struct {
+	DictCode string        `json:"dictCode"`
+	Value    string        `json:"value,omitempty"` // 为空表示字典本身
+	Action   string        `json:"action"`
+	Fields   []FieldChange `json:"fields,omitempty"`
+}
+
+// Plan 导入计划：按 DictCode / Value 匹配现有数据，只新增或更新，不删除
+type Plan struct {
+	Created   int      `json:"created"`
+	Updated   int      `json:"updated"`
+	Unchanged int      `json:"unchanged"`
+	Changes   []Change `json:"changes"`
+}
+
+// BuildPlan 比较字典包与现有数据（existing 为按编码索引的现有字典），生成导入计划
+func BuildPlan(bundle *Bundle, existing map[string]Dict) *Plan {
+	plan := &Plan{Changes: []Change{}}
+	for _, d := range bundle.Dicts {
+		old, ok := existing[d.DictCode]
+		if !ok {
+			plan.add(Change{DictCode: d.DictCode, Action: ActionCreate})
+			for _, item := range d.Items {
+				plan.add(Change{DictCode: d.DictCode, Value: item.Value, Action: ActionCreate})
+			}
+			continue
+		}
+		plan.add(diffChange(Change{DictCode: d.DictCode}, []FieldChange{
+			field("name", old.Name, d.Name),
+			field("status", strconv.Itoa(old.Status), strconv.Itoa(d.Status)),
+			field("remark", old.Remark, d.Remark),
+			field("sort", strconv.Itoa(old.Sort), strconv.Itoa(d.Sort)),
+		}))
+
+		oldItems := make(map[string]Item, len(old.Items))
+		for _, item := range old.Items {
+			oldItems[item.Value] = item
+		}
+		for _, item := range d.Items {
+			oldItem, ok := oldItems[item.Value]
+			if !ok {
+				plan.add(Change{DictCode: d.DictCode, Value: item.Value, Action: ActionCreate})
+				continue
+			}
+			plan.add(diffChange(Change{DictCode: d.DictCode, Value: item.Value}, []FieldChange{
+				field("label", oldItem.Label, item.Label),
+				field("tagType", oldItem.TagType, item.TagType),
+				field("status", strconv.Itoa(oldItem.Status), strconv.Itoa(item.Status)),
+				field("sort", strconv.Itoa(oldItem.Sort), strconv.Itoa(item.Sort)),
+			}))
+		}
+	}
+	return plan
+}
+
+// HasChanges 计划中是否有需要写入的变更
+func (p *Plan) HasChanges() bool {
+	return p.Created+p.Updated > 0
+}
+
+// String 计划摘要
+func (p *Plan) String() string {
+	return fmt.Sprintf("新增 %d，更新 %d，未变化 %d", p.Created, p.Updated, p.Unchanged)
+}
+
+func (p *Plan) add(c Change) {
+	switch c.Action {
+	case ActionCreate:
+		p.Created++
+	case ActionUpdate:
+		p.Updated++
+	default:
+		p.Unchanged++
+	}
+	p.Changes = append(p.Changes, c)
+}
+
+// field 值不同时返回字段变化，否则返回空的 FieldChange
+func field(name, from, to string) FieldChange {
+	if from == to {
+		return FieldChange{}
+	}
+	return FieldChange{Field: name, From: from, To: to}
+}
+
+func diffChange(c Change, fields []FieldChange) Change {
+	for _, f := range fields {
+		if f.Field != "" {
+			c.Fields = append(c.Fields, f)
+		}
+	}
+	c.Action = ActionUnchanged
+	if len(c.Fields) > 0 {
+		c.Action = ActionUpdate
+	}
+	return c
+}
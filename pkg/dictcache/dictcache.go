@@ -0,0 +1,294 @@
+package dictcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/zmqge/vireo-gin-admin/pkg/redis"
+)
+
+const (
+	keyPrefix     = "dict:items:"     // Redis 缓存键前缀，键为 前缀+编码:版本
+	versionPrefix = "dict:version:"   // 字典编码的缓存版本，失效时递增，旧版本的缓存不再被读取
+	channel       = "dict:invalidate" // 失效通知频道，各副本收到后清除本地缓存
+	redisTTL      = 24 * time.Hour
+	memoryTTL     = 5 * time.Minute // 本地缓存兜底过期时间，防止错过失效通知
+)
+
+// Option 前端下拉使用的字典项
+type Option struct {
+	Value   string            `json:"value"`
+	Label   string            `json:"label"`
+	TagType string            `json:"tagType"`
+	Labels  map[string]string `json:"labels,omitempty"` // 其他语言的标签（语言 -> 标签），返回前端前由 Localize 去除
+}
+
+// LabelOf 指定语言的标签，没有翻译时回退到默认语言的标签
+func (o Option) LabelOf(locale string) string {
+	if label := o.Labels[locale]; label != "" {
+		return label
+	}
+	return o.Label
+}
+
+// Localize 返回指定语言的字典项，Label 替换为对应语言的标签
+func Localize(options []Option, locale string) []Option {
+	list := make([]Option, len(options))
+	for i, o := range options {
+		list[i] = Option{Value: o.Value, Label: o.LabelOf(locale), TagType: o.TagType}
+	}
+	return list
+}
+
+// LocalizeETag 不同语言返回的内容不同，ETag 需区分语言
+func LocalizeETag(etag, locale string) string {
+	if locale == "" {
+		return etag
+	}
+	return hashETag([]byte(etag + "|" + locale))
+}
+
+// Entry 一个字典编码的缓存内容
+type Entry struct {
+	Options []Option `json:"options"`
+	ETag    string   `json:"etag"`
+}
+
+// Loader 从数据库加载字典项
+type Loader func(ctx context.Context, dictCode string) ([]Option, error)
+
+type memoryEntry struct {
+	entry    *Entry
+	expireAt time.Time
+}
+
+// Cache 字典项两级缓存：本地内存 + Redis，写操作后按字典编码失效
+// 加载与失效并发时，加载结果按加载开始时的版本写入：Redis 写到已废弃的版本键，本地缓存不写入，
+// 避免失效后又被旧数据覆盖
+type Cache struct {
+	mu     sync.RWMutex
+	local  map[string]memoryEntry
+	gens   map[string]uint64 // 本地失效次数，加载期间发生失效时不写入本地缓存
+	loader Loader
+}
+
+// Default 全局字典缓存
+var Default = New(nil)
+
+// New 创建字典缓存
+func New(loader Loader) *Cache {
+	return &Cache{local: make(map[string]memoryEntry), gens: make(map[string]uint64), loader: loader}
+}
+
+// SetLoader 设置字典项加载函数
+func (c *Cache) SetLoader(loader Loader) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loader = loader
+}
+
+// Get 获取字典项，依次读取本地缓存、Redis 和数据库
+func (c *Cache) Get(ctx context.Context, dictCode string) (*Entry, error) {
+	c.mu.RLock()
+	m, ok := c.local[dictCode]
+	gen := c.gens[dictCode]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(m.expireAt) {
+		return m.entry, nil
+	}
+
+	version, redisOK := c.redisVersion(ctx, dictCode)
+	if redisOK {
+		if entry := c.getRedis(ctx, dictCode, version); entry != nil {
+			c.setLocal(dictCode, gen, entry)
+			return entry, nil
+		}
+	}
+
+	c.mu.RLock()
+	loader := c.loader
+	c.mu.RUnlock()
+	if loader == nil {
+		return nil, fmt.Errorf("字典缓存未设置加载函数")
+	}
+	options, err := loader(ctx, dictCode)
+	if err != nil {
+		return nil, err
+	}
+	entry := NewEntry(options)
+	c.setLocal(dictCode, gen, entry)
+	if redisOK {
+		c.setRedis(ctx, dictCode, version, entry)
+	}
+	return entry, nil
+}
+
+// GetMany 批量获取字典项，返回各编码的结果和整体 ETag
+func (c *Cache) GetMany(ctx context.Context, dictCodes []string) (map[string][]Option, string, error) {
+	codes := UniqueCodes(dictCodes)
+	result := make(map[string][]Option, len(codes))
+	tags := make([]string, 0, len(codes))
+	for _, code := range codes {
+		entry, err := c.Get(ctx, code)
+		if err != nil {
+			return nil, "", err
+		}
+		result[code] = entry.Options
+		tags = append(tags, code+"="+entry.ETag)
+	}
+	return result, hashETag([]byte(strings.Join(tags, ","))), nil
+}
+
+// Invalidate 失效指定字典编码：递增 Redis 中的缓存版本并删除旧版本缓存，通知所有副本清除本地缓存
+func (c *Cache) Invalidate(ctx context.Context, dictCodes ...string) {
+	for _, code := range UniqueCodes(dictCodes) {
+		c.evictLocal(code)
+		if redis.Client == nil {
+			continue
+		}
+		version, err := redis.Client.Incr(ctx, versionPrefix+code).Result()
+		if err != nil {
+			log.Printf("[DictCache] 更新缓存版本 %s 失败: %v", code, err)
+		} else if err := redis.Client.Del(ctx, redisKey(code, version-1)).Err(); err != nil {
+			log.Printf("[DictCache] 删除缓存 %s 失败: %v", code, err)
+		}
+		if err := redis.Client.Publish(ctx, channel, code).Err(); err != nil {
+			log.Printf("[DictCache] 发布失效通知 %s 失败: %v", code, err)
+		}
+	}
+}
+
+// Run 订阅失效通知，清除本实例的本地缓存，ctx 取消时退出
+func (c *Cache) Run(ctx context.Context) {
+	if redis.Client == nil {
+		return
+	}
+	sub := redis.Client.Subscribe(ctx, channel)
+	defer sub.Close()
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			c.evictLocal(msg.Payload)
+		}
+	}
+}
+
+// NewEntry 由字典项生成缓存内容，ETag 为内容摘要
+func NewEntry(options []Option) *Entry {
+	if options == nil {
+		options = []Option{}
+	}
+	data, _ := json.Marshal(options)
+	return &Entry{Options: options, ETag: hashETag(data)}
+}
+
+// MatchETag 判断 If-None-Match 请求头是否命中 ETag
+func MatchETag(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// redisKey 指定版本的字典缓存键
+func redisKey(dictCode string, version int64) string {
+	return fmt.Sprintf("%s%s:%d", keyPrefix, dictCode, version)
+}
+
+// redisVersion 字典编码当前的缓存版本，未失效过为 0；Redis 不可用时第二个返回值为 false，不读写 Redis 缓存
+func (c *Cache) redisVersion(ctx context.Context, dictCode string) (int64, bool) {
+	if redis.Client == nil {
+		return 0, false
+	}
+	version, err := redis.Client.Get(ctx, versionPrefix+dictCode).Int64()
+	if errors.Is(err, goredis.Nil) {
+		return 0, true
+	}
+	if err != nil {
+		log.Printf("[DictCache] 读取缓存版本 %s 失败: %v", dictCode, err)
+		return 0, false
+	}
+	return version, true
+}
+
+func (c *Cache) getRedis(ctx context.Context, dictCode string, version int64) *Entry {
+	data, err := redis.Client.Get(ctx, redisKey(dictCode, version)).Bytes()
+	if err != nil {
+		return nil
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+func (c *Cache) setRedis(ctx context.Context, dictCode string, version int64, entry *Entry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := redis.Client.Set(ctx, redisKey(dictCode, version), data, redisTTL).Err(); err != nil {
+		log.Printf("[DictCache] 写入缓存 %s 失败: %v", dictCode, err)
+	}
+}
+
+// setLocal 写入本地缓存，gen 为加载开始时的失效次数，期间发生过失效时不写入
+func (c *Cache) setLocal(dictCode string, gen uint64, entry *Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gens[dictCode] != gen {
+		return
+	}
+	c.local[dictCode] = memoryEntry{entry: entry, expireAt: time.Now().Add(memoryTTL)}
+}
+
+func (c *Cache) evictLocal(dictCode string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.local, dictCode)
+	c.gens[dictCode]++
+}
+
+func hashETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%q", hex.EncodeToString(sum[:8]))
+}
+
+// UniqueCodes 去掉空白和重复的字典编码并排序
+func UniqueCodes(codes []string) []string {
+	seen := make(map[string]bool, len(codes))
+	list := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = strings.TrimSpace(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		list = append(list, code)
+	}
+	sort.Strings(list)
+	return list
+}
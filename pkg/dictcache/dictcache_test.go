@@ -0,0 +1,124 @@
+package dictcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCacheLoadsOnceAndInvalidates(t *testing.T) {
+	calls := map[string]int{}
+	data := map[string][]Option{
+		"gender": {{Value: "1", Label: "男"}, {Value: "2", Label: "女"}},
+		"status": {{Value: "0", Label: "禁用"}},
+	}
+	c := New(func(ctx context.Context, code string) ([]Option, error) {
+		calls[code]++
+		return data[code], nil
+	})
+	ctx := context.Background()
+
+	first, err := c.Get(ctx, "gender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := c.Get(ctx, "gender")
+	if calls["gender"] != 1 || first.ETag != second.ETag {
+		t.Fatalf("应命中本地缓存，加载次数 %d", calls["gender"])
+	}
+
+	data["gender"] = append(data["gender"], Option{Value: "0", Label: "未知"})
+	c.Invalidate(ctx, "gender")
+	third, _ := c.Get(ctx, "gender")
+	if calls["gender"] != 2 || third.ETag == first.ETag || len(third.Options) != 3 {
+		t.Fatalf("失效后应重新加载并生成新的 ETag")
+	}
+
+	all, etag, err := c.GetMany(ctx, []string{"status", "gender", "status", " "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || etag == "" {
+		t.Fatalf("GetMany = %v, %s", all, etag)
+	}
+	if _, again, _ := c.GetMany(ctx, []string{"gender", "status"}); again != etag {
+		t.Fatal("编码顺序不同时整体 ETag 应一致")
+	}
+	if empty, _ := c.Get(ctx, "missing"); empty.Options == nil {
+		t.Fatal("不存在的字典应返回空数组")
+	}
+}
+
+func TestCacheSkipsEntryLoadedBeforeInvalidate(t *testing.T) {
+	var c *Cache
+	version := 1
+	c = New(func(ctx context.Context, code string) ([]Option, error) {
+		options := []Option{{Value: fmt.Sprint(version), Label: "男"}}
+		if version == 1 {
+			// 加载期间字典被修改并失效，本次加载的旧数据不应写入缓存
+			version = 2
+			c.Invalidate(ctx, code)
+		}
+		return options, nil
+	})
+	ctx := context.Background()
+	if _, err := c.Get(ctx, "gender"); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := c.Get(ctx, "gender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Options[0].Value != "2" {
+		t.Fatalf("失效后应重新加载，got %v", entry.Options)
+	}
+}
+
+func TestCacheDoesNotStoreLoadErrors(t *testing.T) {
+	calls := 0
+	c := New(func(ctx context.Context, code string) ([]Option, error) {
+		calls++
+		return nil, errors.New("字典不存在")
+	})
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get(ctx, "unknown"); err == nil {
+			t.Fatal("加载失败时应返回错误")
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("加载失败不应写入缓存，加载次数 %d", calls)
+	}
+	if _, _, err := c.GetMany(ctx, []string{"unknown"}); err == nil {
+		t.Fatal("批量查询中任一编码加载失败时应返回错误")
+	}
+}
+
+func TestMatchETag(t *testing.T) {
+	etag := NewEntry([]Option{{Value: "1"}}).ETag
+	if !MatchETag(etag, etag) || !MatchETag(`"x", W/`+etag, etag) || !MatchETag("*", etag) {
+		t.Fatal("应命中 ETag")
+	}
+	if MatchETag("", etag) || MatchETag(`"other"`, etag) {
+		t.Fatal("不应命中 ETag")
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	options := []Option{
+		{Value: "M", Label: "男", Labels: map[string]string{"en-US": "Male"}},
+		{Value: "F", Label: "女"},
+	}
+	en := Localize(options, "en-US")
+	if en[0].Label != "Male" || en[1].Label != "女" || en[0].Labels != nil {
+		t.Fatalf("en-US = %+v", en)
+	}
+	if zh := Localize(options, "zh-CN"); zh[0].Label != "男" {
+		t.Fatalf("zh-CN = %+v", zh)
+	}
+	etag := NewEntry(options).ETag
+	if LocalizeETag(etag, "en-US") == LocalizeETag(etag, "zh-CN") {
+		t.Fatal("不同语言的 ETag 应不同")
+	}
+}
@@ -0,0 +1,318 @@
+package dictcache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/zmqge/vireo-gin-admin/pkg/i18n"
+)
+
+// TagName 结构体字段上声明字典编码的标签，如 `json:"gender" dict:"gender"`
+// 序列化时会增加同名的 xxxLabel 字段，值为字典项的标签
+const TagName = "dict"
+
+// LabelSuffix 字典标签字段的后缀
+const LabelSuffix = "Label"
+
+// lookupFunc 根据字典编码和值查询标签
+type lookupFunc func(code, value string) (string, bool)
+
+// Translate 为带 dict 标签的字段补充 xxxLabel，支持嵌套的结构体、切片、map 和指针
+// 数据中没有 dict 标签时原样返回
+func Translate(ctx context.Context, data interface{}) interface{} {
+	return Default.Translate(ctx, data)
+}
+
+// Translate 使用当前缓存查询字典标签，标签语言取 ctx 中协商的语言
+func (c *Cache) Translate(ctx context.Context, data interface{}) interface{} {
+	locale := i18n.FromContext(ctx)
+	labels := make(map[string]map[string]string)
+	lookup := func(code, value string) (string, bool) {
+		m, ok := labels[code]
+		if !ok {
+			m = make(map[string]string)
+			if entry, err := c.Get(ctx, code); err == nil {
+				for _, o := range entry.Options {
+					m[o.Value] = o.LabelOf(locale)
+				}
+			}
+			labels[code] = m
+		}
+		label, ok := m[value]
+		return label, ok
+	}
+	return translate(data, lookup)
+}
+
+func translate(data interface{}, lookup lookupFunc) interface{} {
+	if data == nil {
+		return nil
+	}
+	out, changed := walk(reflect.ValueOf(data), lookup)
+	if !changed {
+		return data
+	}
+	return out
+}
+
+var marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// walk 遍历值，只有某一层实际补充了字典标签时 changed 为 true，否则调用方应使用原值，
+// 保证没有 dict 标签的数据（如 gin.H、分页包装）按 encoding/json 原样序列化
+func walk(v reflect.Value, lookup lookupFunc) (interface{}, bool) {
+	if !v.IsValid() {
+		return nil, false
+	}
+	if !mayHaveDict(v.Type()) {
+		return nil, false
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, false
+		}
+		return walk(v.Elem(), lookup)
+	case reflect.Struct:
+		m := make(map[string]interface{})
+		if !fillStruct(m, v, lookup) {
+			return nil, false
+		}
+		return m, true
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, false
+		}
+		list := make([]interface{}, v.Len())
+		changed := false
+		for i := 0; i < v.Len(); i++ {
+			out, ok := walk(v.Index(i), lookup)
+			if ok {
+				list[i] = out
+				changed = true
+			} else {
+				list[i] = interfaceOf(v.Index(i))
+			}
+		}
+		return list, changed
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		m := make(map[string]interface{}, v.Len())
+		changed := false
+		iter := v.MapRange()
+		for iter.Next() {
+			out, ok := walk(iter.Value(), lookup)
+			if ok {
+				m[iter.Key().String()] = out
+				changed = true
+			} else {
+				m[iter.Key().String()] = interfaceOf(iter.Value())
+			}
+		}
+		return m, changed
+	}
+	return nil, false
+}
+
+// fillStruct 按 encoding/json 的规则将结构体字段写入 map 并补充字典标签，返回是否补充了标签
+func fillStruct(m map[string]interface{}, v reflect.Value, lookup lookupFunc) bool {
+	changed := false
+	t := v.Type()
+	// 先处理自身字段，再处理匿名嵌入的字段，外层同名字段优先
+	var embedded []reflect.Value
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, skip := jsonField(f)
+		if skip {
+			continue
+		}
+		fv := v.Field(i)
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv, ft = fv.Elem(), ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !implementsMarshaler(ft) {
+				embedded = append(embedded, fv)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if opts.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		if out, ok := walk(fv, lookup); ok {
+			m[name] = out
+			changed = true
+		} else if opts.quoted && isQuotable(f.Type) {
+			m[name] = quotedValue(fv)
+		} else {
+			m[name] = interfaceOf(fv)
+		}
+		if code := f.Tag.Get(TagName); code != "" {
+			m[name+LabelSuffix] = labelOf(fv, code, lookup)
+			changed = true
+		}
+	}
+	for _, ev := range embedded {
+		inner := make(map[string]interface{})
+		if fillStruct(inner, ev, lookup) {
+			changed = true
+		}
+		for k, val := range inner {
+			if _, exists := m[k]; !exists {
+				m[k] = val
+			}
+		}
+	}
+	return changed
+}
+
+// isEmptyValue 与 encoding/json 的 omitempty 规则一致：结构体（含 time.Time）不视为空
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Ptr:
+		return v.IsZero()
+	}
+	return false
+}
+
+// isQuotable ,string 选项只对字符串、数字、布尔类型（及其指针）生效
+func isQuotable(t reflect.Type) bool {
+	if t.Name() == "" && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	}
+	return false
+}
+
+// quotedValue ,string 选项的输出：值的 JSON 编码再作为字符串输出，nil 指针为 null
+func quotedValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	data, err := json.Marshal(interfaceOf(v))
+	if err != nil {
+		return interfaceOf(v)
+	}
+	return string(data)
+}
+
+// labelOf 字段值对应的字典标签，未找到时为空字符串
+func labelOf(v reflect.Value, code string, lookup lookupFunc) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	label, _ := lookup(code, fmt.Sprint(v.Interface()))
+	return label
+}
+
+// jsonOptions json 标签中的选项
+type jsonOptions struct {
+	omitEmpty bool
+	quoted    bool // ,string
+}
+
+// jsonField 解析 json 标签
+func jsonField(f reflect.StructField) (name string, opts jsonOptions, skip bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", opts, true
+	}
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "omitempty":
+			opts.omitEmpty = true
+		case "string":
+			opts.quoted = true
+		}
+	}
+	return parts[0], opts, false
+}
+
+func interfaceOf(v reflect.Value) interface{} {
+	if !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func implementsMarshaler(t reflect.Type) bool {
+	return t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType)
+}
+
+// dictTypes 类型是否可能包含 dict 标签的缓存
+var dictTypes sync.Map // reflect.Type -> bool
+
+// mayHaveDict 判断类型中是否可能存在 dict 标签；interface 需在运行时判断
+func mayHaveDict(t reflect.Type) bool {
+	if cached, ok := dictTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	result := computeHasDict(t, make(map[reflect.Type]bool))
+	dictTypes.Store(t, result)
+	return result
+}
+
+// computeHasDict visiting 记录正在检查的类型，防止递归类型死循环
+func computeHasDict(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+	if implementsMarshaler(t) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return computeHasDict(t.Elem(), visiting)
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && computeHasDict(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Tag.Get("json") == "-" {
+				continue
+			}
+			if f.Tag.Get(TagName) != "" || computeHasDict(f.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package dictcache
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type baseVO struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type noticeVO struct {
+	baseVO
+	Title    string     `json:"title"`
+	Type     string     `json:"type" dict:"notice_type"`
+	Level    *string    `json:"level,omitempty" dict:"notice_level"`
+	Secret   string     `json:"-"`
+	Children []noticeVO `json:"children,omitempty"`
+}
+
+type plainVO struct {
+	Name string `json:"name"`
+}
+
+func fakeLookup(code, value string) (string, bool) {
+	labels := map[string]map[string]string{
+		"notice_type":  {"1": "系统升级"},
+		"notice_level": {"H": "高"},
+	}
+	label, ok := labels[code][value]
+	return label, ok
+}
+
+func TestTranslateAddsLabels(t *testing.T) {
+	level := "H"
+	data := map[string]interface{}{
+		"list": []*noticeVO{{
+			baseVO:   baseVO{ID: 7},
+			Title:    "升级",
+			Type:     "1",
+			Level:    &level,
+			Secret:   "x",
+			Children: []noticeVO{{Type: "9"}},
+		}},
+		"total": 1,
+	}
+	raw, err := json.Marshal(translate(data, fakeLookup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		List []map[string]interface{} `json:"list"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	item := out.List[0]
+	if item["typeLabel"] != "系统升级" || item["levelLabel"] != "高" || item["id"] != float64(7) {
+		t.Fatalf("标签或嵌入字段不正确: %v", item)
+	}
+	if _, ok := item["Secret"]; ok {
+		t.Fatal("json:\"-\" 字段不应输出")
+	}
+	if _, ok := item["createdAt"].(string); !ok {
+		t.Fatal("time.Time 应保持原有序列化格式")
+	}
+	child := item["children"].([]interface{})[0].(map[string]interface{})
+	if child["typeLabel"] != "" {
+		t.Fatalf("未知的值应返回空标签: %v", child)
+	}
+	if _, ok := child["level"]; ok {
+		t.Fatal("omitempty 的空字段不应输出")
+	}
+}
+
+func TestTranslateKeepsPlainData(t *testing.T) {
+	plain := []plainVO{{Name: "a"}}
+	if out, ok := translate(plain, fakeLookup).([]plainVO); !ok || out[0].Name != "a" {
+		t.Fatal("没有 dict 标签的数据应原样返回")
+	}
+	m := map[string]interface{}{"user": plainVO{Name: "b"}}
+	if _, ok := translate(m, fakeLookup).(map[string]interface{})["user"].(plainVO); !ok {
+		t.Fatal("没有 dict 标签的 map 应原样返回")
+	}
+}
+
+type pageVO struct {
+	List     []plainVO   `json:"list"`
+	Total    int64       `json:"total,string"`
+	Updated  time.Time   `json:"updated,omitempty"`
+	Base     baseVO      `json:"base,omitempty"`
+	Extra    interface{} `json:"extra,omitempty"`
+	Nickname string      `json:"nickname,omitempty"`
+}
+
+func TestTranslateKeepsTagFreePayloadBytes(t *testing.T) {
+	data := map[string]interface{}{
+		"page": pageVO{List: []plainVO{{Name: "a"}}, Total: 3},
+		"meta": map[string]interface{}{"user": &plainVO{Name: "b"}, "when": time.Time{}},
+	}
+	want, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(translate(data, fakeLookup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("没有 dict 标签的数据序列化结果应不变\n got: %s\nwant: %s", got, want)
+	}
+}
+
+type taggedPageVO struct {
+	Type    string    `json:"type" dict:"notice_type"`
+	Total   int64     `json:"total,string"`
+	Updated time.Time `json:"updated,omitempty"`
+	Base    baseVO    `json:"base,omitempty"`
+	Note    string    `json:"note,omitempty"`
+}
+
+func TestTranslateFollowsJSONOptions(t *testing.T) {
+	data := taggedPageVO{Type: "1", Total: 3}
+	plain, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(translate(data, fakeLookup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, got map[string]interface{}
+	if err := json.Unmarshal(plain, &want); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["typeLabel"] != "系统升级" {
+		t.Fatalf("应补充字典标签: %v", got)
+	}
+	delete(got, "typeLabel")
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Fatalf("omitempty 和 ,string 应与 encoding/json 一致\n got: %s\nwant: %s", gotJSON, wantJSON)
+	}
+}
@@ -0,0 +1,116 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed locales/*.yaml
+var localeFS embed.FS
+
+// Catalog 消息目录：语言 -> 原文 -> 译文
+// 以代码中的原文作为消息键，未收录的消息原样返回
+type Catalog struct {
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+}
+
+// Messages 全局消息目录，启动时加载内置的 locales/*.yaml
+var Messages = NewCatalog()
+
+func init() {
+	if err := Messages.LoadFS(localeFS, "locales"); err != nil {
+		panic(fmt.Sprintf("加载内置消息目录失败: %v", err))
+	}
+}
+
+// NewCatalog 创建空的消息目录
+func NewCatalog() *Catalog {
+	return &Catalog{messages: make(map[string]map[string]string)}
+}
+
+// Add 合并指定语言的消息，已存在的键被覆盖
+func (c *Catalog) Add(locale string, messages map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	locale = strings.ToLower(locale)
+	m, ok := c.messages[locale]
+	if !ok {
+		m = make(map[string]string, len(messages))
+		c.messages[locale] = m
+	}
+	for k, v := range messages {
+		m[k] = v
+	}
+}
+
+// LoadFS 加载目录下的 <locale>.yaml 文件，文件内容为 原文: 译文
+func (c *Catalog) LoadFS(fsys embed.FS, dir string) error {
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".yaml" {
+			continue
+		}
+		data, err := fsys.ReadFile(path.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		var messages map[string]string
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		c.Add(strings.TrimSuffix(e.Name(), ".yaml"), messages)
+	}
+	return nil
+}
+
+// T 翻译消息：先完全匹配；未收录时按第一个冒号拆分，分别翻译前缀和后半部分
+// （兼容 "参数错误: xxx" 这类拼接了错误详情的消息），仍未收录则原样返回
+func (c *Catalog) T(locale, msg string) string {
+	if msg == "" {
+		return msg
+	}
+	if v, ok := c.lookup(locale, msg); ok {
+		return v
+	}
+	for _, sep := range []string{": ", "：", ":"} {
+		if i := strings.Index(msg, sep); i > 0 {
+			prefix, rest := msg[:i], strings.TrimSpace(msg[i+len(sep):])
+			p, ok := c.lookup(locale, prefix)
+			if !ok {
+				return msg
+			}
+			if r, ok := c.lookup(locale, rest); ok {
+				rest = r
+			}
+			return p + ": " + rest
+		}
+	}
+	return msg
+}
+
+func (c *Catalog) lookup(locale, msg string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.messages[strings.ToLower(locale)][msg]
+	return v, ok && v != ""
+}
+
+// T 使用全局消息目录翻译
+func T(locale, msg string) string {
+	return Messages.T(locale, msg)
+}
+
+// Tc 按 context 中协商的语言翻译
+func Tc(ctx context.Context, msg string) string {
+	return Messages.T(FromContext(ctx), msg)
+}
@@ -0,0 +1,154 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContextKey gin 上下文中保存协商语言的键
+const ContextKey = "locale"
+
+var (
+	mu            sync.RWMutex
+	defaultLocale = "zh-CN"
+	supported     = []string{"zh-CN", "en-US"}
+)
+
+// Configure 设置默认语言和支持的语言列表，默认语言总是包含在支持列表中
+func Configure(def string, locales []string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if def = strings.TrimSpace(def); def != "" {
+		defaultLocale = def
+	}
+	list := []string{defaultLocale}
+	for _, l := range locales {
+		l = strings.TrimSpace(l)
+		if l != "" && !containsFold(list, l) {
+			list = append(list, l)
+		}
+	}
+	if len(locales) > 0 || def != "" {
+		supported = list
+	}
+}
+
+// DefaultLocale 默认语言
+func DefaultLocale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultLocale
+}
+
+// Supported 支持的语言列表，第一个为默认语言
+func Supported() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]string(nil), supported...)
+}
+
+// IsSupported 判断语言是否在支持列表中
+func IsSupported(locale string) bool {
+	return containsFold(Supported(), locale)
+}
+
+// Negotiate 根据 Accept-Language 请求头（或单个语言标签）选择支持的语言，无匹配时返回默认语言
+func Negotiate(header string) string {
+	return negotiate(header, Supported(), DefaultLocale())
+}
+
+type weightedTag struct {
+	tag string
+	q   float64
+}
+
+func negotiate(header string, supported []string, def string) string {
+	var tags []weightedTag
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if v, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weightedTag{tag: strings.ReplaceAll(tag, "_", "-"), q: q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	for _, t := range tags {
+		if t.tag == "*" {
+			return def
+		}
+		// 完全匹配优先，其次按语言部分匹配（en、en-GB 均匹配 en-US）
+		for _, s := range supported {
+			if strings.EqualFold(s, t.tag) {
+				return s
+			}
+		}
+		lang := baseLanguage(t.tag)
+		if strings.EqualFold(baseLanguage(def), lang) {
+			return def
+		}
+		for _, s := range supported {
+			if strings.EqualFold(baseLanguage(s), lang) {
+				return s
+			}
+		}
+	}
+	return def
+}
+
+type ctxKey struct{}
+
+// WithLocale 将语言写入 context，用于非 HTTP 请求的场景
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, locale)
+}
+
+// FromContext 读取协商后的语言，gin.Context 读取 Locale 中间件写入的值，未设置时返回默认语言
+func FromContext(ctx context.Context) string {
+	if ctx != nil {
+		if v, ok := ctx.Value(ctxKey{}).(string); ok && v != "" {
+			return v
+		}
+		if v, ok := ctx.Value(ContextKey).(string); ok && v != "" {
+			return v
+		}
+	}
+	return DefaultLocale()
+}
+
+// IsDefault 判断是否为默认语言（默认语言的文本即数据表中的原文，无需翻译）
+func IsDefault(locale string) bool {
+	return locale == "" || strings.EqualFold(locale, DefaultLocale())
+}
+
+func baseLanguage(tag string) string {
+	if i := strings.IndexByte(tag, '-'); i > 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	supported := []string{"zh-CN", "en-US"}
+	cases := []struct {
+		header, want string
+	}{
+		{"", "zh-CN"},
+		{"en-US", "en-US"},
+		{"en", "en-US"},
+		{"en-GB,en;q=0.8", "en-US"},
+		{"fr-FR,en;q=0.5,zh;q=0.9", "zh-CN"},
+		{"zh-TW", "zh-CN"},
+		{"de,fr;q=0.5", "zh-CN"},
+		{"en;q=0,zh-CN", "zh-CN"},
+		{"en_us", "en-US"},
+		{"*", "zh-CN"},
+	}
+	for _, c := range cases {
+		if got := negotiate(c.header, supported, "zh-CN"); got != c.want {
+			t.Errorf("negotiate(%q) = %q, want %q", c.header, got, c.want)
+		}
+	}
+}
+
+func TestCatalogT(t *testing.T) {
+	c := NewCatalog()
+	c.Add("en-US", map[string]string{
+		"参数错误":    "Invalid parameters",
+		"无效的请求参数": "Invalid request parameters",
+	})
+
+	if got := c.T("en-US", "无效的请求参数"); got != "Invalid request parameters" {
+		t.Errorf("exact = %q", got)
+	}
+	if got := c.T("EN-us", "无效的请求参数"); got != "Invalid request parameters" {
+		t.Errorf("locale case = %q", got)
+	}
+	if got := c.T("en-US", "参数错误: 无效的请求参数"); got != "Invalid parameters: Invalid request parameters" {
+		t.Errorf("prefix and rest = %q", got)
+	}
+	if got := c.T("en-US", "参数错误: name too long"); got != "Invalid parameters: name too long" {
+		t.Errorf("prefix = %q", got)
+	}
+	if got := c.T("en-US", "未收录: 无效的请求参数"); got != "未收录: 无效的请求参数" {
+		t.Errorf("unknown prefix = %q", got)
+	}
+	if got := c.T("zh-CN", "无效的请求参数"); got != "无效的请求参数" {
+		t.Errorf("missing locale = %q", got)
+	}
+}
+
+func TestBuiltinCatalog(t *testing.T) {
+	if got := T("en-US", "验证码错误"); got != "Invalid captcha" {
+		t.Errorf("en-US = %q", got)
+	}
+	if got := T("zh-CN", "Invalid ID"); got != "无效的ID" {
+		t.Errorf("zh-CN = %q", got)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != DefaultLocale() {
+		t.Errorf("default = %q", got)
+	}
+	if got := FromContext(WithLocale(context.Background(), "en-US")); got != "en-US" {
+		t.Errorf("WithLocale = %q", got)
+	}
+}
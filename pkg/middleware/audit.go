@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/pkg/response"
+	"go.uber.org/zap"
+)
+
+// Audit 审计日志中间件：请求结束后记录操作人、操作名称、接口、结果和耗时
+// action 为空时以路由路径作为操作名称
+func Audit(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		name := action
+		if name == "" {
+			name = c.FullPath()
+		}
+		zap.L().Info("audit",
+			zap.String("action", name),
+			zap.String("userID", c.GetString("userID")),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", c.Writer.Status()),
+			zap.String("ip", c.ClientIP()),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("requestId", response.RequestID(c)),
+		)
+	}
+}
@@ -5,7 +5,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/config"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/response"
+	"github.com/zmqge/vireo-gin-admin/pkg/sysconfig"
 )
 
 func DemoMode() gin.HandlerFunc {
@@ -20,7 +22,8 @@ func DemoMode() gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 		// 如果不是演示模式，直接放行
-		if !config.App.DemoMode {
+		// 系统配置 sys.demo_mode 可在运行时覆盖配置文件
+		if !sysconfig.GetBool(sysconfig.KeyDemoMode, config.App.DemoMode) {
 			c.Next()
 			return
 		}
@@ -44,7 +47,7 @@ func DemoMode() gin.HandlerFunc {
 
 		// 检查请求方法是否为禁止的类型（POST/PUT/DELETE）
 		if c.Request.Method == "POST" || c.Request.Method == "PUT" || c.Request.Method == "DELETE" {
-			response.Forbidden(c, "演示模式下禁止此操作")
+			response.Fail(c, apperr.ErrDemoMode)
 			c.Abort()
 			return
 		}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/redis"
+	"github.com/zmqge/vireo-gin-admin/pkg/response"
+)
+
+// IdempotencyHeader 幂等键请求头
+const IdempotencyHeader = "Idempotency-Key"
+
+// defaultIdempotentTTL 幂等键默认保留时间
+const defaultIdempotentTTL = 10 * time.Minute
+
+func parseIdempotentTTL(args []string) (time.Duration, error) {
+	if len(args) == 0 {
+		return defaultIdempotentTTL, nil
+	}
+	if len(args) > 1 {
+		return 0, fmt.Errorf("最多 1 个参数")
+	}
+	ttl, err := time.ParseDuration(args[0])
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("保留时间 %q 应为正的时长，如 30s、10m", args[0])
+	}
+	return ttl, nil
+}
+
+func validateIdempotent(args []string) error {
+	_, err := parseIdempotentTTL(args)
+	return err
+}
+
+func mustIdempotentTTL(args []string) time.Duration {
+	ttl, err := parseIdempotentTTL(args)
+	if err != nil {
+		panic(err)
+	}
+	return ttl
+}
+
+// Idempotent 幂等中间件：请求需携带 Idempotency-Key，同一用户在 ttl 内以相同的键重复调用同一接口时返回 409
+// 处理失败（状态码 >= 400）时释放键，允许客户端重试；Redis 不可用时放行
+func Idempotent(ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyHeader)
+		if key == "" {
+			response.Fail(c, apperr.ErrIdempotencyKey)
+			c.Abort()
+			return
+		}
+		owner := c.GetString("userID")
+		if owner == "" {
+			owner = c.ClientIP()
+		}
+		redisKey := fmt.Sprintf("idempotent:%s:%s %s:%s", owner, c.Request.Method, c.FullPath(), key)
+
+		if redis.Client == nil {
+			c.Next()
+			return
+		}
+		ok, err := redis.Client.SetNX(c.Request.Context(), redisKey, response.RequestID(c), ttl).Result()
+		if err != nil {
+			log.Printf("幂等键写入失败，已放行: %v", err)
+			c.Next()
+			return
+		}
+		if !ok {
+			response.Fail(c, apperr.ErrDuplicateRequest)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+		if c.Writer.Status() >= 400 {
+			if err := redis.Client.Del(c.Request.Context(), redisKey).Err(); err != nil {
+				log.Printf("释放幂等键失败: %v", err)
+			}
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"fmt"
 	"log"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -20,8 +22,38 @@ type Claims struct {
 
 // JWT JWT 中间件
 func JWT() gin.HandlerFunc {
+	return jwtAuth(false)
+}
+
+// JWTOrTicket 在 JWT 的基础上，允许 GET 请求以 ?ticket= 传递一次性的流连接票据（见 auth.IssueStreamTicket），
+// 仅用于 EventSource、WebSocket 这类无法设置请求头的连接
+func JWTOrTicket() gin.HandlerFunc {
+	return jwtAuth(true)
+}
+
+// validateJWT jwt 中间件的参数：可选的 ticket
+func validateJWT(args []string) error {
+	if len(args) > 1 || (len(args) == 1 && args[0] != "ticket") {
+		return fmt.Errorf("只接受参数 ticket")
+	}
+	return nil
+}
+
+func jwtAuth(allowTicket bool) gin.HandlerFunc {
 	jwt := auth.NewJWT()
 	return func(c *gin.Context) {
+		if allowTicket && c.GetHeader("Authorization") == "" && c.Request.Method == http.MethodGet {
+			userID, err := auth.ConsumeStreamTicket(c.Request.Context(), c.Query("ticket"))
+			if err != nil {
+				response.Unauthorized(c, "票据无效或已使用")
+				c.Abort()
+				return
+			}
+			c.Set("userID", userID)
+			c.Next()
+			return
+		}
+
 		// 1. 从请求头中提取 Token
 		tokenString := extractToken(c)
 		if tokenString == "" {
@@ -57,7 +89,7 @@ func JWT() gin.HandlerFunc {
 	}
 }
 
-// extractToken 从请求头中提取 Token
+// extractToken 从请求头中提取 Token，不接受地址参数中的令牌
 func extractToken(c *gin.Context) string {
 	tokenString := c.GetHeader("Authorization")
 	if tokenString == "" {
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/pkg/i18n"
+)
+
+// Locale 协商请求语言：优先使用 lang 查询参数，其次 Accept-Language 请求头
+// 结果写入上下文，供响应消息、字典标签和菜单标题翻译使用
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var locale string
+		if lang := c.Query("lang"); lang != "" {
+			locale = i18n.Negotiate(lang)
+		} else {
+			locale = i18n.Negotiate(c.GetHeader("Accept-Language"))
+		}
+		c.Set(i18n.ContextKey, locale)
+		c.Header("Content-Language", locale)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
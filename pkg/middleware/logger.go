@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/pkg/response"
 )
 
 // Logger 日志中间件
@@ -12,6 +13,6 @@ func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		c.Next()
-		log.Printf("[%s] %s %s %v", c.Request.Method, c.Request.URL.Path, c.ClientIP(), time.Since(start))
+		log.Printf("[%s] %s %s %v request=%s", c.Request.Method, c.Request.URL.Path, c.ClientIP(), time.Since(start), response.RequestID(c))
 	}
 }
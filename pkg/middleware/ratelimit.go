@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/response"
+)
+
+// RateLimitOptions 限流参数：每个用户（未登录时按客户端 IP）在每个路由上每 Per 时间内最多 Limit 次，Burst 为突发容量
+type RateLimitOptions struct {
+	Limit int
+	Per   time.Duration
+	Burst int
+}
+
+// parseRate 解析 10/s、100/m、1000/h
+func parseRate(s string) (int, time.Duration, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("%q 应为 次数/单位", s)
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || limit <= 0 {
+		return 0, 0, fmt.Errorf("次数 %q 应为正整数", parts[0])
+	}
+	switch strings.TrimSpace(parts[1]) {
+	case "s":
+		return limit, time.Second, nil
+	case "m":
+		return limit, time.Minute, nil
+	case "h":
+		return limit, time.Hour, nil
+	}
+	return 0, 0, fmt.Errorf("单位 %q 应为 s、m 或 h", parts[1])
+}
+
+// parseRateLimit 解析注解参数 (10/s) 或 (10/s, 20)
+func parseRateLimit(args []string) (RateLimitOptions, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return RateLimitOptions{}, fmt.Errorf("需要 1 到 2 个参数")
+	}
+	limit, per, err := parseRate(args[0])
+	if err != nil {
+		return RateLimitOptions{}, err
+	}
+	opts := RateLimitOptions{Limit: limit, Per: per, Burst: limit}
+	if len(args) == 2 {
+		if opts.Burst, err = strconv.Atoi(args[1]); err != nil || opts.Burst <= 0 {
+			return RateLimitOptions{}, fmt.Errorf("突发容量 %q 应为正整数", args[1])
+		}
+	}
+	return opts, nil
+}
+
+func validateRateLimit(args []string) error {
+	_, err := parseRateLimit(args)
+	return err
+}
+
+func mustRateLimit(args []string) RateLimitOptions {
+	opts, err := parseRateLimit(args)
+	if err != nil {
+		panic(err)
+	}
+	return opts
+}
+
+// tokenBucket 令牌桶
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter 进程内限流，多副本部署时每个副本单独计数
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64 // 每秒补充的令牌数
+	burst   float64
+	buckets map[string]*tokenBucket
+	sweep   time.Time
+}
+
+func newRateLimiter(opts RateLimitOptions) *rateLimiter {
+	return &rateLimiter{
+		rate:    float64(opts.Limit) / opts.Per.Seconds(),
+		burst:   float64(opts.Burst),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// allow 取一个令牌，没有可用令牌时返回 false
+func (l *rateLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// 定期清理已回满的令牌桶，避免按 IP 计数时 map 无限增长
+	if now.Sub(l.sweep) > time.Minute {
+		for k, b := range l.buckets {
+			if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+				delete(l.buckets, k)
+			}
+		}
+		l.sweep = now
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// RateLimit 限流中间件，超出限制时返回 429
+func RateLimit(opts RateLimitOptions) gin.HandlerFunc {
+	limiter := newRateLimiter(opts)
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if userID := c.GetString("userID"); userID != "" {
+			key = "user:" + userID
+		}
+		if !limiter.allow(key+"|"+c.FullPath(), time.Now()) {
+			response.Fail(c, apperr.ErrTooManyRequests)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/zmqge/vireo-gin-admin/config"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
 	"github.com/zmqge/vireo-gin-admin/pkg/response"
 	"gorm.io/gorm"
 )
@@ -19,7 +20,7 @@ func RBAC(requiredCodes ...string) gin.HandlerFunc {
 		db, err := getDBFromContext(c)
 		if err != nil {
 			log.Println("数据库实例获取失败:", err)
-			response.Error(c, fmt.Errorf("内部服务错误"))
+			response.Error(c, err)
 			c.Abort()
 			return
 		}
@@ -45,9 +46,9 @@ func RBAC(requiredCodes ...string) gin.HandlerFunc {
 				log.Println("权限检查失败:", err)
 				switch err {
 				case ErrPermissionDenied:
-					response.Forbidden(c, "无权访问")
+					response.Fail(c, apperr.ErrForbidden)
 				default:
-					response.Error(c, fmt.Errorf("权限验证失败"))
+					response.Error(c, fmt.Errorf("权限验证失败: %w", err))
 				}
 				c.Abort()
 				return
@@ -118,6 +119,14 @@ func checkUserPermissions(db *gorm.DB, userID uint, requiredCodes []string) erro
 	return nil
 }
 
+// HasPermission 判断用户是否拥有指定权限（超级管理员拥有全部权限）
+func HasPermission(db *gorm.DB, userID uint, code string) bool {
+	if isSuperAdmin(db, userID) {
+		return true
+	}
+	return checkUserPermissions(db, userID, []string{code}) == nil
+}
+
 // getUserPermissions 获取用户的所有权限码
 func getUserPermissions(db *gorm.DB, userID uint) ([]string, error) {
 	var perms []string
@@ -1,18 +1,21 @@
 package middleware
 
 import (
+	"fmt"
 	"log"
+	"runtime/debug"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/pkg/response"
 )
 
-// Recovery 恢复中间件
+// Recovery 恢复中间件，panic 按内部错误返回（记录堆栈和请求 ID）
 func Recovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				log.Printf("Recovered from panic: %v", err)
-				c.AbortWithStatusJSON(500, gin.H{"error": "Internal Server Error"})
+				log.Printf("Recovered from panic: %v\n%s", err, debug.Stack())
+				response.Fail(c, fmt.Errorf("panic: %v", err))
 			}
 		}()
 		c.Next()
@@ -0,0 +1,185 @@
+package middleware
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Definition 可在 @Group、@Route 的 middlewares 中按名称引用的中间件
+// 注解写法为 名称 或 名称(参数1, 参数2)，如 "jwt"、"ratelimit(10/s)"
+type Definition struct {
+	Name  string // 注解中使用的名称，匹配时忽略大小写
+	Usage string // 用法说明，出现在校验错误中
+	// Validate 校验参数，为 nil 时不接受参数；routegen 生成路由前调用，不应依赖配置和外部服务
+	Validate func(args []string) error
+	// New 创建中间件，参数已通过 Validate 校验
+	New func(args []string) gin.HandlerFunc
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Definition)
+)
+
+// Register 注册中间件，名称重复时 panic
+func Register(def Definition) {
+	name := strings.ToLower(def.Name)
+	if name == "" || def.New == nil {
+		panic("middleware: 注册的中间件缺少名称或 New")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("middleware: 中间件 %s 重复注册", name))
+	}
+	registry[name] = def
+}
+
+// Lookup 按名称查找已注册的中间件
+func Lookup(name string) (Definition, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	def, ok := registry[strings.ToLower(name)]
+	return def, ok
+}
+
+// Names 已注册的中间件名称，按字母排序
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseSpec 拆分注解中的中间件写法 名称(参数1, 参数2)，返回小写名称和参数
+func ParseSpec(spec string) (string, []string, error) {
+	spec = strings.TrimSpace(spec)
+	open := strings.IndexByte(spec, '(')
+	if open < 0 {
+		if strings.ContainsAny(spec, ") ") || spec == "" {
+			return "", nil, fmt.Errorf("中间件 %q 格式错误", spec)
+		}
+		return strings.ToLower(spec), nil, nil
+	}
+	name := strings.TrimSpace(spec[:open])
+	if name == "" || !strings.HasSuffix(spec, ")") || strings.Count(spec, "(") != 1 || strings.Count(spec, ")") != 1 {
+		return "", nil, fmt.Errorf("中间件 %q 格式错误，应为 名称 或 名称(参数)", spec)
+	}
+	var args []string
+	if inner := strings.TrimSpace(spec[open+1 : len(spec)-1]); inner != "" {
+		for _, arg := range strings.Split(inner, ",") {
+			args = append(args, strings.TrimSpace(arg))
+		}
+	}
+	return strings.ToLower(name), args, nil
+}
+
+// Validate 校验注解中的中间件写法：名称已注册且参数合法
+func Validate(spec string) error {
+	_, _, err := resolve(spec)
+	return err
+}
+
+// Use 按注解写法创建中间件，供生成的路由文件调用；写法无效时 panic（routegen 已在生成时校验）
+func Use(spec string) gin.HandlerFunc {
+	def, args, err := resolve(spec)
+	if err != nil {
+		panic("middleware: " + err.Error())
+	}
+	return def.New(args)
+}
+
+func resolve(spec string) (Definition, []string, error) {
+	name, args, err := ParseSpec(spec)
+	if err != nil {
+		return Definition{}, nil, err
+	}
+	def, ok := Lookup(name)
+	if !ok {
+		return Definition{}, nil, fmt.Errorf("未知的中间件 %q，可用: %s", name, strings.Join(Names(), ", "))
+	}
+	if def.Validate == nil {
+		if len(args) > 0 {
+			return Definition{}, nil, fmt.Errorf("中间件 %s 不接受参数", name)
+		}
+		return def, nil, nil
+	}
+	if err := def.Validate(args); err != nil {
+		usage := def.Usage
+		if usage == "" {
+			usage = name
+		}
+		return Definition{}, nil, fmt.Errorf("中间件 %s 的参数无效: %v，用法: %s", name, err, usage)
+	}
+	return def, args, nil
+}
+
+// simple 无参数的中间件
+func simple(name string, fn func() gin.HandlerFunc) Definition {
+	return Definition{Name: name, New: func([]string) gin.HandlerFunc { return fn() }}
+}
+
+// 内置中间件，RBAC 由 @Permission 生成，不在注册表中
+func init() {
+	Register(Definition{
+		Name:     "jwt",
+		Usage:    "jwt 或 jwt(ticket)，ticket 时 GET 请求也可用 ?ticket= 传递一次性的流连接票据",
+		Validate: validateJWT,
+		New: func(args []string) gin.HandlerFunc {
+			if len(args) > 0 {
+				return JWTOrTicket()
+			}
+			return JWT()
+		},
+	})
+	Register(simple("dataperm", DATAPERM))
+	Register(simple("demomode", DemoMode))
+	Register(simple("cors", Cors))
+	Register(simple("locale", Locale))
+	Register(simple("logger", Logger))
+	Register(simple("recovery", Recovery))
+	Register(simple("requestid", RequestID))
+	Register(simple("errorhandler", ErrorHandler))
+	Register(Definition{
+		Name:     "ratelimit",
+		Usage:    "ratelimit(10/s) 或 ratelimit(100/m, 20)，单位 s/m/h，第二个参数为突发容量",
+		Validate: validateRateLimit,
+		New:      func(args []string) gin.HandlerFunc { return RateLimit(mustRateLimit(args)) },
+	})
+	Register(Definition{
+		Name:     "audit",
+		Usage:    "audit 或 audit(操作名称)",
+		Validate: maxArgs(1),
+		New: func(args []string) gin.HandlerFunc {
+			action := ""
+			if len(args) > 0 {
+				action = args[0]
+			}
+			return Audit(action)
+		},
+	})
+	Register(Definition{
+		Name:     "idempotent",
+		Usage:    "idempotent 或 idempotent(10m)，参数为幂等键的保留时间",
+		Validate: validateIdempotent,
+		New:      func(args []string) gin.HandlerFunc { return Idempotent(mustIdempotentTTL(args)) },
+	})
+}
+
+// maxArgs 最多接受 n 个参数
+func maxArgs(n int) func([]string) error {
+	return func(args []string) error {
+		if len(args) > n {
+			return fmt.Errorf("最多 %d 个参数", n)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSpec(t *testing.T) {
+	name, args, err := ParseSpec(" RateLimit(10/s, 20) ")
+	if err != nil || name != "ratelimit" || len(args) != 2 || args[1] != "20" {
+		t.Fatalf("解析结果不正确: %s %v %v", name, args, err)
+	}
+	for _, spec := range []string{"", "ratelimit(10/s", "rate limit", "(10/s)", "a(b)(c)"} {
+		if _, _, err := ParseSpec(spec); err == nil {
+			t.Errorf("%q 应解析失败", spec)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, spec := range []string{"jwt", "JWT", "jwt(ticket)", "ratelimit(100/m)", "ratelimit(10/s, 5)", "audit", "audit(删除用户)", "idempotent(30s)"} {
+		if err := Validate(spec); err != nil {
+			t.Errorf("%q 应校验通过: %v", spec, err)
+		}
+	}
+	for _, spec := range []string{"cache", "jwt(1)", "ratelimit", "ratelimit(0/s)", "ratelimit(10/d)", "idempotent(abc)"} {
+		if err := Validate(spec); err == nil {
+			t.Errorf("%q 应校验失败", spec)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(RateLimitOptions{Limit: 2, Per: time.Second, Burst: 2})
+	now := time.Now()
+	if !limiter.allow("a", now) || !limiter.allow("a", now) || limiter.allow("a", now) {
+		t.Fatal("突发容量用完后应拒绝")
+	}
+	if !limiter.allow("b", now) {
+		t.Error("不同的键应单独计数")
+	}
+	if !limiter.allow("a", now.Add(500*time.Millisecond)) {
+		t.Error("经过 0.5 秒应补充 1 个令牌")
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/zmqge/vireo-gin-admin/pkg/response"
+)
+
+// requestIDHeader 请求 ID 请求/响应头，网关已生成时沿用
+const requestIDHeader = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID 为每个请求分配 ID，写入上下文和响应头，错误日志和错误响应中携带该 ID 便于排查
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+		}
+		c.Set(response.RequestIDKey, id)
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
+
+// ErrorHandler 统一渲染处理函数通过 c.Error 记录、但尚未写入响应的错误
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if c.Writer.Written() || len(c.Errors) == 0 {
+			return
+		}
+		response.Fail(c, c.Errors.Last().Err)
+	}
+}
@@ -0,0 +1,112 @@
+// Package noticetpl 通知模板渲染
+//
+// 模板使用 Go 模板语法，可用变量：
+//
+//	{{.User.Nickname}} {{.User.Username}} {{.User.Email}} {{.User.Mobile}}
+//	{{.Dept.Name}} {{.Dept.Code}}
+//	{{.Vars.xxx}}  模板自定义变量
+//	{{.Now.Format "2006-01-02"}}
+//
+// 通知内容按 HTML 展示，因此使用 html/template：变量值会被转义，模板本身的 HTML 原样保留
+package noticetpl
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// VarNamePattern 自定义变量名规则
+var VarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,49}$`)
+
+// User 接收人信息
+type User struct {
+	ID       uint
+	Username string
+	Nickname string
+	Email    string
+	Mobile   string
+}
+
+// Dept 接收人部门信息
+type Dept struct {
+	ID   uint
+	Name string
+	Code string
+}
+
+// Data 模板渲染数据
+type Data struct {
+	User User
+	Dept Dept
+	Vars map[string]string
+	Now  time.Time
+}
+
+// Template 已编译的模板
+type Template struct {
+	tpl *template.Template
+}
+
+// Compile 编译模板，未声明的自定义变量在渲染时报错
+func Compile(text string) (*Template, error) {
+	tpl, err := template.New("notice").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("模板语法错误: %s", cleanError(err))
+	}
+	return &Template{tpl: tpl}, nil
+}
+
+// Render 渲染模板
+func (t *Template) Render(data Data) (string, error) {
+	if data.Vars == nil {
+		data.Vars = map[string]string{}
+	}
+	if data.Now.IsZero() {
+		data.Now = time.Now()
+	}
+	var buf bytes.Buffer
+	if err := t.tpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("模板渲染失败: %s", cleanError(err))
+	}
+	return buf.String(), nil
+}
+
+// Validate 校验模板语法，并用示例数据试渲染一次，
+// 以发现引用了不存在的字段或未声明的自定义变量
+func Validate(text string, varNames []string) error {
+	for _, name := range varNames {
+		if !VarNamePattern.MatchString(name) {
+			return fmt.Errorf("变量名 '%s' 无效（字母或下划线开头，仅含字母、数字、下划线）", name)
+		}
+	}
+	tpl, err := Compile(text)
+	if err != nil {
+		return err
+	}
+	vars := make(map[string]string, len(varNames))
+	for _, name := range varNames {
+		vars[name] = name
+	}
+	_, err = tpl.Render(Data{
+		User: User{ID: 1, Username: "username", Nickname: "nickname", Email: "user@example.com", Mobile: "13800000000"},
+		Dept: Dept{ID: 1, Name: "dept", Code: "code"},
+		Vars: vars,
+	})
+	return err
+}
+
+// IsTemplate 文本中是否包含模板动作
+func IsTemplate(text string) bool {
+	return strings.Contains(text, "{{")
+}
+
+// cleanError 去掉模板名前缀，使错误信息更易读
+func cleanError(err error) string {
+	msg := err.Error()
+	msg = strings.TrimPrefix(msg, "template: ")
+	return strings.TrimPrefix(msg, "notice:")
+}
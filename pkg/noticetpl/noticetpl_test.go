@@ -0,0 +1,68 @@
+package noticetpl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tpl, err := Compile(`<p>{{.User.Nickname}}（{{.Dept.Name}}）：{{.Vars.date}} 停机维护</p>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tpl.Render(Data{
+		User: User{Nickname: "张三"},
+		Dept: Dept{Name: "研发部"},
+		Vars: map[string]string{"date": "10月20日"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<p>张三（研发部）：10月20日 停机维护</p>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRenderEscapesValues(t *testing.T) {
+	tpl, err := Compile(`<p>{{.User.Nickname}}</p>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tpl.Render(Data{User: User{Nickname: "<script>"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "<script>") {
+		t.Errorf("变量值未转义: %s", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		text    string
+		vars    []string
+		wantErr string
+	}{
+		{"ok", "{{.User.Nickname}} {{.Vars.date}}", []string{"date"}, ""},
+		{"plain", "没有变量", nil, ""},
+		{"syntax", "{{.User.Nickname", nil, "模板语法错误"},
+		{"unknown field", "{{.User.Age}}", nil, "模板渲染失败"},
+		{"undeclared var", "{{.Vars.date}}", nil, "模板渲染失败"},
+		{"bad var name", "x", []string{"1abc"}, "变量名"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.text, tc.vars)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
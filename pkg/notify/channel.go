@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 渠道名称
+const (
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelWebhook = "webhook"
+)
+
+// ErrNoAddress 接收人缺少该渠道的地址（如未填写邮箱），属于不可重试错误
+var ErrNoAddress = errors.New("接收人未配置该渠道的地址")
+
+// Recipient 接收人
+type Recipient struct {
+	UserID        uint   `json:"userId"`
+	Nickname      string `json:"nickname"`
+	Email         string `json:"email,omitempty"`
+	Mobile        string `json:"mobile,omitempty"`
+	WebhookURL    string `json:"-"` // 用户自定义的 Webhook 地址，为空时使用系统默认地址
+	WebhookSecret string `json:"-"` // 用户 Webhook 的签名密钥
+}
+
+// Message 待发送的通知消息
+type Message struct {
+	NoticeID uint      `json:"noticeId"`
+	Title    string    `json:"title"`
+	Content  string    `json:"content"`
+	Level    string    `json:"level"`
+	To       Recipient `json:"to"`
+}
+
+// Channel 通知渠道
+type Channel interface {
+	// Name 渠道名称
+	Name() string
+	// Send 发送消息，返回 ErrNoAddress 时不再重试
+	Send(ctx context.Context, msg Message) error
+}
+
+// Registry 已启用的渠道
+type Registry struct {
+	channels map[string]Channel
+}
+
+// NewRegistry 创建渠道注册表
+func NewRegistry(channels ...Channel) *Registry {
+	r := &Registry{channels: make(map[string]Channel)}
+	for _, ch := range channels {
+		if ch != nil {
+			r.channels[ch.Name()] = ch
+		}
+	}
+	return r
+}
+
+// Get 获取渠道
+func (r *Registry) Get(name string) (Channel, error) {
+	ch, ok := r.channels[name]
+	if !ok {
+		return nil, fmt.Errorf("通知渠道 %s 未启用", name)
+	}
+	return ch, nil
+}
+
+// Enabled 渠道是否已启用
+func (r *Registry) Enabled(name string) bool {
+	_, ok := r.channels[name]
+	return ok
+}
+
+// ChannelsForLevel 根据通知级别返回应触发的渠道（级别不区分大小写）
+func ChannelsForLevel(levelChannels map[string][]string, level string) []string {
+	for k, v := range levelChannels {
+		if strings.EqualFold(k, level) {
+			return v
+		}
+	}
+	return nil
+}
+
+// maxBackoff 重试间隔上限
+const maxBackoff = 6 * time.Hour
+
+// Backoff 计算第 attempt 次失败后的重试间隔（指数退避：base, 2base, 4base...）
+func Backoff(base time.Duration, attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	d := base
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
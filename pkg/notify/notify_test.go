@@ -0,0 +1,276 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpStub 最简 SMTP 服务，记录收到的收件人和邮件内容
+type smtpStub struct {
+	ln   net.Listener
+	rcpt chan string
+	data chan string
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpStub{ln: ln, rcpt: make(chan string, 1), data: make(chan string, 1)}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *smtpStub) port() int { return s.ln.Addr().(*net.TCPAddr).Port }
+
+func (s *smtpStub) serve() {
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 stub ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 stub")
+		case strings.HasPrefix(cmd, "MAIL FROM"):
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO"):
+			s.rcpt <- strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>")
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var sb strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				sb.WriteString(l)
+			}
+			s.data <- sb.String()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestEmailChannelSend(t *testing.T) {
+	stub := newSMTPStub(t)
+	ch := NewEmailChannel(EmailConfig{Host: "127.0.0.1", Port: stub.port(), From: "noreply@example.com"})
+
+	err := ch.Send(context.Background(), Message{
+		NoticeID: 1,
+		Title:    "系统维护通知",
+		Content:  "今晚 22:00 停机维护",
+		To:       Recipient{UserID: 2, Email: "user@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if got := <-stub.rcpt; got != "user@example.com" {
+		t.Errorf("收件人 = %q", got)
+	}
+	data := <-stub.data
+	if !strings.Contains(data, "Subject: =?UTF-8?b?") {
+		t.Errorf("标题未按 UTF-8 编码: %s", data)
+	}
+}
+
+func TestEmailChannelSendClosesConnOnCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	closed := make(chan struct{})
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		// 不发送问候语，模拟挂起的 SMTP 服务，客户端关闭连接时读到 EOF
+		io.Copy(io.Discard, conn)
+		close(closed)
+	}()
+
+	ch := NewEmailChannel(EmailConfig{Host: "127.0.0.1", Port: ln.Addr().(*net.TCPAddr).Port, From: "noreply@example.com"})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = ch.Send(ctx, Message{Title: "t", To: Recipient{Email: "user@example.com"}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("ctx 超时后连接应被关闭")
+	}
+}
+
+func TestEmailChannelNoAddress(t *testing.T) {
+	ch := NewEmailChannel(EmailConfig{Host: "127.0.0.1"})
+	if err := ch.Send(context.Background(), Message{}); !errors.Is(err, ErrNoAddress) {
+		t.Fatalf("err = %v, want ErrNoAddress", err)
+	}
+}
+
+func TestSMSChannelSend(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	ch := NewSMSChannel(SMSConfig{URL: srv.URL, APIKey: "key", Sign: "Vireo"})
+	err := ch.Send(context.Background(), Message{Title: "新通知", To: Recipient{Mobile: "13800000000"}})
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if got["mobile"] != "13800000000" || got["sign"] != "Vireo" || got["content"] != "新通知" {
+		t.Errorf("请求体 = %v", got)
+	}
+}
+
+func TestWebhookChannelSignature(t *testing.T) {
+	// 期望的签名密钥，系统地址用系统密钥，用户地址用用户密钥
+	want := "system-secret"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts := r.Header.Get(TimestampHeader)
+		if r.Header.Get(SignatureHeader) != Sign(want, ts, body) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if _, err := strconv.ParseInt(ts, 10, 64); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	ch := NewWebhookChannel(WebhookConfig{URL: srv.URL, Secret: "system-secret"})
+	if err := ch.Send(context.Background(), Message{NoticeID: 1, Title: "t"}); err != nil {
+		t.Fatalf("发送到系统地址失败: %v", err)
+	}
+
+	// 测试服务在本机，用户地址改用不限制地址的客户端
+	ch.userClient = srv.Client()
+	want = "user-secret"
+	msg := Message{NoticeID: 1, Title: "t", To: Recipient{WebhookURL: srv.URL, WebhookSecret: "user-secret"}}
+	if err := ch.Send(context.Background(), msg); err != nil {
+		t.Fatalf("发送到用户地址失败: %v", err)
+	}
+
+	// 未配置任何地址
+	ch = NewWebhookChannel(WebhookConfig{Secret: "system-secret"})
+	if err := ch.Send(context.Background(), Message{}); !errors.Is(err, ErrNoAddress) {
+		t.Fatalf("err = %v, want ErrNoAddress", err)
+	}
+}
+
+func TestWebhookChannelRejectsPrivateUserURL(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	ch := NewWebhookChannel(WebhookConfig{Timeout: time.Second})
+	err := ch.Send(context.Background(), Message{To: Recipient{WebhookURL: srv.URL}})
+	if !errors.Is(err, ErrForbiddenAddress) || called {
+		t.Fatalf("err = %v, called = %v, want ErrForbiddenAddress", err, called)
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	cases := []struct {
+		url string
+		ok  bool
+	}{
+		{"https://93.184.216.34/hook", true},
+		{"ftp://93.184.216.34/hook", false},
+		{"http://127.0.0.1:8080/hook", false},
+		{"http://localhost/hook", false},
+		{"http://10.0.0.1/hook", false},
+		{"http://192.168.1.10/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://[::1]/hook", false},
+		{"http://[fd00::1]/hook", false},
+		{"http://100.64.0.1/hook", false},
+		{"http://0.0.0.0/hook", false},
+	}
+	for _, tc := range cases {
+		if err := ValidateWebhookURL(context.Background(), tc.url); (err == nil) != tc.ok {
+			t.Errorf("ValidateWebhookURL(%q) = %v, want ok=%v", tc.url, err, tc.ok)
+		}
+	}
+}
+
+func TestWebhookChannelServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	ch := NewWebhookChannel(WebhookConfig{URL: srv.URL, Timeout: time.Second})
+	err := ch.Send(context.Background(), Message{})
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Fatalf("err = %v, want HTTP 502", err)
+	}
+}
+
+func TestChannelsForLevel(t *testing.T) {
+	// viper 会把 map 的 key 转为小写
+	cfg := map[string][]string{"h": {ChannelEmail, ChannelSMS}, "m": {ChannelEmail}}
+	if got := ChannelsForLevel(cfg, "H"); len(got) != 2 {
+		t.Errorf("H = %v", got)
+	}
+	if got := ChannelsForLevel(cfg, "L"); got != nil {
+		t.Errorf("L = %v", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base := time.Minute
+	if got := Backoff(base, 1); got != time.Minute {
+		t.Errorf("attempt 1 = %v", got)
+	}
+	if got := Backoff(base, 3); got != 4*time.Minute {
+		t.Errorf("attempt 3 = %v", got)
+	}
+	if got := Backoff(base, 20); got != maxBackoff {
+		t.Errorf("attempt 20 = %v", got)
+	}
+}
@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SMSConfig 通用 HTTP 短信网关配置
+// 网关接收 JSON: {"mobile": "...", "content": "...", "sign": "..."}，返回 2xx 视为成功
+type SMSConfig struct {
+	URL     string
+	APIKey  string // 以 Authorization: Bearer 方式传递
+	Sign    string // 短信签名
+	Timeout time.Duration
+}
+
+// SMSChannel 短信渠道
+type SMSChannel struct {
+	cfg    SMSConfig
+	client *http.Client
+}
+
+// NewSMSChannel 创建短信渠道
+func NewSMSChannel(cfg SMSConfig) *SMSChannel {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &SMSChannel{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+}
+
+// Name 渠道名称
+func (c *SMSChannel) Name() string { return ChannelSMS }
+
+// Send 发送短信，短信内容只包含标题
+func (c *SMSChannel) Send(ctx context.Context, msg Message) error {
+	if msg.To.Mobile == "" {
+		return ErrNoAddress
+	}
+	body, err := json.Marshal(map[string]string{
+		"mobile":  msg.To.Mobile,
+		"content": msg.Title,
+		"sign":    c.cfg.Sign,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+	return doRequest(c.client, req)
+}
+
+// doRequest 发送请求，非 2xx 返回错误
+func doRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	return nil
+}
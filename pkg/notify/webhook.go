@@ -0,0 +1,172 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// SignatureHeader Webhook 签名请求头，值为 hex(HMAC-SHA256(secret, timestamp + "." + body))
+const (
+	SignatureHeader = "X-Vireo-Signature"
+	TimestampHeader = "X-Vireo-Timestamp"
+)
+
+// ErrForbiddenAddress 用户 Webhook 地址解析到内网、回环、链路本地等非公网地址，属于不可重试错误
+var ErrForbiddenAddress = errors.New("Webhook 地址不允许指向内网地址")
+
+// WebhookConfig Webhook 配置
+type WebhookConfig struct {
+	URL     string // 系统默认地址，用户配置了自己的地址时优先使用用户地址
+	Secret  string // 系统默认地址的签名密钥；用户地址使用各自的密钥签名
+	Timeout time.Duration
+}
+
+// WebhookChannel 外发 Webhook 渠道
+type WebhookChannel struct {
+	cfg        WebhookConfig
+	client     *http.Client // 系统默认地址，由管理员配置，可指向内网
+	userClient *http.Client // 用户地址，只允许连接公网地址
+}
+
+// NewWebhookChannel 创建 Webhook 渠道
+func NewWebhookChannel(cfg WebhookConfig) *WebhookChannel {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &WebhookChannel{
+		cfg:        cfg,
+		client:     &http.Client{Timeout: cfg.Timeout},
+		userClient: newPublicClient(cfg.Timeout),
+	}
+}
+
+// Name 渠道名称
+func (c *WebhookChannel) Name() string { return ChannelWebhook }
+
+// Send 以 JSON 推送消息
+// 用户地址经过公网地址校验并以用户密钥签名，系统默认地址以系统密钥签名
+func (c *WebhookChannel) Send(ctx context.Context, msg Message) error {
+	url, secret, client := c.cfg.URL, c.cfg.Secret, c.client
+	if msg.To.WebhookURL != "" {
+		url, secret, client = msg.To.WebhookURL, msg.To.WebhookSecret, c.userClient
+	}
+	if url == "" {
+		return ErrNoAddress
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"event":   "notice",
+		"message": msg,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, ts)
+		req.Header.Set(SignatureHeader, Sign(secret, ts, body))
+	}
+	if err := doRequest(client, req); err != nil {
+		if errors.Is(err, ErrForbiddenAddress) {
+			return ErrForbiddenAddress
+		}
+		return err
+	}
+	return nil
+}
+
+// Sign 计算 Webhook 签名，接收方可用同样方法校验
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewWebhookSecret 生成用户 Webhook 签名密钥
+func NewWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ValidateWebhookURL 校验用户 Webhook 地址：http/https，主机名解析出的地址均为公网地址
+// 发送时连接阶段会再次校验，防止 DNS 解析结果在保存后变化
+func ValidateWebhookURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("地址格式无效")
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("域名解析失败")
+	}
+	for _, a := range addrs {
+		if !isPublicIP(a.IP) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+// newPublicClient 只连接公网地址的 HTTP 客户端：在建立连接时校验实际连接的 IP（含重定向），不使用环境代理
+func newPublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+	}
+}
+
+// isPublicIP 排除回环、内网、链路本地（含云厂商元数据地址 169.254.169.254）、组播和未指定地址
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		// 0.0.0.0/8、100.64.0.0/10（运营商 NAT）、198.18.0.0/15（基准测试）
+		switch {
+		case ip4[0] == 0,
+			ip4[0] == 100 && ip4[1]&0xc0 == 64,
+			ip4[0] == 198 && ip4[1]&0xfe == 18:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/zmqge/vireo-gin-admin/pkg/redis"
+)
+
+// 推送事件类型
+const (
+	EventNotice = "notice" // 新通知
+	EventUnread = "unread" // 未读数变化
+	EventRevoke = "revoke" // 通知撤回
+)
+
+// channel Redis 发布订阅频道，所有副本共享
+const channel = "push:events"
+
+// clientBuffer 每个连接的发送缓冲，写满时丢弃新消息，避免慢连接拖住推送
+const clientBuffer = 32
+
+// Event 跨副本传递的推送事件
+type Event struct {
+	Type    string          `json:"type"`
+	UserIDs []uint          `json:"userIds"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Message 下发给客户端的消息
+type Message struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// UnreadCounter 查询用户未读数，用于在新通知/撤回后同步推送未读数
+type UnreadCounter func(ctx context.Context, userID uint) (int64, error)
+
+// Client 一个已连接的客户端（SSE 或 WebSocket）
+type Client struct {
+	UserID uint
+	Send   chan Message
+}
+
+// Hub 管理本实例上的推送连接
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[uint]map[*Client]struct{}
+	counter UnreadCounter
+}
+
+// Default 全局推送中心
+var Default = NewHub()
+
+// NewHub 创建推送中心
+func NewHub() *Hub {
+	return &Hub{clients: make(map[uint]map[*Client]struct{})}
+}
+
+// SetUnreadCounter 设置未读数查询函数
+func (h *Hub) SetUnreadCounter(counter UnreadCounter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counter = counter
+}
+
+// Subscribe 注册连接
+func (h *Hub) Subscribe(userID uint) *Client {
+	c := &Client{UserID: userID, Send: make(chan Message, clientBuffer)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*Client]struct{})
+	}
+	h.clients[userID][c] = struct{}{}
+	return c
+}
+
+// Unsubscribe 注销连接
+func (h *Hub) Unsubscribe(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if set, ok := h.clients[c.UserID]; ok {
+		delete(set, c)
+		if len(set) == 0 {
+			delete(h.clients, c.UserID)
+		}
+	}
+}
+
+// Publish 发布事件：经 Redis 广播到所有副本，Redis 不可用时仅投递本实例
+func (h *Hub) Publish(ctx context.Context, eventType string, userIDs []uint, data interface{}) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	evt := Event{Type: eventType, UserIDs: userIDs, Data: raw}
+	if redis.Client == nil {
+		h.deliver(ctx, evt)
+		return nil
+	}
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	return redis.Client.Publish(ctx, channel, payload).Err()
+}
+
+// Run 订阅 Redis 频道并将事件投递给本实例的连接，ctx 取消时退出
+func (h *Hub) Run(ctx context.Context) {
+	if redis.Client == nil {
+		return
+	}
+	sub := redis.Client.Subscribe(ctx, channel)
+	defer sub.Close()
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var evt Event
+			if err := json.Unmarshal([]byte(msg.Payload), &evt); err != nil {
+				log.Printf("[Push] 解析事件失败: %v", err)
+				continue
+			}
+			h.deliver(ctx, evt)
+		}
+	}
+}
+
+// deliver 投递到本实例上的连接
+func (h *Hub) deliver(ctx context.Context, evt Event) {
+	h.mu.RLock()
+	counter := h.counter
+	var online []uint
+	for _, uid := range evt.UserIDs {
+		if len(h.clients[uid]) > 0 {
+			online = append(online, uid)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, uid := range online {
+		h.sendTo(uid, Message{Type: evt.Type, Data: evt.Data})
+		// 新通知和撤回都会改变未读数，只为在线用户查询
+		if counter != nil && (evt.Type == EventNotice || evt.Type == EventRevoke) {
+			count, err := counter(ctx, uid)
+			if err != nil {
+				log.Printf("[Push] 查询用户 %d 未读数失败: %v", uid, err)
+				continue
+			}
+			data, _ := json.Marshal(map[string]int64{"count": count})
+			h.sendTo(uid, Message{Type: EventUnread, Data: data})
+		}
+	}
+}
+
+func (h *Hub) sendTo(userID uint, msg Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.clients[userID] {
+		select {
+		case c.Send <- msg:
+		default:
+			log.Printf("[Push] 用户 %d 的连接缓冲已满，丢弃 %s 消息", userID, msg.Type)
+		}
+	}
+}
+
+// Publish 通过全局推送中心发布事件
+func Publish(ctx context.Context, eventType string, userIDs []uint, data interface{}) error {
+	return Default.Publish(ctx, eventType, userIDs, data)
+}
@@ -0,0 +1,44 @@
+package push
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHubDeliversToSubscribedUsers(t *testing.T) {
+	h := NewHub()
+	h.SetUnreadCounter(func(ctx context.Context, userID uint) (int64, error) {
+		return 3, nil
+	})
+	alice := h.Subscribe(1)
+	bob := h.Subscribe(2)
+	defer h.Unsubscribe(bob)
+
+	// Redis 未初始化时仅投递本实例
+	if err := h.Publish(context.Background(), EventNotice, []uint{1}, map[string]int{"id": 7}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	want := []string{EventNotice, EventUnread}
+	for _, typ := range want {
+		select {
+		case msg := <-alice.Send:
+			if msg.Type != typ {
+				t.Fatalf("got %s, want %s", msg.Type, typ)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for %s", typ)
+		}
+	}
+	select {
+	case msg := <-bob.Send:
+		t.Fatalf("unexpected message for other user: %+v", msg)
+	default:
+	}
+
+	h.Unsubscribe(alice)
+	if len(h.clients[1]) != 0 {
+		t.Fatalf("client not removed")
+	}
+}
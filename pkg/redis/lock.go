@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// unlockScript 仅当锁仍由自己持有时才删除，避免误删其他实例的锁
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// refreshScript 仅当锁仍由自己持有时才延长有效期
+var refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// Lock 分布式锁（多副本部署时保证同一时刻只有一个实例执行）
+type Lock struct {
+	key   string
+	token string
+}
+
+// TryLock 尝试获取分布式锁，获取失败时返回 nil, nil
+func TryLock(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	if Client == nil {
+		return nil, errors.New("Redis 客户端未初始化")
+	}
+	token := uuid.NewString()
+	ok, err := Client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &Lock{key: key, token: token}, nil
+}
+
+// Unlock 释放分布式锁
+func (l *Lock) Unlock(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	return unlockScript.Run(ctx, Client, []string{l.key}, l.token).Err()
+}
+
+// Refresh 延长锁的有效期，锁已过期或被其他实例持有时返回 false
+func (l *Lock) Refresh(ctx context.Context, ttl time.Duration) (bool, error) {
+	n, err := refreshScript.Run(ctx, Client, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// KeepAlive 每 ttl/3 续期一次，直到 ctx 结束；续期失败（锁已丢失）时调用 onLost 并退出
+// 用于执行时间可能超过 ttl 的任务，避免任务未结束锁就过期、被其他实例重复执行
+func (l *Lock) KeepAlive(ctx context.Context, ttl time.Duration, onLost func()) {
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := l.Refresh(ctx, ttl)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil || !ok {
+				onLost()
+				return
+			}
+		}
+	}
+}
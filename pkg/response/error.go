@@ -0,0 +1,61 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/i18n"
+	"gorm.io/gorm"
+)
+
+// RequestIDKey gin 上下文中保存请求 ID 的键，由 RequestID 中间件写入
+const RequestIDKey = "requestID"
+
+// RequestID 当前请求的 ID
+func RequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
+
+// Fail 渲染错误，是应用错误到 HTTP 响应的唯一映射：
+// 应用错误按目录返回业务码、HTTP 状态和翻译后的消息；记录不存在映射为 404；
+// 其他错误（数据库错误、未归类的错误）记录日志后以内部错误返回，不向客户端暴露原始信息
+func Fail(c *gin.Context, err error) {
+	e, ok := apperr.As(err)
+	if !ok {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			e = apperr.ErrNotFound.Wrap(err)
+		} else {
+			e = apperr.ErrInternal.Wrap(err)
+		}
+	}
+	requestID := RequestID(c)
+	if e.Status >= 500 {
+		log.Printf("[Error] request=%s %s %s: %v", requestID, c.Request.Method, c.Request.URL.Path, err)
+	}
+
+	locale := i18n.FromContext(c)
+	body := gin.H{"code": e.Code, "msg": localize(locale, e.Key, e.Args)}
+	if requestID != "" {
+		body["requestId"] = requestID
+	}
+	if len(e.Details) > 0 {
+		details := make([]apperr.FieldError, len(e.Details))
+		for i, d := range e.Details {
+			details[i] = apperr.FieldError{Field: d.Field, Message: i18n.T(locale, d.Message)}
+		}
+		body["details"] = details
+	}
+	c.AbortWithStatusJSON(e.Status, body)
+}
+
+// localize 先翻译消息键再填充参数
+func localize(locale, key string, args []interface{}) string {
+	msg := i18n.T(locale, key)
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
@@ -0,0 +1,71 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+	"github.com/zmqge/vireo-gin-admin/pkg/i18n"
+	"gorm.io/gorm"
+)
+
+type failBody struct {
+	Code      int                 `json:"code"`
+	Msg       string              `json:"msg"`
+	RequestID string              `json:"requestId"`
+	Details   []apperr.FieldError `json:"details"`
+}
+
+func render(t *testing.T, locale string, err error) (int, failBody) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/test", nil)
+	c.Set(RequestIDKey, "req-1")
+	c.Set(i18n.ContextKey, locale)
+	Fail(c, err)
+	var body failBody
+	if e := json.Unmarshal(w.Body.Bytes(), &body); e != nil {
+		t.Fatal(e)
+	}
+	return w.Code, body
+}
+
+func TestFail(t *testing.T) {
+	status, body := render(t, "en-US", apperr.ErrNoticeRemindTooFrequent.WithArgs(5))
+	if status != http.StatusTooManyRequests || body.Code != 14014 || body.RequestID != "req-1" {
+		t.Fatalf("应用错误渲染不正确: %d %+v", status, body)
+	}
+	if body.Msg != "Reminders are too frequent, please try again in 5 minutes" {
+		t.Fatalf("消息应先翻译再填充参数: %s", body.Msg)
+	}
+
+	_, body = render(t, "en-US", apperr.ErrValidation.WithField("name", "不能为空"))
+	if len(body.Details) != 1 || body.Details[0].Message != "is required" {
+		t.Fatalf("字段详情应翻译: %+v", body.Details)
+	}
+
+	status, body = render(t, "zh-CN", errors.New("dial tcp 10.0.0.1:3306: connection refused"))
+	if status != http.StatusInternalServerError || body.Code != apperr.ErrInternal.Code || body.Msg != "内部服务错误" {
+		t.Fatalf("内部错误应屏蔽原始信息: %d %+v", status, body)
+	}
+
+	status, body = render(t, "zh-CN", gorm.ErrRecordNotFound)
+	if status != http.StatusNotFound || body.Code != apperr.ErrNotFound.Code {
+		t.Fatalf("记录不存在应映射为 404: %d %+v", status, body)
+	}
+}
+
+// 目录中的每条错误都应有英文翻译
+func TestCatalogTranslated(t *testing.T) {
+	for _, e := range apperr.Catalog() {
+		if i18n.T("en-US", e.Key) == e.Key {
+			t.Errorf("错误 %d 缺少英文翻译: %s", e.Code, e.Key)
+		}
+	}
+}
@@ -1,48 +1,56 @@
 package response
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/pkg/dictcache"
+	"github.com/zmqge/vireo-gin-admin/pkg/i18n"
+)
 
+// 响应消息按 Locale 中间件协商的语言经消息目录翻译，未收录的消息原样返回
+
+// Success 返回成功结果，带 dict 标签的字段会补充 xxxLabel
 func Success(c *gin.Context, data interface{}, msg ...string) {
 	message := "success"
 	if len(msg) > 0 {
-		message = msg[0]
+		message = i18n.Tc(c, msg[0])
 	}
-	c.JSON(200, gin.H{"code": 0, "data": data, "msg": message})
+	c.JSON(200, gin.H{"code": 0, "data": dictcache.Translate(c, data), "msg": message})
 }
 
 func Forbidden(c *gin.Context, msg string) {
-	c.JSON(403, gin.H{"code": 403, "msg": msg})
+	c.JSON(403, gin.H{"code": 403, "msg": i18n.Tc(c, msg)})
 }
 
 func BadRequest(c *gin.Context, msg string) {
-	c.JSON(400, gin.H{"code": 400, "msg": msg})
+	c.JSON(400, gin.H{"code": 400, "msg": i18n.Tc(c, msg)})
 }
 
+// Error 返回错误，映射规则见 Fail
 func Error(c *gin.Context, err error) {
-	c.JSON(500, gin.H{"code": 500, "msg": err.Error()})
+	Fail(c, err)
 }
 
 func PageSuccess(c *gin.Context, data interface{}, total int64) {
-	c.JSON(200, gin.H{"code": 0, "data": data, "total": total})
+	c.JSON(200, gin.H{"code": 0, "data": dictcache.Translate(c, data), "total": total})
 }
 
 // Unauthorized 返回401未授权错误
 func Unauthorized(ctx *gin.Context, message string) {
 	ctx.JSON(401, gin.H{
 		"code": 401,
-		"msg":  message,
+		"msg":  i18n.Tc(ctx, message),
 	})
 }
 
 // 刷新token过期，返回402错误
 func RefresTokenExpired(c *gin.Context, msg string) {
-	c.JSON(402, gin.H{"code": 402, "msg": msg})
+	c.JSON(402, gin.H{"code": 402, "msg": i18n.Tc(c, msg)})
 }
 
 // NotFound 返回404未找到错误
 func NotFound(c *gin.Context, msg string) {
-	c.JSON(404, gin.H{"code": 404, "msg": msg})
+	c.JSON(404, gin.H{"code": 404, "msg": i18n.Tc(c, msg)})
 }
 func DemoMode(c *gin.Context, msg string) {
-	c.JSON(403, gin.H{"code": 403, "msg": msg})
+	c.JSON(403, gin.H{"code": 403, "msg": i18n.Tc(c, msg)})
 }
@@ -0,0 +1,165 @@
+package richtext
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Markdown 将常用 Markdown 语法转换为 HTML，支持：
+// 标题、段落、换行、粗体/斜体/删除线、行内代码、代码块、引用、有序/无序列表、分隔线、链接、图片
+// 输出需再经过 Sanitize 过滤（Render 已处理）
+func Markdown(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var sb strings.Builder
+	renderBlocks(&sb, lines)
+	return sb.String()
+}
+
+var (
+	mdHeading = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	mdHR      = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	mdUL      = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	mdOL      = regexp.MustCompile(`^\s*(\d+)[.)]\s+(.*)$`)
+	mdFence   = regexp.MustCompile("^\\s*(```|~~~)\\s*([\\w+-]*)\\s*$")
+)
+
+func renderBlocks(sb *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			i++
+		case mdFence.MatchString(line):
+			m := mdFence.FindStringSubmatch(line)
+			var code []string
+			i++
+			for i < len(lines) && strings.TrimSpace(lines[i]) != m[1] {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // 结束围栏
+			if m[2] != "" {
+				fmt.Fprintf(sb, `<pre><code class="language-%s">`, html.EscapeString(m[2]))
+			} else {
+				sb.WriteString("<pre><code>")
+			}
+			sb.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			sb.WriteString("</code></pre>\n")
+		case mdHeading.MatchString(trimmed):
+			m := mdHeading.FindStringSubmatch(trimmed)
+			fmt.Fprintf(sb, "<h%d>%s</h%d>\n", len(m[1]), inline(m[2]), len(m[1]))
+			i++
+		case mdHR.MatchString(line):
+			sb.WriteString("<hr>\n")
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			var quote []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quote = append(quote, strings.TrimPrefix(q, " "))
+				i++
+			}
+			sb.WriteString("<blockquote>\n")
+			renderBlocks(sb, quote)
+			sb.WriteString("</blockquote>\n")
+		case mdUL.MatchString(line):
+			sb.WriteString("<ul>\n")
+			for i < len(lines) && mdUL.MatchString(lines[i]) {
+				fmt.Fprintf(sb, "<li>%s</li>\n", inline(mdUL.FindStringSubmatch(lines[i])[1]))
+				i++
+			}
+			sb.WriteString("</ul>\n")
+		case mdOL.MatchString(line):
+			m := mdOL.FindStringSubmatch(line)
+			if m[1] != "1" {
+				fmt.Fprintf(sb, "<ol start=\"%s\">\n", m[1])
+			} else {
+				sb.WriteString("<ol>\n")
+			}
+			for i < len(lines) && mdOL.MatchString(lines[i]) {
+				fmt.Fprintf(sb, "<li>%s</li>\n", inline(mdOL.FindStringSubmatch(lines[i])[2]))
+				i++
+			}
+			sb.WriteString("</ol>\n")
+		default:
+			// 段落：连续的非空行，行尾两个空格表示换行
+			var para []string
+			for i < len(lines) && isParagraphLine(lines[i]) {
+				l := lines[i]
+				text := inline(strings.TrimSpace(l))
+				if strings.HasSuffix(l, "  ") {
+					text += "<br>"
+				}
+				para = append(para, text)
+				i++
+			}
+			sb.WriteString("<p>" + strings.Join(para, "\n") + "</p>\n")
+		}
+	}
+}
+
+func isParagraphLine(line string) bool {
+	t := strings.TrimSpace(line)
+	return t != "" && !mdFence.MatchString(line) && !mdHeading.MatchString(t) && !mdHR.MatchString(line) &&
+		!strings.HasPrefix(t, ">") && !mdUL.MatchString(line) && !mdOL.MatchString(line)
+}
+
+var (
+	mdCode     = regexp.MustCompile("`([^`]+)`")
+	mdTplVar   = regexp.MustCompile(`\{\{.*?\}\}`)
+	mdImage    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)(?:\s+"([^"]*)")?\)`)
+	mdLink     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+"([^"]*)")?\)`)
+	mdBold     = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	mdBoldU    = regexp.MustCompile(`__(.+?)__`)
+	mdItalic   = regexp.MustCompile(`(^|[^\w*])\*([^*\s][^*]*?)\*`)
+	mdItalicU  = regexp.MustCompile(`(^|[^\w_])_([^_\s][^_]*?)_([^\w_]|$)`)
+	mdStrike   = regexp.MustCompile(`~~(.+?)~~`)
+	mdAutoLink = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+)
+
+// inline 处理行内语法。行内代码和模板动作先替换为占位符，避免被其他规则改写
+func inline(text string) string {
+	var protected []string
+	protect := func(s string) string {
+		protected = append(protected, s)
+		return fmt.Sprintf("\x00%d\x00", len(protected)-1)
+	}
+	text = mdCode.ReplaceAllStringFunc(text, func(m string) string {
+		return protect("<code>" + html.EscapeString(mdCode.FindStringSubmatch(m)[1]) + "</code>")
+	})
+	text = mdTplVar.ReplaceAllStringFunc(text, protect)
+	text = mdAutoLink.ReplaceAllStringFunc(text, func(m string) string {
+		u := mdAutoLink.FindStringSubmatch(m)[1]
+		return protect(`<a href="` + html.EscapeString(u) + `">` + html.EscapeString(u) + `</a>`)
+	})
+
+	text = escapeText(text)
+	text = mdImage.ReplaceAllStringFunc(text, func(m string) string {
+		g := mdImage.FindStringSubmatch(m)
+		return protect(`<img src="` + g[2] + `" alt="` + g[1] + `"` + titleAttr(g[3]) + `>`)
+	})
+	text = mdLink.ReplaceAllStringFunc(text, func(m string) string {
+		g := mdLink.FindStringSubmatch(m)
+		return `<a href="` + g[2] + `"` + titleAttr(g[3]) + `>` + g[1] + `</a>`
+	})
+	text = mdBold.ReplaceAllString(text, "<strong>$1</strong>")
+	text = mdBoldU.ReplaceAllString(text, "<strong>$1</strong>")
+	text = mdItalic.ReplaceAllString(text, "$1<em>$2</em>")
+	text = mdItalicU.ReplaceAllString(text, "$1<em>$2</em>$3")
+	text = mdStrike.ReplaceAllString(text, "<del>$1</del>")
+
+	for i := len(protected) - 1; i >= 0; i-- {
+		text = strings.ReplaceAll(text, fmt.Sprintf("\x00%d\x00", i), protected[i])
+	}
+	return text
+}
+
+func titleAttr(title string) string {
+	if title == "" {
+		return ""
+	}
+	return ` title="` + title + `"`
+}
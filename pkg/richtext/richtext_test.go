@@ -0,0 +1,64 @@
+package richtext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	cases := []struct {
+		name, in, want string
+	}{
+		{"keeps allowed", `<p>你好<strong>世界</strong></p>`, `<p>你好<strong>世界</strong></p>`},
+		{"drops script", `<p>a<script>alert(1)</script>b</p>`, `<p>ab</p>`},
+		{"drops event attr", `<img src="/a.png" onerror="alert(1)">`, `<img src="/a.png">`},
+		{"drops js url", `<a href="javascript:alert(1)">x</a>`, `<a rel="noopener noreferrer">x</a>`},
+		{"drops style attr", `<p style="background:url(x)">x</p>`, `<p>x</p>`},
+		{"unknown tag keeps text", `<marquee>hi</marquee>`, `hi`},
+		{"closes unclosed", `<p><em>x`, `<p><em>x</em></p>`},
+		{"ignores stray end", `x</div>`, `x`},
+		{"escapes text", `a &lt; b`, `a &lt; b`},
+		{"keeps template action", `<p>{{.Now.Format "2006-01-02"}}</p>`, `<p>{{.Now.Format "2006-01-02"}}</p>`},
+		{"drops template action in attr", `<a href="{{/**/}}javascript:alert(1)" title="{{.User.Nickname}}">x</a>`, `<a rel="noopener noreferrer">x</a>`},
+		{"drops svg data image", `<img src="data:image/svg+xml;base64,xx">`, `<img>`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Sanitize(tc.in); got != tc.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	src := "# 维护通知\n\n今晚 **22:00** 停机，详见[公告](https://example.com \"说明\")。\n\n- 数据库\n- 缓存\n\n```go\nfmt.Println(\"<b>\")\n```\n\n> 请提前保存\n\n![图](/api/v1/notices/attachments/1/download)"
+	got := Markdown(src)
+	for _, want := range []string{
+		"<h1>维护通知</h1>",
+		"<strong>22:00</strong>",
+		`<a href="https://example.com" title="说明">公告</a>`,
+		"<ul>\n<li>数据库</li>\n<li>缓存</li>\n</ul>",
+		`<pre><code class="language-go">fmt.Println(&#34;&lt;b&gt;&#34;)</code></pre>`,
+		"<blockquote>\n<p>请提前保存</p>\n</blockquote>",
+		`<img src="/api/v1/notices/attachments/1/download" alt="图">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestMarkdownKeepsTemplateActions(t *testing.T) {
+	got := Markdown("{{.User.Nickname}} 您好，请于 {{.Vars.start_date}} 前完成")
+	if !strings.Contains(got, "{{.Vars.start_date}}") {
+		t.Errorf("模板动作被改写: %s", got)
+	}
+}
+
+func TestRenderMarkdownSanitized(t *testing.T) {
+	got := Render(FormatMarkdown, "[x](javascript:alert(1)) <script>alert(1)</script>")
+	if strings.Contains(got, "javascript:") || strings.Contains(got, "<script>") {
+		t.Errorf("未过滤危险内容: %s", got)
+	}
+}
@@ -0,0 +1,184 @@
+// Package richtext 富文本处理：HTML 白名单过滤与 Markdown 渲染
+package richtext
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// 内容格式
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+)
+
+// allowedTags 允许的标签及其允许的属性
+var allowedTags = map[string]map[string]bool{
+	"p": nil, "br": nil, "hr": nil, "div": nil, "span": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil, "del": nil, "sub": nil, "sup": nil,
+	"blockquote": nil, "pre": nil, "code": nil,
+	"ul": nil, "ol": {"start": true}, "li": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil,
+	"th": {"colspan": true, "rowspan": true}, "td": {"colspan": true, "rowspan": true},
+	"a":   {"href": true, "title": true, "target": true},
+	"img": {"src": true, "alt": true, "title": true, "width": true, "height": true},
+}
+
+// globalAttrs 所有允许标签通用的属性（style 可能注入脚本，不允许）
+var globalAttrs = map[string]bool{"class": true}
+
+// urlAttrs 需要校验协议的属性
+var urlAttrs = map[string]bool{"href": true, "src": true}
+
+// dropWithContent 连同内容一起删除的标签
+var dropWithContent = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "textarea": true, "select": true, "title": true,
+}
+
+// voidTags 自闭合标签
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// Sanitize 按白名单过滤 HTML，去除脚本、事件属性和危险链接
+// 文本中的模板动作（{{...}}）原样保留，供通知模板使用；属性中不允许模板动作，
+// 否则 {{/**/}} 之类的注释可在渲染后拼出危险链接
+func Sanitize(input string) string {
+	var sb strings.Builder
+	z := html.NewTokenizer(strings.NewReader(input))
+	var open []string // 已输出且未闭合的标签
+	skipDepth := 0    // 处于需整体删除的标签内部
+	skipTag := ""
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break // io.EOF 或解析错误，已输出的部分保留
+		}
+		tok := z.Token()
+		name := tok.Data
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if skipDepth > 0 {
+				if name == skipTag && tt == html.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+			if dropWithContent[name] {
+				if tt == html.StartTagToken {
+					skipDepth, skipTag = 1, name
+				}
+				continue
+			}
+			attrs, ok := allowedTags[name]
+			if !ok {
+				continue // 未知标签去掉标签本身，保留内容
+			}
+			sb.WriteString("<" + name)
+			for _, a := range tok.Attr {
+				key := strings.ToLower(a.Key)
+				if !globalAttrs[key] && !attrs[key] {
+					continue
+				}
+				val := a.Val
+				if strings.Contains(val, "{{") {
+					continue
+				}
+				if urlAttrs[key] {
+					safe, ok := safeURL(val, name == "img")
+					if !ok {
+						continue
+					}
+					val = safe
+				}
+				if key == "target" && val != "_blank" {
+					continue
+				}
+				sb.WriteString(" " + key + `="` + escapeAttr(val) + `"`)
+			}
+			if name == "a" {
+				sb.WriteString(` rel="noopener noreferrer"`)
+			}
+			sb.WriteString(">")
+			if !voidTags[name] && tt == html.StartTagToken {
+				open = append(open, name)
+			}
+		case html.EndTagToken:
+			if skipDepth > 0 {
+				if name == skipTag {
+					skipDepth--
+				}
+				continue
+			}
+			// 只闭合已打开的标签，避免输出不配对的结束标签
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == name {
+					for j := len(open) - 1; j >= i; j-- {
+						sb.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+		case html.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+			sb.WriteString(escapeText(tok.Data))
+		}
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		sb.WriteString("</" + open[i] + ">")
+	}
+	return sb.String()
+}
+
+// safeURL 仅允许 http/https/mailto 和站内相对地址，图片额外允许 data:image
+func safeURL(raw string, image bool) (string, bool) {
+	v := strings.TrimSpace(raw)
+	if v == "" {
+		return "", false
+	}
+	lower := strings.ToLower(v)
+	if image && strings.HasPrefix(lower, "data:image/") && !strings.HasPrefix(lower, "data:image/svg") {
+		return v, true
+	}
+	u, err := url.Parse(v)
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return v, true
+	case "mailto":
+		return v, !image
+	case "":
+		// 相对地址，排除 //host 形式以外的协议混淆
+		return v, !strings.Contains(strings.SplitN(v, "?", 2)[0], ":")
+	}
+	return "", false
+}
+
+// escapeText 文本只转义 & < >，保留引号以免破坏模板动作
+func escapeText(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func escapeAttr(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&#34;").Replace(s)
+}
+
+// Render 将内容按格式渲染为安全的 HTML
+func Render(format, source string) string {
+	if format == FormatMarkdown {
+		return Sanitize(Markdown(source))
+	}
+	return Sanitize(source)
+}
+
+// ValidFormat 格式是否受支持
+func ValidFormat(format string) bool {
+	return format == FormatHTML || format == FormatMarkdown
+}
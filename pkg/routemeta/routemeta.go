@@ -0,0 +1,86 @@
+// Package routemeta 保存由 cmd/routegen 生成的路由元数据，运行时可查询每个接口需要的权限
+package routemeta
+
+import (
+	"sort"
+	"sync"
+)
+
+// Route 路由元数据
+type Route struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`                  // 完整路径，如 /api/v1/users/:id
+	Permission  string   `json:"permission,omitempty"`  // RBAC 权限码，为空表示不校验权限
+	Group       string   `json:"group,omitempty"`       // @Group 的 name
+	Handler     string   `json:"handler"`               // 控制器.方法，如 UserController.GetUser
+	Middlewares []string `json:"middlewares,omitempty"` // 分组和路由上声明的中间件（不含按权限生成的 RBAC）
+}
+
+var (
+	mu     sync.RWMutex
+	routes = make(map[string]Route) // METHOD path -> 路由
+)
+
+func key(method, path string) string {
+	return method + " " + path
+}
+
+// Register 注册路由元数据，同一方法和路径重复注册时以后者为准
+func Register(list ...Route) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, r := range list {
+		routes[key(r.Method, r.Path)] = r
+	}
+}
+
+// Reset 清空已注册的元数据，供测试使用
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	routes = make(map[string]Route)
+}
+
+// All 全部路由，按路径和方法排序
+func All() []Route {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]Route, 0, len(routes))
+	for _, r := range routes {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Path != list[j].Path {
+			return list[i].Path < list[j].Path
+		}
+		return list[i].Method < list[j].Method
+	})
+	return list
+}
+
+// Permissions 权限码 -> 使用该权限的路由（METHOD path），不含无需权限的路由
+func Permissions() map[string][]string {
+	perms := make(map[string][]string)
+	for _, r := range All() {
+		if r.Permission != "" {
+			perms[r.Permission] = append(perms[r.Permission], key(r.Method, r.Path))
+		}
+	}
+	return perms
+}
+
+// Missing 路由引用但不在 existing 中的权限码，按权限码排序
+func Missing(existing []string) []string {
+	known := make(map[string]bool, len(existing))
+	for _, code := range existing {
+		known[code] = true
+	}
+	var missing []string
+	for code := range Permissions() {
+		if !known[code] {
+			missing = append(missing, code)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
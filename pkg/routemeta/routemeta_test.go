@@ -0,0 +1,28 @@
+package routemeta
+
+import "testing"
+
+func TestRegister(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	Register(
+		Route{Method: "GET", Path: "/api/v1/users/:id", Permission: "sys:user:view", Handler: "UserController.GetUser"},
+		Route{Method: "DELETE", Path: "/api/v1/users/:id", Permission: "sys:user:delete", Handler: "UserController.DeleteUser"},
+		Route{Method: "POST", Path: "/api/v1/auth/login", Handler: "AuthController.Login"},
+	)
+	// 重复注册以后者为准
+	Register(Route{Method: "GET", Path: "/api/v1/users/:id", Permission: "sys:user:query", Handler: "UserController.GetUser"})
+
+	all := All()
+	if len(all) != 3 || all[0].Path != "/api/v1/auth/login" || all[1].Method != "DELETE" {
+		t.Fatalf("排序或去重不正确: %+v", all)
+	}
+	perms := Permissions()
+	if len(perms) != 2 || perms["sys:user:query"][0] != "GET /api/v1/users/:id" {
+		t.Errorf("权限映射不正确: %v", perms)
+	}
+	if missing := Missing([]string{"sys:user:query", "sys:role:query"}); len(missing) != 1 || missing[0] != "sys:user:delete" {
+		t.Errorf("缺失的权限不正确: %v", missing)
+	}
+}
@@ -63,6 +63,28 @@ func DataPermissionScope(ctx *gin.Context) func(db *gorm.DB) *gorm.DB {
 	}
 }
 
+// DeptInScope 部门是否在当前用户的数据权限范围内，判断与 DataPermissionScope 一致
+// 未使用 dataperm 中间件、超级管理员或拥有全部数据权限时不限制；仅本人数据权限的用户不能指定任何部门
+func DeptInScope(ctx *gin.Context, deptID uint) bool {
+	if !ctx.GetBool("dataPermEnabled") {
+		return true
+	}
+	user, err := getCurrentUser(ctx)
+	if err != nil {
+		log.Printf("[DeptInScope] 获取用户失败: %v", err)
+		return false
+	}
+	if isAdmin(user) || user.DataScope == 1 {
+		return true
+	}
+	for _, id := range user.PermissionDepts {
+		if id == deptID {
+			return true
+		}
+	}
+	return false
+}
+
 // getCurrentUser 从上下文中获取用户信息
 func getCurrentUser(ctx *gin.Context) (*models.User, error) {
 	user, exists := ctx.Get("currentUser")
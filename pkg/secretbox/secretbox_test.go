@@ -0,0 +1,32 @@
+package secretbox
+
+import "testing"
+
+func TestEncryptDecrypt(t *testing.T) {
+	box, err := New("test-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := box.Encrypt("p@ssw0rd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(enc) || enc == "p@ssw0rd" {
+		t.Fatalf("密文格式不正确: %s", enc)
+	}
+	if again, _ := box.Encrypt("p@ssw0rd"); again == enc {
+		t.Fatal("相同明文应生成不同密文")
+	}
+	plain, err := box.Decrypt(enc)
+	if err != nil || plain != "p@ssw0rd" {
+		t.Fatalf("Decrypt = %q, %v", plain, err)
+	}
+
+	other, _ := New("other-secret")
+	if _, err := other.Decrypt(enc); err != ErrDecrypt {
+		t.Fatalf("错误密钥应解密失败，得到 %v", err)
+	}
+	if plain, _ := box.Decrypt("legacy"); plain != "legacy" {
+		t.Fatal("未加密的值应原样返回")
+	}
+}
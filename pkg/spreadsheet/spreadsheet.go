@@ -0,0 +1,405 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 支持的文件格式
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// 读取限制，防止小文件通过稀疏的行号、列号或高压缩比占用大量内存
+const (
+	MaxRows     = 10000    // 最大行数（含表头），xlsx 的行号也不能超过该值
+	MaxColumns  = 256      // 最大列数，xlsx 的列号也不能超过该值
+	maxFileSize = 20 << 20 // 上传文件大小上限
+	maxPartSize = 50 << 20 // xlsx 中单个 XML 解压后的大小上限
+)
+
+// ErrUnsupportedFormat 不支持的文件格式
+var ErrUnsupportedFormat = errors.New("仅支持 xlsx 或 csv 文件")
+
+// ErrTooLarge 表格超出读取限制
+var ErrTooLarge = fmt.Errorf("表格过大，最多 %d 行、%d 列，文件不超过 %d MB", MaxRows, MaxColumns, maxFileSize>>20)
+
+// FormatOf 根据文件名判断表格格式
+func FormatOf(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// ReadRows 读取表格的全部行（xlsx 只读取第一个工作表），超出 MaxRows、MaxColumns 或文件大小上限时返回 ErrTooLarge
+func ReadRows(format string, r io.Reader) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		data, err := readLimited(r)
+		if err != nil {
+			return nil, err
+		}
+		return readCSV(data)
+	case FormatXLSX:
+		data, err := readLimited(r)
+		if err != nil {
+			return nil, err
+		}
+		return readXLSX(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// WriteRows 按指定格式写出表格
+func WriteRows(format string, w io.Writer, sheetName string, rows [][]string) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, rows)
+	case FormatXLSX:
+		return writeXLSX(w, sheetName, rows)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// ContentType 返回格式对应的 MIME 类型
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// readLimited 读取上传文件，超过大小上限时返回 ErrTooLarge
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxFileSize {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	// 兼容 Excel 导出的带 BOM 的 UTF-8 文件
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("解析CSV失败: %w", err)
+	}
+	if len(rows) > MaxRows {
+		return nil, ErrTooLarge
+	}
+	for _, row := range rows {
+		if len(row) > MaxColumns {
+			return nil, ErrTooLarge
+		}
+	}
+	return rows, nil
+}
+
+func writeCSV(w io.Writer, rows [][]string) error {
+	// 写入 BOM，避免 Excel 打开时中文乱码
+	if _, err := w.Write([]byte("\xef\xbb\xbf")); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+const (
+	nsRelationships = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+)
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxRichText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (rt xlsxRichText) String() string {
+	if len(rt.R) == 0 {
+		return rt.T
+	}
+	var sb strings.Builder
+	for _, r := range rt.R {
+		sb.WriteString(r.T)
+	}
+	return sb.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R  string        `xml:"r,attr"`
+			T  string        `xml:"t,attr"`
+			V  string        `xml:"v"`
+			Is *xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("解析xlsx失败: %w", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeZipXML(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, errors.New("xlsx中没有工作表")
+	}
+	var rels xlsxRelationships
+	if err := decodeZipXML(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	sheetPath := ""
+	for _, rel := range rels.Items {
+		if rel.ID == workbook.Sheets[0].RID {
+			sheetPath = rel.Target
+			break
+		}
+	}
+	if sheetPath == "" {
+		return nil, errors.New("xlsx中未找到第一个工作表")
+	}
+	if strings.HasPrefix(sheetPath, "/") {
+		sheetPath = strings.TrimPrefix(sheetPath, "/")
+	} else {
+		sheetPath = path.Join("xl", sheetPath)
+	}
+
+	var shared xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(files, "xl/sharedStrings.xml", &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	var sheet xlsxWorksheet
+	if err := decodeZipXML(files, sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for i, row := range sheet.Rows {
+		rowIndex := row.R - 1
+		if row.R == 0 {
+			rowIndex = i
+		}
+		if rowIndex < 0 || rowIndex >= MaxRows || len(rows) >= MaxRows {
+			return nil, ErrTooLarge
+		}
+		for len(rows) < rowIndex {
+			rows = append(rows, nil)
+		}
+		var values []string
+		for j, cell := range row.Cells {
+			col := j
+			if cell.R != "" {
+				if c, err := columnIndex(cell.R); err == nil {
+					col = c
+				}
+			}
+			if col >= MaxColumns || len(values) >= MaxColumns {
+				return nil, ErrTooLarge
+			}
+			for len(values) < col {
+				values = append(values, "")
+			}
+			values = append(values, cellValue(cell.T, cell.V, cell.Is, shared))
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+func cellValue(typ, v string, is *xlsxRichText, shared xlsxSharedStrings) string {
+	switch typ {
+	case "s":
+		idx, err := strconv.Atoi(v)
+		if err != nil || idx < 0 || idx >= len(shared.Items) {
+			return ""
+		}
+		return shared.Items[idx].String()
+	case "inlineStr":
+		if is == nil {
+			return ""
+		}
+		return is.String()
+	case "b":
+		if v == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return v
+	}
+}
+
+// columnIndex 将单元格引用（如 "AB12"）转换为从0开始的列号，列名最多 3 个字母
+func columnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, ch := range ref {
+		if ch >= 'a' && ch <= 'z' {
+			ch -= 'a' - 'A'
+		}
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A'+1)
+		n++
+		if n > 3 {
+			return 0, fmt.Errorf("无效的单元格引用: %s", ref) // 超过 XFD 的列
+		}
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("无效的单元格引用: %s", ref)
+	}
+	return col - 1, nil
+}
+
+// columnName 将从0开始的列号转换为列名（如 27 -> "AB"）
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func decodeZipXML(files map[string]*zip.File, name string, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("xlsx缺少 %s", name)
+	}
+	// 先按声明的解压大小拒绝，实际读取时再限制，声明值可以伪造
+	if f.UncompressedSize64 > maxPartSize {
+		return ErrTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	lr := &io.LimitedReader{R: rc, N: maxPartSize + 1}
+	if err := xml.NewDecoder(lr).Decode(v); err != nil {
+		if lr.N <= 0 {
+			return ErrTooLarge
+		}
+		return fmt.Errorf("解析 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+func writeXLSX(w io.Writer, sheetName string, rows [][]string) error {
+	if sheetName == "" {
+		sheetName = "Sheet1"
+	}
+	var sheetName_ bytes.Buffer
+	if err := xml.EscapeText(&sheetName_, []byte(sheetName)); err != nil {
+		return err
+	}
+
+	var sheet bytes.Buffer
+	sheet.WriteString(xml.Header)
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%d">`, i+1)
+		for j, value := range row {
+			fmt.Fprintf(&sheet, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(j), i+1)
+			if err := xml.EscapeText(&sheet, []byte(value)); err != nil {
+				return err
+			}
+			sheet.WriteString(`</t></is></c>`)
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + nsRelationships + `/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="` + nsRelationships + `">` +
+			`<sheets><sheet name="` + sheetName_.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + nsRelationships + `/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`},
+		{"xl/worksheets/sheet1.xml", sheet.String()},
+	}
+
+	zw := zip.NewWriter(w)
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
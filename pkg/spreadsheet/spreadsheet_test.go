@@ -0,0 +1,97 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestXLSXRoundTrip(t *testing.T) {
+	rows := [][]string{
+		{"用户名", "昵称", "角色编码"},
+		{"zhangsan", "张三 & <李四>", "admin,guest"},
+		{"", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "AA"},
+	}
+	var buf bytes.Buffer
+	if err := WriteRows(FormatXLSX, &buf, "Sheet1", rows); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := ReadRows(FormatXLSX, &buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(got) != len(rows) {
+		t.Fatalf("rows = %d, want %d", len(got), len(rows))
+	}
+	for i := range rows {
+		if strings.Join(got[i], "|") != strings.Join(rows[i], "|") {
+			t.Errorf("row %d = %q, want %q", i, got[i], rows[i])
+		}
+	}
+}
+
+func TestReadCSVWithBOM(t *testing.T) {
+	got, err := ReadRows(FormatCSV, strings.NewReader("\xef\xbb\xbfusername,nickname\nlisi,李四\n"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if got[0][0] != "username" || got[1][1] != "李四" {
+		t.Errorf("unexpected rows: %q", got)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for index, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(index); got != want {
+			t.Errorf("columnName(%d) = %s, want %s", index, got, want)
+		}
+		if got, _ := columnIndex(want + "12"); got != index {
+			t.Errorf("columnIndex(%s) = %d, want %d", want, got, index)
+		}
+	}
+}
+
+// sheetXLSX 用给定的 sheetData 内容构造最小的 xlsx
+func sheetXLSX(t *testing.T, sheetData string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := WriteRows(FormatXLSX, &buf, "Sheet1", nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for _, f := range zr.File {
+		w, _ := zw.Create(f.Name)
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			io.WriteString(w, `<worksheet><sheetData>`+sheetData+`</sheetData></worksheet>`)
+			continue
+		}
+		rc, _ := f.Open()
+		io.Copy(w, rc)
+		rc.Close()
+	}
+	zw.Close()
+	return out.Bytes()
+}
+
+func TestReadXLSXLimits(t *testing.T) {
+	cases := map[string]string{
+		"row index":    `<row r="1048576"><c r="A1048576" t="inlineStr"><is><t>x</t></is></c></row>`,
+		"column index": `<row r="1"><c r="XFD1" t="inlineStr"><is><t>x</t></is></c></row>`,
+		"row count":    strings.Repeat(`<row r="1"><c><v>1</v></c></row>`, MaxRows+1),
+	}
+	for name, sheetData := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadRows(FormatXLSX, bytes.NewReader(sheetXLSX(t, sheetData))); !errors.Is(err, ErrTooLarge) {
+				t.Errorf("err = %v, want ErrTooLarge", err)
+			}
+		})
+	}
+}
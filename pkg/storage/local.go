@@ -0,0 +1,104 @@
+// Package storage 上传文件存储
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidKey 非法的存储键（如包含 ..）
+var ErrInvalidKey = errors.New("非法的文件路径")
+
+// Storage 文件存储接口，key 为存储内的相对路径
+type Storage interface {
+	Save(r io.Reader, ext string) (key string, size int64, err error)
+	Open(key string) (io.ReadSeekCloser, error)
+	Delete(key string) error
+}
+
+// LocalStorage 本地磁盘存储，按日期分目录，文件名使用 UUID
+type LocalStorage struct {
+	root    string
+	maxSize int64
+}
+
+// NewLocalStorage 创建本地存储，maxSize<=0 表示不限制大小
+func NewLocalStorage(root string, maxSize int64) *LocalStorage {
+	return &LocalStorage{root: root, maxSize: maxSize}
+}
+
+// ErrTooLarge 文件超过大小限制
+type ErrTooLarge struct {
+	Max int64
+}
+
+func (e *ErrTooLarge) Error() string {
+	return fmt.Sprintf("文件大小不能超过 %d MB", e.Max>>20)
+}
+
+// Save 保存文件，超过大小限制时删除已写入的部分
+func (s *LocalStorage) Save(r io.Reader, ext string) (string, int64, error) {
+	key := filepath.ToSlash(filepath.Join(time.Now().Format("2006/01/02"), uuid.NewString()+strings.ToLower(ext)))
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", 0, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	src := r
+	if s.maxSize > 0 {
+		src = io.LimitReader(r, s.maxSize+1)
+	}
+	size, err := io.Copy(f, src)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && s.maxSize > 0 && size > s.maxSize {
+		err = &ErrTooLarge{Max: s.maxSize}
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", 0, err
+	}
+	return key, size, nil
+}
+
+// Open 打开文件
+func (s *LocalStorage) Open(key string) (io.ReadSeekCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Delete 删除文件，文件不存在时不报错
+func (s *LocalStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path 将存储键转换为磁盘路径，拒绝跳出根目录的键
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.root, clean), nil
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorageSaveOpenDelete(t *testing.T) {
+	s := NewLocalStorage(t.TempDir(), 1<<20)
+	key, size, err := s.Save(strings.NewReader("hello"), ".TXT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 5 || !strings.HasSuffix(key, ".txt") {
+		t.Fatalf("key=%s size=%d", key, size)
+	}
+	f, err := s.Open(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(f)
+	f.Close()
+	if string(data) != "hello" {
+		t.Errorf("content = %q", data)
+	}
+	if err := s.Delete(key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Open(key); err == nil {
+		t.Error("文件删除后仍可打开")
+	}
+}
+
+func TestLocalStorageTooLarge(t *testing.T) {
+	s := NewLocalStorage(t.TempDir(), 4)
+	_, _, err := s.Save(strings.NewReader("hello"), ".txt")
+	var tooLarge *ErrTooLarge
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("err = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestLocalStorageRejectsTraversal(t *testing.T) {
+	s := NewLocalStorage(t.TempDir(), 0)
+	for _, key := range []string{"../etc/passwd", "/etc/passwd", "a/../../b", ""} {
+		if _, err := s.Open(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Open(%q) err = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package sysconfig
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+)
+
+// 内置的系统配置键，未配置时沿用配置文件或默认值
+const (
+	KeyDemoMode       = "sys.demo_mode"          // 演示模式，覆盖 DEMO_MODE
+	KeyCaptchaEnabled = "sys.captcha.enabled"    // 登录是否需要验证码
+	KeyPasswordPolicy = "sys.password.policy"    // 密码策略（JSON）
+	KeyInitPassword   = "sys.user.init_password" // 新增、导入用户未填写密码时的初始密码
+)
+
+// DefaultInitPassword 未配置 sys.user.init_password 时的初始密码
+const DefaultInitPassword = "123456"
+
+// PasswordPolicy 密码策略
+type PasswordPolicy struct {
+	MinLength      int  `json:"minLength"`
+	RequireDigit   bool `json:"requireDigit"`
+	RequireLetter  bool `json:"requireLetter"`
+	RequireUpper   bool `json:"requireUpper"`
+	RequireSpecial bool `json:"requireSpecial"`
+}
+
+// DefaultPasswordPolicy 未配置时的密码策略
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: 6}
+
+// Validate 校验密码是否符合策略
+func (p PasswordPolicy) Validate(password string) error {
+	if len([]rune(password)) < p.MinLength {
+		return apperr.ErrPasswordTooShort.WithArgs(p.MinLength)
+	}
+	var digit, letter, upper, special bool
+	for _, r := range password {
+		switch {
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsLetter(r):
+			letter = true
+			upper = upper || unicode.IsUpper(r)
+		case !unicode.IsSpace(r):
+			special = true
+		}
+	}
+	var missing []string
+	if p.RequireDigit && !digit {
+		missing = append(missing, "数字")
+	}
+	if p.RequireLetter && !letter {
+		missing = append(missing, "字母")
+	}
+	if p.RequireUpper && !upper {
+		missing = append(missing, "大写字母")
+	}
+	if p.RequireSpecial && !special {
+		missing = append(missing, "特殊字符")
+	}
+	if len(missing) > 0 {
+		return apperr.ErrPasswordWeak.WithArgs(strings.Join(missing, "、"))
+	}
+	return nil
+}
+
+// GetInitPassword 新增、导入用户时的初始密码，需符合当前密码策略，否则返回 ErrInitPasswordWeak
+func GetInitPassword() (string, error) {
+	password := GetString(KeyInitPassword, DefaultInitPassword)
+	if err := GetPasswordPolicy().Validate(password); err != nil {
+		return "", apperr.ErrInitPasswordWeak
+	}
+	return password, nil
+}
+
+// GetPasswordPolicy 当前密码策略，配置无效时使用默认策略
+func GetPasswordPolicy() PasswordPolicy {
+	policy := DefaultPasswordPolicy
+	if ok, err := GetJSON(KeyPasswordPolicy, &policy); err != nil || !ok {
+		return DefaultPasswordPolicy
+	}
+	return policy
+}
@@ -0,0 +1,238 @@
+package sysconfig
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zmqge/vireo-gin-admin/pkg/redis"
+)
+
+// channel 配置变更通知频道，所有副本订阅后重新加载
+const channel = "sysconfig:reload"
+
+// Loader 从数据源加载全部配置（键 -> 值）
+type Loader func(ctx context.Context) (map[string]string, error)
+
+// Watcher 配置变更回调；删除时 deleted 为 true
+type Watcher func(key, value string, deleted bool)
+
+// Store 系统配置的内存快照，支持热更新和变更订阅
+type Store struct {
+	mu       sync.RWMutex
+	values   map[string]string
+	loader   Loader
+	watchers map[string][]Watcher // 空键表示订阅全部
+}
+
+// Default 全局系统配置
+var Default = NewStore(nil)
+
+// NewStore 创建系统配置
+func NewStore(loader Loader) *Store {
+	return &Store{
+		values:   make(map[string]string),
+		loader:   loader,
+		watchers: make(map[string][]Watcher),
+	}
+}
+
+// SetLoader 设置配置加载函数
+func (s *Store) SetLoader(loader Loader) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loader = loader
+}
+
+// Load 重新加载全部配置并通知变更
+func (s *Store) Load(ctx context.Context) error {
+	s.mu.RLock()
+	loader := s.loader
+	s.mu.RUnlock()
+	if loader == nil {
+		return nil
+	}
+	values, err := loader(ctx)
+	if err != nil {
+		return err
+	}
+	s.Replace(values)
+	return nil
+}
+
+// Replace 替换全部配置，对新增、修改、删除的键通知订阅者
+func (s *Store) Replace(values map[string]string) {
+	next := make(map[string]string, len(values))
+	for k, v := range values {
+		next[k] = v
+	}
+
+	s.mu.Lock()
+	prev := s.values
+	s.values = next
+	type change struct {
+		key, value string
+		deleted    bool
+	}
+	var changes []change
+	for k, v := range next {
+		if old, ok := prev[k]; !ok || old != v {
+			changes = append(changes, change{key: k, value: v})
+		}
+	}
+	for k := range prev {
+		if _, ok := next[k]; !ok {
+			changes = append(changes, change{key: k, deleted: true})
+		}
+	}
+	s.mu.Unlock()
+
+	for _, c := range changes {
+		for _, w := range s.watchersOf(c.key) {
+			w(c.key, c.value, c.deleted)
+		}
+	}
+}
+
+// Watch 订阅指定键的变更，key 为空时订阅全部
+func (s *Store) Watch(key string, w Watcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchers[key] = append(s.watchers[key], w)
+}
+
+func (s *Store) watchersOf(key string) []Watcher {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]Watcher, 0, len(s.watchers[key])+len(s.watchers[""]))
+	list = append(list, s.watchers[key]...)
+	return append(list, s.watchers[""]...)
+}
+
+// Lookup 获取原始值
+func (s *Store) Lookup(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// GetString 获取字符串，不存在时返回默认值
+func (s *Store) GetString(key, def string) string {
+	if v, ok := s.Lookup(key); ok {
+		return v
+	}
+	return def
+}
+
+// GetBool 获取布尔值（true/false/1/0/on/off/yes/no），无法解析时返回默认值
+func (s *Store) GetBool(key string, def bool) bool {
+	v, ok := s.Lookup(key)
+	if !ok {
+		return def
+	}
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "1", "true", "on", "yes", "y":
+		return true
+	case "0", "false", "off", "no", "n":
+		return false
+	}
+	return def
+}
+
+// GetInt 获取整数，无法解析时返回默认值
+func (s *Store) GetInt(key string, def int) int {
+	v, ok := s.Lookup(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// GetDuration 获取时长（如 30s、5m），纯数字按秒处理，无法解析时返回默认值
+func (s *Store) GetDuration(key string, def time.Duration) time.Duration {
+	v, ok := s.Lookup(key)
+	if !ok {
+		return def
+	}
+	v = strings.TrimSpace(v)
+	if n, err := strconv.Atoi(v); err == nil {
+		return time.Duration(n) * time.Second
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return def
+	}
+	return d
+}
+
+// GetJSON 将 JSON 值解码到 out，键不存在时返回 false
+func (s *Store) GetJSON(key string, out interface{}) (bool, error) {
+	v, ok := s.Lookup(key)
+	if !ok || strings.TrimSpace(v) == "" {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(v), out); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Notify 通知所有副本重新加载配置，Redis 不可用时仅重新加载本实例
+func (s *Store) Notify(ctx context.Context) error {
+	if redis.Client == nil {
+		return s.Load(ctx)
+	}
+	return redis.Client.Publish(ctx, channel, "reload").Err()
+}
+
+// Run 订阅配置变更频道，收到通知时重新加载，ctx 取消时退出
+func (s *Store) Run(ctx context.Context) {
+	if redis.Client == nil {
+		return
+	}
+	sub := redis.Client.Subscribe(ctx, channel)
+	defer sub.Close()
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := s.Load(ctx); err != nil {
+				log.Printf("[SysConfig] 重新加载配置失败: %v", err)
+			}
+		}
+	}
+}
+
+// GetString 读取全局系统配置
+func GetString(key, def string) string { return Default.GetString(key, def) }
+
+// GetBool 读取全局系统配置
+func GetBool(key string, def bool) bool { return Default.GetBool(key, def) }
+
+// GetInt 读取全局系统配置
+func GetInt(key string, def int) int { return Default.GetInt(key, def) }
+
+// GetDuration 读取全局系统配置
+func GetDuration(key string, def time.Duration) time.Duration {
+	return Default.GetDuration(key, def)
+}
+
+// GetJSON 读取全局系统配置
+func GetJSON(key string, out interface{}) (bool, error) { return Default.GetJSON(key, out) }
+
+// Watch 订阅全局系统配置变更
+func Watch(key string, w Watcher) { Default.Watch(key, w) }
@@ -0,0 +1,125 @@
+package sysconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTypedGetters(t *testing.T) {
+	s := NewStore(nil)
+	s.Replace(map[string]string{
+		"b":    "on",
+		"bad":  "maybe",
+		"n":    "42",
+		"d":    "1m30s",
+		"secs": "15",
+		"j":    `{"minLength":8,"requireDigit":true}`,
+	})
+
+	if !s.GetBool("b", false) || !s.GetBool("bad", true) || s.GetBool("missing", false) {
+		t.Fatal("GetBool 结果不正确")
+	}
+	if s.GetInt("n", 0) != 42 || s.GetInt("b", 7) != 7 {
+		t.Fatal("GetInt 结果不正确")
+	}
+	if s.GetDuration("d", 0) != 90*time.Second || s.GetDuration("secs", 0) != 15*time.Second {
+		t.Fatal("GetDuration 结果不正确")
+	}
+	var p PasswordPolicy
+	ok, err := s.GetJSON("j", &p)
+	if err != nil || !ok || p.MinLength != 8 || !p.RequireDigit {
+		t.Fatalf("GetJSON = %+v, %v, %v", p, ok, err)
+	}
+	if ok, _ := s.GetJSON("missing", &p); ok {
+		t.Fatal("不存在的键应返回 false")
+	}
+}
+
+func TestReloadNotifiesWatchers(t *testing.T) {
+	values := map[string]string{"a": "1", "b": "2"}
+	s := NewStore(func(ctx context.Context) (map[string]string, error) { return values, nil })
+	if err := s.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var keyed []string
+	var all []string
+	s.Watch("a", func(key, value string, deleted bool) { keyed = append(keyed, value) })
+	s.Watch("", func(key, value string, deleted bool) {
+		if deleted {
+			key = "-" + key
+		}
+		all = append(all, key)
+	})
+
+	values = map[string]string{"a": "3", "c": "4"}
+	if err := s.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(keyed) != 1 || keyed[0] != "3" {
+		t.Fatalf("键订阅收到 %v", keyed)
+	}
+	if len(all) != 3 {
+		t.Fatalf("全部订阅收到 %v，期望 a、c、-b", all)
+	}
+	if s.GetString("b", "none") != "none" {
+		t.Fatal("删除的键仍可读取")
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	p := PasswordPolicy{MinLength: 8, RequireDigit: true, RequireUpper: true, RequireSpecial: true}
+	if err := p.Validate("Abc!"); err == nil {
+		t.Fatal("过短的密码应被拒绝")
+	}
+	if err := p.Validate("abcdefgh1"); err == nil {
+		t.Fatal("缺少大写字母和特殊字符的密码应被拒绝")
+	}
+	if err := p.Validate("Abcdefg1!"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInitPasswordFollowsPolicy(t *testing.T) {
+	defer Default.Replace(nil)
+	Default.Replace(nil)
+	if password, err := GetInitPassword(); err != nil || password != DefaultInitPassword {
+		t.Fatalf("默认策略下应使用默认初始密码，got %q, %v", password, err)
+	}
+	Default.Replace(map[string]string{KeyPasswordPolicy: `{"minLength":8,"requireDigit":true}`})
+	if _, err := GetInitPassword(); err == nil {
+		t.Fatal("不符合密码策略的初始密码应被拒绝")
+	}
+	Default.Replace(map[string]string{
+		KeyPasswordPolicy: `{"minLength":8,"requireDigit":true}`,
+		KeyInitPassword:   "Init2026pass",
+	})
+	if password, err := GetInitPassword(); err != nil || password != "Init2026pass" {
+		t.Fatalf("应使用配置的初始密码，got %q, %v", password, err)
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	cases := []struct {
+		typ, value string
+		enum       []string
+		ok         bool
+	}{
+		{TypeString, "任意", nil, true},
+		{TypeInt, " 12 ", nil, true},
+		{TypeInt, "1.5", nil, false},
+		{TypeBool, "Off", nil, true},
+		{TypeBool, "maybe", nil, false},
+		{TypeJSON, `{"a":[1,2]}`, nil, true},
+		{TypeJSON, `{a:1}`, nil, false},
+		{TypeEnum, "1", []string{"0", "1"}, true},
+		{TypeEnum, "2", []string{"0", "1"}, false},
+		{"float", "1", nil, false},
+	}
+	for _, c := range cases {
+		if err := ValidateValue(c.typ, c.value, c.enum); (err == nil) != c.ok {
+			t.Errorf("ValidateValue(%s, %q) = %v, want ok=%v", c.typ, c.value, err, c.ok)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package sysconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/zmqge/vireo-gin-admin/pkg/apperr"
+)
+
+// 配置值类型
+const (
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeBool   = "bool"
+	TypeJSON   = "json"
+	TypeEnum   = "enum" // 取值来自字典项
+)
+
+// SecretMask 密文配置在接口中返回的掩码
+const SecretMask = "******"
+
+// ValidType 类型是否受支持
+func ValidType(valueType string) bool {
+	switch valueType {
+	case TypeString, TypeInt, TypeBool, TypeJSON, TypeEnum:
+		return true
+	}
+	return false
+}
+
+// ValidateValue 按声明的类型校验配置值，enum 类型需传入字典项的取值
+func ValidateValue(valueType, value string, enumValues []string) error {
+	switch valueType {
+	case TypeString, "":
+		return nil
+	case TypeInt:
+		if _, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err != nil {
+			return apperr.ErrConfigInvalidInt.WithArgs(value)
+		}
+	case TypeBool:
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "true", "false", "1", "0", "on", "off", "yes", "no", "y", "n":
+		default:
+			return apperr.ErrConfigInvalidBool.WithArgs(value)
+		}
+	case TypeJSON:
+		if !json.Valid([]byte(value)) {
+			return apperr.ErrConfigInvalidJSON
+		}
+	case TypeEnum:
+		for _, v := range enumValues {
+			if v == value {
+				return nil
+			}
+		}
+		return apperr.ErrConfigValueNotInEnum.WithArgs(value, strings.Join(enumValues, "、"))
+	default:
+		return apperr.ErrConfigInvalidType.WithArgs(valueType)
+	}
+	return nil
+}
+
+// FormatValue 规范化展示用的值：JSON 缩进后便于逐行比较
+func FormatValue(valueType, value string) string {
+	if valueType != TypeJSON {
+		return value
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(value), "", "  "); err != nil {
+		return value
+	}
+	return buf.String()
+}
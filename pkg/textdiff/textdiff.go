@@ -0,0 +1,96 @@
+package textdiff
+
+import "strings"
+
+// 差异行类型
+const (
+	OpEqual  = " "
+	OpDelete = "-"
+	OpInsert = "+"
+)
+
+// Line 一行差异
+type Line struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// maxCells LCS 表的上限，超出时退化为整体替换，避免大文本占用过多内存
+const maxCells = 4_000_000
+
+// Lines 按行比较 a 和 b，返回基于最长公共子序列的差异
+func Lines(a, b string) []Line {
+	x, y := split(a), split(b)
+	if len(x)*len(y) > maxCells {
+		return replaceAll(x, y)
+	}
+
+	// lcs[i][j] 为 x[i:] 与 y[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	diff := make([]Line, 0, len(x)+len(y))
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			diff = append(diff, Line{Op: OpEqual, Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, Line{Op: OpDelete, Text: x[i]})
+			i++
+		default:
+			diff = append(diff, Line{Op: OpInsert, Text: y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		diff = append(diff, Line{Op: OpDelete, Text: x[i]})
+	}
+	for ; j < len(y); j++ {
+		diff = append(diff, Line{Op: OpInsert, Text: y[j]})
+	}
+	return diff
+}
+
+// Changed 差异中是否存在修改
+func Changed(diff []Line) bool {
+	for _, l := range diff {
+		if l.Op != OpEqual {
+			return true
+		}
+	}
+	return false
+}
+
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
+
+func replaceAll(x, y []string) []Line {
+	diff := make([]Line, 0, len(x)+len(y))
+	for _, l := range x {
+		diff = append(diff, Line{Op: OpDelete, Text: l})
+	}
+	for _, l := range y {
+		diff = append(diff, Line{Op: OpInsert, Text: l})
+	}
+	return diff
+}
@@ -0,0 +1,25 @@
+package textdiff
+
+import "testing"
+
+func TestLines(t *testing.T) {
+	diff := Lines("a\nb\nc", "a\nc\nd")
+	want := []Line{{OpEqual, "a"}, {OpDelete, "b"}, {OpEqual, "c"}, {OpInsert, "d"}}
+	if len(diff) != len(want) {
+		t.Fatalf("Lines = %v, want %v", diff, want)
+	}
+	for i := range want {
+		if diff[i] != want[i] {
+			t.Fatalf("Lines[%d] = %v, want %v", i, diff[i], want[i])
+		}
+	}
+	if !Changed(diff) {
+		t.Fatal("应检测到修改")
+	}
+	if Changed(Lines("x\ny", "x\r\ny")) {
+		t.Fatal("换行符差异不应视为修改")
+	}
+	if d := Lines("", "new"); len(d) != 1 || d[0].Op != OpInsert {
+		t.Fatalf("空文本比较结果不正确: %v", d)
+	}
+}
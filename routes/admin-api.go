@@ -2,112 +2,303 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/zmqge/vireo-gin-admin/pkg/middleware"
-	"gorm.io/gorm"
 	"github.com/zmqge/vireo-gin-admin/app/admin/controllers"
-	"github.com/zmqge/vireo-gin-admin/app/admin/services"
 	"github.com/zmqge/vireo-gin-admin/app/admin/repositories"
+	"github.com/zmqge/vireo-gin-admin/app/admin/services"
+	"github.com/zmqge/vireo-gin-admin/pkg/middleware"
+	"github.com/zmqge/vireo-gin-admin/pkg/routemeta"
+	"gorm.io/gorm"
 )
 
-func RegisterAdminRoutes(engine *gin.Engine, db *gorm.DB) {
-	deptController := controllers.NewDeptController(db)
-	menuController := controllers.NewMenuController(db)
-	noticeReceiverRepository := repositories.NewNoticeReceiverRepository(db)
-	noticeReceiverService := services.NewNoticeReceiverService(noticeReceiverRepository)
-	noticeReceiverController := controllers.NewNoticeReceiverController(noticeReceiverService)
-	permissionController := controllers.NewPermissionController()
-	roleController := controllers.NewRoleController(db)
+func RegisterAdminRoutes(engine *gin.Engine, db *gorm.DB) *AdminServices {
 	userService := services.NewUserService(db)
 	tokenService := services.NewTokenService()
 	authController := controllers.NewAuthController(userService, tokenService)
 	configRepository := repositories.NewConfigRepository(db)
-	configService := services.NewConfigService(configRepository)
-	configController := controllers.NewConfigController(configService)
 	dictRepository := repositories.NewDictRepository(db)
+	configService := services.NewConfigService(configRepository, dictRepository)
+	configController := controllers.NewConfigController(configService)
+	deptController := controllers.NewDeptController(db)
 	dictService := services.NewDictService(dictRepository)
 	dictController := controllers.NewDictController(dictService)
+	menuController := controllers.NewMenuController(db)
+	noticeAttachmentRepository := repositories.NewNoticeAttachmentRepository(db)
+	noticeAttachmentService := services.NewNoticeAttachmentService(noticeAttachmentRepository)
+	noticeAttachmentController := controllers.NewNoticeAttachmentController(noticeAttachmentService)
+	noticeDeliveryRepository := repositories.NewNoticeDeliveryRepository(db)
+	noticeDeliveryService := services.NewNoticeDeliveryService(noticeDeliveryRepository, services.NewNotifyRegistry())
+	noticeDeliveryController := controllers.NewNoticeDeliveryController(noticeDeliveryService)
 	noticesRepository := repositories.NewNoticesRepository(db)
 	userRepository := repositories.NewUserRepository(db)
-	noticesService := services.NewNoticesService(noticesRepository, userRepository, noticeReceiverRepository)
+	noticeReceiverRepository := repositories.NewNoticeReceiverRepository(db)
+	noticesService := services.NewNoticesService(noticesRepository, userRepository, noticeReceiverRepository, noticeDeliveryService, noticeAttachmentService)
+	noticePushController := controllers.NewNoticePushController(noticesService)
+	noticeReceiverService := services.NewNoticeReceiverService(noticeReceiverRepository)
+	noticeReceiverController := controllers.NewNoticeReceiverController(noticeReceiverService)
+	noticeTemplateRepository := repositories.NewNoticeTemplateRepository(db)
+	noticeTemplateService := services.NewNoticeTemplateService(noticeTemplateRepository, noticesService)
+	noticeTemplateController := controllers.NewNoticeTemplateController(noticeTemplateService)
 	noticesController := controllers.NewNoticesController(noticesService)
+	permissionController := controllers.NewPermissionController()
+	roleController := controllers.NewRoleController(db)
+	systemController := controllers.NewSystemController()
 	userController := controllers.NewUserController(userService)
+	groupapi_v1_auth := engine.Group("/api/v1/auth")
+	{
+		groupapi_v1_auth.POST("/login", authController.Login)
+		groupapi_v1_auth.GET("/captcha", authController.GetCaptcha)
+		groupapi_v1_auth.DELETE("/logout", middleware.Use("jwt"), authController.Logout)
+		groupapi_v1_auth.POST("/refresh-token", authController.RefreshToken)
+	}
+	groupapi_v1_jwt := engine.Group("/api/v1", middleware.Use("jwt"))
+	{
+		groupapi_v1_jwt.GET("/config/:id", middleware.RBAC("sys:config:view"), middleware.Use("dataperm"), configController.GetConfigDetails)
+		groupapi_v1_jwt.GET("/config/page", middleware.RBAC("sys:config:query"), middleware.Use("dataperm"), configController.ListConfigs)
+		groupapi_v1_jwt.POST("/config", middleware.RBAC("sys:config:add"), configController.CreateConfig)
+		groupapi_v1_jwt.PUT("/config/:id", middleware.RBAC("sys:config:update"), middleware.Use("dataperm"), configController.UpdateConfig)
+		groupapi_v1_jwt.DELETE("/config/:id", middleware.RBAC("sys:config:delete"), configController.DeleteConfig)
+		groupapi_v1_jwt.GET("/config/:id/form", middleware.RBAC("sys:config:details"), middleware.Use("dataperm"), configController.GetConfigForm)
+		groupapi_v1_jwt.GET("/config/:id/history", middleware.RBAC("sys:config:history"), middleware.Use("dataperm"), configController.ListConfigHistory)
+		groupapi_v1_jwt.GET("/config/:id/history/:version/diff", middleware.RBAC("sys:config:diff"), middleware.Use("dataperm"), configController.DiffConfigVersion)
+		groupapi_v1_jwt.PUT("/config/:id/rollback/:version", middleware.RBAC("sys:config:rollback"), middleware.Use("dataperm"), configController.RollbackConfig)
+		groupapi_v1_jwt.POST("/dept", middleware.RBAC("sys:dept:add"), deptController.CreateDept)
+		groupapi_v1_jwt.PUT("/dept/:id", middleware.RBAC("sys:dept:edit"), deptController.UpdateDept)
+		groupapi_v1_jwt.GET("/dept/:id/form", middleware.RBAC("sys:dept:view"), deptController.GetDept)
+		groupapi_v1_jwt.GET("/dept/options", middleware.RBAC("sys:dept:options"), middleware.Use("dataperm"), deptController.GetDeptOptions)
+		groupapi_v1_jwt.GET("/dept", middleware.RBAC("sys:dept:query"), deptController.GetDepts)
+		groupapi_v1_jwt.DELETE("/dept/:id", middleware.RBAC("sys:dept:delete"), deptController.DeleteDept)
+		groupapi_v1_jwt.GET("/dicts/:id/form", middleware.RBAC("sys:dict:details"), dictController.GetDict)
+		groupapi_v1_jwt.GET("/dicts/page", middleware.RBAC("sys:dict:query"), dictController.ListDicts)
+		groupapi_v1_jwt.POST("/dicts", middleware.RBAC("sys:dict:add"), dictController.CreateDict)
+		groupapi_v1_jwt.PUT("/dicts/:id", middleware.RBAC("sys:dict:edit"), dictController.UpdateDict)
+		groupapi_v1_jwt.DELETE("/dicts/:id", middleware.RBAC("sys:dict-item:delete"), dictController.DeleteDict)
+		groupapi_v1_jwt.GET("/dicts-items/:dictCode/items", middleware.RBAC("sys:dict-item:details"), dictController.GetDictItem)
+		groupapi_v1_jwt.GET("/dicts-items/batch", middleware.RBAC("sys:dict-item:batch"), dictController.GetDictItemsBatch)
+		groupapi_v1_jwt.GET("/dicts/export", middleware.RBAC("sys:dict:export"), dictController.ExportDicts)
+		groupapi_v1_jwt.POST("/dicts/import", middleware.RBAC("sys:dict:import"), dictController.ImportDicts)
+		groupapi_v1_jwt.GET("/dicts-items/:dictCode/items/page", middleware.RBAC("sys:dict-item:query"), dictController.GetDictItemPage)
+		groupapi_v1_jwt.POST("/dicts-items/:dictCode/items", middleware.RBAC("sys:dict-item:add"), dictController.CreateDictItem)
+		groupapi_v1_jwt.PUT("/dicts-items/:dictCode/items/:id", middleware.RBAC("sys:dict-item:edit"), dictController.UpdateDictItem)
+		groupapi_v1_jwt.DELETE("/dicts-items/:dictCode/items/:id", middleware.RBAC("sys:dict:delete"), dictController.DeleteDictItem)
+		groupapi_v1_jwt.GET("/dicts-items/:dictCode/items/:itemId/form", middleware.RBAC("sys:dict-item:form"), dictController.GetDictItemForm)
+		groupapi_v1_jwt.GET("/dicts-items/:dictCode/i18n", middleware.RBAC("sys:dict-item:i18n"), dictController.GetDictItemI18n)
+		groupapi_v1_jwt.PUT("/dicts-items/:dictCode/i18n", middleware.RBAC("sys:dict-item:i18n-edit"), dictController.UpdateDictItemI18n)
+		groupapi_v1_jwt.GET("/menus/routes", middleware.RBAC("sys:menu:routes"), menuController.GetCurrentUserRoutes)
+		groupapi_v1_jwt.GET("/menus", middleware.RBAC("sys:menu:query"), menuController.ListMenus)
+		groupapi_v1_jwt.GET("/menus/options", middleware.RBAC("sys:menu:options"), menuController.ListMenuOptions)
+		groupapi_v1_jwt.GET("/menus/:id/form", middleware.RBAC("sys:menu:details"), menuController.GetMenuDetail)
+		groupapi_v1_jwt.POST("/menus", middleware.RBAC("sys:menu:add"), menuController.AddMenu)
+		groupapi_v1_jwt.PUT("/menus/:id", middleware.RBAC("sys:menu:edit"), menuController.UpdateMenu)
+		groupapi_v1_jwt.DELETE("/menus/:id", middleware.RBAC("sys:menu:delete"), menuController.DeleteMenu)
+		groupapi_v1_jwt.GET("/menus/:id/i18n", middleware.RBAC("sys:menu:i18n"), menuController.GetMenuI18n)
+		groupapi_v1_jwt.PUT("/menus/:id/i18n", middleware.RBAC("sys:menu:i18n-edit"), menuController.UpdateMenuI18n)
+		groupapi_v1_jwt.GET("/notices/:id/deliveries", middleware.RBAC("sys:notice:deliveries"), middleware.Use("dataperm"), noticeDeliveryController.ListDeliveries)
+		groupapi_v1_jwt.GET("/notices/notify-preference", middleware.RBAC("sys:notice:preference-view"), noticeDeliveryController.GetMyPreference)
+		groupapi_v1_jwt.PUT("/notices/notify-preference", middleware.RBAC("sys:notice:preference-update"), noticeDeliveryController.UpdateMyPreference)
+		groupapi_v1_jwt.GET("/noticereceiver/:id", middleware.RBAC("sys:noticereceiver:view"), middleware.Use("dataperm"), noticeReceiverController.GetNoticeReceiverDetails)
+		groupapi_v1_jwt.GET("/noticereceiver/page", middleware.RBAC("sys:noticereceiver:query"), middleware.Use("dataperm"), noticeReceiverController.ListNoticeReceivers)
+		groupapi_v1_jwt.POST("/noticereceiver", middleware.RBAC("sys:noticereceiver:add"), noticeReceiverController.CreateNoticeReceiver)
+		groupapi_v1_jwt.PUT("/noticereceiver/:id", middleware.RBAC("sys:noticereceiver:update"), middleware.Use("dataperm"), noticeReceiverController.UpdateNoticeReceiver)
+		groupapi_v1_jwt.DELETE("/noticereceiver/:id", middleware.RBAC("sys:noticereceiver:delete"), noticeReceiverController.DeleteNoticeReceiver)
+		groupapi_v1_jwt.GET("/noticereceiver/:id/form", middleware.RBAC("sys:noticereceiver:details"), middleware.Use("dataperm"), noticeReceiverController.GetNoticeReceiverForm)
+		groupapi_v1_jwt.GET("/noticetemplate/:id", middleware.RBAC("sys:noticetemplate:view"), middleware.Use("dataperm"), noticeTemplateController.GetNoticeTemplateDetails)
+		groupapi_v1_jwt.GET("/noticetemplate/page", middleware.RBAC("sys:noticetemplate:query"), middleware.Use("dataperm"), noticeTemplateController.ListNoticeTemplates)
+		groupapi_v1_jwt.POST("/noticetemplate", middleware.RBAC("sys:noticetemplate:add"), noticeTemplateController.CreateNoticeTemplate)
+		groupapi_v1_jwt.PUT("/noticetemplate/:id", middleware.RBAC("sys:noticetemplate:update"), middleware.Use("dataperm"), noticeTemplateController.UpdateNoticeTemplate)
+		groupapi_v1_jwt.DELETE("/noticetemplate/:id", middleware.RBAC("sys:noticetemplate:delete"), noticeTemplateController.DeleteNoticeTemplate)
+		groupapi_v1_jwt.POST("/noticetemplate/:id/preview", middleware.RBAC("sys:noticetemplate:preview"), middleware.Use("dataperm"), noticeTemplateController.PreviewNoticeTemplate)
+		groupapi_v1_jwt.POST("/notices/from-template", middleware.RBAC("sys:notice:from-template"), noticeTemplateController.CreateNoticeFromTemplate)
+		groupapi_v1_jwt.GET("/notices/:id/detail", middleware.RBAC("sys:notice:detail"), middleware.Use("dataperm"), noticesController.GetNoticesDetails)
+		groupapi_v1_jwt.GET("/notices/:id/my-detail", middleware.RBAC("sys:notice:my-detail"), noticesController.GetMyNoticesDetails)
+		groupapi_v1_jwt.GET("/notices/page", middleware.RBAC("sys:notice:query"), middleware.Use("dataperm"), noticesController.ListNoticess)
+		groupapi_v1_jwt.POST("/notices", middleware.RBAC("sys:notice:add"), noticesController.CreateNotices)
+		groupapi_v1_jwt.PUT("/notices/:id", middleware.RBAC("sys:notice:update"), middleware.Use("dataperm"), noticesController.UpdateNotices)
+		groupapi_v1_jwt.DELETE("/notices/:id", middleware.RBAC("sys:notice:delete"), noticesController.DeleteNotices)
+		groupapi_v1_jwt.GET("/notices/:id/form", middleware.RBAC("sys:notice:form"), middleware.Use("dataperm"), noticesController.GetNoticesForm)
+		groupapi_v1_jwt.PUT("/notices/:id/revoke", middleware.RBAC("sys:notice:revoke"), noticesController.RevokeNotice)
+		groupapi_v1_jwt.PUT("/notices/:id/publish", middleware.RBAC("sys:notice:publish"), noticesController.PublishNotice)
+		groupapi_v1_jwt.PUT("/notices/:id/cancel-schedule", middleware.RBAC("sys:notice:cancel-schedule"), noticesController.CancelSchedule)
+		groupapi_v1_jwt.PUT("/notices/:id/submit", middleware.RBAC("sys:notice:submit"), noticesController.SubmitNotice)
+		groupapi_v1_jwt.PUT("/notices/:id/approve", middleware.RBAC("sys:notice:approve"), noticesController.ApproveNotice)
+		groupapi_v1_jwt.PUT("/notices/:id/reject", middleware.RBAC("sys:notice:reject"), noticesController.RejectNotice)
+		groupapi_v1_jwt.GET("/notices/:id/history", middleware.RBAC("sys:notice:history"), middleware.Use("dataperm"), noticesController.GetNoticeHistory)
+		groupapi_v1_jwt.GET("/notices/:id/read-stats", middleware.RBAC("sys:notice:read-stats"), middleware.Use("dataperm"), noticesController.GetNoticeReadStats)
+		groupapi_v1_jwt.GET("/notices/:id/unread", middleware.RBAC("sys:notice:unread"), middleware.Use("dataperm"), noticesController.ListNoticeUnreadUsers)
+		groupapi_v1_jwt.POST("/notices/:id/remind", middleware.RBAC("sys:notice:remind"), middleware.Use("dataperm"), noticesController.RemindUnread)
+		groupapi_v1_jwt.GET("/notices/my-page", middleware.RBAC("sys:notice:mynotice"), middleware.Use("dataperm"), noticesController.GetMyNoticess)
+		groupapi_v1_jwt.PUT("/notices/my-page/read-all", middleware.RBAC("sys:notice:read-all"), noticesController.MarkAllAsRead)
+		groupapi_v1_jwt.GET("/perms/options", middleware.RBAC("sys:perm:options"), permissionController.ListPermOptions)
+		groupapi_v1_jwt.POST("/roles", middleware.RBAC("sys:role:add"), roleController.Create)
+		groupapi_v1_jwt.PUT("/roles/:id", middleware.RBAC("sys:role:edit"), roleController.UpdateRole)
+		groupapi_v1_jwt.DELETE("/roles/:id", middleware.RBAC("sys:role:delete"), roleController.DeleteRole)
+		groupapi_v1_jwt.GET("/roles/:id/form", middleware.RBAC("sys:role:detail"), roleController.GetRoleDetail)
+		groupapi_v1_jwt.GET("/roles/page", middleware.RBAC("sys:role:query"), roleController.List)
+		groupapi_v1_jwt.GET("/roles/:id/menuIds", middleware.RBAC("sys:role:menu"), roleController.GetRoleMenus)
+		groupapi_v1_jwt.GET("/roles/:id/permCodes", middleware.RBAC("sys:role:perm"), roleController.GetRolePerms)
+		groupapi_v1_jwt.PUT("/roles/:id/menus", middleware.RBAC("sys:role:menu:update"), roleController.UpdateRoleMenus)
+		groupapi_v1_jwt.PUT("/roles/:id/perms", middleware.RBAC("sys:role:perm:update"), roleController.UpdateRolePerms)
+		groupapi_v1_jwt.GET("/roles/options", middleware.RBAC("sys:role:options"), roleController.ListRoleOptions)
+		groupapi_v1_jwt.GET("/system/routes", middleware.RBAC("sys:system:routes"), systemController.GetRoutes)
+		groupapi_v1_jwt.GET("users/me", middleware.RBAC("sys:user:me"), userController.Me)
+		groupapi_v1_jwt.DELETE("/users/:id", middleware.RBAC("sys:user:delete"), userController.Delete)
+		groupapi_v1_jwt.GET("users/page", middleware.RBAC("sys:user:page"), middleware.Use("dataperm"), userController.GetUserPage)
+		groupapi_v1_jwt.GET("/users/:id/form", middleware.RBAC("sys:user:info"), userController.GetUser)
+		groupapi_v1_jwt.PUT("/users/:id", middleware.RBAC("sys:user:edit"), userController.UpdateUser)
+		groupapi_v1_jwt.POST("/users", middleware.RBAC("sys:user:add"), userController.CreateUser)
+		groupapi_v1_jwt.PUT("/users/:id/password/reset", middleware.RBAC("sys:user:reset-password"), userController.ResetPassword)
+		groupapi_v1_jwt.GET("/users/profile", middleware.RBAC("sys:user:profile"), userController.GetUserProfile)
+		groupapi_v1_jwt.PUT("/users/password", middleware.RBAC("sys:user:change-password"), userController.ChangePassword)
+		groupapi_v1_jwt.PUT("/users/profile", middleware.RBAC("sys:user:update-profile"), userController.UpdateMyProfile)
+		groupapi_v1_jwt.GET("/users/options", middleware.RBAC("sys:user:options"), middleware.Use("dataperm"), userController.ListUserOptions)
+		groupapi_v1_jwt.POST("/users/import", middleware.RBAC("sys:user:import"), middleware.Use("dataperm"), userController.ImportUsers)
+		groupapi_v1_jwt.GET("/users/import/template", middleware.RBAC("sys:user:import-template"), userController.DownloadImportTemplate)
+		groupapi_v1_jwt.GET("/users/import/report/:reportId", middleware.RBAC("sys:user:import-report"), userController.DownloadImportReport)
+	}
 	groupapi_v1 := engine.Group("/api/v1")
 	{
-	groupapi_v1.GET("/config/:id", middleware.JWT(), middleware.RBAC("sys:config:view"), middleware.DATAPERM(), configController.GetConfigDetails)
-	groupapi_v1.GET("/config/page", middleware.JWT(), middleware.RBAC("sys:config:query"), middleware.DATAPERM(), configController.ListConfigs)
-	groupapi_v1.POST("/config", middleware.JWT(), middleware.RBAC("sys:config:add"), configController.CreateConfig)
-	groupapi_v1.PUT("/config/:id", middleware.JWT(), middleware.RBAC("sys:config:update"), middleware.DATAPERM(), configController.UpdateConfig)
-	groupapi_v1.DELETE("/config/:id", middleware.JWT(), middleware.RBAC("sys:config:delete"), configController.DeleteConfig)
-	groupapi_v1.GET("/config/:id/form", middleware.JWT(), middleware.RBAC("sys:config:details"), middleware.DATAPERM(), configController.GetConfigForm)
-	groupapi_v1.POST("/dept", middleware.JWT(), middleware.RBAC("sys:dept:add"), deptController.CreateDept)
-	groupapi_v1.PUT("/dept/:id", middleware.JWT(), middleware.RBAC("sys:dept:edit"), deptController.UpdateDept)
-	groupapi_v1.GET("/dept/:id/form", middleware.JWT(), middleware.RBAC("sys:dept:view"), deptController.GetDept)
-	groupapi_v1.GET("/dept/options", middleware.JWT(), middleware.RBAC("sys:dept:options"), middleware.DATAPERM(), deptController.GetDeptOptions)
-	groupapi_v1.GET("/dept", middleware.JWT(), middleware.RBAC("sys:dept:query"), deptController.GetDepts)
-	groupapi_v1.DELETE("/dept/:id", middleware.JWT(), middleware.RBAC("sys:dept:delete"), deptController.DeleteDept)
-	groupapi_v1.GET("/dicts/:id/form", middleware.JWT(), middleware.RBAC("sys:dict:details"), dictController.GetDict)
-	groupapi_v1.GET("/dicts/page", middleware.JWT(), middleware.RBAC("sys:dict:query"), dictController.ListDicts)
-	groupapi_v1.POST("/dicts", middleware.JWT(), middleware.RBAC("sys:dict:add"), dictController.CreateDict)
-	groupapi_v1.PUT("/dicts/:id", middleware.JWT(), middleware.RBAC("sys:dict:edit"), dictController.UpdateDict)
-	groupapi_v1.DELETE("/dicts/:id", middleware.JWT(), middleware.RBAC("sys:dict-item:delete"), dictController.DeleteDict)
-	groupapi_v1.GET("/dicts-items/:dictCode/items", middleware.JWT(), middleware.RBAC("sys:dict-item:details"), dictController.GetDictItem)
-	groupapi_v1.GET("/dicts-items/:dictCode/items/page", middleware.JWT(), middleware.RBAC("sys:dict-item:query"), dictController.GetDictItemPage)
-	groupapi_v1.POST("/dicts-items/:dictCode/items", middleware.JWT(), middleware.RBAC("sys:dict-item:add"), dictController.CreateDictItem)
-	groupapi_v1.PUT("/dicts-items/:dictCode/items/:id", middleware.JWT(), middleware.RBAC("sys:dict-item:edit"), dictController.UpdateDictItem)
-	groupapi_v1.DELETE("/dicts-items/:dictCode/items/:id", middleware.JWT(), middleware.RBAC("sys:dict:delete"), dictController.DeleteDictItem)
-	groupapi_v1.GET("/dicts-items/:dictCode/items/:itemId/form", middleware.JWT(), middleware.RBAC("sys:dict-item:form"), dictController.GetDictItemForm)
-	groupapi_v1.GET("/menus/routes", middleware.JWT(), middleware.RBAC("sys:menu:routes"), menuController.GetCurrentUserRoutes)
-	groupapi_v1.GET("/menus", middleware.JWT(), middleware.RBAC("sys:menu:query"), menuController.ListMenus)
-	groupapi_v1.GET("/menus/options", middleware.JWT(), middleware.RBAC("sys:menu:options"), menuController.ListMenuOptions)
-	groupapi_v1.GET("/menus/:id/form", middleware.JWT(), middleware.RBAC("sys:menu:details"), menuController.GetMenuDetail)
-	groupapi_v1.POST("/menus", middleware.JWT(), middleware.RBAC("sys:menu:add"), menuController.AddMenu)
-	groupapi_v1.PUT("/menus/:id", middleware.JWT(), middleware.RBAC("sys:menu:edit"), menuController.UpdateMenu)
-	groupapi_v1.DELETE("/menus/:id", middleware.JWT(), middleware.RBAC("sys:menu:delete"), menuController.DeleteMenu)
-	groupapi_v1.GET("/noticereceiver/:id", middleware.JWT(), middleware.RBAC("sys:noticereceiver:view"), middleware.DATAPERM(), noticeReceiverController.GetNoticeReceiverDetails)
-	groupapi_v1.GET("/noticereceiver/page", middleware.JWT(), middleware.RBAC("sys:noticereceiver:query"), middleware.DATAPERM(), noticeReceiverController.ListNoticeReceivers)
-	groupapi_v1.POST("/noticereceiver", middleware.JWT(), middleware.RBAC("sys:noticereceiver:add"), noticeReceiverController.CreateNoticeReceiver)
-	groupapi_v1.PUT("/noticereceiver/:id", middleware.JWT(), middleware.RBAC("sys:noticereceiver:update"), middleware.DATAPERM(), noticeReceiverController.UpdateNoticeReceiver)
-	groupapi_v1.DELETE("/noticereceiver/:id", middleware.JWT(), middleware.RBAC("sys:noticereceiver:delete"), noticeReceiverController.DeleteNoticeReceiver)
-	groupapi_v1.GET("/noticereceiver/:id/form", middleware.JWT(), middleware.RBAC("sys:noticereceiver:details"), middleware.DATAPERM(), noticeReceiverController.GetNoticeReceiverForm)
-	groupapi_v1.GET("/notices/:id/detail", middleware.JWT(), middleware.RBAC("sys:notice:detail"), middleware.DATAPERM(), noticesController.GetNoticesDetails)
-	groupapi_v1.GET("/notices/:id/my-detail", middleware.JWT(), middleware.RBAC("sys:notice:my-detail"), noticesController.GetMyNoticesDetails)
-	groupapi_v1.GET("/notices/page", middleware.JWT(), middleware.RBAC("sys:notice:query"), middleware.DATAPERM(), noticesController.ListNoticess)
-	groupapi_v1.POST("/notices", middleware.JWT(), middleware.RBAC("sys:notice:add"), noticesController.CreateNotices)
-	groupapi_v1.PUT("/notices/:id", middleware.JWT(), middleware.RBAC("sys:notice:update"), middleware.DATAPERM(), noticesController.UpdateNotices)
-	groupapi_v1.DELETE("/notices/:id", middleware.JWT(), middleware.RBAC("sys:notice:delete"), noticesController.DeleteNotices)
-	groupapi_v1.GET("/notices/:id/form", middleware.JWT(), middleware.RBAC("sys:notice:form"), middleware.DATAPERM(), noticesController.GetNoticesForm)
-	groupapi_v1.PUT("/notices/:id/revoke", middleware.JWT(), middleware.RBAC("sys:notice:revoke"), noticesController.RevokeNotice)
-	groupapi_v1.PUT("/notices/:id/publish", middleware.JWT(), middleware.RBAC("sys:notice:publish"), noticesController.PublishNotice)
-	groupapi_v1.GET("/notices/my-page", middleware.JWT(), middleware.RBAC("sys:notice:mynotice"), middleware.DATAPERM(), noticesController.GetMyNoticess)
-	groupapi_v1.PUT("/notices/my-page/read-all", middleware.JWT(), middleware.RBAC("sys:notice:read-all"), noticesController.MarkAllAsRead)
-	groupapi_v1.GET("/perms/options", middleware.JWT(), middleware.RBAC("sys:perm:options"), permissionController.ListPermOptions)
-	groupapi_v1.POST("/roles", middleware.JWT(), middleware.RBAC("sys:role:add"), roleController.Create)
-	groupapi_v1.PUT("/roles/:id", middleware.JWT(), middleware.RBAC("sys:role:edit"), roleController.UpdateRole)
-	groupapi_v1.DELETE("/roles/:id", middleware.JWT(), middleware.RBAC("sys:role:delete"), roleController.DeleteRole)
-	groupapi_v1.GET("/roles/:id/form", middleware.JWT(), middleware.RBAC("sys:role:detail"), roleController.GetRoleDetail)
-	groupapi_v1.GET("/roles/page", middleware.JWT(), middleware.RBAC("sys:role:query"), roleController.List)
-	groupapi_v1.GET("/roles/:id/menuIds", middleware.JWT(), middleware.RBAC("sys:role:menu"), roleController.GetRoleMenus)
-	groupapi_v1.GET("/roles/:id/permCodes", middleware.JWT(), middleware.RBAC("sys:role:perm"), roleController.GetRolePerms)
-	groupapi_v1.PUT("/roles/:id/menus", middleware.JWT(), middleware.RBAC("sys:role:menu:update"), roleController.UpdateRoleMenus)
-	groupapi_v1.PUT("/roles/:id/perms", middleware.JWT(), middleware.RBAC("sys:role:perm:update"), roleController.UpdateRolePerms)
-	groupapi_v1.GET("/roles/options", middleware.JWT(), middleware.RBAC("sys:role:options"), roleController.ListRoleOptions)
-	groupapi_v1.GET("users/me", middleware.JWT(), middleware.RBAC("sys:user:me"), userController.Me)
-	groupapi_v1.DELETE("/users/:id", middleware.JWT(), middleware.RBAC("sys:user:delete"), userController.Delete)
-	groupapi_v1.GET("users/page", middleware.JWT(), middleware.RBAC("sys:user:page"), middleware.DATAPERM(), userController.GetUserPage)
-	groupapi_v1.GET("/users/:id/form", middleware.JWT(), middleware.RBAC("sys:user:info"), userController.GetUser)
-	groupapi_v1.PUT("/users/:id", middleware.JWT(), middleware.RBAC("sys:user:edit"), userController.UpdateUser)
-	groupapi_v1.POST("/users", middleware.JWT(), middleware.RBAC("sys:user:add"), userController.CreateUser)
-	groupapi_v1.PUT("/users/:id/password/reset", middleware.JWT(), middleware.RBAC("sys:user:reset-password"), userController.ResetPassword)
-	groupapi_v1.GET("/users/profile", middleware.JWT(), middleware.RBAC("sys:user:profile"), userController.GetUserProfile)
-	groupapi_v1.PUT("/users/password", middleware.JWT(), middleware.RBAC("sys:user:change-password"), userController.ChangePassword)
-	groupapi_v1.PUT("/users/profile", middleware.JWT(), middleware.RBAC("sys:user:update-profile"), userController.UpdateMyProfile)
-	groupapi_v1.GET("/users/options", middleware.JWT(), middleware.RBAC("sys:user:options"), middleware.DATAPERM(), userController.ListUserOptions)
+		groupapi_v1.POST("/notices/attachments", middleware.Use("jwt"), middleware.RBAC("sys:notice:attachment-upload"), noticeAttachmentController.UploadAttachment)
+		groupapi_v1.GET("/notices/attachments/:id/download", noticeAttachmentController.DownloadAttachment)
 	}
-	groupapi_v1_auth := engine.Group("/api/v1/auth")
+	groupapi_v1_jwt_ticket := engine.Group("/api/v1", middleware.Use("jwt(ticket)"))
 	{
-	groupapi_v1_auth.POST("/login", authController.Login)
-	groupapi_v1_auth.GET("/captcha", authController.GetCaptcha)
-	groupapi_v1_auth.DELETE("/logout", middleware.JWT(), authController.Logout)
-	groupapi_v1_auth.POST("/refresh-token", authController.RefreshToken)
+		groupapi_v1_jwt_ticket.POST("/notices/stream-ticket", noticePushController.StreamTicket)
+		groupapi_v1_jwt_ticket.GET("/notices/stream", middleware.RBAC("sys:notice:stream"), noticePushController.Stream)
+		groupapi_v1_jwt_ticket.GET("/notices/ws", middleware.RBAC("sys:notice:ws"), noticePushController.WebSocket)
 	}
+	routemeta.Register(
+		routemeta.Route{Method: "POST", Path: "/api/v1/auth/login", Group: "认证", Handler: "AuthController.Login"},
+		routemeta.Route{Method: "GET", Path: "/api/v1/auth/captcha", Group: "认证", Handler: "AuthController.GetCaptcha"},
+		routemeta.Route{Method: "DELETE", Path: "/api/v1/auth/logout", Group: "认证", Handler: "AuthController.Logout", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/auth/refresh-token", Group: "认证", Handler: "AuthController.RefreshToken"},
+		routemeta.Route{Method: "GET", Path: "/api/v1/config/:id", Permission: "sys:config:view", Group: "Config管理", Handler: "ConfigController.GetConfigDetails", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/config/page", Permission: "sys:config:query", Group: "Config管理", Handler: "ConfigController.ListConfigs", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/config", Permission: "sys:config:add", Group: "Config管理", Handler: "ConfigController.CreateConfig", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/config/:id", Permission: "sys:config:update", Group: "Config管理", Handler: "ConfigController.UpdateConfig", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "DELETE", Path: "/api/v1/config/:id", Permission: "sys:config:delete", Group: "Config管理", Handler: "ConfigController.DeleteConfig", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/config/:id/form", Permission: "sys:config:details", Group: "Config管理", Handler: "ConfigController.GetConfigForm", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/config/:id/history", Permission: "sys:config:history", Group: "Config管理", Handler: "ConfigController.ListConfigHistory", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/config/:id/history/:version/diff", Permission: "sys:config:diff", Group: "Config管理", Handler: "ConfigController.DiffConfigVersion", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/config/:id/rollback/:version", Permission: "sys:config:rollback", Group: "Config管理", Handler: "ConfigController.RollbackConfig", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/dept", Permission: "sys:dept:add", Group: "部门管理", Handler: "DeptController.CreateDept", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/dept/:id", Permission: "sys:dept:edit", Group: "部门管理", Handler: "DeptController.UpdateDept", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/dept/:id/form", Permission: "sys:dept:view", Group: "部门管理", Handler: "DeptController.GetDept", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/dept/options", Permission: "sys:dept:options", Group: "部门管理", Handler: "DeptController.GetDeptOptions", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/dept", Permission: "sys:dept:query", Group: "部门管理", Handler: "DeptController.GetDepts", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "DELETE", Path: "/api/v1/dept/:id", Permission: "sys:dept:delete", Group: "部门管理", Handler: "DeptController.DeleteDept", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/dicts/:id/form", Permission: "sys:dict:details", Handler: "DictController.GetDict", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/dicts/page", Permission: "sys:dict:query", Handler: "DictController.ListDicts", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/dicts", Permission: "sys:dict:add", Handler: "DictController.CreateDict", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/dicts/:id", Permission: "sys:dict:edit", Handler: "DictController.UpdateDict", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "DELETE", Path: "/api/v1/dicts/:id", Permission: "sys:dict-item:delete", Handler: "DictController.DeleteDict", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/dicts-items/:dictCode/items", Permission: "sys:dict-item:details", Handler: "DictController.GetDictItem", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/dicts-items/batch", Permission: "sys:dict-item:batch", Handler: "DictController.GetDictItemsBatch", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/dicts/export", Permission: "sys:dict:export", Handler: "DictController.ExportDicts", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/dicts/import", Permission: "sys:dict:import", Handler: "DictController.ImportDicts", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/dicts-items/:dictCode/items/page", Permission: "sys:dict-item:query", Handler: "DictController.GetDictItemPage", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/dicts-items/:dictCode/items", Permission: "sys:dict-item:add", Handler: "DictController.CreateDictItem", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/dicts-items/:dictCode/items/:id", Permission: "sys:dict-item:edit", Handler: "DictController.UpdateDictItem", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "DELETE", Path: "/api/v1/dicts-items/:dictCode/items/:id", Permission: "sys:dict:delete", Handler: "DictController.DeleteDictItem", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/dicts-items/:dictCode/items/:itemId/form", Permission: "sys:dict-item:form", Handler: "DictController.GetDictItemForm", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/dicts-items/:dictCode/i18n", Permission: "sys:dict-item:i18n", Handler: "DictController.GetDictItemI18n", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/dicts-items/:dictCode/i18n", Permission: "sys:dict-item:i18n-edit", Handler: "DictController.UpdateDictItemI18n", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/menus/routes", Permission: "sys:menu:routes", Group: "菜单管理", Handler: "MenuController.GetCurrentUserRoutes", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/menus", Permission: "sys:menu:query", Group: "菜单管理", Handler: "MenuController.ListMenus", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/menus/options", Permission: "sys:menu:options", Group: "菜单管理", Handler: "MenuController.ListMenuOptions", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/menus/:id/form", Permission: "sys:menu:details", Group: "菜单管理", Handler: "MenuController.GetMenuDetail", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/menus", Permission: "sys:menu:add", Group: "菜单管理", Handler: "MenuController.AddMenu", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/menus/:id", Permission: "sys:menu:edit", Group: "菜单管理", Handler: "MenuController.UpdateMenu", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "DELETE", Path: "/api/v1/menus/:id", Permission: "sys:menu:delete", Group: "菜单管理", Handler: "MenuController.DeleteMenu", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/menus/:id/i18n", Permission: "sys:menu:i18n", Group: "菜单管理", Handler: "MenuController.GetMenuI18n", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/menus/:id/i18n", Permission: "sys:menu:i18n-edit", Group: "菜单管理", Handler: "MenuController.UpdateMenuI18n", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/notices/attachments", Permission: "sys:notice:attachment-upload", Group: "Notices管理", Handler: "NoticeAttachmentController.UploadAttachment", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/attachments/:id/download", Group: "Notices管理", Handler: "NoticeAttachmentController.DownloadAttachment"},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/:id/deliveries", Permission: "sys:notice:deliveries", Group: "Notices管理", Handler: "NoticeDeliveryController.ListDeliveries", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/notify-preference", Permission: "sys:notice:preference-view", Group: "Notices管理", Handler: "NoticeDeliveryController.GetMyPreference", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/notices/notify-preference", Permission: "sys:notice:preference-update", Group: "Notices管理", Handler: "NoticeDeliveryController.UpdateMyPreference", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/notices/stream-ticket", Group: "Notices管理", Handler: "NoticePushController.StreamTicket", Middlewares: []string{"jwt(ticket)"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/stream", Permission: "sys:notice:stream", Group: "Notices管理", Handler: "NoticePushController.Stream", Middlewares: []string{"jwt(ticket)"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/ws", Permission: "sys:notice:ws", Group: "Notices管理", Handler: "NoticePushController.WebSocket", Middlewares: []string{"jwt(ticket)"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/noticereceiver/:id", Permission: "sys:noticereceiver:view", Group: "NoticeReceiver管理", Handler: "NoticeReceiverController.GetNoticeReceiverDetails", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/noticereceiver/page", Permission: "sys:noticereceiver:query", Group: "NoticeReceiver管理", Handler: "NoticeReceiverController.ListNoticeReceivers", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/noticereceiver", Permission: "sys:noticereceiver:add", Group: "NoticeReceiver管理", Handler: "NoticeReceiverController.CreateNoticeReceiver", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/noticereceiver/:id", Permission: "sys:noticereceiver:update", Group: "NoticeReceiver管理", Handler: "NoticeReceiverController.UpdateNoticeReceiver", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "DELETE", Path: "/api/v1/noticereceiver/:id", Permission: "sys:noticereceiver:delete", Group: "NoticeReceiver管理", Handler: "NoticeReceiverController.DeleteNoticeReceiver", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/noticereceiver/:id/form", Permission: "sys:noticereceiver:details", Group: "NoticeReceiver管理", Handler: "NoticeReceiverController.GetNoticeReceiverForm", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/noticetemplate/:id", Permission: "sys:noticetemplate:view", Group: "NoticeTemplate管理", Handler: "NoticeTemplateController.GetNoticeTemplateDetails", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/noticetemplate/page", Permission: "sys:noticetemplate:query", Group: "NoticeTemplate管理", Handler: "NoticeTemplateController.ListNoticeTemplates", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/noticetemplate", Permission: "sys:noticetemplate:add", Group: "NoticeTemplate管理", Handler: "NoticeTemplateController.CreateNoticeTemplate", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/noticetemplate/:id", Permission: "sys:noticetemplate:update", Group: "NoticeTemplate管理", Handler: "NoticeTemplateController.UpdateNoticeTemplate", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "DELETE", Path: "/api/v1/noticetemplate/:id", Permission: "sys:noticetemplate:delete", Group: "NoticeTemplate管理", Handler: "NoticeTemplateController.DeleteNoticeTemplate", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/noticetemplate/:id/preview", Permission: "sys:noticetemplate:preview", Group: "NoticeTemplate管理", Handler: "NoticeTemplateController.PreviewNoticeTemplate", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/notices/from-template", Permission: "sys:notice:from-template", Group: "NoticeTemplate管理", Handler: "NoticeTemplateController.CreateNoticeFromTemplate", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/:id/detail", Permission: "sys:notice:detail", Group: "Notices管理", Handler: "NoticesController.GetNoticesDetails", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/:id/my-detail", Permission: "sys:notice:my-detail", Group: "Notices管理", Handler: "NoticesController.GetMyNoticesDetails", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/page", Permission: "sys:notice:query", Group: "Notices管理", Handler: "NoticesController.ListNoticess", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/notices", Permission: "sys:notice:add", Group: "Notices管理", Handler: "NoticesController.CreateNotices", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/notices/:id", Permission: "sys:notice:update", Group: "Notices管理", Handler: "NoticesController.UpdateNotices", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "DELETE", Path: "/api/v1/notices/:id", Permission: "sys:notice:delete", Group: "Notices管理", Handler: "NoticesController.DeleteNotices", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/:id/form", Permission: "sys:notice:form", Group: "Notices管理", Handler: "NoticesController.GetNoticesForm", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/notices/:id/revoke", Permission: "sys:notice:revoke", Group: "Notices管理", Handler: "NoticesController.RevokeNotice", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/notices/:id/publish", Permission: "sys:notice:publish", Group: "Notices管理", Handler: "NoticesController.PublishNotice", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/notices/:id/cancel-schedule", Permission: "sys:notice:cancel-schedule", Group: "Notices管理", Handler: "NoticesController.CancelSchedule", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/notices/:id/submit", Permission: "sys:notice:submit", Group: "Notices管理", Handler: "NoticesController.SubmitNotice", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/notices/:id/approve", Permission: "sys:notice:approve", Group: "Notices管理", Handler: "NoticesController.ApproveNotice", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/notices/:id/reject", Permission: "sys:notice:reject", Group: "Notices管理", Handler: "NoticesController.RejectNotice", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/:id/history", Permission: "sys:notice:history", Group: "Notices管理", Handler: "NoticesController.GetNoticeHistory", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/:id/read-stats", Permission: "sys:notice:read-stats", Group: "Notices管理", Handler: "NoticesController.GetNoticeReadStats", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/:id/unread", Permission: "sys:notice:unread", Group: "Notices管理", Handler: "NoticesController.ListNoticeUnreadUsers", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/notices/:id/remind", Permission: "sys:notice:remind", Group: "Notices管理", Handler: "NoticesController.RemindUnread", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/notices/my-page", Permission: "sys:notice:mynotice", Group: "Notices管理", Handler: "NoticesController.GetMyNoticess", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/notices/my-page/read-all", Permission: "sys:notice:read-all", Group: "Notices管理", Handler: "NoticesController.MarkAllAsRead", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/perms/options", Permission: "sys:perm:options", Group: "权限管理", Handler: "PermissionController.ListPermOptions", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/roles", Permission: "sys:role:add", Group: "角色管理", Handler: "RoleController.Create", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/roles/:id", Permission: "sys:role:edit", Group: "角色管理", Handler: "RoleController.UpdateRole", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "DELETE", Path: "/api/v1/roles/:id", Permission: "sys:role:delete", Group: "角色管理", Handler: "RoleController.DeleteRole", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/roles/:id/form", Permission: "sys:role:detail", Group: "角色管理", Handler: "RoleController.GetRoleDetail", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/roles/page", Permission: "sys:role:query", Group: "角色管理", Handler: "RoleController.List", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/roles/:id/menuIds", Permission: "sys:role:menu", Group: "角色管理", Handler: "RoleController.GetRoleMenus", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/roles/:id/permCodes", Permission: "sys:role:perm", Group: "角色管理", Handler: "RoleController.GetRolePerms", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/roles/:id/menus", Permission: "sys:role:menu:update", Group: "角色管理", Handler: "RoleController.UpdateRoleMenus", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/roles/:id/perms", Permission: "sys:role:perm:update", Group: "角色管理", Handler: "RoleController.UpdateRolePerms", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/roles/options", Permission: "sys:role:options", Group: "角色管理", Handler: "RoleController.ListRoleOptions", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/system/routes", Permission: "sys:system:routes", Group: "系统管理", Handler: "SystemController.GetRoutes", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/users/me", Permission: "sys:user:me", Group: "用户管理", Handler: "UserController.Me", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "DELETE", Path: "/api/v1/users/:id", Permission: "sys:user:delete", Group: "用户管理", Handler: "UserController.Delete", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/users/page", Permission: "sys:user:page", Group: "用户管理", Handler: "UserController.GetUserPage", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/users/:id/form", Permission: "sys:user:info", Group: "用户管理", Handler: "UserController.GetUser", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/users/:id", Permission: "sys:user:edit", Group: "用户管理", Handler: "UserController.UpdateUser", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/users", Permission: "sys:user:add", Group: "用户管理", Handler: "UserController.CreateUser", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/users/:id/password/reset", Permission: "sys:user:reset-password", Group: "用户管理", Handler: "UserController.ResetPassword", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/users/profile", Permission: "sys:user:profile", Group: "用户管理", Handler: "UserController.GetUserProfile", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/users/password", Permission: "sys:user:change-password", Group: "用户管理", Handler: "UserController.ChangePassword", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "PUT", Path: "/api/v1/users/profile", Permission: "sys:user:update-profile", Group: "用户管理", Handler: "UserController.UpdateMyProfile", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/users/options", Permission: "sys:user:options", Group: "用户管理", Handler: "UserController.ListUserOptions", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "POST", Path: "/api/v1/users/import", Permission: "sys:user:import", Group: "用户管理", Handler: "UserController.ImportUsers", Middlewares: []string{"jwt", "dataperm"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/users/import/template", Permission: "sys:user:import-template", Group: "用户管理", Handler: "UserController.DownloadImportTemplate", Middlewares: []string{"jwt"}},
+		routemeta.Route{Method: "GET", Path: "/api/v1/users/import/report/:reportId", Permission: "sys:user:import-report", Group: "用户管理", Handler: "UserController.DownloadImportReport", Middlewares: []string{"jwt"}},
+	)
+	return &AdminServices{
+		UserService:             userService,
+		TokenService:            tokenService,
+		ConfigService:           configService,
+		DictService:             dictService,
+		NoticeAttachmentService: noticeAttachmentService,
+		NoticeDeliveryService:   noticeDeliveryService,
+		NoticesService:          noticesService,
+		NoticeReceiverService:   noticeReceiverService,
+		NoticeTemplateService:   noticeTemplateService,
+	}
+}
+
+// AdminServices RegisterAdminRoutes 创建的服务实例
+type AdminServices struct {
+	UserService             *services.UserServiceImpl
+	TokenService            services.TokenService
+	ConfigService           services.ConfigService
+	DictService             services.DictService
+	NoticeAttachmentService services.NoticeAttachmentService
+	NoticeDeliveryService   services.NoticeDeliveryService
+	NoticesService          services.NoticesService
+	NoticeReceiverService   services.NoticeReceiverService
+	NoticeTemplateService   services.NoticeTemplateService
 }
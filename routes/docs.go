@@ -0,0 +1,103 @@
+package routes
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zmqge/vireo-gin-admin/config"
+)
+
+// swaggerUIVersion 文档中给出的 swagger-ui-dist 版本，静态资源按此版本放到 DOCS.ASSETS_DIR
+const swaggerUIVersion = "5.17.14"
+
+// swaggerAssets Swagger UI 页面需要的静态资源，均从 DOCS.ASSETS_DIR 读取，不从 CDN 加载
+var swaggerAssets = []string{"swagger-ui.css", "swagger-ui-bundle.js"}
+
+// swaggerCSP 文档页面的内容安全策略：脚本和样式只允许同源加载
+const swaggerCSP = "default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; " +
+	"connect-src 'self'; object-src 'none'; base-uri 'none'; frame-ancestors 'none'"
+
+// swaggerHTML Swagger UI 页面，静态资源取 /swagger/assets，文档取 /swagger/openapi.json
+const swaggerHTML = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+  <meta charset="utf-8">
+  <title>接口文档</title>
+  <link rel="stylesheet" href="/swagger/assets/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="/swagger/assets/swagger-ui-bundle.js"></script>
+  <script src="/swagger/init.js"></script>
+</body>
+</html>`
+
+// swaggerInitJS 初始化 Swagger UI；CSP 不允许内联脚本，单独提供
+// 不开启 persistAuthorization，填写的 token 只保存在页面内存中，不写入 localStorage
+const swaggerInitJS = `window.ui = SwaggerUIBundle({
+  url: "/swagger/openapi.json",
+  dom_id: "#swagger-ui",
+  docExpansion: "none",
+  filter: true
+});
+`
+
+// docsPath 配置中的相对路径按项目根目录解析
+func docsPath(path, def string) string {
+	if path == "" {
+		path = def
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(config.ProjectRoot(), path)
+	}
+	return path
+}
+
+// RegisterDocsRoutes 注册接口文档：/swagger/index.html 为 Swagger UI，/swagger/openapi.json 为 cmd/apidoc 生成的文档
+// Swagger UI 的静态资源需预先放到 DOCS.ASSETS_DIR（见 cmd/apidoc/readme.md），缺少时页面返回 404 提示
+func RegisterDocsRoutes(engine *gin.Engine) {
+	file := docsPath(config.App.Docs.File, "docs/openapi.json")
+	assetsDir := docsPath(config.App.Docs.AssetsDir, "docs/swagger-ui")
+
+	docs := engine.Group("/swagger")
+	docs.GET("", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
+	})
+	docs.GET("/index.html", func(c *gin.Context) {
+		for _, name := range swaggerAssets {
+			if _, err := os.Stat(filepath.Join(assetsDir, name)); err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"code": 404,
+					"msg": "Swagger UI 静态资源不存在，请将 swagger-ui-dist@" + swaggerUIVersion + " 的 dist 文件放到 DOCS.ASSETS_DIR"})
+				return
+			}
+		}
+		c.Header("Content-Security-Policy", swaggerCSP)
+		c.Header("Referrer-Policy", "no-referrer")
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerHTML))
+	})
+	docs.GET("/init.js", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/javascript; charset=utf-8", []byte(swaggerInitJS))
+	})
+	docs.GET("/assets/:name", func(c *gin.Context) {
+		name := c.Param("name")
+		for _, asset := range swaggerAssets {
+			if name == asset {
+				c.Header("X-Content-Type-Options", "nosniff")
+				c.File(filepath.Join(assetsDir, name))
+				return
+			}
+		}
+		c.Status(http.StatusNotFound)
+	})
+	docs.GET("/openapi.json", func(c *gin.Context) {
+		if _, err := os.Stat(file); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"code": 404, "msg": "接口文档未生成，请先执行 go run ./cmd/apidoc"})
+			return
+		}
+		c.Header("Cache-Control", "no-cache")
+		c.File(file)
+	})
+}
@@ -3,9 +3,15 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
-
 )
 
-func RegisterAllRoutes(engine *gin.Engine, db *gorm.DB) {
-	RegisterAdminRoutes(engine, db)
+// Services 各模块路由创建的服务实例，后台任务复用同一实例
+type Services struct {
+	Admin *AdminServices
+}
+
+func RegisterAllRoutes(engine *gin.Engine, db *gorm.DB) *Services {
+	return &Services{
+		Admin: RegisterAdminRoutes(engine, db),
+	}
 }